@@ -59,6 +59,19 @@ func (src *AzureCluster) ConvertTo(dstRaw conversion.Hub) error { // nolint
 	}
 
 	dst.Status.FailureDomains = restored.Status.FailureDomains
+	dst.Status.Conditions = restored.Status.Conditions
+	dst.Status.Bastion.HostID = restored.Status.Bastion.HostID
+
+	dst.Spec.ProximityPlacementGroup = restored.Spec.ProximityPlacementGroup
+
+	dst.Spec.NetworkSpec.Vnet.SubscriptionID = restored.Spec.NetworkSpec.Vnet.SubscriptionID
+	dst.Spec.NetworkSpec.Vnet.DDoSProtectionPlanID = restored.Spec.NetworkSpec.Vnet.DDoSProtectionPlanID
+	dst.Spec.NetworkSpec.NodeOutboundLB = restored.Spec.NetworkSpec.NodeOutboundLB
+	dst.Spec.NetworkSpec.APIServerIP = restored.Spec.NetworkSpec.APIServerIP
+	dst.Spec.NetworkSpec.APIServerDNS = restored.Spec.NetworkSpec.APIServerDNS
+	dst.Spec.NetworkSpec.APIServerLB = restored.Spec.NetworkSpec.APIServerLB
+	dst.Spec.NetworkSpec.OutboundType = restored.Spec.NetworkSpec.OutboundType
+	dst.Spec.NetworkSpec.DefaultRouteNextHopIP = restored.Spec.NetworkSpec.DefaultRouteNextHopIP
 
 	for _, restoredSubnet := range restored.Spec.NetworkSpec.Subnets {
 		if restoredSubnet != nil {
@@ -67,6 +80,10 @@ func (src *AzureCluster) ConvertTo(dstRaw conversion.Hub) error { // nolint
 					dstSubnet.RouteTable = restoredSubnet.RouteTable
 
 					dstSubnet.SecurityGroup.IngressRules = restoredSubnet.SecurityGroup.IngressRules
+
+					dstSubnet.ServiceEndpoints = restoredSubnet.ServiceEndpoints
+					dstSubnet.PrivateEndpoints = restoredSubnet.PrivateEndpoints
+					dstSubnet.PrefixLength = restoredSubnet.PrefixLength
 				}
 			}
 		}
@@ -200,6 +217,16 @@ func Convert_v1alpha3_NetworkSpec_To_v1alpha2_NetworkSpec(in *infrav1alpha3.Netw
 	return nil
 }
 
+// Convert_v1alpha3_VnetSpec_To_v1alpha2_VnetSpec.
+func Convert_v1alpha3_VnetSpec_To_v1alpha2_VnetSpec(in *infrav1alpha3.VnetSpec, out *VnetSpec, s apiconversion.Scope) error { //nolint
+	return autoConvert_v1alpha3_VnetSpec_To_v1alpha2_VnetSpec(in, out, s)
+}
+
+// Convert_v1alpha3_VM_To_v1alpha2_VM.
+func Convert_v1alpha3_VM_To_v1alpha2_VM(in *infrav1alpha3.VM, out *VM, s apiconversion.Scope) error { //nolint
+	return autoConvert_v1alpha3_VM_To_v1alpha2_VM(in, out, s)
+}
+
 // Convert_v1alpha2_SubnetSpec_To_v1alpha3_SubnetSpec.
 func Convert_v1alpha2_SubnetSpec_To_v1alpha3_SubnetSpec(in *SubnetSpec, out *infrav1alpha3.SubnetSpec, s apiconversion.Scope) error { //nolint
 	return autoConvert_v1alpha2_SubnetSpec_To_v1alpha3_SubnetSpec(in, out, s)
@@ -38,6 +38,15 @@ func (src *AzureMachine) ConvertTo(dstRaw conversion.Hub) error { // nolint
 	}
 
 	restoreAzureMachineSpec(&restored.Spec, &dst.Spec)
+	dst.Status.SSHPort = restored.Status.SSHPort
+	dst.Status.DedicatedHostID = restored.Status.DedicatedHostID
+	dst.Status.Conditions = restored.Status.Conditions
+	dst.Status.SerialLogConfigMapName = restored.Status.SerialLogConfigMapName
+	dst.Status.SpotVMEvictionTime = restored.Status.SpotVMEvictionTime
+	dst.Status.SpotVMRestartAttempts = restored.Status.SpotVMRestartAttempts
+	dst.Status.LastSpotVMRestartTime = restored.Status.LastSpotVMRestartTime
+	dst.Status.Image = restored.Status.Image
+	dst.Status.LastResizeVMSize = restored.Status.LastResizeVMSize
 	return nil
 }
 
@@ -57,6 +66,20 @@ func restoreAzureMachineSpec(restored, dst *infrav1alpha3.AzureMachineSpec) {
 	if restored.SpotVMOptions != nil {
 		dst.SpotVMOptions = restored.SpotVMOptions.DeepCopy()
 	}
+
+	if len(restored.NetworkInterfaces) > 0 {
+		dst.NetworkInterfaces = restored.NetworkInterfaces
+	}
+
+	dst.ProximityPlacementGroupName = restored.ProximityPlacementGroupName
+	dst.DedicatedHost = restored.DedicatedHost
+	dst.Extensions = restored.Extensions
+	dst.BootDiagnostics = restored.BootDiagnostics
+	dst.AllowInPlaceResize = restored.AllowInPlaceResize
+
+	if restored.Image != nil && restored.Image.Marketplace != nil && dst.Image != nil && dst.Image.Marketplace != nil {
+		dst.Image.Marketplace.Plan = restored.Image.Marketplace.Plan
+	}
 }
 
 // ConvertFrom converts from the Hub version (v1alpha3) to this version.
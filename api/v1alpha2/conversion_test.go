@@ -22,6 +22,7 @@ import (
 	fuzz "github.com/google/gofuzz"
 	. "github.com/onsi/gomega"
 
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	runtimeserializer "k8s.io/apimachinery/pkg/runtime/serializer"
 	v1alpha3 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha3"
@@ -34,9 +35,9 @@ func TestFuzzyConversion(t *testing.T) {
 	g.Expect(AddToScheme(scheme)).To(Succeed())
 	g.Expect(v1alpha3.AddToScheme(scheme)).To(Succeed())
 
-	t.Run("for AzureCluster", utilconversion.FuzzTestFunc(scheme, &v1alpha3.AzureCluster{}, &AzureCluster{}, overrideImageFuncs))
-	t.Run("for AzureMachine", utilconversion.FuzzTestFunc(scheme, &v1alpha3.AzureMachine{}, &AzureMachine{}, overrideImageFuncs))
-	t.Run("for AzureMachineTemplate", utilconversion.FuzzTestFunc(scheme, &v1alpha3.AzureMachineTemplate{}, &AzureMachineTemplate{}, overrideImageFuncs))
+	t.Run("for AzureCluster", utilconversion.FuzzTestFunc(scheme, &v1alpha3.AzureCluster{}, &AzureCluster{}, overrideImageFuncs, overrideVMExtensionFuncs))
+	t.Run("for AzureMachine", utilconversion.FuzzTestFunc(scheme, &v1alpha3.AzureMachine{}, &AzureMachine{}, overrideImageFuncs, overrideVMExtensionFuncs))
+	t.Run("for AzureMachineTemplate", utilconversion.FuzzTestFunc(scheme, &v1alpha3.AzureMachineTemplate{}, &AzureMachineTemplate{}, overrideImageFuncs, overrideVMExtensionFuncs))
 }
 
 func overrideImageFuncs(codecs runtimeserializer.CodecFactory) []interface{} {
@@ -51,3 +52,12 @@ func overrideImageFuncs(codecs runtimeserializer.CodecFactory) []interface{} {
 		},
 	}
 }
+
+func overrideVMExtensionFuncs(codecs runtimeserializer.CodecFactory) []interface{} {
+	return []interface{}{
+		func(extension *v1alpha3.VMExtension, c fuzz.Continue) {
+			c.FuzzNoCustom(extension)
+			extension.Settings = &apiextensionsv1.JSON{Raw: []byte(`{"commandToExecute":"echo hello"}`)}
+		},
+	}
+}
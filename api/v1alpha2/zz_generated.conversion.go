@@ -206,21 +206,11 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*v1alpha3.VM)(nil), (*VM)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_v1alpha3_VM_To_v1alpha2_VM(a.(*v1alpha3.VM), b.(*VM), scope)
-	}); err != nil {
-		return err
-	}
 	if err := s.AddGeneratedConversionFunc((*VnetSpec)(nil), (*v1alpha3.VnetSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha2_VnetSpec_To_v1alpha3_VnetSpec(a.(*VnetSpec), b.(*v1alpha3.VnetSpec), scope)
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*v1alpha3.VnetSpec)(nil), (*VnetSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_v1alpha3_VnetSpec_To_v1alpha2_VnetSpec(a.(*v1alpha3.VnetSpec), b.(*VnetSpec), scope)
-	}); err != nil {
-		return err
-	}
 	if err := s.AddConversionFunc((*AzureClusterSpec)(nil), (*v1alpha3.AzureClusterSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha2_AzureClusterSpec_To_v1alpha3_AzureClusterSpec(a.(*AzureClusterSpec), b.(*v1alpha3.AzureClusterSpec), scope)
 	}); err != nil {
@@ -316,6 +306,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddConversionFunc((*v1alpha3.VM)(nil), (*VM)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha3_VM_To_v1alpha2_VM(a.(*v1alpha3.VM), b.(*VM), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddConversionFunc((*v1alpha3.VnetSpec)(nil), (*VnetSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha3_VnetSpec_To_v1alpha2_VnetSpec(a.(*v1alpha3.VnetSpec), b.(*VnetSpec), scope)
+	}); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -434,6 +434,7 @@ func autoConvert_v1alpha3_AzureClusterSpec_To_v1alpha2_AzureClusterSpec(in *v1al
 	out.Location = in.Location
 	// WARNING: in.ControlPlaneEndpoint requires manual conversion: does not exist in peer-type
 	out.AdditionalTags = *(*Tags)(unsafe.Pointer(&in.AdditionalTags))
+	// WARNING: in.ProximityPlacementGroup requires manual conversion: does not exist in peer-type
 	return nil
 }
 
@@ -458,6 +459,7 @@ func autoConvert_v1alpha3_AzureClusterStatus_To_v1alpha2_AzureClusterStatus(in *
 		return err
 	}
 	out.Ready = in.Ready
+	// WARNING: in.Conditions requires manual conversion: does not exist in peer-type
 	return nil
 }
 
@@ -563,6 +565,7 @@ func autoConvert_v1alpha2_AzureMachineSpec_To_v1alpha3_AzureMachineSpec(in *Azur
 func autoConvert_v1alpha3_AzureMachineSpec_To_v1alpha2_AzureMachineSpec(in *v1alpha3.AzureMachineSpec, out *AzureMachineSpec, s conversion.Scope) error {
 	out.ProviderID = (*string)(unsafe.Pointer(in.ProviderID))
 	out.VMSize = in.VMSize
+	// WARNING: in.AllowInPlaceResize requires manual conversion: does not exist in peer-type
 	// WARNING: in.FailureDomain requires manual conversion: does not exist in peer-type
 	if err := Convert_v1alpha3_AvailabilityZone_To_v1alpha2_AvailabilityZone(&in.AvailabilityZone, &out.AvailabilityZone, s); err != nil {
 		return err
@@ -586,7 +589,12 @@ func autoConvert_v1alpha3_AzureMachineSpec_To_v1alpha2_AzureMachineSpec(in *v1al
 	out.AdditionalTags = *(*Tags)(unsafe.Pointer(&in.AdditionalTags))
 	out.AllocatePublicIP = in.AllocatePublicIP
 	// WARNING: in.AcceleratedNetworking requires manual conversion: does not exist in peer-type
+	// WARNING: in.NetworkInterfaces requires manual conversion: does not exist in peer-type
 	// WARNING: in.SpotVMOptions requires manual conversion: does not exist in peer-type
+	// WARNING: in.ProximityPlacementGroupName requires manual conversion: does not exist in peer-type
+	// WARNING: in.DedicatedHost requires manual conversion: does not exist in peer-type
+	// WARNING: in.Extensions requires manual conversion: does not exist in peer-type
+	// WARNING: in.BootDiagnostics requires manual conversion: does not exist in peer-type
 	return nil
 }
 
@@ -603,8 +611,17 @@ func autoConvert_v1alpha3_AzureMachineStatus_To_v1alpha2_AzureMachineStatus(in *
 	out.Ready = in.Ready
 	out.Addresses = *(*[]v1.NodeAddress)(unsafe.Pointer(&in.Addresses))
 	out.VMState = (*VMState)(unsafe.Pointer(in.VMState))
+	// WARNING: in.SSHPort requires manual conversion: does not exist in peer-type
+	// WARNING: in.DedicatedHostID requires manual conversion: does not exist in peer-type
+	// WARNING: in.SerialLogConfigMapName requires manual conversion: does not exist in peer-type
+	// WARNING: in.SpotVMEvictionTime requires manual conversion: does not exist in peer-type
+	// WARNING: in.SpotVMRestartAttempts requires manual conversion: does not exist in peer-type
+	// WARNING: in.LastSpotVMRestartTime requires manual conversion: does not exist in peer-type
+	// WARNING: in.LastResizeVMSize requires manual conversion: does not exist in peer-type
+	// WARNING: in.Image requires manual conversion: does not exist in peer-type
 	// WARNING: in.FailureReason requires manual conversion: does not exist in peer-type
 	// WARNING: in.FailureMessage requires manual conversion: does not exist in peer-type
+	// WARNING: in.Conditions requires manual conversion: does not exist in peer-type
 	return nil
 }
 
@@ -833,6 +850,8 @@ func autoConvert_v1alpha3_IngressRule_To_v1alpha2_IngressRule(in *v1alpha3.Ingre
 	out.DestinationPorts = (*string)(unsafe.Pointer(in.DestinationPorts))
 	out.Source = (*string)(unsafe.Pointer(in.Source))
 	out.Destination = (*string)(unsafe.Pointer(in.Destination))
+	// WARNING: in.SourceApplicationSecurityGroup requires manual conversion: does not exist in peer-type
+	// WARNING: in.DestinationApplicationSecurityGroup requires manual conversion: does not exist in peer-type
 	return nil
 }
 
@@ -955,6 +974,12 @@ func autoConvert_v1alpha3_NetworkSpec_To_v1alpha2_NetworkSpec(in *v1alpha3.Netwo
 	} else {
 		out.Subnets = nil
 	}
+	// WARNING: in.OutboundType requires manual conversion: does not exist in peer-type
+	// WARNING: in.DefaultRouteNextHopIP requires manual conversion: does not exist in peer-type
+	// WARNING: in.NodeOutboundLB requires manual conversion: does not exist in peer-type
+	// WARNING: in.APIServerIP requires manual conversion: does not exist in peer-type
+	// WARNING: in.APIServerDNS requires manual conversion: does not exist in peer-type
+	// WARNING: in.APIServerLB requires manual conversion: does not exist in peer-type
 	return nil
 }
 
@@ -1067,11 +1092,14 @@ func autoConvert_v1alpha3_SubnetSpec_To_v1alpha2_SubnetSpec(in *v1alpha3.SubnetS
 	out.ID = in.ID
 	out.Name = in.Name
 	out.CidrBlock = in.CidrBlock
+	// WARNING: in.PrefixLength requires manual conversion: does not exist in peer-type
 	out.InternalLBIPAddress = in.InternalLBIPAddress
 	if err := Convert_v1alpha3_SecurityGroup_To_v1alpha2_SecurityGroup(&in.SecurityGroup, &out.SecurityGroup, s); err != nil {
 		return err
 	}
 	// WARNING: in.RouteTable requires manual conversion: does not exist in peer-type
+	// WARNING: in.ServiceEndpoints requires manual conversion: does not exist in peer-type
+	// WARNING: in.PrivateEndpoints requires manual conversion: does not exist in peer-type
 	return nil
 }
 
@@ -1114,15 +1142,11 @@ func autoConvert_v1alpha3_VM_To_v1alpha2_VM(in *v1alpha3.VM, out *VM, s conversi
 	out.State = VMState(in.State)
 	out.Identity = VMIdentity(in.Identity)
 	out.Tags = *(*Tags)(unsafe.Pointer(&in.Tags))
+	// WARNING: in.HostID requires manual conversion: does not exist in peer-type
 	out.Addresses = *(*[]v1.NodeAddress)(unsafe.Pointer(&in.Addresses))
 	return nil
 }
 
-// Convert_v1alpha3_VM_To_v1alpha2_VM is an autogenerated conversion function.
-func Convert_v1alpha3_VM_To_v1alpha2_VM(in *v1alpha3.VM, out *VM, s conversion.Scope) error {
-	return autoConvert_v1alpha3_VM_To_v1alpha2_VM(in, out, s)
-}
-
 func autoConvert_v1alpha2_VnetSpec_To_v1alpha3_VnetSpec(in *VnetSpec, out *v1alpha3.VnetSpec, s conversion.Scope) error {
 	out.ResourceGroup = in.ResourceGroup
 	out.ID = in.ID
@@ -1141,12 +1165,9 @@ func autoConvert_v1alpha3_VnetSpec_To_v1alpha2_VnetSpec(in *v1alpha3.VnetSpec, o
 	out.ResourceGroup = in.ResourceGroup
 	out.ID = in.ID
 	out.Name = in.Name
+	// WARNING: in.SubscriptionID requires manual conversion: does not exist in peer-type
 	out.CidrBlock = in.CidrBlock
+	// WARNING: in.DDoSProtectionPlanID requires manual conversion: does not exist in peer-type
 	out.Tags = *(*Tags)(unsafe.Pointer(&in.Tags))
 	return nil
 }
-
-// Convert_v1alpha3_VnetSpec_To_v1alpha2_VnetSpec is an autogenerated conversion function.
-func Convert_v1alpha3_VnetSpec_To_v1alpha2_VnetSpec(in *v1alpha3.VnetSpec, out *VnetSpec, s conversion.Scope) error {
-	return autoConvert_v1alpha3_VnetSpec_To_v1alpha2_VnetSpec(in, out, s)
-}
@@ -18,39 +18,57 @@ package v1alpha3
 
 import (
 	"fmt"
+	"sort"
+
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/cluster-api-provider-azure/internal/ipam"
 )
 
 const (
 	// DefaultVnetCIDR is the default Vnet CIDR
 	DefaultVnetCIDR = "10.0.0.0/8"
 	// DefaultControlPlaneSubnetCIDR is the default Control Plane Subnet CIDR
+	// Deprecated: subnet CIDR blocks are allocated from the Vnet address space, see NetworkSpec.AllocateSubnetCIDRs.
 	DefaultControlPlaneSubnetCIDR = "10.0.0.0/16"
 	// DefaultNodeSubnetCIDR is the default Node Subnet CIDR
+	// Deprecated: subnet CIDR blocks are allocated from the Vnet address space, see NetworkSpec.AllocateSubnetCIDRs.
 	DefaultNodeSubnetCIDR = "10.1.0.0/16"
+	// DefaultHealthProbeRequestPath is the default API server endpoint probed by HTTPS health probes
+	DefaultHealthProbeRequestPath = "/readyz"
+	// DefaultHealthProbeIntervalInSeconds is the default interval between two health probes
+	DefaultHealthProbeIntervalInSeconds = 15
+	// DefaultHealthProbeNumberOfProbes is the default number of failed health probes before a node stops receiving traffic
+	DefaultHealthProbeNumberOfProbes = 4
 )
 
-func (c *AzureCluster) setDefaults() {
-	c.setNetworkSpecDefaults()
+func (c *AzureCluster) setDefaults() error {
+	return c.setNetworkSpecDefaults()
 }
 
-func (c *AzureCluster) setNetworkSpecDefaults() {
+func (c *AzureCluster) setNetworkSpecDefaults() error {
 	c.setVnetDefaults()
-	c.setSubnetDefaults()
+	err := c.setSubnetDefaults()
+	c.setAPIServerDNSDefaults()
+	c.setAPIServerLBDefaults()
+	return err
 }
 
 func (c *AzureCluster) setVnetDefaults() {
 	if c.Spec.NetworkSpec.Vnet.ResourceGroup == "" {
 		c.Spec.NetworkSpec.Vnet.ResourceGroup = c.Spec.ResourceGroup
 	}
+	// the address space of a vnet named by the user is only known once it is read from Azure or created,
+	// so that its subnets aren't validated against the default address space
 	if c.Spec.NetworkSpec.Vnet.Name == "" {
 		c.Spec.NetworkSpec.Vnet.Name = generateVnetName(c.ObjectMeta.Name)
-	}
-	if c.Spec.NetworkSpec.Vnet.CidrBlock == "" {
-		c.Spec.NetworkSpec.Vnet.CidrBlock = DefaultVnetCIDR
+		if c.Spec.NetworkSpec.Vnet.CidrBlock == "" {
+			c.Spec.NetworkSpec.Vnet.CidrBlock = DefaultVnetCIDR
+		}
 	}
 }
 
-func (c *AzureCluster) setSubnetDefaults() {
+func (c *AzureCluster) setSubnetDefaults() error {
 	cpSubnet := c.Spec.NetworkSpec.GetControlPlaneSubnet()
 	if cpSubnet == nil {
 		cpSubnet = &SubnetSpec{Role: SubnetControlPlane}
@@ -66,9 +84,6 @@ func (c *AzureCluster) setSubnetDefaults() {
 	if cpSubnet.Name == "" {
 		cpSubnet.Name = generateControlPlaneSubnetName(c.ObjectMeta.Name)
 	}
-	if cpSubnet.CidrBlock == "" {
-		cpSubnet.CidrBlock = DefaultControlPlaneSubnetCIDR
-	}
 	if cpSubnet.SecurityGroup.Name == "" {
 		cpSubnet.SecurityGroup.Name = generateControlPlaneSecurityGroupName(c.ObjectMeta.Name)
 	}
@@ -79,15 +94,118 @@ func (c *AzureCluster) setSubnetDefaults() {
 	if nodeSubnet.Name == "" {
 		nodeSubnet.Name = generateNodeSubnetName(c.ObjectMeta.Name)
 	}
-	if nodeSubnet.CidrBlock == "" {
-		nodeSubnet.CidrBlock = DefaultNodeSubnetCIDR
-	}
 	if nodeSubnet.SecurityGroup.Name == "" {
 		nodeSubnet.SecurityGroup.Name = generateNodeSecurityGroupName(c.ObjectMeta.Name)
 	}
 	if nodeSubnet.RouteTable.Name == "" {
 		nodeSubnet.RouteTable.Name = generateRouteTableName(c.ObjectMeta.Name)
 	}
+
+	// the subnets of a vnet named by the user get their CIDR blocks when the cluster is reconciled,
+	// once it is known whether the vnet already exists
+	if !c.HasDefaultVnetName() {
+		return nil
+	}
+	return c.Spec.NetworkSpec.AllocateSubnetCIDRs()
+}
+
+// HasDefaultVnetName returns true if the Vnet has the name defaulted by the webhook, which means it is created by the provider.
+func (c *AzureCluster) HasDefaultVnetName() bool {
+	return c.Spec.NetworkSpec.Vnet.Name == "" || c.Spec.NetworkSpec.Vnet.Name == generateVnetName(c.ObjectMeta.Name)
+}
+
+func (c *AzureCluster) setAPIServerDNSDefaults() {
+	if c.Spec.NetworkSpec.APIServerDNS == nil {
+		return
+	}
+	if c.Spec.NetworkSpec.APIServerDNS.RecordName == "" {
+		c.Spec.NetworkSpec.APIServerDNS.RecordName = generateAPIServerRecordName(c.ObjectMeta.Name)
+	}
+}
+
+func (c *AzureCluster) setAPIServerLBDefaults() {
+	if c.Spec.NetworkSpec.APIServerLB == nil || c.Spec.NetworkSpec.APIServerLB.HealthProbe == nil {
+		return
+	}
+	c.Spec.NetworkSpec.APIServerLB.HealthProbe.SetDefaults(c.Spec.NetworkSpec.GetLoadBalancerSKU())
+}
+
+// SetDefaults sets the unset fields of the health probe of a load balancer with the given SKU:
+// HTTPS probes of /readyz, or TCP probes with the Basic SKU which doesn't support HTTPS.
+func (p *HealthProbeSpec) SetDefaults(sku SKU) {
+	if p.Protocol == "" {
+		p.Protocol = HealthProbeProtocolHTTPS
+		if sku == SKUBasic {
+			p.Protocol = HealthProbeProtocolTCP
+		}
+	}
+	if p.Protocol == HealthProbeProtocolHTTPS && p.RequestPath == "" {
+		p.RequestPath = DefaultHealthProbeRequestPath
+	}
+	if p.IntervalInSeconds == nil {
+		p.IntervalInSeconds = to.Int32Ptr(DefaultHealthProbeIntervalInSeconds)
+	}
+	if p.NumberOfProbes == nil {
+		p.NumberOfProbes = to.Int32Ptr(DefaultHealthProbeNumberOfProbes)
+	}
+}
+
+// AllocateSubnetCIDRs assigns a CIDR block carved out of the Vnet address space to every subnet
+// that doesn't specify one, starting with the control plane and node subnets.
+// It must only be called for managed Vnets, the CIDR blocks of the subnets of a custom Vnet are read from Azure.
+// It is called by the defaulting webhook for Vnets with a defaulted name, and when the cluster is reconciled for
+// managed Vnets named by the user.
+// It returns an error if user specified CIDR blocks overlap or the Vnet runs out of address space.
+func (n *NetworkSpec) AllocateSubnetCIDRs() error {
+	vnetCIDR := n.Vnet.CidrBlock
+	if vnetCIDR == "" {
+		vnetCIDR = DefaultVnetCIDR
+	}
+	allocator, err := ipam.NewAllocator(vnetCIDR)
+	if err != nil {
+		return errors.Wrap(err, "failed to parse vnet CIDR block")
+	}
+	defaultPrefixLength, err := ipam.DefaultPrefixLength(vnetCIDR)
+	if err != nil {
+		return errors.Wrap(err, "failed to parse vnet CIDR block")
+	}
+
+	var pending Subnets
+	for _, subnet := range n.Subnets {
+		if subnet.CidrBlock == "" {
+			pending = append(pending, subnet)
+			continue
+		}
+		if err := allocator.Reserve(subnet.CidrBlock); err != nil {
+			return errors.Wrapf(err, "invalid CIDR block for subnet %s", subnet.Name)
+		}
+	}
+	// the control plane and node subnets get the first blocks of the address space
+	allocationOrder := func(role SubnetRole) int {
+		switch role {
+		case SubnetControlPlane:
+			return 0
+		case SubnetNode:
+			return 1
+		}
+		return 2
+	}
+	sort.SliceStable(pending, func(i, j int) bool {
+		return allocationOrder(pending[i].Role) < allocationOrder(pending[j].Role)
+	})
+
+	for _, subnet := range pending {
+		prefixLength := defaultPrefixLength
+		if subnet.PrefixLength != 0 {
+			prefixLength = int(subnet.PrefixLength)
+		}
+		cidr, err := allocator.Allocate(prefixLength)
+		if err != nil {
+			return errors.Wrapf(err, "failed to allocate CIDR block for subnet %s", subnet.Name)
+		}
+		subnet.CidrBlock = cidr
+	}
+	return nil
 }
 
 // generateVnetName generates a virtual network name, based on the cluster name.
@@ -119,3 +237,8 @@ func generateNodeSecurityGroupName(clusterName string) string {
 func generateRouteTableName(clusterName string) string {
 	return fmt.Sprintf("%s-%s", clusterName, "node-routetable")
 }
+
+// generateAPIServerRecordName generates the name of the API server DNS record, based on the cluster name.
+func generateAPIServerRecordName(clusterName string) string {
+	return fmt.Sprintf("%s.%s", "api", clusterName)
+}
@@ -21,6 +21,8 @@ import (
 	"reflect"
 	"testing"
 
+	"github.com/Azure/go-autorest/autorest/to"
+	. "github.com/onsi/gomega"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -39,7 +41,6 @@ func TestVnetDefaults(t *testing.T) {
 						Vnet: VnetSpec{
 							ResourceGroup: "custom-vnet",
 							Name:          "my-vnet",
-							CidrBlock:     DefaultVnetCIDR,
 						},
 						Subnets: Subnets{
 							{
@@ -313,13 +314,112 @@ func TestSubnetDefaults(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "subnet CIDRs allocated from custom vnet",
+			cluster: &AzureCluster{
+				ObjectMeta: v1.ObjectMeta{
+					Name: "cluster-test",
+				},
+				Spec: AzureClusterSpec{
+					NetworkSpec: NetworkSpec{
+						Vnet: VnetSpec{
+							CidrBlock: "172.16.0.0/16",
+						},
+						Subnets: Subnets{
+							{
+								Role:      SubnetNode,
+								Name:      "my-node-subnet",
+								CidrBlock: "172.16.0.0/18",
+							},
+							{
+								Role:         SubnetControlPlane,
+								Name:         "my-controlplane-subnet",
+								PrefixLength: 24,
+							},
+						},
+					},
+				},
+			},
+			output: &AzureCluster{
+				ObjectMeta: v1.ObjectMeta{
+					Name: "cluster-test",
+				},
+				Spec: AzureClusterSpec{
+					NetworkSpec: NetworkSpec{
+						Vnet: VnetSpec{
+							CidrBlock: "172.16.0.0/16",
+						},
+						Subnets: Subnets{
+							{
+								Role:          SubnetNode,
+								Name:          "my-node-subnet",
+								CidrBlock:     "172.16.0.0/18",
+								SecurityGroup: SecurityGroup{Name: "cluster-test-node-nsg"},
+								RouteTable:    RouteTable{Name: "cluster-test-node-routetable"},
+							},
+							{
+								Role:          SubnetControlPlane,
+								Name:          "my-controlplane-subnet",
+								CidrBlock:     "172.16.64.0/24",
+								PrefixLength:  24,
+								SecurityGroup: SecurityGroup{Name: "cluster-test-controlplane-nsg"},
+								RouteTable:    RouteTable{Name: "cluster-test-node-routetable"},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "subnet CIDRs not allocated for vnet named by the user",
+			cluster: &AzureCluster{
+				ObjectMeta: v1.ObjectMeta{
+					Name: "cluster-test",
+				},
+				Spec: AzureClusterSpec{
+					NetworkSpec: NetworkSpec{
+						Vnet: VnetSpec{
+							Name: "my-vnet",
+						},
+					},
+				},
+			},
+			output: &AzureCluster{
+				ObjectMeta: v1.ObjectMeta{
+					Name: "cluster-test",
+				},
+				Spec: AzureClusterSpec{
+					NetworkSpec: NetworkSpec{
+						Vnet: VnetSpec{
+							Name: "my-vnet",
+						},
+						Subnets: Subnets{
+							{
+								Role:          SubnetControlPlane,
+								Name:          "cluster-test-controlplane-subnet",
+								SecurityGroup: SecurityGroup{Name: "cluster-test-controlplane-nsg"},
+								RouteTable:    RouteTable{Name: "cluster-test-node-routetable"},
+							},
+							{
+								Role:          SubnetNode,
+								Name:          "cluster-test-node-subnet",
+								SecurityGroup: SecurityGroup{Name: "cluster-test-node-nsg"},
+								RouteTable:    RouteTable{Name: "cluster-test-node-routetable"},
+							},
+						},
+					},
+				},
+			},
+		},
 	}
 
 	for _, c := range cases {
 		tc := c
 		t.Run(tc.name, func(t *testing.T) {
 			t.Parallel()
-			tc.cluster.setSubnetDefaults()
+			if err := tc.cluster.setSubnetDefaults(); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
 			if !reflect.DeepEqual(tc.cluster, tc.output) {
 				expected, _ := json.MarshalIndent(tc.output, "", "\t")
 				actual, _ := json.MarshalIndent(tc.cluster, "", "\t")
@@ -328,3 +428,76 @@ func TestSubnetDefaults(t *testing.T) {
 		})
 	}
 }
+
+func TestAllocateSubnetCIDRs(t *testing.T) {
+	g := NewWithT(t)
+
+	networkSpec := NetworkSpec{
+		Vnet: VnetSpec{CidrBlock: "10.0.0.0/16"},
+		Subnets: Subnets{
+			{Role: SubnetNode, Name: "node-subnet"},
+			{Role: SubnetControlPlane, Name: "controlplane-subnet", PrefixLength: 24},
+			{Role: SubnetNode, Name: "extra-subnet", CidrBlock: "10.0.64.0/18"},
+		},
+	}
+	g.Expect(networkSpec.AllocateSubnetCIDRs()).To(Succeed())
+	g.Expect(networkSpec.Subnets[0].CidrBlock).To(Equal("10.0.128.0/18"))
+	g.Expect(networkSpec.Subnets[1].CidrBlock).To(Equal("10.0.0.0/24"))
+	g.Expect(networkSpec.Subnets[1].InternalLBIPAddress).To(BeEmpty())
+
+	networkSpec.Subnets[0].CidrBlock = "10.0.0.0/24"
+	g.Expect(networkSpec.AllocateSubnetCIDRs()).To(MatchError("invalid CIDR block for subnet controlplane-subnet: CIDR block 10.0.0.0/24 overlaps 10.0.0.0/24"))
+}
+
+func TestAPIServerDNSDefaults(t *testing.T) {
+	g := NewWithT(t)
+
+	cluster := &AzureCluster{
+		ObjectMeta: v1.ObjectMeta{
+			Name: "cluster-test",
+		},
+	}
+	cluster.setAPIServerDNSDefaults()
+	g.Expect(cluster.Spec.NetworkSpec.APIServerDNS).To(BeNil())
+
+	cluster.Spec.NetworkSpec.APIServerDNS = &DNSRecordSpec{ZoneID: "zone"}
+	cluster.setAPIServerDNSDefaults()
+	g.Expect(cluster.Spec.NetworkSpec.APIServerDNS.RecordName).To(Equal("api.cluster-test"))
+
+	cluster.Spec.NetworkSpec.APIServerDNS.RecordName = "k8s"
+	cluster.setAPIServerDNSDefaults()
+	g.Expect(cluster.Spec.NetworkSpec.APIServerDNS.RecordName).To(Equal("k8s"))
+}
+
+func TestAPIServerLBDefaults(t *testing.T) {
+	g := NewWithT(t)
+
+	cluster := &AzureCluster{}
+	cluster.setAPIServerLBDefaults()
+	g.Expect(cluster.Spec.NetworkSpec.APIServerLB).To(BeNil())
+
+	cluster.Spec.NetworkSpec.APIServerLB = &APIServerLBSpec{HealthProbe: &HealthProbeSpec{}}
+	cluster.setAPIServerLBDefaults()
+	g.Expect(cluster.Spec.NetworkSpec.APIServerLB.HealthProbe).To(Equal(&HealthProbeSpec{
+		Protocol:          HealthProbeProtocolHTTPS,
+		RequestPath:       "/readyz",
+		IntervalInSeconds: to.Int32Ptr(15),
+		NumberOfProbes:    to.Int32Ptr(4),
+	}))
+
+	cluster.Spec.NetworkSpec.APIServerLB = &APIServerLBSpec{SKU: SKUBasic, HealthProbe: &HealthProbeSpec{}}
+	cluster.setAPIServerLBDefaults()
+	g.Expect(cluster.Spec.NetworkSpec.APIServerLB.HealthProbe.Protocol).To(Equal(HealthProbeProtocolTCP))
+	g.Expect(cluster.Spec.NetworkSpec.APIServerLB.HealthProbe.RequestPath).To(BeEmpty())
+
+	cluster.Spec.NetworkSpec.APIServerLB.HealthProbe = &HealthProbeSpec{
+		Protocol:          HealthProbeProtocolTCP,
+		IntervalInSeconds: to.Int32Ptr(5),
+	}
+	cluster.setAPIServerLBDefaults()
+	g.Expect(cluster.Spec.NetworkSpec.APIServerLB.HealthProbe).To(Equal(&HealthProbeSpec{
+		Protocol:          HealthProbeProtocolTCP,
+		IntervalInSeconds: to.Int32Ptr(5),
+		NumberOfProbes:    to.Int32Ptr(4),
+	}))
+}
@@ -46,6 +46,11 @@ type AzureClusterSpec struct {
 	// ones added by default.
 	// +optional
 	AdditionalTags Tags `json:"additionalTags,omitempty"`
+
+	// ProximityPlacementGroup is a proximity placement group created by the provider in the cluster resource group.
+	// Control plane machines are placed in it unless they specify their own proximity placement group.
+	// +optional
+	ProximityPlacementGroup *ProximityPlacementGroupSpec `json:"proximityPlacementGroup,omitempty"`
 }
 
 // AzureClusterStatus defines the observed state of AzureCluster
@@ -65,6 +70,10 @@ type AzureClusterStatus struct {
 	// Ready is true when the provider resource is ready.
 	// +optional
 	Ready bool `json:"ready"`
+
+	// Conditions defines current service state of the AzureCluster.
+	// +optional
+	Conditions clusterv1.Conditions `json:"conditions,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -87,6 +96,16 @@ type AzureCluster struct {
 	Status AzureClusterStatus `json:"status,omitempty"`
 }
 
+// GetConditions returns the list of conditions for an AzureCluster API object.
+func (c *AzureCluster) GetConditions() clusterv1.Conditions {
+	return c.Status.Conditions
+}
+
+// SetConditions will set the given conditions on an AzureCluster object.
+func (c *AzureCluster) SetConditions(conditions clusterv1.Conditions) {
+	c.Status.Conditions = conditions
+}
+
 // +kubebuilder:object:root=true
 
 // AzureClusterList contains a list of AzureCluster
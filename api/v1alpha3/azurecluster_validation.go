@@ -18,11 +18,16 @@ package v1alpha3
 
 import (
 	"fmt"
+	"net"
 	"regexp"
+	"strings"
 
+	"github.com/Azure/go-autorest/autorest/azure"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/cluster-api-provider-azure/internal/ipam"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha3"
 )
 
 const (
@@ -31,6 +36,18 @@ const (
 	// described in https://docs.microsoft.com/en-us/azure/azure-resource-manager/management/resource-name-rules
 	subnetRegex = `^[-\w\._]+$`
 	ipv4Regex   = `^(?:[0-9]{1,3}\.){3}[0-9]{1,3}$`
+	// described in https://docs.microsoft.com/en-us/azure/azure-resource-manager/management/resource-name-rules
+	privateEndpointRegex = `^[a-zA-Z0-9][-\w\.]{0,78}\w$`
+	// described in https://docs.microsoft.com/en-us/azure/virtual-network/public-ip-addresses#dns-hostname-resolution
+	dnsLabelRegex = `^[a-z][a-z0-9-]{1,61}[a-z0-9]$`
+	// a relative DNS name made of labels separated by dots
+	dnsRecordNameRegex = `^[a-zA-Z0-9_]([-a-zA-Z0-9_]{0,61}[a-zA-Z0-9_])?(\.[a-zA-Z0-9_]([-a-zA-Z0-9_]{0,61}[a-zA-Z0-9_])?)*$`
+	// described in https://docs.microsoft.com/en-us/azure/load-balancer/outbound-rules
+	maxAllocatedOutboundPorts = 64000
+	minOutboundIdleTimeout    = 4
+	maxOutboundIdleTimeout    = 120
+	// described in https://docs.microsoft.com/en-us/azure/load-balancer/load-balancer-custom-probe-overview
+	minHealthProbeIntervalInSeconds = 5
 )
 
 // validateCluster validates a cluster
@@ -46,11 +63,77 @@ func (c *AzureCluster) validateCluster() error {
 		c.Name, allErrs)
 }
 
+// validateClusterUpdate validates an update of a cluster
+func (c *AzureCluster) validateClusterUpdate(old *AzureCluster) error {
+	var allErrs field.ErrorList
+	allErrs = append(allErrs, c.validateClusterSpec()...)
+	allErrs = append(allErrs, validateLoadBalancerSKUUpdate(
+		old.Spec.NetworkSpec,
+		c.Spec.NetworkSpec,
+		field.NewPath("spec").Child("networkSpec"))...)
+	allErrs = append(allErrs, validateAPIServerIPUpdate(
+		old.Spec.NetworkSpec.APIServerIP,
+		c.Spec.NetworkSpec.APIServerIP,
+		field.NewPath("spec").Child("networkSpec", "apiServerIP"))...)
+	if len(allErrs) == 0 {
+		return nil
+	}
+
+	return apierrors.NewInvalid(
+		schema.GroupKind{Group: "infrastructure.cluster.x-k8s.io", Kind: "AzureCluster"},
+		c.Name, allErrs)
+}
+
+// ValidateClusterNetwork validates the AzureCluster like the validating webhook does, and additionally
+// checks that the pod and service CIDR blocks of the owner Cluster don't collide with the Vnet.
+func (c *AzureCluster) ValidateClusterNetwork(clusterNetwork *clusterv1.ClusterNetwork) error {
+	var allErrs field.ErrorList
+	allErrs = append(allErrs, c.validateClusterSpec()...)
+	allErrs = append(allErrs, validateClusterNetworkCIDRs(
+		c.Spec.NetworkSpec,
+		clusterNetwork,
+		field.NewPath("spec").Child("networkSpec"))...)
+	if len(allErrs) == 0 {
+		return nil
+	}
+
+	return apierrors.NewInvalid(
+		schema.GroupKind{Group: "infrastructure.cluster.x-k8s.io", Kind: "AzureCluster"},
+		c.Name, allErrs)
+}
+
 // validateClusterSpec validates a ClusterSpec
 func (c *AzureCluster) validateClusterSpec() field.ErrorList {
-	return validateNetworkSpec(
+	var allErrs field.ErrorList
+	allErrs = append(allErrs, validateNetworkSpec(
 		c.Spec.NetworkSpec,
-		field.NewPath("spec").Child("networkSpec"))
+		field.NewPath("spec").Child("networkSpec"))...)
+	allErrs = append(allErrs, validateManagedVnetDefaultRoute(
+		c.Spec.NetworkSpec,
+		generateVnetName(c.Name),
+		field.NewPath("spec").Child("networkSpec"))...)
+	allErrs = append(allErrs, validateManagedVnetSubscription(
+		c.Spec.NetworkSpec,
+		generateVnetName(c.Name),
+		c.Spec.SubscriptionID,
+		field.NewPath("spec").Child("networkSpec"))...)
+	if len(allErrs) == 0 && c.HasDefaultVnetName() {
+		allErrs = append(allErrs, validateSubnetCIDRAllocation(
+			c.Spec.NetworkSpec,
+			field.NewPath("spec").Child("networkSpec"))...)
+	}
+	return allErrs
+}
+
+// validateSubnetCIDRAllocation validates that the defaulting webhook can allocate the CIDR blocks of the subnets
+// of the Vnet created by the provider, e.g. that its address space is large enough
+func validateSubnetCIDRAllocation(networkSpec NetworkSpec, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	networkSpec.Subnets = networkSpec.Subnets.DeepCopy()
+	if err := networkSpec.AllocateSubnetCIDRs(); err != nil {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("vnet", "cidrBlock"), networkSpec.Vnet.CidrBlock, err.Error()))
+	}
+	return allErrs
 }
 
 // validateNetworkSpec validates a NetworkSpec
@@ -66,12 +149,256 @@ func validateNetworkSpec(networkSpec NetworkSpec, fldPath *field.Path) field.Err
 		}
 		allErrs = append(allErrs, validateSubnets(networkSpec.Subnets, fldPath.Child("subnets"))...)
 	}
+	if networkSpec.Vnet.DDoSProtectionPlanID != "" {
+		if err := validateDDoSProtectionPlanID(networkSpec.Vnet.DDoSProtectionPlanID,
+			fldPath.Child("vnet").Child("ddosProtectionPlanID")); err != nil {
+			allErrs = append(allErrs, err)
+		}
+	}
+	allErrs = append(allErrs, validateNetworkCIDRs(networkSpec, fldPath)...)
+	allErrs = append(allErrs, validateEndpoints(networkSpec.Subnets, fldPath.Child("subnets"))...)
+	if networkSpec.NodeOutboundLB != nil {
+		allErrs = append(allErrs, validateNodeOutboundLB(networkSpec.NodeOutboundLB, fldPath.Child("nodeOutboundLB"))...)
+	}
+	if networkSpec.APIServerIP != nil {
+		allErrs = append(allErrs, validateAPIServerIP(networkSpec.APIServerIP, fldPath.Child("apiServerIP"))...)
+	}
+	if networkSpec.APIServerDNS != nil {
+		allErrs = append(allErrs, validateAPIServerDNS(networkSpec.APIServerDNS, fldPath.Child("apiServerDNS"))...)
+	}
+	if networkSpec.APIServerLB != nil && networkSpec.APIServerLB.HealthProbe != nil {
+		allErrs = append(allErrs, validateHealthProbe(networkSpec.APIServerLB.HealthProbe, fldPath.Child("apiServerLB", "healthProbe"))...)
+	}
+	allErrs = append(allErrs, validateLoadBalancerSKU(networkSpec, fldPath)...)
+	allErrs = append(allErrs, validateOutboundType(networkSpec, fldPath)...)
 	if len(allErrs) == 0 {
 		return nil
 	}
 	return allErrs
 }
 
+// validateNodeOutboundLB validates a NodeOutboundLBSpec
+func validateNodeOutboundLB(lb *NodeOutboundLBSpec, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if lb.FrontendIPsCount != nil && *lb.FrontendIPsCount < 1 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("frontendIPsCount"), *lb.FrontendIPsCount,
+			"frontendIPsCount must be at least 1"))
+	}
+	if lb.PublicIPPrefixID != "" {
+		if lb.FrontendIPsCount != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("frontendIPsCount"), *lb.FrontendIPsCount,
+				"frontendIPsCount cannot be used together with publicIPPrefixID"))
+		}
+		if _, err := azure.ParseResourceID(lb.PublicIPPrefixID); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("publicIPPrefixID"), lb.PublicIPPrefixID,
+				"publicIPPrefixID must be a valid Azure resource ID"))
+		}
+	}
+	if lb.AllocatedOutboundPorts != nil {
+		if ports := *lb.AllocatedOutboundPorts; ports < 0 || ports > maxAllocatedOutboundPorts || ports%8 != 0 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("allocatedOutboundPorts"), ports,
+				fmt.Sprintf("allocatedOutboundPorts must be a multiple of 8 between 0 and %d", maxAllocatedOutboundPorts)))
+		}
+	}
+	if lb.IdleTimeoutInMinutes != nil {
+		if timeout := *lb.IdleTimeoutInMinutes; timeout < minOutboundIdleTimeout || timeout > maxOutboundIdleTimeout {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("idleTimeoutInMinutes"), timeout,
+				fmt.Sprintf("idleTimeoutInMinutes must be between %d and %d", minOutboundIdleTimeout, maxOutboundIdleTimeout)))
+		}
+	}
+	return allErrs
+}
+
+// validateAPIServerIP validates the PublicIPSpec of the API server
+func validateAPIServerIP(ip *PublicIPSpec, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if ip.ID != "" {
+		if ip.DNSLabel != "" {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("dnsLabel"), ip.DNSLabel,
+				"dnsLabel cannot be used together with id"))
+		}
+		if res, err := azure.ParseResourceID(ip.ID); err != nil || res.Provider != "Microsoft.Network" || res.ResourceType != "publicIPAddresses" {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("id"), ip.ID,
+				"id must be the resource ID of a public IP address"))
+		}
+	}
+	if ip.DNSLabel != "" {
+		if success, _ := regexp.MatchString(dnsLabelRegex, ip.DNSLabel); !success {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("dnsLabel"), ip.DNSLabel,
+				fmt.Sprintf("dnsLabel doesn't match regex %s", dnsLabelRegex)))
+		}
+	}
+	return allErrs
+}
+
+// validateAPIServerDNS validates the DNSRecordSpec of the API server
+func validateAPIServerDNS(record *DNSRecordSpec, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	zone, err := azure.ParseResourceID(record.ZoneID)
+	if err != nil || zone.Provider != "Microsoft.Network" || (!strings.EqualFold(zone.ResourceType, "dnszones") && !strings.EqualFold(zone.ResourceType, "privateDnsZones")) {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("zoneID"), record.ZoneID,
+			"zoneID must be the resource ID of a DNS zone or private DNS zone"))
+	} else if record.CreateVnetLink && !strings.EqualFold(zone.ResourceType, "privateDnsZones") {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("createVnetLink"), record.CreateVnetLink,
+			"createVnetLink can only be used with private DNS zones"))
+	}
+	if record.RecordName != "" {
+		if success, _ := regexp.MatchString(dnsRecordNameRegex, record.RecordName); !success {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("recordName"), record.RecordName,
+				fmt.Sprintf("recordName doesn't match regex %s", dnsRecordNameRegex)))
+		}
+	}
+	return allErrs
+}
+
+// validateHealthProbe validates the HealthProbeSpec of the API server load balancers
+func validateHealthProbe(probe *HealthProbeSpec, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if probe.Protocol == HealthProbeProtocolTCP && probe.RequestPath != "" {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("requestPath"), probe.RequestPath,
+			"requestPath can only be used with the Https protocol"))
+	}
+	if probe.IntervalInSeconds != nil && *probe.IntervalInSeconds < minHealthProbeIntervalInSeconds {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("intervalInSeconds"), *probe.IntervalInSeconds,
+			fmt.Sprintf("intervalInSeconds must be at least %d", minHealthProbeIntervalInSeconds)))
+	}
+	if probe.NumberOfProbes != nil && *probe.NumberOfProbes < 1 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("numberOfProbes"), *probe.NumberOfProbes,
+			"numberOfProbes must be at least 1"))
+	}
+	return allErrs
+}
+
+// validateLoadBalancerSKU validates that all the load balancers of the cluster use the same SKU
+// and that Basic load balancers don't use features of the Standard SKU.
+func validateLoadBalancerSKU(networkSpec NetworkSpec, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	apiServerLB, nodeOutboundLB := networkSpec.APIServerLB, networkSpec.NodeOutboundLB
+	if apiServerLB != nil && apiServerLB.SKU != "" && nodeOutboundLB != nil && nodeOutboundLB.SKU != "" && apiServerLB.SKU != nodeOutboundLB.SKU {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("nodeOutboundLB", "sku"), nodeOutboundLB.SKU,
+			fmt.Sprintf("nodeOutboundLB.sku must match apiServerLB.sku %s, machines cannot belong to load balancers of different SKUs", apiServerLB.SKU)))
+		return allErrs
+	}
+	if networkSpec.GetLoadBalancerSKU() != SKUBasic {
+		return allErrs
+	}
+
+	if apiServerLB != nil && apiServerLB.HealthProbe != nil && apiServerLB.HealthProbe.Protocol == HealthProbeProtocolHTTPS {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("apiServerLB", "healthProbe", "protocol"), apiServerLB.HealthProbe.Protocol,
+			"HTTPS health probes require the Standard SKU"))
+	}
+	if nodeOutboundLB != nil {
+		lbPath := fldPath.Child("nodeOutboundLB")
+		if nodeOutboundLB.FrontendIPsCount != nil && *nodeOutboundLB.FrontendIPsCount > 1 {
+			allErrs = append(allErrs, field.Invalid(lbPath.Child("frontendIPsCount"), *nodeOutboundLB.FrontendIPsCount,
+				"several outbound public IPs require the Standard SKU"))
+		}
+		if nodeOutboundLB.PublicIPPrefixID != "" {
+			allErrs = append(allErrs, field.Invalid(lbPath.Child("publicIPPrefixID"), nodeOutboundLB.PublicIPPrefixID,
+				"public IP prefixes require the Standard SKU"))
+		}
+		if nodeOutboundLB.AllocatedOutboundPorts != nil {
+			allErrs = append(allErrs, field.Invalid(lbPath.Child("allocatedOutboundPorts"), *nodeOutboundLB.AllocatedOutboundPorts,
+				"outbound rules require the Standard SKU"))
+		}
+		if nodeOutboundLB.IdleTimeoutInMinutes != nil {
+			allErrs = append(allErrs, field.Invalid(lbPath.Child("idleTimeoutInMinutes"), *nodeOutboundLB.IdleTimeoutInMinutes,
+				"outbound rules require the Standard SKU"))
+		}
+		if nodeOutboundLB.EnableTCPReset != nil {
+			allErrs = append(allErrs, field.Invalid(lbPath.Child("enableTCPReset"), *nodeOutboundLB.EnableTCPReset,
+				"outbound rules require the Standard SKU"))
+		}
+	}
+	return allErrs
+}
+
+// validateDDoSProtectionPlanID validates the resource ID of a DDoS protection plan
+func validateDDoSProtectionPlanID(id string, fldPath *field.Path) *field.Error {
+	if res, err := azure.ParseResourceID(id); err != nil || res.Provider != "Microsoft.Network" || !strings.EqualFold(res.ResourceType, "ddosProtectionPlans") {
+		return field.Invalid(fldPath, id, "ddosProtectionPlanID must be the resource ID of a DDoS protection plan")
+	}
+	return nil
+}
+
+// validateOutboundType validates the settings of the outbound type of the cluster
+func validateOutboundType(networkSpec NetworkSpec, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if networkSpec.GetOutboundType() != OutboundTypeUserDefinedRouting {
+		if networkSpec.DefaultRouteNextHopIP != "" {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("defaultRouteNextHopIP"), networkSpec.DefaultRouteNextHopIP,
+				"defaultRouteNextHopIP can only be used with the UserDefinedRouting outbound type"))
+		}
+		return allErrs
+	}
+
+	if networkSpec.DefaultRouteNextHopIP != "" && net.ParseIP(networkSpec.DefaultRouteNextHopIP) == nil {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("defaultRouteNextHopIP"), networkSpec.DefaultRouteNextHopIP,
+			"defaultRouteNextHopIP must be a valid IP address"))
+	}
+	if networkSpec.NodeOutboundLB != nil && !networkSpec.NodeOutboundLB.Disabled {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("nodeOutboundLB"),
+			"the node outbound load balancer is not created with the UserDefinedRouting outbound type"))
+	}
+	return allErrs
+}
+
+// validateManagedVnetDefaultRoute validates that the UserDefinedRouting outbound type has a next hop for the default
+// route when the vnet is created by the provider, i.e. when it has the default name, since its machines would otherwise
+// have no way to reach the internet. A vnet named by the user may already exist with its own route tables, so it is
+// only checked once reconciled.
+func validateManagedVnetDefaultRoute(networkSpec NetworkSpec, defaultVnetName string, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if networkSpec.GetOutboundType() == OutboundTypeUserDefinedRouting && networkSpec.Vnet.Name == defaultVnetName &&
+		networkSpec.DefaultRouteNextHopIP == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("defaultRouteNextHopIP"),
+			"defaultRouteNextHopIP is required with the UserDefinedRouting outbound type when the vnet is created by the provider"))
+	}
+	return allErrs
+}
+
+// validateManagedVnetSubscription validates that the vnet created by the provider, i.e. the vnet with the default
+// name, is not in a different subscription than the cluster, since its subnets are linked to the network security
+// groups and route tables of the cluster, and Azure cannot link them across subscriptions.
+func validateManagedVnetSubscription(networkSpec NetworkSpec, defaultVnetName, subscriptionID string, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	vnet := networkSpec.Vnet
+	if vnet.Name == defaultVnetName && vnet.SubscriptionID != "" && vnet.SubscriptionID != subscriptionID {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("vnet", "subscriptionID"),
+			"subscriptionID can only be set to another subscription than the cluster for an existing vnet"))
+	}
+	return allErrs
+}
+
+// validateLoadBalancerSKUUpdate validates that the SKU of the load balancers is not changed, since Azure
+// cannot change the SKU of existing load balancers and public IPs.
+func validateLoadBalancerSKUUpdate(oldNetworkSpec, networkSpec NetworkSpec, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if oldSKU, sku := oldNetworkSpec.GetLoadBalancerSKU(), networkSpec.GetLoadBalancerSKU(); sku != oldSKU {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("apiServerLB", "sku"), sku,
+			fmt.Sprintf("the SKU of the load balancers cannot be changed from %s", oldSKU)))
+	}
+	return allErrs
+}
+
+// validateAPIServerIPUpdate validates that the DNS label of the API server public IP isn't changed, since
+// the public IP is only created once and the API server endpoint of the cluster is derived from its FQDN.
+func validateAPIServerIPUpdate(oldIP, ip *PublicIPSpec, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	var oldDNSLabel, dnsLabel string
+	if oldIP != nil {
+		oldDNSLabel = oldIP.DNSLabel
+	}
+	if ip != nil {
+		dnsLabel = ip.DNSLabel
+	}
+	if dnsLabel != oldDNSLabel {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("dnsLabel"), dnsLabel,
+			fmt.Sprintf("the DNS label of the API server public IP cannot be changed from %q", oldDNSLabel)))
+	}
+	return allErrs
+}
+
 // validateResourceGroup validates a ResourceGroup
 func validateResourceGroup(resourceGroup string, fldPath *field.Path) *field.Error {
 	if success, _ := regexp.MatchString(resourceGroupRegex, resourceGroup); !success {
@@ -132,6 +459,138 @@ func validateSubnets(subnets Subnets, fldPath *field.Path) field.ErrorList {
 	return allErrs
 }
 
+// validateNetworkCIDRs validates that the CIDR blocks of the Vnet and Subnets are valid, that every
+// Subnet sits inside the Vnet address space and that Subnets don't overlap each other
+func validateNetworkCIDRs(networkSpec NetworkSpec, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	var vnetBlock *net.IPNet
+	if networkSpec.Vnet.CidrBlock != "" {
+		var err *field.Error
+		if vnetBlock, err = parseCIDRBlock(networkSpec.Vnet.CidrBlock, fldPath.Child("vnet").Child("cidrBlock")); err != nil {
+			allErrs = append(allErrs, err)
+		}
+	}
+
+	var blocks []*net.IPNet
+	for i, subnet := range networkSpec.Subnets {
+		if subnet == nil || subnet.CidrBlock == "" {
+			continue
+		}
+		cidrFldPath := fldPath.Child("subnets").Index(i).Child("cidrBlock")
+		block, err := parseCIDRBlock(subnet.CidrBlock, cidrFldPath)
+		if err != nil {
+			allErrs = append(allErrs, err)
+			continue
+		}
+		if vnetBlock != nil && !ipam.Contains(vnetBlock, block) {
+			allErrs = append(allErrs, field.Invalid(cidrFldPath, subnet.CidrBlock,
+				fmt.Sprintf("cidrBlock must be inside the vnet CIDR block %s", vnetBlock.String())))
+		}
+		for _, other := range blocks {
+			if ipam.Overlaps(block, other) {
+				allErrs = append(allErrs, field.Invalid(cidrFldPath, subnet.CidrBlock,
+					fmt.Sprintf("cidrBlock overlaps the CIDR block %s of another subnet", other.String())))
+				break
+			}
+		}
+		blocks = append(blocks, block)
+	}
+	return allErrs
+}
+
+// validateClusterNetworkCIDRs validates that the pod and service CIDR blocks of a ClusterNetwork
+// don't collide with the Vnet address space
+func validateClusterNetworkCIDRs(networkSpec NetworkSpec, clusterNetwork *clusterv1.ClusterNetwork, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if clusterNetwork == nil || networkSpec.Vnet.CidrBlock == "" {
+		return nil
+	}
+	_, vnetBlock, err := net.ParseCIDR(networkSpec.Vnet.CidrBlock)
+	if err != nil {
+		// reported by validateNetworkCIDRs
+		return nil
+	}
+	for _, ranges := range []struct {
+		kind   string
+		ranges *clusterv1.NetworkRanges
+	}{
+		{kind: "pod", ranges: clusterNetwork.Pods},
+		{kind: "service", ranges: clusterNetwork.Services},
+	} {
+		if ranges.ranges == nil {
+			continue
+		}
+		for _, cidr := range ranges.ranges.CIDRBlocks {
+			_, block, err := net.ParseCIDR(cidr)
+			if err != nil {
+				continue
+			}
+			if ipam.Overlaps(vnetBlock, block) {
+				allErrs = append(allErrs, field.Invalid(fldPath.Child("vnet").Child("cidrBlock"), networkSpec.Vnet.CidrBlock,
+					fmt.Sprintf("vnet CIDR block overlaps the %s CIDR block %s of the cluster", ranges.kind, cidr)))
+			}
+		}
+	}
+	return allErrs
+}
+
+// parseCIDRBlock parses an IPv4 CIDR block
+func parseCIDRBlock(cidr string, fldPath *field.Path) (*net.IPNet, *field.Error) {
+	_, block, err := net.ParseCIDR(cidr)
+	if err != nil || block.IP.To4() == nil {
+		return nil, field.Invalid(fldPath, cidr, "cidrBlock must be a valid IPv4 CIDR block")
+	}
+	return block, nil
+}
+
+// validateEndpoints validates the service endpoints and private endpoints of a list of Subnets
+func validateEndpoints(subnets Subnets, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	privateEndpointNames := make(map[string]bool)
+
+	for i, subnet := range subnets {
+		if subnet == nil {
+			continue
+		}
+		for j, serviceEndpoint := range subnet.ServiceEndpoints {
+			if serviceEndpoint.Service == "" {
+				allErrs = append(allErrs, field.Required(fldPath.Index(i).Child("serviceEndpoints").Index(j).Child("service"),
+					"service endpoint service cannot be empty"))
+			}
+		}
+		for j, privateEndpoint := range subnet.PrivateEndpoints {
+			peFldPath := fldPath.Index(i).Child("privateEndpoints").Index(j)
+			if err := validatePrivateEndpointName(privateEndpoint.Name, peFldPath.Child("name")); err != nil {
+				allErrs = append(allErrs, err)
+			}
+			if privateEndpointNames[privateEndpoint.Name] {
+				allErrs = append(allErrs, field.Duplicate(peFldPath.Child("name"), privateEndpoint.Name))
+			}
+			privateEndpointNames[privateEndpoint.Name] = true
+			if _, err := azure.ParseResourceID(privateEndpoint.PrivateLinkResourceID); err != nil {
+				allErrs = append(allErrs, field.Invalid(peFldPath.Child("privateLinkResourceID"), privateEndpoint.PrivateLinkResourceID,
+					"privateLinkResourceID is not a valid Azure resource ID"))
+			}
+			if privateEndpoint.PrivateDNSZoneID != "" {
+				if _, err := azure.ParseResourceID(privateEndpoint.PrivateDNSZoneID); err != nil {
+					allErrs = append(allErrs, field.Invalid(peFldPath.Child("privateDNSZoneID"), privateEndpoint.PrivateDNSZoneID,
+						"privateDNSZoneID is not a valid Azure resource ID"))
+				}
+			}
+		}
+	}
+	return allErrs
+}
+
+// validatePrivateEndpointName validates the Name of a PrivateEndpoint
+func validatePrivateEndpointName(name string, fldPath *field.Path) *field.Error {
+	if success, _ := regexp.Match(privateEndpointRegex, []byte(name)); !success {
+		return field.Invalid(fldPath, name,
+			fmt.Sprintf("name of private endpoint doesn't match regex %s", privateEndpointRegex))
+	}
+	return nil
+}
+
 // validateSubnetName validates the Name of a Subnet
 func validateSubnetName(name string, fldPath *field.Path) *field.Error {
 	if success, _ := regexp.Match(subnetRegex, []byte(name)); !success {
@@ -156,6 +615,14 @@ func validateIngressRule(ingressRule *IngressRule, fldPath *field.Path) *field.E
 		return field.Invalid(fldPath, ingressRule.Priority,
 			fmt.Sprintf("ingress priorities should be between 100 and 4096"))
 	}
+	if ingressRule.Source != nil && ingressRule.SourceApplicationSecurityGroup != "" {
+		return field.Invalid(fldPath.Child("sourceApplicationSecurityGroup"), ingressRule.SourceApplicationSecurityGroup,
+			"sourceApplicationSecurityGroup cannot be used together with source")
+	}
+	if ingressRule.Destination != nil && ingressRule.DestinationApplicationSecurityGroup != "" {
+		return field.Invalid(fldPath.Child("destinationApplicationSecurityGroup"), ingressRule.DestinationApplicationSecurityGroup,
+			"destinationApplicationSecurityGroup cannot be used together with destination")
+	}
 
 	return nil
 }
@@ -19,8 +19,10 @@ package v1alpha3
 import (
 	"testing"
 
+	"github.com/Azure/go-autorest/autorest/to"
 	. "github.com/onsi/gomega"
 	"k8s.io/apimachinery/pkg/util/validation/field"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha3"
 )
 
 func TestClusterWithPreexistingVnetValid(t *testing.T) {
@@ -520,6 +522,38 @@ func TestIngressRules(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "ingressRule - valid application security group source",
+			validRule: &IngressRule{
+				Name:                           "allow_ssh_from_control_plane",
+				Description:                    "Allow SSH from control plane machines",
+				Priority:                       102,
+				SourceApplicationSecurityGroup: SecurityGroupControlPlane,
+			},
+			wantErr: false,
+		},
+		{
+			name: "ingressRule - invalid source and application security group source",
+			validRule: &IngressRule{
+				Name:                           "allow_ssh_from_control_plane",
+				Description:                    "Allow SSH from control plane machines",
+				Priority:                       102,
+				Source:                         to.StringPtr("*"),
+				SourceApplicationSecurityGroup: SecurityGroupControlPlane,
+			},
+			wantErr: true,
+		},
+		{
+			name: "ingressRule - invalid destination and application security group destination",
+			validRule: &IngressRule{
+				Name:                                "allow_ssh_to_nodes",
+				Description:                         "Allow SSH to node machines",
+				Priority:                            102,
+				Destination:                         to.StringPtr("*"),
+				DestinationApplicationSecurityGroup: SecurityGroupNode,
+			},
+			wantErr: true,
+		},
 	}
 	for _, testCase := range tests {
 
@@ -537,6 +571,224 @@ func TestIngressRules(t *testing.T) {
 	}
 }
 
+func TestNetworkCIDRs(t *testing.T) {
+	g := NewWithT(t)
+
+	tests := []struct {
+		name     string
+		vnetCIDR string
+		subnets  Subnets
+		wantErrs int
+	}{
+		{
+			name:     "network CIDRs - valid",
+			vnetCIDR: "10.0.0.0/8",
+			subnets: Subnets{
+				{Name: "control-plane-subnet", CidrBlock: "10.0.0.0/16"},
+				{Name: "node-subnet", CidrBlock: "10.1.0.0/16"},
+				{Name: "other-subnet"},
+			},
+			wantErrs: 0,
+		},
+		{
+			name:     "network CIDRs - overlapping subnets",
+			vnetCIDR: "10.0.0.0/8",
+			subnets: Subnets{
+				{Name: "control-plane-subnet", CidrBlock: "10.0.0.0/16"},
+				{Name: "node-subnet", CidrBlock: "10.0.128.0/24"},
+			},
+			wantErrs: 1,
+		},
+		{
+			name:     "network CIDRs - subnet outside of vnet",
+			vnetCIDR: "10.0.0.0/16",
+			subnets: Subnets{
+				{Name: "control-plane-subnet", CidrBlock: "10.0.0.0/24"},
+				{Name: "node-subnet", CidrBlock: "10.1.0.0/16"},
+			},
+			wantErrs: 1,
+		},
+		{
+			name: "network CIDRs - vnet CIDR not set",
+			subnets: Subnets{
+				{Name: "control-plane-subnet", CidrBlock: "192.168.0.0/24"},
+				{Name: "node-subnet", CidrBlock: "192.168.1.0/24"},
+			},
+			wantErrs: 0,
+		},
+		{
+			name:     "network CIDRs - subnet larger than vnet",
+			vnetCIDR: "10.0.0.0/16",
+			subnets: Subnets{
+				{Name: "control-plane-subnet", CidrBlock: "10.0.0.0/8"},
+			},
+			wantErrs: 1,
+		},
+		{
+			name:     "network CIDRs - invalid",
+			vnetCIDR: "10.0.0.0",
+			subnets: Subnets{
+				{Name: "control-plane-subnet", CidrBlock: "10.0.0.0"},
+				{Name: "node-subnet", CidrBlock: "fd00::/64"},
+			},
+			wantErrs: 3,
+		},
+	}
+	for _, testCase := range tests {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			networkSpec := NetworkSpec{
+				Vnet:    VnetSpec{CidrBlock: testCase.vnetCIDR},
+				Subnets: testCase.subnets,
+			}
+			errs := validateNetworkCIDRs(networkSpec, field.NewPath("spec").Child("networkSpec"))
+			g.Expect(errs).To(HaveLen(testCase.wantErrs))
+			for _, err := range errs {
+				g.Expect(err.Type).To(Equal(field.ErrorTypeInvalid))
+			}
+		})
+	}
+}
+
+func TestClusterNetworkCIDRs(t *testing.T) {
+	g := NewWithT(t)
+
+	tests := []struct {
+		name           string
+		clusterNetwork *clusterv1.ClusterNetwork
+		wantErrs       int
+	}{
+		{
+			name:           "cluster network CIDRs - no cluster network",
+			clusterNetwork: nil,
+			wantErrs:       0,
+		},
+		{
+			name: "cluster network CIDRs - valid",
+			clusterNetwork: &clusterv1.ClusterNetwork{
+				Pods:     &clusterv1.NetworkRanges{CIDRBlocks: []string{"192.168.0.0/16"}},
+				Services: &clusterv1.NetworkRanges{CIDRBlocks: []string{"172.16.0.0/16"}},
+			},
+			wantErrs: 0,
+		},
+		{
+			name: "cluster network CIDRs - pods and services overlap vnet",
+			clusterNetwork: &clusterv1.ClusterNetwork{
+				Pods:     &clusterv1.NetworkRanges{CIDRBlocks: []string{"192.168.0.0/16", "10.244.0.0/16"}},
+				Services: &clusterv1.NetworkRanges{CIDRBlocks: []string{"10.96.0.0/12"}},
+			},
+			wantErrs: 2,
+		},
+	}
+	for _, testCase := range tests {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			networkSpec := NetworkSpec{
+				Vnet: VnetSpec{CidrBlock: "10.0.0.0/8"},
+			}
+			errs := validateClusterNetworkCIDRs(networkSpec, testCase.clusterNetwork, field.NewPath("spec").Child("networkSpec"))
+			g.Expect(errs).To(HaveLen(testCase.wantErrs))
+			for _, err := range errs {
+				g.Expect(err.Type).To(Equal(field.ErrorTypeInvalid))
+				g.Expect(err.Field).To(Equal("spec.networkSpec.vnet.cidrBlock"))
+			}
+		})
+	}
+}
+
+func TestValidateClusterNetwork(t *testing.T) {
+	g := NewWithT(t)
+
+	cluster := createValidCluster()
+	cluster.Spec.NetworkSpec.Vnet.CidrBlock = "10.0.0.0/16"
+	g.Expect(cluster.ValidateClusterNetwork(&clusterv1.ClusterNetwork{
+		Pods: &clusterv1.NetworkRanges{CIDRBlocks: []string{"192.168.0.0/16"}},
+	})).To(Succeed())
+
+	cluster.Spec.NetworkSpec.Subnets[0].CidrBlock = "10.1.0.0/24"
+	err := cluster.ValidateClusterNetwork(&clusterv1.ClusterNetwork{
+		Pods: &clusterv1.NetworkRanges{CIDRBlocks: []string{"10.0.0.0/12"}},
+	})
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("spec.networkSpec.subnets[0].cidrBlock"))
+	g.Expect(err.Error()).To(ContainSubstring("vnet CIDR block overlaps the pod CIDR block 10.0.0.0/12 of the cluster"))
+}
+
+func TestEndpoints(t *testing.T) {
+	g := NewWithT(t)
+
+	storageID := "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Storage/storageAccounts/mystorage"
+	zoneID := "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/privateDnsZones/privatelink.blob.core.windows.net"
+
+	tests := []struct {
+		name      string
+		subnet    *SubnetSpec
+		wantErrs  int
+		errorType field.ErrorType
+	}{
+		{
+			name: "endpoints - valid",
+			subnet: &SubnetSpec{
+				Name:             "node",
+				ServiceEndpoints: []ServiceEndpointSpec{{Service: "Microsoft.Storage"}},
+				PrivateEndpoints: []PrivateEndpointSpec{
+					{Name: "my-pe", PrivateLinkResourceID: storageID, GroupIDs: []string{"blob"}, PrivateDNSZoneID: zoneID},
+				},
+			},
+			wantErrs: 0,
+		},
+		{
+			name: "endpoints - empty service endpoint",
+			subnet: &SubnetSpec{
+				Name:             "node",
+				ServiceEndpoints: []ServiceEndpointSpec{{}},
+			},
+			wantErrs:  1,
+			errorType: field.ErrorTypeRequired,
+		},
+		{
+			name: "endpoints - invalid private link resource ID",
+			subnet: &SubnetSpec{
+				Name:             "node",
+				PrivateEndpoints: []PrivateEndpointSpec{{Name: "my-pe", PrivateLinkResourceID: "mystorage"}},
+			},
+			wantErrs:  1,
+			errorType: field.ErrorTypeInvalid,
+		},
+		{
+			name: "endpoints - invalid private DNS zone ID",
+			subnet: &SubnetSpec{
+				Name:             "node",
+				PrivateEndpoints: []PrivateEndpointSpec{{Name: "my-pe", PrivateLinkResourceID: storageID, PrivateDNSZoneID: "zone"}},
+			},
+			wantErrs:  1,
+			errorType: field.ErrorTypeInvalid,
+		},
+		{
+			name: "endpoints - duplicate private endpoint names",
+			subnet: &SubnetSpec{
+				Name: "node",
+				PrivateEndpoints: []PrivateEndpointSpec{
+					{Name: "my-pe", PrivateLinkResourceID: storageID},
+					{Name: "my-pe", PrivateLinkResourceID: storageID},
+				},
+			},
+			wantErrs:  1,
+			errorType: field.ErrorTypeDuplicate,
+		},
+	}
+	for _, testCase := range tests {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			errs := validateEndpoints(Subnets{testCase.subnet}, field.NewPath("spec").Child("networkSpec").Child("subnets"))
+			g.Expect(errs).To(HaveLen(testCase.wantErrs))
+			if testCase.wantErrs > 0 {
+				g.Expect(errs[0].Type).To(Equal(testCase.errorType))
+			}
+		})
+	}
+}
+
 func createValidCluster() *AzureCluster {
 	return &AzureCluster{
 		Spec: AzureClusterSpec{
@@ -545,6 +797,492 @@ func createValidCluster() *AzureCluster {
 	}
 }
 
+func TestNodeOutboundLB(t *testing.T) {
+	g := NewWithT(t)
+
+	prefixID := "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/publicIPPrefixes/my-prefix"
+
+	tests := []struct {
+		name     string
+		lb       *NodeOutboundLBSpec
+		wantErrs int
+	}{
+		{
+			name: "nodeOutboundLB - valid frontend IPs",
+			lb: &NodeOutboundLBSpec{
+				FrontendIPsCount:       to.Int32Ptr(3),
+				AllocatedOutboundPorts: to.Int32Ptr(1024),
+				IdleTimeoutInMinutes:   to.Int32Ptr(30),
+				EnableTCPReset:         to.BoolPtr(true),
+			},
+			wantErrs: 0,
+		},
+		{
+			name:     "nodeOutboundLB - valid public IP prefix",
+			lb:       &NodeOutboundLBSpec{PublicIPPrefixID: prefixID},
+			wantErrs: 0,
+		},
+		{
+			name:     "nodeOutboundLB - frontend IPs and public IP prefix",
+			lb:       &NodeOutboundLBSpec{FrontendIPsCount: to.Int32Ptr(2), PublicIPPrefixID: prefixID},
+			wantErrs: 1,
+		},
+		{
+			name:     "nodeOutboundLB - invalid public IP prefix ID",
+			lb:       &NodeOutboundLBSpec{PublicIPPrefixID: "my-prefix"},
+			wantErrs: 1,
+		},
+		{
+			name:     "nodeOutboundLB - allocated ports not a multiple of 8",
+			lb:       &NodeOutboundLBSpec{AllocatedOutboundPorts: to.Int32Ptr(1004)},
+			wantErrs: 1,
+		},
+		{
+			name:     "nodeOutboundLB - idle timeout too low",
+			lb:       &NodeOutboundLBSpec{IdleTimeoutInMinutes: to.Int32Ptr(2)},
+			wantErrs: 1,
+		},
+	}
+	for _, testCase := range tests {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			errs := validateNodeOutboundLB(testCase.lb, field.NewPath("spec").Child("networkSpec").Child("nodeOutboundLB"))
+			g.Expect(errs).To(HaveLen(testCase.wantErrs))
+			if testCase.wantErrs > 0 {
+				g.Expect(errs[0].Type).To(Equal(field.ErrorTypeInvalid))
+			}
+		})
+	}
+}
+
+func TestAPIServerIP(t *testing.T) {
+	g := NewWithT(t)
+
+	ipID := "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/publicIPAddresses/my-ip"
+
+	tests := []struct {
+		name     string
+		ip       *PublicIPSpec
+		wantErrs int
+	}{
+		{
+			name:     "apiServerIP - valid ID",
+			ip:       &PublicIPSpec{ID: ipID},
+			wantErrs: 0,
+		},
+		{
+			name:     "apiServerIP - valid DNS label",
+			ip:       &PublicIPSpec{DNSLabel: "my-cluster-api"},
+			wantErrs: 0,
+		},
+		{
+			name:     "apiServerIP - ID of another resource type",
+			ip:       &PublicIPSpec{ID: "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/publicIPPrefixes/my-prefix"},
+			wantErrs: 1,
+		},
+		{
+			name:     "apiServerIP - invalid DNS label",
+			ip:       &PublicIPSpec{DNSLabel: "My_Cluster"},
+			wantErrs: 1,
+		},
+		{
+			name:     "apiServerIP - ID and DNS label",
+			ip:       &PublicIPSpec{ID: ipID, DNSLabel: "my-cluster-api"},
+			wantErrs: 1,
+		},
+	}
+	for _, testCase := range tests {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			errs := validateAPIServerIP(testCase.ip, field.NewPath("spec").Child("networkSpec").Child("apiServerIP"))
+			g.Expect(errs).To(HaveLen(testCase.wantErrs))
+			if testCase.wantErrs > 0 {
+				g.Expect(errs[0].Type).To(Equal(field.ErrorTypeInvalid))
+			}
+		})
+	}
+}
+
+func TestAPIServerDNS(t *testing.T) {
+	g := NewWithT(t)
+
+	zoneID := "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/dnszones/example.com"
+	privateZoneID := "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/privateDnsZones/example.internal"
+
+	tests := []struct {
+		name     string
+		record   *DNSRecordSpec
+		wantErrs int
+	}{
+		{
+			name:     "apiServerDNS - valid public zone",
+			record:   &DNSRecordSpec{ZoneID: zoneID, RecordName: "api.my-cluster"},
+			wantErrs: 0,
+		},
+		{
+			name:     "apiServerDNS - valid private zone with vnet link",
+			record:   &DNSRecordSpec{ZoneID: privateZoneID, RecordName: "api", CreateVnetLink: true},
+			wantErrs: 0,
+		},
+		{
+			name:     "apiServerDNS - invalid zone ID",
+			record:   &DNSRecordSpec{ZoneID: "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/publicIPAddresses/my-ip"},
+			wantErrs: 1,
+		},
+		{
+			name:     "apiServerDNS - vnet link for public zone",
+			record:   &DNSRecordSpec{ZoneID: zoneID, CreateVnetLink: true},
+			wantErrs: 1,
+		},
+		{
+			name:     "apiServerDNS - invalid record name",
+			record:   &DNSRecordSpec{ZoneID: zoneID, RecordName: "api..my-cluster"},
+			wantErrs: 1,
+		},
+	}
+	for _, testCase := range tests {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			errs := validateAPIServerDNS(testCase.record, field.NewPath("spec").Child("networkSpec").Child("apiServerDNS"))
+			g.Expect(errs).To(HaveLen(testCase.wantErrs))
+			if testCase.wantErrs > 0 {
+				g.Expect(errs[0].Type).To(Equal(field.ErrorTypeInvalid))
+			}
+		})
+	}
+}
+
+func TestHealthProbe(t *testing.T) {
+	g := NewWithT(t)
+
+	tests := []struct {
+		name     string
+		probe    *HealthProbeSpec
+		wantErrs int
+	}{
+		{
+			name:     "healthProbe - valid HTTPS probe",
+			probe:    &HealthProbeSpec{Protocol: HealthProbeProtocolHTTPS, RequestPath: "/readyz", IntervalInSeconds: to.Int32Ptr(5), NumberOfProbes: to.Int32Ptr(2)},
+			wantErrs: 0,
+		},
+		{
+			name:     "healthProbe - valid TCP probe",
+			probe:    &HealthProbeSpec{Protocol: HealthProbeProtocolTCP},
+			wantErrs: 0,
+		},
+		{
+			name:     "healthProbe - request path with TCP probe",
+			probe:    &HealthProbeSpec{Protocol: HealthProbeProtocolTCP, RequestPath: "/healthz"},
+			wantErrs: 1,
+		},
+		{
+			name:     "healthProbe - interval too short",
+			probe:    &HealthProbeSpec{Protocol: HealthProbeProtocolHTTPS, IntervalInSeconds: to.Int32Ptr(4)},
+			wantErrs: 1,
+		},
+		{
+			name:     "healthProbe - no probes",
+			probe:    &HealthProbeSpec{Protocol: HealthProbeProtocolHTTPS, NumberOfProbes: to.Int32Ptr(0)},
+			wantErrs: 1,
+		},
+	}
+	for _, testCase := range tests {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			errs := validateHealthProbe(testCase.probe, field.NewPath("spec").Child("networkSpec").Child("apiServerLB").Child("healthProbe"))
+			g.Expect(errs).To(HaveLen(testCase.wantErrs))
+			if testCase.wantErrs > 0 {
+				g.Expect(errs[0].Type).To(Equal(field.ErrorTypeInvalid))
+			}
+		})
+	}
+}
+
+func TestLoadBalancerSKU(t *testing.T) {
+	g := NewWithT(t)
+
+	tests := []struct {
+		name        string
+		networkSpec NetworkSpec
+		wantErrs    int
+	}{
+		{
+			name:        "loadBalancerSKU - default SKU",
+			networkSpec: NetworkSpec{},
+			wantErrs:    0,
+		},
+		{
+			name: "loadBalancerSKU - matching Basic SKUs",
+			networkSpec: NetworkSpec{
+				APIServerLB:    &APIServerLBSpec{SKU: SKUBasic},
+				NodeOutboundLB: &NodeOutboundLBSpec{SKU: SKUBasic},
+			},
+			wantErrs: 0,
+		},
+		{
+			name: "loadBalancerSKU - mixed SKUs",
+			networkSpec: NetworkSpec{
+				APIServerLB:    &APIServerLBSpec{SKU: SKUBasic},
+				NodeOutboundLB: &NodeOutboundLBSpec{SKU: SKUStandard},
+			},
+			wantErrs: 1,
+		},
+		{
+			name: "loadBalancerSKU - HTTPS probe with Basic SKU",
+			networkSpec: NetworkSpec{
+				APIServerLB: &APIServerLBSpec{
+					SKU:         SKUBasic,
+					HealthProbe: &HealthProbeSpec{Protocol: HealthProbeProtocolHTTPS},
+				},
+			},
+			wantErrs: 1,
+		},
+		{
+			name: "loadBalancerSKU - outbound rule settings with Basic SKU",
+			networkSpec: NetworkSpec{
+				NodeOutboundLB: &NodeOutboundLBSpec{
+					SKU:                    SKUBasic,
+					FrontendIPsCount:       to.Int32Ptr(2),
+					AllocatedOutboundPorts: to.Int32Ptr(1024),
+					IdleTimeoutInMinutes:   to.Int32Ptr(30),
+					EnableTCPReset:         to.BoolPtr(true),
+				},
+			},
+			wantErrs: 4,
+		},
+		{
+			name: "loadBalancerSKU - outbound rule settings with Standard SKU",
+			networkSpec: NetworkSpec{
+				NodeOutboundLB: &NodeOutboundLBSpec{
+					FrontendIPsCount:       to.Int32Ptr(2),
+					AllocatedOutboundPorts: to.Int32Ptr(1024),
+				},
+			},
+			wantErrs: 0,
+		},
+	}
+	for _, testCase := range tests {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			errs := validateLoadBalancerSKU(testCase.networkSpec, field.NewPath("spec").Child("networkSpec"))
+			g.Expect(errs).To(HaveLen(testCase.wantErrs))
+			if testCase.wantErrs > 0 {
+				g.Expect(errs[0].Type).To(Equal(field.ErrorTypeInvalid))
+			}
+		})
+	}
+}
+
+func TestDDoSProtectionPlanID(t *testing.T) {
+	g := NewWithT(t)
+
+	tests := []struct {
+		name    string
+		id      string
+		wantErr bool
+	}{
+		{
+			name:    "ddosProtectionPlanID - valid",
+			id:      "/subscriptions/123/resourceGroups/hub-rg/providers/Microsoft.Network/ddosProtectionPlans/my-plan",
+			wantErr: false,
+		},
+		{
+			name:    "ddosProtectionPlanID - not a DDoS protection plan",
+			id:      "/subscriptions/123/resourceGroups/hub-rg/providers/Microsoft.Network/virtualNetworks/my-vnet",
+			wantErr: true,
+		},
+		{
+			name:    "ddosProtectionPlanID - invalid resource ID",
+			id:      "my-plan",
+			wantErr: true,
+		},
+	}
+	for _, testCase := range tests {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			err := validateDDoSProtectionPlanID(testCase.id, field.NewPath("spec").Child("networkSpec").Child("vnet").Child("ddosProtectionPlanID"))
+			if testCase.wantErr {
+				g.Expect(err).NotTo(BeNil())
+				g.Expect(err.Type).To(Equal(field.ErrorTypeInvalid))
+			} else {
+				g.Expect(err).To(BeNil())
+			}
+		})
+	}
+}
+
+func TestOutboundType(t *testing.T) {
+	g := NewWithT(t)
+
+	tests := []struct {
+		name        string
+		networkSpec NetworkSpec
+		wantErrs    []field.ErrorType
+	}{
+		{
+			name:        "outboundType - default",
+			networkSpec: NetworkSpec{},
+		},
+		{
+			name: "outboundType - user defined routing with next hop",
+			networkSpec: NetworkSpec{
+				OutboundType:          OutboundTypeUserDefinedRouting,
+				DefaultRouteNextHopIP: "10.100.0.4",
+				NodeOutboundLB:        &NodeOutboundLBSpec{Disabled: true},
+			},
+		},
+		{
+			name: "outboundType - next hop with load balancer outbound type",
+			networkSpec: NetworkSpec{
+				DefaultRouteNextHopIP: "10.100.0.4",
+			},
+			wantErrs: []field.ErrorType{field.ErrorTypeInvalid},
+		},
+		{
+			name: "outboundType - invalid next hop",
+			networkSpec: NetworkSpec{
+				OutboundType:          OutboundTypeUserDefinedRouting,
+				DefaultRouteNextHopIP: "10.100.0",
+			},
+			wantErrs: []field.ErrorType{field.ErrorTypeInvalid},
+		},
+		{
+			name: "outboundType - node outbound load balancer with user defined routing",
+			networkSpec: NetworkSpec{
+				OutboundType:   OutboundTypeUserDefinedRouting,
+				NodeOutboundLB: &NodeOutboundLBSpec{FrontendIPsCount: to.Int32Ptr(2)},
+			},
+			wantErrs: []field.ErrorType{field.ErrorTypeForbidden},
+		},
+	}
+	for _, testCase := range tests {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			errs := validateOutboundType(testCase.networkSpec, field.NewPath("spec").Child("networkSpec"))
+			g.Expect(errs).To(HaveLen(len(testCase.wantErrs)))
+			for i, errType := range testCase.wantErrs {
+				g.Expect(errs[i].Type).To(Equal(errType))
+			}
+		})
+	}
+}
+
+func TestManagedVnetDefaultRoute(t *testing.T) {
+	g := NewWithT(t)
+
+	tests := []struct {
+		name        string
+		networkSpec NetworkSpec
+		wantErrs    []field.ErrorType
+	}{
+		{
+			name: "managed vnet - load balancer outbound type",
+			networkSpec: NetworkSpec{
+				Vnet: VnetSpec{Name: "my-cluster-vnet"},
+			},
+		},
+		{
+			name: "managed vnet - user defined routing with next hop",
+			networkSpec: NetworkSpec{
+				Vnet:                  VnetSpec{Name: "my-cluster-vnet"},
+				OutboundType:          OutboundTypeUserDefinedRouting,
+				DefaultRouteNextHopIP: "10.100.0.4",
+			},
+		},
+		{
+			name: "managed vnet - user defined routing without next hop",
+			networkSpec: NetworkSpec{
+				Vnet:         VnetSpec{Name: "my-cluster-vnet"},
+				OutboundType: OutboundTypeUserDefinedRouting,
+			},
+			wantErrs: []field.ErrorType{field.ErrorTypeRequired},
+		},
+		{
+			name: "vnet named by the user - user defined routing without next hop",
+			networkSpec: NetworkSpec{
+				Vnet:         VnetSpec{Name: "my-vnet"},
+				OutboundType: OutboundTypeUserDefinedRouting,
+			},
+		},
+	}
+	for _, testCase := range tests {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			errs := validateManagedVnetDefaultRoute(testCase.networkSpec, generateVnetName("my-cluster"), field.NewPath("spec").Child("networkSpec"))
+			g.Expect(errs).To(HaveLen(len(testCase.wantErrs)))
+			for i, errType := range testCase.wantErrs {
+				g.Expect(errs[i].Type).To(Equal(errType))
+			}
+		})
+	}
+}
+
+func TestManagedVnetSubscription(t *testing.T) {
+	g := NewWithT(t)
+
+	tests := []struct {
+		name        string
+		networkSpec NetworkSpec
+		wantErrs    []field.ErrorType
+	}{
+		{
+			name: "managed vnet - cluster subscription",
+			networkSpec: NetworkSpec{
+				Vnet: VnetSpec{Name: "my-cluster-vnet"},
+			},
+		},
+		{
+			name: "managed vnet - same subscription as the cluster",
+			networkSpec: NetworkSpec{
+				Vnet: VnetSpec{Name: "my-cluster-vnet", SubscriptionID: "123"},
+			},
+		},
+		{
+			name: "managed vnet - another subscription",
+			networkSpec: NetworkSpec{
+				Vnet: VnetSpec{Name: "my-cluster-vnet", SubscriptionID: "456"},
+			},
+			wantErrs: []field.ErrorType{field.ErrorTypeForbidden},
+		},
+		{
+			name: "vnet named by the user - another subscription",
+			networkSpec: NetworkSpec{
+				Vnet: VnetSpec{Name: "my-vnet", SubscriptionID: "456"},
+			},
+		},
+	}
+	for _, testCase := range tests {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			errs := validateManagedVnetSubscription(testCase.networkSpec, generateVnetName("my-cluster"), "123", field.NewPath("spec").Child("networkSpec"))
+			g.Expect(errs).To(HaveLen(len(testCase.wantErrs)))
+			for i, errType := range testCase.wantErrs {
+				g.Expect(errs[i].Type).To(Equal(errType))
+			}
+		})
+	}
+}
+
+func TestSubnetCIDRAllocation(t *testing.T) {
+	g := NewWithT(t)
+
+	networkSpec := NetworkSpec{
+		Vnet: VnetSpec{CidrBlock: "10.0.0.0/24"},
+		Subnets: Subnets{
+			{Role: SubnetControlPlane, Name: "controlplane-subnet", PrefixLength: 26},
+			{Role: SubnetNode, Name: "node-subnet"},
+		},
+	}
+	g.Expect(validateSubnetCIDRAllocation(networkSpec, field.NewPath("spec").Child("networkSpec"))).To(BeEmpty())
+	g.Expect(networkSpec.Subnets[0].CidrBlock).To(BeEmpty())
+
+	networkSpec.Subnets[0].PrefixLength = 24
+	errs := validateSubnetCIDRAllocation(networkSpec, field.NewPath("spec").Child("networkSpec"))
+	g.Expect(errs).To(HaveLen(1))
+	g.Expect(errs[0].Type).To(Equal(field.ErrorTypeInvalid))
+	g.Expect(errs[0].Field).To(Equal("spec.networkSpec.vnet.cidrBlock"))
+}
+
 func createValidNetworkSpec() NetworkSpec {
 	return NetworkSpec{
 		Vnet: VnetSpec{
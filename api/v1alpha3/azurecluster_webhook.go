@@ -42,7 +42,10 @@ var _ webhook.Defaulter = &AzureCluster{}
 func (c *AzureCluster) Default() {
 	clusterlog.Info("default", "name", c.Name)
 
-	c.setDefaults()
+	if err := c.setDefaults(); err != nil {
+		// the validating webhook rejects the cluster with the same error
+		clusterlog.Error(err, "failed to set defaults", "name", c.Name)
+	}
 }
 
 // ValidateCreate implements webhook.Validator so a webhook will be registered for the type
@@ -56,7 +59,11 @@ func (c *AzureCluster) ValidateCreate() error {
 func (c *AzureCluster) ValidateUpdate(old runtime.Object) error {
 	clusterlog.Info("validate update", "name", c.Name)
 
-	return c.validateCluster()
+	oldCluster, ok := old.(*AzureCluster)
+	if !ok {
+		return c.validateCluster()
+	}
+	return c.validateClusterUpdate(oldCluster)
 }
 
 // ValidateDelete implements webhook.Validator so a webhook will be registered for the type
@@ -175,6 +175,42 @@ func TestAzureCluster_ValidateUpdate(t *testing.T) {
 			}(),
 			wantErr: true,
 		},
+		{
+			name: "azurecluster with the default load balancer SKU set explicitly",
+			cluster: func() *AzureCluster {
+				cluster := createValidCluster()
+				cluster.Spec.NetworkSpec.APIServerLB = &APIServerLBSpec{SKU: SKUStandard}
+				return cluster
+			}(),
+			wantErr: false,
+		},
+		{
+			name: "azurecluster with a changed load balancer SKU",
+			cluster: func() *AzureCluster {
+				cluster := createValidCluster()
+				cluster.Spec.NetworkSpec.APIServerLB = &APIServerLBSpec{SKU: SKUBasic}
+				return cluster
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "azurecluster with an empty API server public IP spec",
+			cluster: func() *AzureCluster {
+				cluster := createValidCluster()
+				cluster.Spec.NetworkSpec.APIServerIP = &PublicIPSpec{}
+				return cluster
+			}(),
+			wantErr: false,
+		},
+		{
+			name: "azurecluster with a changed API server DNS label",
+			cluster: func() *AzureCluster {
+				cluster := createValidCluster()
+				cluster.Spec.NetworkSpec.APIServerIP = &PublicIPSpec{DNSLabel: "my-cluster-api"}
+				return cluster
+			}(),
+			wantErr: true,
+		},
 	}
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
@@ -112,6 +112,17 @@ func validateMarketplaceImage(image *Image, fldPath *field.Path) field.ErrorList
 	if image.Marketplace.Version == "" {
 		allErrs = append(allErrs, field.Invalid(fldPath.Child("Version"), "", "Version cannot be empty when specifying an AzureMarketplaceImage"))
 	}
+	if plan := image.Marketplace.Plan; plan != nil {
+		if plan.Publisher == "" {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("Plan", "Publisher"), "", "Publisher cannot be empty when specifying the Plan of an AzureMarketplaceImage"))
+		}
+		if plan.Product == "" {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("Plan", "Product"), "", "Product cannot be empty when specifying the Plan of an AzureMarketplaceImage"))
+		}
+		if plan.Name == "" {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("Plan", "Name"), "", "Name cannot be empty when specifying the Plan of an AzureMarketplaceImage"))
+		}
+	}
 	return allErrs
 }
 
@@ -124,6 +124,14 @@ func TestMarketPlaceImageValid(t *testing.T) {
 			expectedErrors: 1,
 			image:          createTestMarketPlaceImage("PUB1234", "OFFER1234", "SKU1234", ""),
 		},
+		"AzureMarketplaceImage - with plan": {
+			expectedErrors: 0,
+			image:          createTestMarketPlaceImageWithPlan("PUB1234", "OFFER1234", "PLAN1234"),
+		},
+		"AzureMarketplaceImage - plan missing publisher and name": {
+			expectedErrors: 2,
+			image:          createTestMarketPlaceImageWithPlan("", "OFFER1234", ""),
+		},
 	}
 
 	for _, tc := range testCases {
@@ -176,6 +184,16 @@ func createTestMarketPlaceImage(publisher, offer, sku, version string) *Image {
 	}
 }
 
+func createTestMarketPlaceImageWithPlan(planPublisher, planProduct, planName string) *Image {
+	image := createTestMarketPlaceImage("PUB1234", "OFFER1234", "SKU1234", "1.0.0")
+	image.Marketplace.Plan = &ImagePlan{
+		Publisher: planPublisher,
+		Product:   planProduct,
+		Name:      planName,
+	}
+	return image
+}
+
 func createTestImageByID(imageID string) *Image {
 	return &Image{
 		ID: &imageID,
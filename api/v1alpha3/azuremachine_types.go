@@ -19,6 +19,7 @@ package v1alpha3
 import (
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha3"
 	"sigs.k8s.io/cluster-api/errors"
 )
 
@@ -26,6 +27,10 @@ const (
 	// MachineFinalizer allows ReconcileAzureMachine to clean up Azure resources associated with AzureMachine before
 	// removing it from the apiserver.
 	MachineFinalizer = "azuremachine.infrastructure.cluster.x-k8s.io"
+
+	// FetchSerialLogAnnotation requests the serial console log of the VM of an AzureMachine to be stored in a ConfigMap.
+	// The annotation is removed once the log is stored.
+	FetchSerialLogAnnotation = "azuremachine.infrastructure.cluster.x-k8s.io/fetch-serial-log"
 )
 
 // AzureMachineSpec defines the desired state of AzureMachine
@@ -36,6 +41,11 @@ type AzureMachineSpec struct {
 
 	VMSize string `json:"vmSize"`
 
+	// AllowInPlaceResize resizes the VM in place when VMSize changes instead of leaving it at its original size.
+	// The VM is deallocated and started again when the new size is not available on its current hardware cluster.
+	// +optional
+	AllowInPlaceResize bool `json:"allowInPlaceResize,omitempty"`
+
 	// FailureDomain is the failure domain unique identifier this Machine should be attached to,
 	// as defined in Cluster API. This relates to an Azure Availability Zone
 	FailureDomain *string `json:"failureDomain,omitempty"`
@@ -88,9 +98,35 @@ type AzureMachineSpec struct {
 	// +optional
 	AcceleratedNetworking *bool `json:"acceleratedNetworking,omitempty"`
 
+	// NetworkInterfaces is the list of network interfaces to attach to the VM. The first network interface
+	// is the primary one and is attached to the cluster load balancers.
+	// If omitted, a single network interface is created in the subnet matching the machine role.
+	// +optional
+	NetworkInterfaces []NetworkInterface `json:"networkInterfaces,omitempty"`
+
 	// SpotVMOptions allows the ability to specify the Machine should use a Spot VM
 	// +optional
 	SpotVMOptions *SpotVMOptions `json:"spotVMOptions,omitempty"`
+
+	// ProximityPlacementGroupName is the name of a proximity placement group in the cluster resource group to place
+	// the VM in. The provider creates it if it doesn't exist. Control plane machines default to the proximity placement
+	// group of the AzureCluster, an empty name opts them out.
+	// +optional
+	ProximityPlacementGroupName *string `json:"proximityPlacementGroupName,omitempty"`
+
+	// DedicatedHost places the VM on an Azure Dedicated Host. The zone of the VM is the one of the host group,
+	// the failure domain of the machine must match it when set.
+	// +optional
+	DedicatedHost *DedicatedHostSpec `json:"dedicatedHost,omitempty"`
+
+	// Extensions are the VM extensions installed on the VM once it is created. Extensions removed from the list
+	// are uninstalled.
+	// +optional
+	Extensions []VMExtension `json:"extensions,omitempty"`
+
+	// BootDiagnostics configures the boot diagnostics of the VM, enabled by default.
+	// +optional
+	BootDiagnostics *BootDiagnostics `json:"bootDiagnostics,omitempty"`
 }
 
 // SpotVMOptions defines the options relevant to running the Machine on Spot VMs
@@ -99,8 +135,24 @@ type SpotVMOptions struct {
 	// +optional
 	// +kubebuilder:validation:Type=number
 	MaxPrice *string `json:"maxPrice,omitempty"`
+
+	// EvictionPolicy defines what happens to the Spot VM when it is evicted, Deallocate by default. A deallocated VM
+	// is restarted when capacity is available again, a deleted VM fails its machine so that it is replaced.
+	// +optional
+	EvictionPolicy *SpotEvictionPolicy `json:"evictionPolicy,omitempty"`
 }
 
+// SpotEvictionPolicy defines the eviction policy of a Spot VM.
+// +kubebuilder:validation:Enum=Deallocate;Delete
+type SpotEvictionPolicy string
+
+const (
+	// SpotEvictionPolicyDeallocate stops and deallocates an evicted Spot VM, keeping its disks.
+	SpotEvictionPolicyDeallocate SpotEvictionPolicy = "Deallocate"
+	// SpotEvictionPolicyDelete deletes an evicted Spot VM and its disks.
+	SpotEvictionPolicyDelete SpotEvictionPolicy = "Delete"
+)
+
 // AzureMachineStatus defines the observed state of AzureMachine
 type AzureMachineStatus struct {
 	// Ready is true when the provider resource is ready.
@@ -114,6 +166,42 @@ type AzureMachineStatus struct {
 	// +optional
 	VMState *VMState `json:"vmState,omitempty"`
 
+	// SSHPort is the frontend port of the inbound NAT rule forwarding SSH traffic from the
+	// API server load balancer to this machine. It is only set for control plane machines.
+	// +optional
+	SSHPort int32 `json:"sshPort,omitempty"`
+
+	// DedicatedHostID is the ID of the dedicated host the VM is placed on.
+	// +optional
+	DedicatedHostID string `json:"dedicatedHostID,omitempty"`
+
+	// SerialLogConfigMapName is the name of the ConfigMap holding the end of the serial console log of the VM, stored
+	// when the machine failed or on request with the fetch serial log annotation.
+	// +optional
+	SerialLogConfigMapName string `json:"serialLogConfigMapName,omitempty"`
+
+	// SpotVMEvictionTime is the time the eviction of the Spot VM was noticed. It is cleared once the VM runs again.
+	// +optional
+	SpotVMEvictionTime *metav1.Time `json:"spotVMEvictionTime,omitempty"`
+
+	// SpotVMRestartAttempts is the number of attempts to restart the Spot VM since it was deallocated by an eviction.
+	// +optional
+	SpotVMRestartAttempts int32 `json:"spotVMRestartAttempts,omitempty"`
+
+	// LastSpotVMRestartTime is the time of the last attempt to restart the Spot VM after an eviction.
+	// +optional
+	LastSpotVMRestartTime *metav1.Time `json:"lastSpotVMRestartTime,omitempty"`
+
+	// LastResizeVMSize is the VM size of the last in-place resize of the VM. A failed resize is not retried until the
+	// VMSize of the AzureMachine changes.
+	// +optional
+	LastResizeVMSize string `json:"lastResizeVMSize,omitempty"`
+
+	// Image is the image the VM is created from, with a latest version resolved to the concrete version it referred
+	// to when the image was first reconciled, so that all operations on the VM use the same image build.
+	// +optional
+	Image *Image `json:"image,omitempty"`
+
 	// ErrorReason will be set in the event that there is a terminal problem
 	// reconciling the Machine and will contain a succinct value suitable
 	// for machine interpretation.
@@ -151,6 +239,10 @@ type AzureMachineStatus struct {
 	// controller's output.
 	// +optional
 	FailureMessage *string `json:"failureMessage,omitempty"`
+
+	// Conditions defines current service state of the AzureMachine.
+	// +optional
+	Conditions clusterv1.Conditions `json:"conditions,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -173,6 +265,16 @@ type AzureMachine struct {
 	Status AzureMachineStatus `json:"status,omitempty"`
 }
 
+// GetConditions returns the list of conditions for an AzureMachine API object.
+func (m *AzureMachine) GetConditions() clusterv1.Conditions {
+	return m.Status.Conditions
+}
+
+// SetConditions will set the given conditions on an AzureMachine object.
+func (m *AzureMachine) SetConditions(conditions clusterv1.Conditions) {
+	m.Status.Conditions = conditions
+}
+
 // +kubebuilder:object:root=true
 
 // AzureMachineList contains a list of AzureMachine
@@ -19,12 +19,22 @@ package v1alpha3
 import (
 	"encoding/base64"
 	"fmt"
+	"regexp"
+	"strings"
 
 	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2019-12-01/compute"
 	"golang.org/x/crypto/ssh"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 )
 
+// maxPrivateIPConfigs is the maximum number of IP configurations of an Azure network interface.
+const maxPrivateIPConfigs = 256
+
+var (
+	hostGroupIDRegex = regexp.MustCompile(`(?i)^/subscriptions/[^/]+/resourceGroups/[^/]+/providers/Microsoft\.Compute/hostGroups/[^/]+$`)
+	hostIDRegex      = regexp.MustCompile(`(?i)^(/subscriptions/[^/]+/resourceGroups/[^/]+/providers/Microsoft\.Compute/hostGroups/[^/]+)/hosts/[^/]+$`)
+)
+
 // ValidateSSHKey validates an SSHKey
 func ValidateSSHKey(sshKey string, fldPath *field.Path) field.ErrorList {
 	allErrs := field.ErrorList{}
@@ -71,6 +81,100 @@ func ValidateOSDisk(osDisk OSDisk, fieldPath *field.Path) field.ErrorList {
 	return allErrs
 }
 
+// ValidateNetworkInterfaces validates the network interfaces list
+func ValidateNetworkInterfaces(nics []NetworkInterface, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	for i, nic := range nics {
+		// 0 means the field is unset, which defaults to a single private IP configuration.
+		if nic.PrivateIPConfigs < 0 || nic.PrivateIPConfigs > maxPrivateIPConfigs {
+			allErrs = append(allErrs, field.Invalid(fldPath.Index(i).Child("privateIPConfigs"), nic.PrivateIPConfigs,
+				fmt.Sprintf("the number of private IP configurations should be unset or a value between 1 and %d", maxPrivateIPConfigs)))
+		}
+	}
+
+	return allErrs
+}
+
+// ValidateDedicatedHost validates the dedicated host placement of a VM.
+func ValidateDedicatedHost(dedicatedHost *DedicatedHostSpec, spotVMOptions *SpotVMOptions, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if dedicatedHost == nil {
+		return allErrs
+	}
+
+	if dedicatedHost.HostGroupID == "" && dedicatedHost.HostID == "" {
+		allErrs = append(allErrs, field.Required(fldPath, "one of hostGroupID or hostID is required"))
+	}
+	if dedicatedHost.HostGroupID != "" && !hostGroupIDRegex.MatchString(dedicatedHost.HostGroupID) {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("hostGroupID"), dedicatedHost.HostGroupID,
+			"hostGroupID must be the resource ID of a Microsoft.Compute/hostGroups resource"))
+	}
+	if dedicatedHost.HostID != "" {
+		match := hostIDRegex.FindStringSubmatch(dedicatedHost.HostID)
+		if match == nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("hostID"), dedicatedHost.HostID,
+				"hostID must be the resource ID of a Microsoft.Compute/hostGroups/hosts resource"))
+		} else if dedicatedHost.HostGroupID != "" && !strings.EqualFold(match[1], dedicatedHost.HostGroupID) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("hostID"), dedicatedHost.HostID,
+				"hostID must be a host of the host group hostGroupID"))
+		}
+	}
+	if spotVMOptions != nil {
+		allErrs = append(allErrs, field.Forbidden(fldPath, "Spot VMs cannot be placed on dedicated hosts"))
+	}
+
+	return allErrs
+}
+
+// ValidateVMExtensions validates the VM extensions list
+func ValidateVMExtensions(extensions []VMExtension, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	names := make(map[string]bool, len(extensions))
+	for i, extension := range extensions {
+		idxPath := fldPath.Index(i)
+		if extension.Name == "" {
+			allErrs = append(allErrs, field.Required(idxPath.Child("name"), "the extension name cannot be empty"))
+		} else if names[strings.ToLower(extension.Name)] {
+			allErrs = append(allErrs, field.Duplicate(idxPath.Child("name"), extension.Name))
+		}
+		names[strings.ToLower(extension.Name)] = true
+
+		if extension.Publisher == "" {
+			allErrs = append(allErrs, field.Required(idxPath.Child("publisher"), "the extension publisher cannot be empty"))
+		}
+		if extension.Type == "" {
+			allErrs = append(allErrs, field.Required(idxPath.Child("type"), "the extension type cannot be empty"))
+		}
+		if extension.Version == "" {
+			allErrs = append(allErrs, field.Required(idxPath.Child("version"), "the extension version cannot be empty"))
+		}
+		if extension.Settings != nil {
+			if _, err := extension.GetSettings(); err != nil {
+				allErrs = append(allErrs, field.Invalid(idxPath.Child("settings"), string(extension.Settings.Raw), err.Error()))
+			}
+		}
+	}
+
+	return allErrs
+}
+
+// ValidateMachineVMExtensions validates the VM extensions of an AzureMachine, whose names can't conflict with the
+// extension reporting the result of the bootstrap of Linux machines.
+func ValidateMachineVMExtensions(extensions []VMExtension, fldPath *field.Path) field.ErrorList {
+	allErrs := ValidateVMExtensions(extensions, fldPath)
+
+	for i, extension := range extensions {
+		if strings.EqualFold(extension.Name, BootstrapExtensionName) {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Index(i).Child("name"),
+				fmt.Sprintf("the extension name %s is reserved for the bootstrap sentinel extension", BootstrapExtensionName)))
+		}
+	}
+
+	return allErrs
+}
+
 func validateStorageAccountType(storageAccountType string, fieldPath *field.Path) field.ErrorList {
 	allErrs := field.ErrorList{}
 	storageAccTypeChildPath := fieldPath.Child("ManagedDisk").Child("StorageAccountType")
@@ -24,6 +24,7 @@ import (
 
 	. "github.com/onsi/gomega"
 	"golang.org/x/crypto/ssh"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 )
 
@@ -158,3 +159,208 @@ func generateValidOSDisk() OSDisk {
 		},
 	}
 }
+
+func TestAzureMachine_ValidateNetworkInterfaces(t *testing.T) {
+	g := NewWithT(t)
+
+	tests := []struct {
+		name    string
+		nics    []NetworkInterface
+		wantErr bool
+	}{
+		{
+			name:    "no network interfaces",
+			nics:    nil,
+			wantErr: false,
+		},
+		{
+			name: "valid network interfaces",
+			nics: []NetworkInterface{
+				{SubnetName: "subnet-1"},
+				{SubnetName: "subnet-2", PrivateIPConfigs: 10},
+			},
+			wantErr: false,
+		},
+		{
+			name: "unset number of private IP configurations",
+			nics: []NetworkInterface{
+				{PrivateIPConfigs: 0},
+			},
+			wantErr: false,
+		},
+		{
+			name: "negative number of private IP configurations",
+			nics: []NetworkInterface{
+				{PrivateIPConfigs: -1},
+			},
+			wantErr: true,
+		},
+		{
+			name: "too many private IP configurations",
+			nics: []NetworkInterface{
+				{PrivateIPConfigs: 257},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateNetworkInterfaces(tc.nics, field.NewPath("networkInterfaces"))
+			if tc.wantErr {
+				g.Expect(err).ToNot(HaveLen(0))
+			} else {
+				g.Expect(err).To(HaveLen(0))
+			}
+		})
+	}
+}
+
+func TestAzureMachine_ValidateDedicatedHost(t *testing.T) {
+	g := NewWithT(t)
+
+	hostGroupID := "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/hostGroups/my-group"
+
+	tests := []struct {
+		name          string
+		dedicatedHost *DedicatedHostSpec
+		spotVMOptions *SpotVMOptions
+		wantErr       bool
+	}{
+		{
+			name:          "no dedicated host",
+			dedicatedHost: nil,
+			wantErr:       false,
+		},
+		{
+			name:          "valid host group",
+			dedicatedHost: &DedicatedHostSpec{HostGroupID: hostGroupID},
+			wantErr:       false,
+		},
+		{
+			name:          "valid host in its host group",
+			dedicatedHost: &DedicatedHostSpec{HostGroupID: hostGroupID, HostID: hostGroupID + "/hosts/my-host"},
+			wantErr:       false,
+		},
+		{
+			name:          "empty dedicated host",
+			dedicatedHost: &DedicatedHostSpec{},
+			wantErr:       true,
+		},
+		{
+			name:          "invalid host group ID",
+			dedicatedHost: &DedicatedHostSpec{HostGroupID: "my-group"},
+			wantErr:       true,
+		},
+		{
+			name:          "invalid host ID",
+			dedicatedHost: &DedicatedHostSpec{HostID: hostGroupID},
+			wantErr:       true,
+		},
+		{
+			name: "host in another host group",
+			dedicatedHost: &DedicatedHostSpec{
+				HostGroupID: hostGroupID,
+				HostID:      "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/hostGroups/other-group/hosts/my-host",
+			},
+			wantErr: true,
+		},
+		{
+			name:          "spot VM on a dedicated host",
+			dedicatedHost: &DedicatedHostSpec{HostGroupID: hostGroupID},
+			spotVMOptions: &SpotVMOptions{},
+			wantErr:       true,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateDedicatedHost(tc.dedicatedHost, tc.spotVMOptions, field.NewPath("dedicatedHost"))
+			if tc.wantErr {
+				g.Expect(err).ToNot(HaveLen(0))
+			} else {
+				g.Expect(err).To(HaveLen(0))
+			}
+		})
+	}
+}
+
+func TestAzureMachine_ValidateVMExtensions(t *testing.T) {
+	g := NewWithT(t)
+
+	script := VMExtension{
+		Name:      "my-script",
+		Publisher: "Microsoft.Azure.Extensions",
+		Type:      "CustomScript",
+		Version:   "2.1",
+	}
+
+	tests := []struct {
+		name       string
+		extensions []VMExtension
+		wantErr    bool
+	}{
+		{
+			name:       "no extensions",
+			extensions: nil,
+			wantErr:    false,
+		},
+		{
+			name:       "valid extension",
+			extensions: []VMExtension{script},
+			wantErr:    false,
+		},
+		{
+			name:       "duplicate extension names",
+			extensions: []VMExtension{script, script},
+			wantErr:    true,
+		},
+		{
+			name:       "extension without version",
+			extensions: []VMExtension{{Name: "my-script", Publisher: "Microsoft.Azure.Extensions", Type: "CustomScript"}},
+			wantErr:    true,
+		},
+		{
+			name: "extension with nested settings",
+			extensions: []VMExtension{{Name: "my-script", Publisher: "Microsoft.Azure.Extensions", Type: "CustomScript", Version: "2.1",
+				Settings: &apiextensionsv1.JSON{Raw: []byte(`{"fileUris": ["https://example.com/script.sh"], "timestamp": 123}`)}}},
+			wantErr: false,
+		},
+		{
+			name: "extension with settings which are not a JSON object",
+			extensions: []VMExtension{{Name: "my-script", Publisher: "Microsoft.Azure.Extensions", Type: "CustomScript", Version: "2.1",
+				Settings: &apiextensionsv1.JSON{Raw: []byte(`["echo hello"]`)}}},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateVMExtensions(tc.extensions, field.NewPath("extensions"))
+			if tc.wantErr {
+				g.Expect(err).ToNot(HaveLen(0))
+			} else {
+				g.Expect(err).To(HaveLen(0))
+			}
+		})
+	}
+}
+
+func TestAzureMachine_ValidateMachineVMExtensions(t *testing.T) {
+	g := NewWithT(t)
+
+	script := VMExtension{
+		Name:      "my-script",
+		Publisher: "Microsoft.Azure.Extensions",
+		Type:      "CustomScript",
+		Version:   "2.1",
+	}
+
+	reserved := script
+	reserved.Name = BootstrapExtensionName
+
+	g.Expect(ValidateMachineVMExtensions([]VMExtension{script}, field.NewPath("extensions"))).To(HaveLen(0))
+	g.Expect(ValidateMachineVMExtensions([]VMExtension{reserved}, field.NewPath("extensions"))).ToNot(HaveLen(0))
+}
@@ -17,6 +17,8 @@ limitations under the License.
 package v1alpha3
 
 import (
+	"reflect"
+
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/validation/field"
@@ -60,6 +62,18 @@ func (m *AzureMachine) ValidateCreate() error {
 		allErrs = append(allErrs, errs...)
 	}
 
+	if errs := ValidateNetworkInterfaces(m.Spec.NetworkInterfaces, field.NewPath("networkInterfaces")); len(errs) > 0 {
+		allErrs = append(allErrs, errs...)
+	}
+
+	if errs := ValidateDedicatedHost(m.Spec.DedicatedHost, m.Spec.SpotVMOptions, field.NewPath("dedicatedHost")); len(errs) > 0 {
+		allErrs = append(allErrs, errs...)
+	}
+
+	if errs := ValidateMachineVMExtensions(m.Spec.Extensions, field.NewPath("extensions")); len(errs) > 0 {
+		allErrs = append(allErrs, errs...)
+	}
+
 	if len(allErrs) == 0 {
 		return nil
 	}
@@ -79,6 +93,21 @@ func (m *AzureMachine) ValidateUpdate(old runtime.Object) error {
 		allErrs = append(allErrs, errs...)
 	}
 
+	if errs := ValidateNetworkInterfaces(m.Spec.NetworkInterfaces, field.NewPath("networkInterfaces")); len(errs) > 0 {
+		allErrs = append(allErrs, errs...)
+	}
+
+	if errs := ValidateDedicatedHost(m.Spec.DedicatedHost, m.Spec.SpotVMOptions, field.NewPath("dedicatedHost")); len(errs) > 0 {
+		allErrs = append(allErrs, errs...)
+	}
+
+	// Only validate changed extensions, so that existing machines can still be updated.
+	if oldMachine, ok := old.(*AzureMachine); !ok || !reflect.DeepEqual(m.Spec.Extensions, oldMachine.Spec.Extensions) {
+		if errs := ValidateMachineVMExtensions(m.Spec.Extensions, field.NewPath("extensions")); len(errs) > 0 {
+			allErrs = append(allErrs, errs...)
+		}
+	}
+
 	if len(allErrs) == 0 {
 		return nil
 	}
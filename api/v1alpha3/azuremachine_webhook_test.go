@@ -142,6 +142,30 @@ func TestAzureMachine_ValidateUpdate(t *testing.T) {
 			machine:    createMachineWithUserAssignedIdentities(t, []UserAssignedIdentity{}),
 			wantErr:    true,
 		},
+		{
+			name:       "azuremachine with unchanged invalid extensions",
+			oldMachine: createMachineWithExtensions(t, []VMExtension{{Name: BootstrapExtensionName, Publisher: "Microsoft.Azure.Extensions", Type: "CustomScript", Version: "2.1"}}),
+			machine:    createMachineWithExtensions(t, []VMExtension{{Name: BootstrapExtensionName, Publisher: "Microsoft.Azure.Extensions", Type: "CustomScript", Version: "2.1"}}),
+			wantErr:    false,
+		},
+		{
+			name:       "azuremachine with changed invalid extensions",
+			oldMachine: createMachineWithExtensions(t, nil),
+			machine:    createMachineWithExtensions(t, []VMExtension{{Name: BootstrapExtensionName, Publisher: "Microsoft.Azure.Extensions", Type: "CustomScript", Version: "2.1"}}),
+			wantErr:    true,
+		},
+		{
+			name:       "azuremachine with invalid dedicated host",
+			oldMachine: createMachineWithDedicatedHost(t, nil),
+			machine:    createMachineWithDedicatedHost(t, &DedicatedHostSpec{HostGroupID: "my-group"}),
+			wantErr:    true,
+		},
+		{
+			name:       "azuremachine with invalid network interfaces",
+			oldMachine: createMachineWithNetworkInterfaces(t, nil),
+			machine:    createMachineWithNetworkInterfaces(t, []NetworkInterface{{PrivateIPConfigs: 257}}),
+			wantErr:    true,
+		},
 	}
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
@@ -173,6 +197,24 @@ func TestAzureMachine_Default(t *testing.T) {
 	g.Expect(publicKeyNotExistTest.machine.Spec.SSHPublicKey).To(Not(BeEmpty()))
 }
 
+func createMachineWithExtensions(t *testing.T, extensions []VMExtension) *AzureMachine {
+	machine := hardcodedAzureMachineWithSSHKey(generateSSHPublicKey())
+	machine.Spec.Extensions = extensions
+	return machine
+}
+
+func createMachineWithNetworkInterfaces(t *testing.T, nics []NetworkInterface) *AzureMachine {
+	machine := hardcodedAzureMachineWithSSHKey(generateSSHPublicKey())
+	machine.Spec.NetworkInterfaces = nics
+	return machine
+}
+
+func createMachineWithDedicatedHost(t *testing.T, dedicatedHost *DedicatedHostSpec) *AzureMachine {
+	machine := hardcodedAzureMachineWithSSHKey(generateSSHPublicKey())
+	machine.Spec.DedicatedHost = dedicatedHost
+	return machine
+}
+
 func createMachineWithSharedImage(t *testing.T, subscriptionID, resourceGroup, name, gallery, version string) *AzureMachine {
 	image := &Image{
 		SharedGallery: &AzureSharedGalleryImage{
@@ -0,0 +1,88 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha3
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestAzureMachineTemplate_ValidateCreate(t *testing.T) {
+	g := NewWithT(t)
+
+	hostGroupID := "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/hostGroups/my-group"
+
+	tests := []struct {
+		name     string
+		template *AzureMachineTemplate
+		wantErr  bool
+	}{
+		{
+			name:     "azuremachinetemplate without dedicated host",
+			template: createMachineTemplateWithDedicatedHost(t, nil, nil),
+			wantErr:  false,
+		},
+		{
+			name:     "azuremachinetemplate with valid dedicated host",
+			template: createMachineTemplateWithDedicatedHost(t, &DedicatedHostSpec{HostGroupID: hostGroupID}, nil),
+			wantErr:  false,
+		},
+		{
+			name:     "azuremachinetemplate with invalid host group ID",
+			template: createMachineTemplateWithDedicatedHost(t, &DedicatedHostSpec{HostGroupID: "my-group"}, nil),
+			wantErr:  true,
+		},
+		{
+			name:     "azuremachinetemplate with spot VM on a dedicated host",
+			template: createMachineTemplateWithDedicatedHost(t, &DedicatedHostSpec{HostGroupID: hostGroupID}, &SpotVMOptions{}),
+			wantErr:  true,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.template.ValidateCreate()
+			if tc.wantErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestAzureMachineTemplate_ValidateUpdate(t *testing.T) {
+	g := NewWithT(t)
+
+	oldTemplate := createMachineTemplateWithDedicatedHost(t, nil, nil)
+	template := createMachineTemplateWithDedicatedHost(t, &DedicatedHostSpec{HostID: "my-host"}, nil)
+	g.Expect(template.ValidateUpdate(oldTemplate)).To(HaveOccurred())
+}
+
+func createMachineTemplateWithDedicatedHost(t *testing.T, dedicatedHost *DedicatedHostSpec, spotVMOptions *SpotVMOptions) *AzureMachineTemplate {
+	return &AzureMachineTemplate{
+		Spec: AzureMachineTemplateSpec{
+			Template: AzureMachineTemplateResource{
+				Spec: AzureMachineSpec{
+					SSHPublicKey:  generateSSHPublicKey(),
+					DedicatedHost: dedicatedHost,
+					SpotVMOptions: spotVMOptions,
+				},
+			},
+		},
+	}
+}
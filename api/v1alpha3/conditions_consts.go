@@ -0,0 +1,79 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha3
+
+import clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha3"
+
+// AzureCluster Conditions and Reasons.
+const (
+	// DDoSProtectionPlanAttachedCondition reports whether the DDoS protection plan of the VnetSpec is attached
+	// to the virtual network. It is only set when a plan is specified.
+	DDoSProtectionPlanAttachedCondition clusterv1.ConditionType = "DDoSProtectionPlanAttached"
+
+	// DDoSProtectionPlanNotAccessibleReason (Severity=Error) documents a DDoS protection plan that doesn't exist
+	// or is not in the tenant of the cluster.
+	DDoSProtectionPlanNotAccessibleReason = "DDoSProtectionPlanNotAccessible"
+	// DDoSProtectionPlanAttachFailedReason (Severity=Error) documents a failure to attach the DDoS protection plan
+	// to the virtual network.
+	DDoSProtectionPlanAttachFailedReason = "DDoSProtectionPlanAttachFailed"
+
+	// DefaultRouteReadyCondition reports whether the subnets of the cluster have a default route to a next hop
+	// IP address. It is only set with the UserDefinedRouting outbound type.
+	DefaultRouteReadyCondition clusterv1.ConditionType = "DefaultRouteReady"
+
+	// DefaultRouteMissingReason (Severity=Error) documents a subnet without a default route to a next hop IP address.
+	DefaultRouteMissingReason = "DefaultRouteMissing"
+
+	// ClusterNetworkValidCondition reports whether the pod and service CIDR blocks of the owner Cluster are
+	// compatible with the virtual network, which the AzureCluster webhook cannot check.
+	ClusterNetworkValidCondition clusterv1.ConditionType = "ClusterNetworkValid"
+
+	// ClusterNetworkInvalidReason (Severity=Error) documents pod or service CIDR blocks which overlap the virtual network.
+	ClusterNetworkInvalidReason = "ClusterNetworkInvalid"
+)
+
+// AzureMachine Conditions and Reasons.
+const (
+	// VMExtensionsReadyCondition reports whether the VM extensions of the AzureMachine, or of the scale set instances of
+	// the AzureMachinePool, are provisioned. It is only set when there are extensions.
+	VMExtensionsReadyCondition clusterv1.ConditionType = "VMExtensionsReady"
+
+	// VMExtensionsProvisioningReason (Severity=Info) documents VM extensions being installed or updated.
+	VMExtensionsProvisioningReason = "VMExtensionsProvisioning"
+	// VMExtensionsFailedReason (Severity=Error) documents a VM extension which failed to provision.
+	VMExtensionsFailedReason = "VMExtensionsFailed"
+
+	// BootstrapSucceededCondition reports whether the bootstrap of a Linux VM succeeded, as reported by the
+	// bootstrap sentinel extension.
+	BootstrapSucceededCondition clusterv1.ConditionType = "BootstrapSucceeded"
+
+	// BootstrapInProgressReason (Severity=Info) documents a VM which is bootstrapping.
+	BootstrapInProgressReason = "BootstrapInProgress"
+	// BootstrapFailedReason (Severity=Error) documents a VM which failed to bootstrap.
+	BootstrapFailedReason = "BootstrapFailed"
+
+	// VMResizedCondition reports whether the last in-place resize of the VM succeeded. It is only set once the
+	// VMSize of an AzureMachine allowing in-place resize changes.
+	VMResizedCondition clusterv1.ConditionType = "VMResized"
+
+	// VMResizingReason (Severity=Info) documents a VM being resized.
+	VMResizingReason = "VMResizing"
+	// VMSizeNotAvailableReason (Severity=Error) documents a VM size which is not offered in the location or zone of the VM.
+	VMSizeNotAvailableReason = "VMSizeNotAvailable"
+	// VMResizeFailedReason (Severity=Error) documents a failure to resize the VM.
+	VMResizeFailedReason = "VMResizeFailed"
+)
@@ -17,7 +17,11 @@ limitations under the License.
 package v1alpha3
 
 import (
+	"encoding/json"
+
+	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 )
 
 const (
@@ -45,6 +49,171 @@ type NetworkSpec struct {
 	// Subnets is the configuration for the control-plane subnet and the node subnet.
 	// +optional
 	Subnets Subnets `json:"subnets,omitempty"`
+
+	// OutboundType is how the machines of the cluster reach the internet. With LoadBalancer, they go through the
+	// outbound rules of the node outbound and API server load balancers. With UserDefinedRouting, neither outbound
+	// rules nor the node outbound load balancer are created, and the egress traffic follows the default route of
+	// the route table of the subnets, e.g. to an Azure Firewall. Defaults to LoadBalancer.
+	// +kubebuilder:validation:Enum=LoadBalancer;UserDefinedRouting
+	// +optional
+	OutboundType OutboundType `json:"outboundType,omitempty"`
+
+	// DefaultRouteNextHopIP is the private IP address, e.g. of an Azure Firewall, to which the default route of the
+	// route table of a managed vnet sends the egress traffic with the UserDefinedRouting outbound type.
+	// The route tables of pre-existing vnets must already have a default route to a next hop IP address.
+	// +optional
+	DefaultRouteNextHopIP string `json:"defaultRouteNextHopIP,omitempty"`
+
+	// NodeOutboundLB is the configuration for the load balancer providing outbound connectivity to the nodes.
+	// +optional
+	NodeOutboundLB *NodeOutboundLBSpec `json:"nodeOutboundLB,omitempty"`
+
+	// APIServerIP is the configuration for the public IP of the API server load balancer.
+	// +optional
+	APIServerIP *PublicIPSpec `json:"apiServerIP,omitempty"`
+
+	// APIServerDNS is the configuration for a DNS record of the API server, which is then used as the
+	// control plane endpoint instead of the DNS name of the public IP.
+	// +optional
+	APIServerDNS *DNSRecordSpec `json:"apiServerDNS,omitempty"`
+
+	// APIServerLB is the configuration for the public and internal load balancers of the API server.
+	// +optional
+	APIServerLB *APIServerLBSpec `json:"apiServerLB,omitempty"`
+}
+
+// APIServerLBSpec configures the load balancers of the API server.
+type APIServerLBSpec struct {
+	// SKU is the SKU of the public and internal load balancers of the API server and of their public IP.
+	// Basic load balancers do not support HTTPS health probes. Must match the SKU of the node outbound
+	// load balancer. Defaults to Standard.
+	// +kubebuilder:validation:Enum=Basic;Standard
+	// +optional
+	SKU SKU `json:"sku,omitempty"`
+
+	// HealthProbe is the health probe of the API server load balancing rules. When not set, the public load balancer
+	// probes the API server port over TCP and the internal load balancer probes /healthz over HTTPS.
+	// +optional
+	HealthProbe *HealthProbeSpec `json:"healthProbe,omitempty"`
+}
+
+// HealthProbeProtocol defines the protocol of a load balancer health probe.
+type HealthProbeProtocol string
+
+const (
+	// HealthProbeProtocolTCP probes only check that the API server port accepts connections.
+	HealthProbeProtocolTCP = HealthProbeProtocol("Tcp")
+	// HealthProbeProtocolHTTPS probes check that the API server answers its health endpoint with a 200 status code.
+	HealthProbeProtocolHTTPS = HealthProbeProtocol("Https")
+)
+
+// HealthProbeSpec configures a load balancer health probe on the API server port.
+type HealthProbeSpec struct {
+	// Protocol is the protocol of the probe. HTTPS probes are only supported by Standard SKU load balancers.
+	// Defaults to Https, or to Tcp with the Basic SKU.
+	// +kubebuilder:validation:Enum=Tcp;Https
+	// +optional
+	Protocol HealthProbeProtocol `json:"protocol,omitempty"`
+
+	// RequestPath is the API server endpoint probed by HTTPS probes. Defaults to /readyz.
+	// +kubebuilder:validation:Enum=/readyz;/healthz
+	// +optional
+	RequestPath string `json:"requestPath,omitempty"`
+
+	// IntervalInSeconds is the interval between two probes. Defaults to 15.
+	// +kubebuilder:validation:Minimum=5
+	// +optional
+	IntervalInSeconds *int32 `json:"intervalInSeconds,omitempty"`
+
+	// NumberOfProbes is the number of consecutive failed probes after which a node stops receiving traffic.
+	// Defaults to 4.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	NumberOfProbes *int32 `json:"numberOfProbes,omitempty"`
+}
+
+// DNSRecordSpec configures an A record in an existing Azure DNS zone or private DNS zone.
+type DNSRecordSpec struct {
+	// ZoneID is the resource ID of an existing Azure DNS zone in the cluster subscription, or of an existing
+	// private DNS zone in the subscription of the virtual network. Records in public zones point to the API server
+	// public IP, records in private zones to the internal load balancer.
+	ZoneID string `json:"zoneID"`
+
+	// RecordName is the name of the A record relative to the zone. Defaults to api.<cluster name>.
+	// +optional
+	RecordName string `json:"recordName,omitempty"`
+
+	// CreateVnetLink links a private DNS zone to the cluster virtual network, so that the record resolves
+	// inside the cluster. Leave unset if the zone is already linked to the virtual network.
+	// +optional
+	CreateVnetLink bool `json:"createVnetLink,omitempty"`
+}
+
+// PublicIPSpec configures the public IP of the API server load balancer.
+type PublicIPSpec struct {
+	// ID is the resource ID of a pre-existing static public IP in the cluster subscription to use for the API server,
+	// e.g. an address that is already allowed by firewalls. The public IP is adopted as is and is not deleted
+	// with the cluster. Cannot be used together with DNSLabel.
+	// +optional
+	ID string `json:"id,omitempty"`
+
+	// DNSLabel is the domain name label of the public IP created for the API server, which makes the API server
+	// reachable at <dnsLabel>.<location>.cloudapp.azure.com. The label must be available in the cluster location.
+	// Defaults to the name of the public IP. Immutable.
+	// +optional
+	DNSLabel string `json:"dnsLabel,omitempty"`
+}
+
+// OutboundType defines how the machines of a cluster reach the internet.
+type OutboundType string
+
+const (
+	// OutboundTypeLoadBalancer sends the egress traffic through the outbound rules of the load balancers.
+	OutboundTypeLoadBalancer = OutboundType("LoadBalancer")
+	// OutboundTypeUserDefinedRouting sends the egress traffic to the next hop of the default route of the subnets.
+	OutboundTypeUserDefinedRouting = OutboundType("UserDefinedRouting")
+)
+
+// NodeOutboundLBSpec configures the node outbound load balancer and its outbound rule.
+type NodeOutboundLBSpec struct {
+	// Disabled disables the creation of the node outbound load balancer and its public IPs.
+	// Nodes then need another way to reach the internet, e.g. a NAT gateway or a user-defined route.
+	// +optional
+	Disabled bool `json:"disabled,omitempty"`
+
+	// SKU is the SKU of the load balancer and of its public IPs. Basic load balancers do not support outbound
+	// rules, so nodes then rely on the default outbound access of Azure and none of the outbound settings
+	// can be used. Must match the SKU of the API server load balancers. Defaults to Standard.
+	// +kubebuilder:validation:Enum=Basic;Standard
+	// +optional
+	SKU SKU `json:"sku,omitempty"`
+
+	// FrontendIPsCount is the number of public IPs used for outbound connectivity. Defaults to 1.
+	// Cannot be used together with PublicIPPrefixID.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	FrontendIPsCount *int32 `json:"frontendIPsCount,omitempty"`
+
+	// PublicIPPrefixID is the resource ID of an existing public IP prefix used for outbound connectivity.
+	// +optional
+	PublicIPPrefixID string `json:"publicIPPrefixID,omitempty"`
+
+	// AllocatedOutboundPorts is the number of SNAT ports allocated to each node. Must be a multiple of 8.
+	// Leave empty to let Azure allocate ports based on the size of the backend pool.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=64000
+	// +optional
+	AllocatedOutboundPorts *int32 `json:"allocatedOutboundPorts,omitempty"`
+
+	// IdleTimeoutInMinutes is the timeout of idle outbound connections. Defaults to 4.
+	// +kubebuilder:validation:Minimum=4
+	// +kubebuilder:validation:Maximum=120
+	// +optional
+	IdleTimeoutInMinutes *int32 `json:"idleTimeoutInMinutes,omitempty"`
+
+	// EnableTCPReset sends bidirectional TCP resets when idle outbound connections time out.
+	// +optional
+	EnableTCPReset *bool `json:"enableTCPReset,omitempty"`
 }
 
 // VnetSpec configures an Azure virtual network.
@@ -59,9 +228,22 @@ type VnetSpec struct {
 	// Name defines a name for the virtual network resource.
 	Name string `json:"name"`
 
+	// SubscriptionID is the identifier of the subscription containing the virtual network and its subnets.
+	// Leave empty to use the cluster subscription. Network interfaces and scale sets of the cluster are still
+	// created in the cluster subscription and attached to the subnets of this virtual network.
+	// The provider only creates a virtual network and its subnets in the cluster subscription, so a different
+	// subscription requires an existing virtual network and subnets.
+	// +optional
+	SubscriptionID string `json:"subscriptionID,omitempty"`
+
 	// CidrBlock is the CIDR block to be used when the provider creates a managed virtual network.
 	CidrBlock string `json:"cidrBlock,omitempty"`
 
+	// DDoSProtectionPlanID is the resource ID of an existing DDoS protection plan in the tenant of the cluster,
+	// which is attached to the virtual network when the provider manages it. Removing it doesn't detach the plan.
+	// +optional
+	DDoSProtectionPlanID string `json:"ddosProtectionPlanID,omitempty"`
+
 	// Tags is a collection of tags describing the resource.
 	Tags Tags `json:"tags,omitempty"`
 }
@@ -133,6 +315,16 @@ type IngressRule struct {
 
 	// Destination - The destination address prefix. CIDR or destination IP range. Asterix '*' can also be used to match all source IPs. Default tags such as 'VirtualNetwork', 'AzureLoadBalancer' and 'Internet' can also be used.
 	Destination *string `json:"destination,omitempty"`
+
+	// SourceApplicationSecurityGroup - The role of the machines whose application security group the network traffic originates from. Cannot be used together with Source.
+	// +kubebuilder:validation:Enum=control-plane;node
+	// +optional
+	SourceApplicationSecurityGroup SecurityGroupRole `json:"sourceApplicationSecurityGroup,omitempty"`
+
+	// DestinationApplicationSecurityGroup - The role of the machines whose application security group the network traffic is destined to. Cannot be used together with Destination.
+	// +kubebuilder:validation:Enum=control-plane;node
+	// +optional
+	DestinationApplicationSecurityGroup SecurityGroupRole `json:"destinationApplicationSecurityGroup,omitempty"`
 }
 
 // IngressRules is a slice of Azure ingress rules for security groups.
@@ -209,6 +401,8 @@ type VM struct {
 	State    VMState    `json:"vmState,omitempty"`
 	Identity VMIdentity `json:"identity,omitempty"`
 	Tags     Tags       `json:"tags,omitempty"`
+	// HostID is the ID of the dedicated host the VM is placed on, if any.
+	HostID string `json:"hostID,omitempty"`
 
 	// Addresses contains the addresses associated with the Azure VM.
 	Addresses []corev1.NodeAddress `json:"addresses,omitempty"`
@@ -251,6 +445,23 @@ type AzureMarketplaceImage struct {
 	// time even if a new version becomes available.
 	// +kubebuilder:validation:MinLength=1
 	Version string `json:"version"`
+	// Plan is the purchase plan of an image published by a third party, which is required to create VMs from it.
+	// It is read from the image when omitted.
+	// +optional
+	Plan *ImagePlan `json:"plan,omitempty"`
+}
+
+// ImagePlan defines the purchase plan of a marketplace image published by a third party.
+type ImagePlan struct {
+	// Publisher is the publisher of the plan, usually the publisher of the image.
+	// +kubebuilder:validation:MinLength=1
+	Publisher string `json:"publisher"`
+	// Product is the product of the plan, usually the offer of the image.
+	// +kubebuilder:validation:MinLength=1
+	Product string `json:"product"`
+	// Name is the name of the plan, usually the SKU of the image.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
 }
 
 // AzureSharedGalleryImage defines an image in a Shared Image Gallery to use for VM creation
@@ -305,6 +516,67 @@ type UserAssignedIdentity struct {
 	ProviderID string `json:"providerID"`
 }
 
+// ProximityPlacementGroupSpec defines a proximity placement group created by the provider.
+type ProximityPlacementGroupSpec struct {
+	// Name is the name of the proximity placement group. Defaults to <cluster name>-ppg.
+	// +optional
+	Name string `json:"name,omitempty"`
+}
+
+// DedicatedHostSpec references the Azure Dedicated Host a VM is placed on.
+type DedicatedHostSpec struct {
+	// HostGroupID is the resource ID of a dedicated host group. Without HostID, the VM is placed on the first host of
+	// the group with capacity left for its size.
+	// +optional
+	HostGroupID string `json:"hostGroupID,omitempty"`
+
+	// HostID is the resource ID of the dedicated host to place the VM on.
+	// +optional
+	HostID string `json:"hostID,omitempty"`
+}
+
+// BootstrapExtensionName is the name of the VM extension reporting the bootstrap result of a Linux VM, which is
+// reserved.
+const BootstrapExtensionName = "CAPZ.Linux.Bootstrapping"
+
+// VMExtension specifies a VM extension to install on a VM once it is created.
+type VMExtension struct {
+	// Name is the name of the extension, unique per VM.
+	Name string `json:"name"`
+
+	// Publisher is the name of the extension handler publisher, e.g. Microsoft.Azure.Extensions.
+	Publisher string `json:"publisher"`
+
+	// Type is the type of the extension, e.g. CustomScript.
+	Type string `json:"type"`
+
+	// Version is the version of the extension handler, e.g. 2.1.
+	Version string `json:"version"`
+
+	// Settings are the public settings of the extension, a JSON object whose schema depends on the extension.
+	// +optional
+	Settings *apiextensionsv1.JSON `json:"settings,omitempty"`
+
+	// ProtectedSettingsSecretName is the name of a Secret in the namespace of the machine whose data are the
+	// protected settings of the extension. Protected settings are encrypted and never returned by Azure, use them
+	// for credentials or commands embedding secrets.
+	// +optional
+	ProtectedSettingsSecretName string `json:"protectedSettingsSecretName,omitempty"`
+}
+
+// BootDiagnostics configures the boot diagnostics of a VM.
+type BootDiagnostics struct {
+	// Enabled enables boot diagnostics, which store the serial console log and a screenshot of the VM in the boot
+	// diagnostics storage account of the cluster. Defaults to true.
+	// +optional
+	Enabled *bool `json:"enabled,omitempty"`
+}
+
+// IsEnabled returns true if boot diagnostics are enabled, which is the default.
+func (b *BootDiagnostics) IsEnabled() bool {
+	return b == nil || b.Enabled == nil || *b.Enabled
+}
+
 // OSDisk defines the operating system disk for a VM.
 type OSDisk struct {
 	OSType      string      `json:"osType"`
@@ -317,6 +589,26 @@ type ManagedDisk struct {
 	StorageAccountType string `json:"storageAccountType"`
 }
 
+// NetworkInterface defines a network interface attached to a VM.
+type NetworkInterface struct {
+	// SubnetName is the name of the cluster subnet in which to create the network interface.
+	// Defaults to the subnet matching the machine role.
+	// +optional
+	SubnetName string `json:"subnetName,omitempty"`
+
+	// PrivateIPConfigs is the number of private IP configurations of the network interface.
+	// The first IP configuration is the primary one, additional ones provide secondary private IP addresses.
+	// Defaults to 1.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	PrivateIPConfigs int `json:"privateIPConfigs,omitempty"`
+
+	// AcceleratedNetworking enables or disables Azure accelerated networking on the network interface.
+	// If omitted, it will be set based on whether the requested VMSize supports accelerated networking.
+	// +optional
+	AcceleratedNetworking *bool `json:"acceleratedNetworking,omitempty"`
+}
+
 // SubnetRole defines the unique role of a subnet.
 type SubnetRole string
 
@@ -344,6 +636,13 @@ type SubnetSpec struct {
 	// +optional
 	CidrBlock string `json:"cidrBlock,omitempty"`
 
+	// PrefixLength is the size of the CIDR block allocated from the Vnet address space when
+	// CidrBlock is not set. Defaults to 16, or to a quarter of the Vnet for Vnets smaller than a /14.
+	// +kubebuilder:validation:Minimum=8
+	// +kubebuilder:validation:Maximum=29
+	// +optional
+	PrefixLength int32 `json:"prefixLength,omitempty"`
+
 	// InternalLBIPAddress is the IP address that will be used as the internal LB private IP.
 	// For the control plane subnet only.
 	// +optional
@@ -356,6 +655,44 @@ type SubnetSpec struct {
 	// RouteTable defines the route table that should be attached to this subnet.
 	// +optional
 	RouteTable RouteTable `json:"routeTable,omitempty"`
+
+	// ServiceEndpoints is the list of service endpoints to enable on the subnet.
+	// Only applied to subnets of a vnet managed by the provider.
+	// +optional
+	ServiceEndpoints []ServiceEndpointSpec `json:"serviceEndpoints,omitempty"`
+
+	// PrivateEndpoints is the list of private endpoints to create in the subnet.
+	// +optional
+	PrivateEndpoints []PrivateEndpointSpec `json:"privateEndpoints,omitempty"`
+}
+
+// ServiceEndpointSpec configures an Azure service endpoint.
+type ServiceEndpointSpec struct {
+	// Service is the type of the endpoint service, e.g. Microsoft.Storage or Microsoft.KeyVault.
+	Service string `json:"service"`
+
+	// Locations is the list of locations in which the service is reachable through the endpoint.
+	// Defaults to the cluster location.
+	// +optional
+	Locations []string `json:"locations,omitempty"`
+}
+
+// PrivateEndpointSpec configures an Azure private endpoint.
+type PrivateEndpointSpec struct {
+	// Name defines a name for the private endpoint resource.
+	Name string `json:"name"`
+
+	// PrivateLinkResourceID is the resource ID of the resource to connect to, e.g. a storage account or a key vault.
+	PrivateLinkResourceID string `json:"privateLinkResourceID"`
+
+	// GroupIDs is the list of sub-resources of the target resource to connect to, e.g. blob or vault.
+	// +optional
+	GroupIDs []string `json:"groupIDs,omitempty"`
+
+	// PrivateDNSZoneID is the resource ID of an existing private DNS zone in which an A record for the
+	// private endpoint address is registered, using the name of the target resource as record name.
+	// +optional
+	PrivateDNSZoneID string `json:"privateDNSZoneID,omitempty"`
 }
 
 // GetControlPlaneSubnet returns the cluster control plane subnet.
@@ -377,3 +714,35 @@ func (n *NetworkSpec) GetNodeSubnet() *SubnetSpec {
 	}
 	return nil
 }
+
+// GetLoadBalancerSKU returns the SKU of the cluster load balancers, which is the SKU set on the
+// API server or node outbound load balancer, or Standard if none is set.
+func (n *NetworkSpec) GetLoadBalancerSKU() SKU {
+	if n.APIServerLB != nil && n.APIServerLB.SKU != "" {
+		return n.APIServerLB.SKU
+	}
+	if n.NodeOutboundLB != nil && n.NodeOutboundLB.SKU != "" {
+		return n.NodeOutboundLB.SKU
+	}
+	return SKUStandard
+}
+
+// GetOutboundType returns the outbound type of the cluster, LoadBalancer if none is set.
+func (n *NetworkSpec) GetOutboundType() OutboundType {
+	if n.OutboundType == "" {
+		return OutboundTypeLoadBalancer
+	}
+	return n.OutboundType
+}
+
+// GetSettings returns the public settings of the extension decoded from JSON, or nil if it has none.
+func (e *VMExtension) GetSettings() (map[string]interface{}, error) {
+	if e.Settings == nil || len(e.Settings.Raw) == 0 {
+		return nil, nil
+	}
+	var settings map[string]interface{}
+	if err := json.Unmarshal(e.Settings.Raw, &settings); err != nil {
+		return nil, errors.Wrap(err, "settings must be a JSON object")
+	}
+	return settings, nil
+}
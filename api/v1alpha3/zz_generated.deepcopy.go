@@ -22,11 +22,32 @@ package v1alpha3
 
 import (
 	"k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	apiv1alpha3 "sigs.k8s.io/cluster-api/api/v1alpha3"
 	"sigs.k8s.io/cluster-api/errors"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *APIServerLBSpec) DeepCopyInto(out *APIServerLBSpec) {
+	*out = *in
+	if in.HealthProbe != nil {
+		in, out := &in.HealthProbe, &out.HealthProbe
+		*out = new(HealthProbeSpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIServerLBSpec.
+func (in *APIServerLBSpec) DeepCopy() *APIServerLBSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(APIServerLBSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AvailabilityZone) DeepCopyInto(out *AvailabilityZone) {
 	*out = *in
@@ -123,6 +144,11 @@ func (in *AzureClusterSpec) DeepCopyInto(out *AzureClusterSpec) {
 			(*out)[key] = val
 		}
 	}
+	if in.ProximityPlacementGroup != nil {
+		in, out := &in.ProximityPlacementGroup, &out.ProximityPlacementGroup
+		*out = new(ProximityPlacementGroupSpec)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureClusterSpec.
@@ -147,6 +173,13 @@ func (in *AzureClusterStatus) DeepCopyInto(out *AzureClusterStatus) {
 		}
 	}
 	in.Bastion.DeepCopyInto(&out.Bastion)
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make(apiv1alpha3.Conditions, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureClusterStatus.
@@ -255,11 +288,40 @@ func (in *AzureMachineSpec) DeepCopyInto(out *AzureMachineSpec) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.NetworkInterfaces != nil {
+		in, out := &in.NetworkInterfaces, &out.NetworkInterfaces
+		*out = make([]NetworkInterface, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.SpotVMOptions != nil {
 		in, out := &in.SpotVMOptions, &out.SpotVMOptions
 		*out = new(SpotVMOptions)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.ProximityPlacementGroupName != nil {
+		in, out := &in.ProximityPlacementGroupName, &out.ProximityPlacementGroupName
+		*out = new(string)
+		**out = **in
+	}
+	if in.DedicatedHost != nil {
+		in, out := &in.DedicatedHost, &out.DedicatedHost
+		*out = new(DedicatedHostSpec)
+		**out = **in
+	}
+	if in.Extensions != nil {
+		in, out := &in.Extensions, &out.Extensions
+		*out = make([]VMExtension, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.BootDiagnostics != nil {
+		in, out := &in.BootDiagnostics, &out.BootDiagnostics
+		*out = new(BootDiagnostics)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureMachineSpec.
@@ -285,6 +347,19 @@ func (in *AzureMachineStatus) DeepCopyInto(out *AzureMachineStatus) {
 		*out = new(VMState)
 		**out = **in
 	}
+	if in.SpotVMEvictionTime != nil {
+		in, out := &in.SpotVMEvictionTime, &out.SpotVMEvictionTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastSpotVMRestartTime != nil {
+		in, out := &in.LastSpotVMRestartTime, &out.LastSpotVMRestartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Image != nil {
+		in, out := &in.Image, &out.Image
+		*out = new(Image)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.FailureReason != nil {
 		in, out := &in.FailureReason, &out.FailureReason
 		*out = new(errors.MachineStatusError)
@@ -295,6 +370,13 @@ func (in *AzureMachineStatus) DeepCopyInto(out *AzureMachineStatus) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make(apiv1alpha3.Conditions, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureMachineStatus.
@@ -400,6 +482,11 @@ func (in *AzureMachineTemplateSpec) DeepCopy() *AzureMachineTemplateSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AzureMarketplaceImage) DeepCopyInto(out *AzureMarketplaceImage) {
 	*out = *in
+	if in.Plan != nil {
+		in, out := &in.Plan, &out.Plan
+		*out = new(ImagePlan)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureMarketplaceImage.
@@ -442,6 +529,26 @@ func (in *BackendPool) DeepCopy() *BackendPool {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BootDiagnostics) DeepCopyInto(out *BootDiagnostics) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BootDiagnostics.
+func (in *BootDiagnostics) DeepCopy() *BootDiagnostics {
+	if in == nil {
+		return nil
+	}
+	out := new(BootDiagnostics)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BuildParams) DeepCopyInto(out *BuildParams) {
 	*out = *in
@@ -474,6 +581,36 @@ func (in *BuildParams) DeepCopy() *BuildParams {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DNSRecordSpec) DeepCopyInto(out *DNSRecordSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DNSRecordSpec.
+func (in *DNSRecordSpec) DeepCopy() *DNSRecordSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DNSRecordSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DedicatedHostSpec) DeepCopyInto(out *DedicatedHostSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DedicatedHostSpec.
+func (in *DedicatedHostSpec) DeepCopy() *DedicatedHostSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DedicatedHostSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *FrontendIPConfig) DeepCopyInto(out *FrontendIPConfig) {
 	*out = *in
@@ -489,6 +626,31 @@ func (in *FrontendIPConfig) DeepCopy() *FrontendIPConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HealthProbeSpec) DeepCopyInto(out *HealthProbeSpec) {
+	*out = *in
+	if in.IntervalInSeconds != nil {
+		in, out := &in.IntervalInSeconds, &out.IntervalInSeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.NumberOfProbes != nil {
+		in, out := &in.NumberOfProbes, &out.NumberOfProbes
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HealthProbeSpec.
+func (in *HealthProbeSpec) DeepCopy() *HealthProbeSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(HealthProbeSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Image) DeepCopyInto(out *Image) {
 	*out = *in
@@ -505,7 +667,7 @@ func (in *Image) DeepCopyInto(out *Image) {
 	if in.Marketplace != nil {
 		in, out := &in.Marketplace, &out.Marketplace
 		*out = new(AzureMarketplaceImage)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 }
 
@@ -519,6 +681,21 @@ func (in *Image) DeepCopy() *Image {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImagePlan) DeepCopyInto(out *ImagePlan) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImagePlan.
+func (in *ImagePlan) DeepCopy() *ImagePlan {
+	if in == nil {
+		return nil
+	}
+	out := new(ImagePlan)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *IngressRule) DeepCopyInto(out *IngressRule) {
 	*out = *in
@@ -635,6 +812,26 @@ func (in *Network) DeepCopy() *Network {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkInterface) DeepCopyInto(out *NetworkInterface) {
+	*out = *in
+	if in.AcceleratedNetworking != nil {
+		in, out := &in.AcceleratedNetworking, &out.AcceleratedNetworking
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkInterface.
+func (in *NetworkInterface) DeepCopy() *NetworkInterface {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkInterface)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NetworkSpec) DeepCopyInto(out *NetworkSpec) {
 	*out = *in
@@ -650,6 +847,26 @@ func (in *NetworkSpec) DeepCopyInto(out *NetworkSpec) {
 			}
 		}
 	}
+	if in.NodeOutboundLB != nil {
+		in, out := &in.NodeOutboundLB, &out.NodeOutboundLB
+		*out = new(NodeOutboundLBSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.APIServerIP != nil {
+		in, out := &in.APIServerIP, &out.APIServerIP
+		*out = new(PublicIPSpec)
+		**out = **in
+	}
+	if in.APIServerDNS != nil {
+		in, out := &in.APIServerDNS, &out.APIServerDNS
+		*out = new(DNSRecordSpec)
+		**out = **in
+	}
+	if in.APIServerLB != nil {
+		in, out := &in.APIServerLB, &out.APIServerLB
+		*out = new(APIServerLBSpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkSpec.
@@ -662,6 +879,41 @@ func (in *NetworkSpec) DeepCopy() *NetworkSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeOutboundLBSpec) DeepCopyInto(out *NodeOutboundLBSpec) {
+	*out = *in
+	if in.FrontendIPsCount != nil {
+		in, out := &in.FrontendIPsCount, &out.FrontendIPsCount
+		*out = new(int32)
+		**out = **in
+	}
+	if in.AllocatedOutboundPorts != nil {
+		in, out := &in.AllocatedOutboundPorts, &out.AllocatedOutboundPorts
+		*out = new(int32)
+		**out = **in
+	}
+	if in.IdleTimeoutInMinutes != nil {
+		in, out := &in.IdleTimeoutInMinutes, &out.IdleTimeoutInMinutes
+		*out = new(int32)
+		**out = **in
+	}
+	if in.EnableTCPReset != nil {
+		in, out := &in.EnableTCPReset, &out.EnableTCPReset
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeOutboundLBSpec.
+func (in *NodeOutboundLBSpec) DeepCopy() *NodeOutboundLBSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeOutboundLBSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *OSDisk) DeepCopyInto(out *OSDisk) {
 	*out = *in
@@ -678,6 +930,41 @@ func (in *OSDisk) DeepCopy() *OSDisk {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PrivateEndpointSpec) DeepCopyInto(out *PrivateEndpointSpec) {
+	*out = *in
+	if in.GroupIDs != nil {
+		in, out := &in.GroupIDs, &out.GroupIDs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PrivateEndpointSpec.
+func (in *PrivateEndpointSpec) DeepCopy() *PrivateEndpointSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PrivateEndpointSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProximityPlacementGroupSpec) DeepCopyInto(out *ProximityPlacementGroupSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProximityPlacementGroupSpec.
+func (in *ProximityPlacementGroupSpec) DeepCopy() *ProximityPlacementGroupSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ProximityPlacementGroupSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PublicIP) DeepCopyInto(out *PublicIP) {
 	*out = *in
@@ -693,6 +980,21 @@ func (in *PublicIP) DeepCopy() *PublicIP {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PublicIPSpec) DeepCopyInto(out *PublicIPSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PublicIPSpec.
+func (in *PublicIPSpec) DeepCopy() *PublicIPSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PublicIPSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RouteTable) DeepCopyInto(out *RouteTable) {
 	*out = *in
@@ -741,6 +1043,26 @@ func (in *SecurityGroup) DeepCopy() *SecurityGroup {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceEndpointSpec) DeepCopyInto(out *ServiceEndpointSpec) {
+	*out = *in
+	if in.Locations != nil {
+		in, out := &in.Locations, &out.Locations
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceEndpointSpec.
+func (in *ServiceEndpointSpec) DeepCopy() *ServiceEndpointSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceEndpointSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SpotVMOptions) DeepCopyInto(out *SpotVMOptions) {
 	*out = *in
@@ -749,6 +1071,11 @@ func (in *SpotVMOptions) DeepCopyInto(out *SpotVMOptions) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.EvictionPolicy != nil {
+		in, out := &in.EvictionPolicy, &out.EvictionPolicy
+		*out = new(SpotEvictionPolicy)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SpotVMOptions.
@@ -766,6 +1093,20 @@ func (in *SubnetSpec) DeepCopyInto(out *SubnetSpec) {
 	*out = *in
 	in.SecurityGroup.DeepCopyInto(&out.SecurityGroup)
 	out.RouteTable = in.RouteTable
+	if in.ServiceEndpoints != nil {
+		in, out := &in.ServiceEndpoints, &out.ServiceEndpoints
+		*out = make([]ServiceEndpointSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.PrivateEndpoints != nil {
+		in, out := &in.PrivateEndpoints, &out.PrivateEndpoints
+		*out = make([]PrivateEndpointSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SubnetSpec.
@@ -868,6 +1209,26 @@ func (in *VM) DeepCopy() *VM {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VMExtension) DeepCopyInto(out *VMExtension) {
+	*out = *in
+	if in.Settings != nil {
+		in, out := &in.Settings, &out.Settings
+		*out = new(apiextensionsv1.JSON)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VMExtension.
+func (in *VMExtension) DeepCopy() *VMExtension {
+	if in == nil {
+		return nil
+	}
+	out := new(VMExtension)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VnetSpec) DeepCopyInto(out *VnetSpec) {
 	*out = *in
@@ -0,0 +1,60 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azure
+
+import (
+	"github.com/Azure/go-autorest/autorest"
+)
+
+// subscriptionAuthorizer is an Authorizer which targets a different subscription
+// than the Authorizer it wraps, while reusing its credentials and base URI.
+type subscriptionAuthorizer struct {
+	auth           Authorizer
+	subscriptionID string
+}
+
+// SubscriptionID returns the overridden subscription ID.
+func (a *subscriptionAuthorizer) SubscriptionID() string {
+	return a.subscriptionID
+}
+
+// BaseURI returns the base URI of the wrapped Authorizer.
+func (a *subscriptionAuthorizer) BaseURI() string {
+	return a.auth.BaseURI()
+}
+
+// Authorizer returns the autorest Authorizer of the wrapped Authorizer.
+func (a *subscriptionAuthorizer) Authorizer() autorest.Authorizer {
+	return a.auth.Authorizer()
+}
+
+// WithSubscriptionID returns an Authorizer for the given subscription ID using the credentials of auth.
+// If subscriptionID is empty or matches the subscription of auth, auth is returned unchanged.
+func WithSubscriptionID(auth Authorizer, subscriptionID string) Authorizer {
+	if subscriptionID == "" || subscriptionID == auth.SubscriptionID() {
+		return auth
+	}
+	return &subscriptionAuthorizer{
+		auth:           auth,
+		subscriptionID: subscriptionID,
+	}
+}
+
+// VnetAuthorizer returns an Authorizer targeting the subscription of the cluster virtual network.
+func VnetAuthorizer(scope ClusterDescriber) Authorizer {
+	return WithSubscriptionID(scope, scope.Vnet().SubscriptionID)
+}
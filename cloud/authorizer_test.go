@@ -0,0 +1,68 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azure
+
+import (
+	"testing"
+
+	"github.com/Azure/go-autorest/autorest"
+	. "github.com/onsi/gomega"
+)
+
+type fakeAuthorizer struct{}
+
+func (fakeAuthorizer) SubscriptionID() string          { return "cluster-sub" }
+func (fakeAuthorizer) BaseURI() string                 { return "https://management.azure.com/" }
+func (fakeAuthorizer) Authorizer() autorest.Authorizer { return autorest.NullAuthorizer{} }
+
+func TestWithSubscriptionID(t *testing.T) {
+	g := NewWithT(t)
+
+	var tests = []struct {
+		name           string
+		subscriptionID string
+		expectedResult string
+		expectWrapped  bool
+	}{
+		{
+			name:           "empty subscription uses cluster subscription",
+			subscriptionID: "",
+			expectedResult: "cluster-sub",
+			expectWrapped:  false,
+		},
+		{
+			name:           "same subscription uses cluster subscription",
+			subscriptionID: "cluster-sub",
+			expectedResult: "cluster-sub",
+			expectWrapped:  false,
+		},
+		{
+			name:           "different subscription overrides subscription",
+			subscriptionID: "vnet-sub",
+			expectedResult: "vnet-sub",
+			expectWrapped:  true,
+		},
+	}
+
+	for _, tc := range tests {
+		auth := WithSubscriptionID(fakeAuthorizer{}, tc.subscriptionID)
+		g.Expect(auth.SubscriptionID()).To(Equal(tc.expectedResult), tc.name)
+		g.Expect(auth.BaseURI()).To(Equal("https://management.azure.com/"), tc.name)
+		_, wrapped := auth.(*subscriptionAuthorizer)
+		g.Expect(wrapped).To(Equal(tc.expectWrapped), tc.name)
+	}
+}
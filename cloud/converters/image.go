@@ -18,8 +18,10 @@ package converters
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2019-12-01/compute"
+	"github.com/Azure/go-autorest/autorest/to"
 	"github.com/pkg/errors"
 
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha3"
@@ -42,6 +44,19 @@ func ImageToSDK(image *infrav1.Image) (*compute.ImageReference, error) {
 
 }
 
+// ImageToPlan converts the purchase plan of a CAPZ marketplace image to an Azure SDK Plan, or returns nil if the image
+// doesn't have one.
+func ImageToPlan(image *infrav1.Image) *compute.Plan {
+	if image == nil || image.Marketplace == nil || image.Marketplace.Plan == nil {
+		return nil
+	}
+	return &compute.Plan{
+		Publisher: to.StringPtr(image.Marketplace.Plan.Publisher),
+		Product:   to.StringPtr(image.Marketplace.Plan.Product),
+		Name:      to.StringPtr(image.Marketplace.Plan.Name),
+	}
+}
+
 func mpImageToSDK(image *infrav1.Image) (*compute.ImageReference, error) {
 	return &compute.ImageReference{
 		Publisher: &image.Marketplace.Publisher,
@@ -70,3 +85,60 @@ func specificImageToSDK(image *infrav1.Image) (*compute.ImageReference, error) {
 		ID: image.ID,
 	}, nil
 }
+
+// SDKToImage converts the image reference and purchase plan of an Azure VM or scale set to a CAPZ Image. The version
+// of a marketplace or shared gallery image is the exact version the VM was created from, even if it referred to the
+// latest version.
+func SDKToImage(ref *compute.ImageReference, plan *compute.Plan) infrav1.Image {
+	if ref == nil {
+		return infrav1.Image{}
+	}
+	if ref.ID != nil {
+		if gallery := sdkToSharedGalleryImage(to.String(ref.ID)); gallery != nil {
+			if ref.ExactVersion != nil {
+				gallery.Version = to.String(ref.ExactVersion)
+			}
+			return infrav1.Image{SharedGallery: gallery}
+		}
+		return infrav1.Image{ID: ref.ID}
+	}
+
+	version := to.String(ref.Version)
+	if ref.ExactVersion != nil {
+		version = to.String(ref.ExactVersion)
+	}
+	image := infrav1.Image{
+		Marketplace: &infrav1.AzureMarketplaceImage{
+			Publisher: to.String(ref.Publisher),
+			Offer:     to.String(ref.Offer),
+			SKU:       to.String(ref.Sku),
+			Version:   version,
+		},
+	}
+	if plan != nil {
+		image.Marketplace.Plan = &infrav1.ImagePlan{
+			Publisher: to.String(plan.Publisher),
+			Product:   to.String(plan.Product),
+			Name:      to.String(plan.Name),
+		}
+	}
+	return image
+}
+
+// sdkToSharedGalleryImage parses the ID of a shared gallery image version, or returns nil if the ID refers to
+// another kind of image.
+func sdkToSharedGalleryImage(id string) *infrav1.AzureSharedGalleryImage {
+	// /subscriptions/{sub}/resourceGroups/{rg}/providers/Microsoft.Compute/galleries/{gallery}/images/{name}/versions/{version}
+	parts := strings.Split(strings.Trim(id, "/"), "/")
+	if len(parts) != 12 || !strings.EqualFold(parts[0], "subscriptions") || !strings.EqualFold(parts[2], "resourceGroups") ||
+		!strings.EqualFold(parts[6], "galleries") || !strings.EqualFold(parts[8], "images") || !strings.EqualFold(parts[10], "versions") {
+		return nil
+	}
+	return &infrav1.AzureSharedGalleryImage{
+		SubscriptionID: parts[1],
+		ResourceGroup:  parts[3],
+		Gallery:        parts[7],
+		Name:           parts[9],
+		Version:        parts[11],
+	}
+}
@@ -0,0 +1,150 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package converters_test
+
+import (
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2019-12-01/compute"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/onsi/gomega"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha3"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/converters"
+)
+
+func Test_ImageToPlan(t *testing.T) {
+	cases := []struct {
+		Name   string
+		Image  *infrav1.Image
+		Expect *compute.Plan
+	}{
+		{
+			Name: "ShouldConvertMarketplaceImagePlan",
+			Image: &infrav1.Image{
+				Marketplace: &infrav1.AzureMarketplaceImage{
+					Publisher: "my-publisher",
+					Offer:     "my-offer",
+					SKU:       "my-sku",
+					Version:   "1.0.0",
+					Plan: &infrav1.ImagePlan{
+						Publisher: "my-publisher",
+						Product:   "my-offer",
+						Name:      "my-plan",
+					},
+				},
+			},
+			Expect: &compute.Plan{
+				Publisher: to.StringPtr("my-publisher"),
+				Product:   to.StringPtr("my-offer"),
+				Name:      to.StringPtr("my-plan"),
+			},
+		},
+		{
+			Name: "ShouldNotSetPlanWithoutMarketplaceImagePlan",
+			Image: &infrav1.Image{
+				Marketplace: &infrav1.AzureMarketplaceImage{
+					Publisher: "my-publisher",
+					Offer:     "my-offer",
+					SKU:       "my-sku",
+					Version:   "1.0.0",
+				},
+			},
+		},
+		{
+			Name:  "ShouldNotSetPlanForImageID",
+			Image: &infrav1.Image{ID: to.StringPtr("my-image-id")},
+		},
+	}
+
+	for _, c := range cases {
+		c := c
+		t.Run(c.Name, func(t *testing.T) {
+			g := gomega.NewGomegaWithT(t)
+			g.Expect(converters.ImageToPlan(c.Image)).To(gomega.Equal(c.Expect))
+		})
+	}
+}
+
+func Test_SDKToImage(t *testing.T) {
+	cases := []struct {
+		Name   string
+		Ref    *compute.ImageReference
+		Plan   *compute.Plan
+		Expect infrav1.Image
+	}{
+		{
+			Name: "ShouldUseExactVersionOfMarketplaceImage",
+			Ref: &compute.ImageReference{
+				Publisher:    to.StringPtr("my-publisher"),
+				Offer:        to.StringPtr("my-offer"),
+				Sku:          to.StringPtr("my-sku"),
+				Version:      to.StringPtr("latest"),
+				ExactVersion: to.StringPtr("1.1.0"),
+			},
+			Plan: &compute.Plan{
+				Publisher: to.StringPtr("my-publisher"),
+				Product:   to.StringPtr("my-offer"),
+				Name:      to.StringPtr("my-plan"),
+			},
+			Expect: infrav1.Image{
+				Marketplace: &infrav1.AzureMarketplaceImage{
+					Publisher: "my-publisher",
+					Offer:     "my-offer",
+					SKU:       "my-sku",
+					Version:   "1.1.0",
+					Plan: &infrav1.ImagePlan{
+						Publisher: "my-publisher",
+						Product:   "my-offer",
+						Name:      "my-plan",
+					},
+				},
+			},
+		},
+		{
+			Name: "ShouldParseSharedGalleryImageID",
+			Ref: &compute.ImageReference{
+				ID: to.StringPtr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/galleries/my-gallery/images/my-image/versions/1.0.0"),
+			},
+			Expect: infrav1.Image{
+				SharedGallery: &infrav1.AzureSharedGalleryImage{
+					SubscriptionID: "123",
+					ResourceGroup:  "my-rg",
+					Gallery:        "my-gallery",
+					Name:           "my-image",
+					Version:        "1.0.0",
+				},
+			},
+		},
+		{
+			Name:   "ShouldKeepImageID",
+			Ref:    &compute.ImageReference{ID: to.StringPtr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/images/my-image")},
+			Expect: infrav1.Image{ID: to.StringPtr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/images/my-image")},
+		},
+		{
+			Name: "ShouldReturnEmptyImageWithoutReference",
+		},
+	}
+
+	for _, c := range cases {
+		c := c
+		t.Run(c.Name, func(t *testing.T) {
+			g := gomega.NewGomegaWithT(t)
+			g.Expect(converters.SDKToImage(c.Ref, c.Plan)).To(gomega.Equal(c.Expect))
+		})
+	}
+}
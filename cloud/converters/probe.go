@@ -0,0 +1,44 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package converters
+
+import (
+	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
+	"github.com/Azure/go-autorest/autorest/to"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha3"
+)
+
+// HealthProbeToSDK converts an infrav1.HealthProbeSpec into a probe of a load balancer with the given SKU on the given port,
+// defaulting its unset fields the same way as the AzureCluster webhook.
+func HealthProbeToSDK(name string, port int32, sku infrav1.SKU, probe infrav1.HealthProbeSpec) network.Probe {
+	probe.SetDefaults(sku)
+	props := &network.ProbePropertiesFormat{
+		Protocol:          network.ProbeProtocolTCP,
+		Port:              to.Int32Ptr(port),
+		IntervalInSeconds: probe.IntervalInSeconds,
+		NumberOfProbes:    probe.NumberOfProbes,
+	}
+	if probe.Protocol == infrav1.HealthProbeProtocolHTTPS {
+		props.Protocol = network.ProbeProtocolHTTPS
+		props.RequestPath = to.StringPtr(probe.RequestPath)
+	}
+
+	return network.Probe{
+		Name:                  to.StringPtr(name),
+		ProbePropertiesFormat: props,
+	}
+}
@@ -0,0 +1,121 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package converters_test
+
+import (
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/onsi/gomega"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha3"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/converters"
+)
+
+func Test_HealthProbeToSDK(t *testing.T) {
+	cases := []struct {
+		Name   string
+		SKU    infrav1.SKU
+		Probe  infrav1.HealthProbeSpec
+		Expect network.Probe
+	}{
+		{
+			Name:  "ShouldDefaultTCPProbe",
+			Probe: infrav1.HealthProbeSpec{Protocol: infrav1.HealthProbeProtocolTCP},
+			Expect: network.Probe{
+				Name: to.StringPtr("probe"),
+				ProbePropertiesFormat: &network.ProbePropertiesFormat{
+					Protocol:          network.ProbeProtocolTCP,
+					Port:              to.Int32Ptr(6443),
+					IntervalInSeconds: to.Int32Ptr(15),
+					NumberOfProbes:    to.Int32Ptr(4),
+				},
+			},
+		},
+		{
+			Name: "ShouldPopulateHTTPSProbe",
+			Probe: infrav1.HealthProbeSpec{
+				Protocol:          infrav1.HealthProbeProtocolHTTPS,
+				RequestPath:       "/healthz",
+				IntervalInSeconds: to.Int32Ptr(5),
+				NumberOfProbes:    to.Int32Ptr(2),
+			},
+			Expect: network.Probe{
+				Name: to.StringPtr("probe"),
+				ProbePropertiesFormat: &network.ProbePropertiesFormat{
+					Protocol:          network.ProbeProtocolHTTPS,
+					RequestPath:       to.StringPtr("/healthz"),
+					Port:              to.Int32Ptr(6443),
+					IntervalInSeconds: to.Int32Ptr(5),
+					NumberOfProbes:    to.Int32Ptr(2),
+				},
+			},
+		},
+		{
+			Name:  "ShouldDefaultHTTPSRequestPath",
+			Probe: infrav1.HealthProbeSpec{Protocol: infrav1.HealthProbeProtocolHTTPS},
+			Expect: network.Probe{
+				Name: to.StringPtr("probe"),
+				ProbePropertiesFormat: &network.ProbePropertiesFormat{
+					Protocol:          network.ProbeProtocolHTTPS,
+					RequestPath:       to.StringPtr("/readyz"),
+					Port:              to.Int32Ptr(6443),
+					IntervalInSeconds: to.Int32Ptr(15),
+					NumberOfProbes:    to.Int32Ptr(4),
+				},
+			},
+		},
+		{
+			Name:  "ShouldDefaultToHTTPSProbe",
+			SKU:   infrav1.SKUStandard,
+			Probe: infrav1.HealthProbeSpec{},
+			Expect: network.Probe{
+				Name: to.StringPtr("probe"),
+				ProbePropertiesFormat: &network.ProbePropertiesFormat{
+					Protocol:          network.ProbeProtocolHTTPS,
+					RequestPath:       to.StringPtr("/readyz"),
+					Port:              to.Int32Ptr(6443),
+					IntervalInSeconds: to.Int32Ptr(15),
+					NumberOfProbes:    to.Int32Ptr(4),
+				},
+			},
+		},
+		{
+			Name:  "ShouldDefaultToTCPProbeWithBasicSKU",
+			SKU:   infrav1.SKUBasic,
+			Probe: infrav1.HealthProbeSpec{},
+			Expect: network.Probe{
+				Name: to.StringPtr("probe"),
+				ProbePropertiesFormat: &network.ProbePropertiesFormat{
+					Protocol:          network.ProbeProtocolTCP,
+					Port:              to.Int32Ptr(6443),
+					IntervalInSeconds: to.Int32Ptr(15),
+					NumberOfProbes:    to.Int32Ptr(4),
+				},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		c := c
+		t.Run(c.Name, func(t *testing.T) {
+			g := gomega.NewGomegaWithT(t)
+			g.Expect(converters.HealthProbeToSDK("probe", 6443, c.SKU, c.Probe)).To(gomega.Equal(c.Expect))
+		})
+	}
+}
@@ -17,6 +17,8 @@ limitations under the License.
 package converters
 
 import (
+	"strings"
+
 	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2019-12-01/compute"
 	"github.com/Azure/go-autorest/autorest/to"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha3"
@@ -34,6 +36,14 @@ func SDKToVM(v compute.VirtualMachine) (*infrav1.VM, error) {
 		vm.VMSize = string(v.VirtualMachineProperties.HardwareProfile.VMSize)
 	}
 
+	if v.VirtualMachineProperties != nil && v.VirtualMachineProperties.Host != nil {
+		vm.HostID = to.String(v.VirtualMachineProperties.Host.ID)
+	}
+
+	if v.VirtualMachineProperties != nil && v.VirtualMachineProperties.StorageProfile != nil {
+		vm.Image = SDKToImage(v.VirtualMachineProperties.StorageProfile.ImageReference, v.Plan)
+	}
+
 	if v.Zones != nil && len(*v.Zones) > 0 {
 		vm.AvailabilityZone = to.StringSlice(v.Zones)[0]
 	}
@@ -44,3 +54,48 @@ func SDKToVM(v compute.VirtualMachine) (*infrav1.VM, error) {
 
 	return vm, nil
 }
+
+// powerStatePrefix is the prefix of the instance view status code holding the power state of a VM.
+const powerStatePrefix = "PowerState/"
+
+// GetPowerState returns the power state of a VM or scale set instance from the statuses of its instance view, e.g.
+// running or deallocated, or an empty string if it is unknown.
+func GetPowerState(statuses *[]compute.InstanceViewStatus) string {
+	if statuses == nil {
+		return ""
+	}
+	for _, status := range *statuses {
+		if code := to.String(status.Code); strings.HasPrefix(code, powerStatePrefix) {
+			return strings.TrimPrefix(code, powerStatePrefix)
+		}
+	}
+	return ""
+}
+
+// provisioningStatePrefix is the prefix of the instance view status code holding the provisioning state of a resource.
+const provisioningStatePrefix = "ProvisioningState/"
+
+// GetExtensionStates returns the provisioning state of each VM extension from the instance views of the extensions,
+// keyed by extension name. Extensions without a reported provisioning state are left out.
+func GetExtensionStates(extensions *[]compute.VirtualMachineExtensionInstanceView) map[string]infrav1.VMState {
+	if extensions == nil {
+		return nil
+	}
+	states := make(map[string]infrav1.VMState)
+	for _, extension := range *extensions {
+		if extension.Statuses == nil {
+			continue
+		}
+		for _, status := range *extension.Statuses {
+			code := to.String(status.Code)
+			if !strings.HasPrefix(code, provisioningStatePrefix) {
+				continue
+			}
+			// codes may carry a sub status, e.g. ProvisioningState/failed/1
+			state := strings.SplitN(strings.TrimPrefix(code, provisioningStatePrefix), "/", 2)[0]
+			states[to.String(extension.Name)] = infrav1.VMState(strings.Title(state))
+			break
+		}
+	}
+	return states
+}
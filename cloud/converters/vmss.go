@@ -37,6 +37,10 @@ func SDKToVMSS(sdkvmss compute.VirtualMachineScaleSet, sdkinstances []compute.Vi
 		vmss.Capacity = to.Int64(sdkvmss.Sku.Capacity)
 	}
 
+	if sdkvmss.VirtualMachineScaleSetProperties != nil && sdkvmss.VirtualMachineProfile != nil && sdkvmss.VirtualMachineProfile.StorageProfile != nil {
+		vmss.Image = SDKToImage(sdkvmss.VirtualMachineProfile.StorageProfile.ImageReference, sdkvmss.Plan)
+	}
+
 	if sdkvmss.Zones != nil && len(*sdkvmss.Zones) > 0 {
 		vmss.Zones = to.StringSlice(sdkvmss.Zones)
 	}
@@ -55,6 +59,11 @@ func SDKToVMSS(sdkvmss compute.VirtualMachineScaleSet, sdkinstances []compute.Vi
 				State:      infrav1.VMState(to.String(vm.ProvisioningState)),
 			}
 
+			if vm.InstanceView != nil {
+				instance.PowerState = GetPowerState(vm.InstanceView.Statuses)
+				instance.ExtensionStates = GetExtensionStates(vm.InstanceView.Extensions)
+			}
+
 			if vm.Zones != nil && len(*vm.Zones) > 0 {
 				instance.AvailabilityZone = to.StringSlice(vm.Zones)[0]
 			}
@@ -24,6 +24,7 @@ import (
 	"github.com/Azure/go-autorest/autorest/to"
 	"github.com/onsi/gomega"
 
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha3"
 	"sigs.k8s.io/cluster-api-provider-azure/cloud/converters"
 	infrav1exp "sigs.k8s.io/cluster-api-provider-azure/exp/api/v1alpha3"
 )
@@ -64,6 +65,20 @@ func Test_SDKToVMSS(t *testing.T) {
 							Zones:      to.StringSlicePtr([]string{"zone0"}),
 							VirtualMachineScaleSetVMProperties: &compute.VirtualMachineScaleSetVMProperties{
 								ProvisioningState: to.StringPtr(string(compute.ProvisioningState1Succeeded)),
+								InstanceView: &compute.VirtualMachineScaleSetVMInstanceView{
+									Statuses: &[]compute.InstanceViewStatus{
+										{Code: to.StringPtr("ProvisioningState/succeeded")},
+										{Code: to.StringPtr("PowerState/running")},
+									},
+									Extensions: &[]compute.VirtualMachineExtensionInstanceView{
+										{
+											Name: to.StringPtr("my-script"),
+											Statuses: &[]compute.InstanceViewStatus{
+												{Code: to.StringPtr("ProvisioningState/failed/1")},
+											},
+										},
+									},
+								},
 							},
 						},
 						{
@@ -73,6 +88,12 @@ func Test_SDKToVMSS(t *testing.T) {
 							Zones:      to.StringSlicePtr([]string{"zone1"}),
 							VirtualMachineScaleSetVMProperties: &compute.VirtualMachineScaleSetVMProperties{
 								ProvisioningState: to.StringPtr(string(compute.ProvisioningState1Succeeded)),
+								InstanceView: &compute.VirtualMachineScaleSetVMInstanceView{
+									Statuses: &[]compute.InstanceViewStatus{
+										{Code: to.StringPtr("ProvisioningState/succeeded")},
+										{Code: to.StringPtr("PowerState/deallocated")},
+									},
+								},
 							},
 						},
 					}
@@ -91,6 +112,7 @@ func Test_SDKToVMSS(t *testing.T) {
 					Instances: make([]infrav1exp.VMSSVM, 2),
 				}
 
+				powerStates := []string{"running", "deallocated"}
 				for i := 0; i < 2; i++ {
 					expected.Instances[i] = infrav1exp.VMSSVM{
 						ID:               fmt.Sprintf("vm/%d", i),
@@ -98,8 +120,10 @@ func Test_SDKToVMSS(t *testing.T) {
 						Name:             fmt.Sprintf("vm%d", i),
 						AvailabilityZone: fmt.Sprintf("zone%d", i),
 						State:            "Succeeded",
+						PowerState:       powerStates[i],
 					}
 				}
+				expected.Instances[0].ExtensionStates = map[string]infrav1.VMState{"my-script": infrav1.VMStateFailed}
 				g.Expect(actual).To(gomega.Equal(&expected))
 			},
 		},
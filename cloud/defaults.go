@@ -17,6 +17,7 @@ limitations under the License.
 package azure
 
 import (
+	"crypto/sha256"
 	"fmt"
 
 	"github.com/blang/semver"
@@ -28,8 +29,6 @@ import (
 const (
 	// DefaultUserName is the default username for created vm
 	DefaultUserName = "capi"
-	// DefaultInternalLBIPAddress is the default internal load balancer ip address
-	DefaultInternalLBIPAddress = "10.0.0.100"
 )
 
 const (
@@ -41,6 +40,36 @@ const (
 	LatestVersion = "latest"
 )
 
+const (
+	// BootstrapExtensionName is the name of the VM extension reporting the bootstrap result of a Linux VM.
+	BootstrapExtensionName = infrav1.BootstrapExtensionName
+	// BootstrapSentinelFile is the file written by the bootstrap provider once a node successfully bootstrapped.
+	BootstrapSentinelFile = "/run/cluster-api/bootstrap-success.complete"
+	// bootstrapExtensionCommand waits up to 20 minutes for the bootstrap sentinel file, and fails with the tail of the
+	// cloud-init output if it doesn't show up or cloud-init fails first.
+	bootstrapExtensionCommand = "for i in $(seq 1 240); do " +
+		"test -f " + BootstrapSentinelFile + " && exit 0; " +
+		"cloud-init status 2>/dev/null | grep -q 'status: error' && break; " +
+		"sleep 5; done; " +
+		"echo 'bootstrap sentinel file " + BootstrapSentinelFile + " not found' >&2; " +
+		"tail -n 20 /var/log/cloud-init-output.log >&2; exit 1"
+)
+
+// GetBootstrapExtension returns the spec of the custom script extension waiting for the bootstrap sentinel file of a
+// Linux VM.
+func GetBootstrapExtension(vmName string) VMExtensionSpec {
+	return VMExtensionSpec{
+		Name:      BootstrapExtensionName,
+		VMName:    vmName,
+		Publisher: "Microsoft.Azure.Extensions",
+		Type:      "CustomScript",
+		Version:   "2.1",
+		Settings: map[string]interface{}{
+			"commandToExecute": bootstrapExtensionCommand,
+		},
+	}
+}
+
 // SupportedAvailabilityZoneLocations is a slice of the locations where Availability Zones are supported.
 // This is used to validate whether a virtual machine should leverage an Availability Zone.
 // Based on the Availability Zones listed in https://docs.microsoft.com/en-us/azure/availability-zones/az-overview
@@ -67,6 +96,14 @@ func GenerateInternalLBName(clusterName string) string {
 	return fmt.Sprintf("%s-%s", clusterName, "internal-lb")
 }
 
+// GenerateBootDiagnosticsStorageAccountName generates the name of the boot diagnostics storage account of a cluster.
+// Storage account names are globally unique and made of at most 24 lower case letters and digits, so the name ends
+// with a hash of the subscription, resource group and cluster name.
+func GenerateBootDiagnosticsStorageAccountName(subscriptionID, resourceGroup, clusterName string) string {
+	hash := sha256.Sum256([]byte(fmt.Sprintf("%s/%s/%s", subscriptionID, resourceGroup, clusterName)))
+	return fmt.Sprintf("capzdiag%x", hash[:8])
+}
+
 // GeneratePublicLBName generates a public load balancer name, based on the cluster name.
 func GeneratePublicLBName(clusterName string) string {
 	return fmt.Sprintf("%s-%s", clusterName, "public-lb")
@@ -82,6 +119,11 @@ func GenerateNodeOutboundIPName(clusterName string) string {
 	return fmt.Sprintf("pip-%s-node-outbound", clusterName)
 }
 
+// GenerateAdditionalNodeOutboundIPName generates the name of an additional node outbound public IP, based on the cluster name and the IP index.
+func GenerateAdditionalNodeOutboundIPName(clusterName string, index int) string {
+	return fmt.Sprintf("%s-%d", GenerateNodeOutboundIPName(clusterName), index)
+}
+
 // GenerateNodePublicIPName generates a node public IP name, based on the NIC name.
 func GenerateNodePublicIPName(nicName string) string {
 	return fmt.Sprintf("%s-public-ip", nicName)
@@ -92,11 +134,51 @@ func GenerateNICName(machineName string) string {
 	return fmt.Sprintf("%s-nic", machineName)
 }
 
+// GenerateSecondaryNICName generates the name of an additional network interface based on the name of a VM and the interface index.
+func GenerateSecondaryNICName(machineName string, index int) string {
+	return fmt.Sprintf("%s-nic-%d", machineName, index)
+}
+
+// GenerateApplicationSecurityGroupName generates the name of an application security group, based on the cluster name and a machine role.
+func GenerateApplicationSecurityGroupName(clusterName, role string) string {
+	return fmt.Sprintf("%s-%s-asg", clusterName, role)
+}
+
+// GenerateVnetLinkName generates the name of the link between a private DNS zone and the cluster virtual network, based on the cluster name.
+func GenerateVnetLinkName(clusterName string) string {
+	return fmt.Sprintf("%s-vnet-link", clusterName)
+}
+
 // GenerateOSDiskName generates the name of an OS disk based on the name of a VM.
 func GenerateOSDiskName(machineName string) string {
 	return fmt.Sprintf("%s_OSDisk", machineName)
 }
 
+// GenerateProximityPlacementGroupName generates the name of the proximity placement group of a cluster, based on the cluster name.
+func GenerateProximityPlacementGroupName(clusterName string) string {
+	return fmt.Sprintf("%s-ppg", clusterName)
+}
+
+// ProximityPlacementGroupID returns the azure resource ID for a given proximity placement group.
+func ProximityPlacementGroupID(subscriptionID, resourceGroup, ppgName string) string {
+	return fmt.Sprintf("/subscriptions/%s/resourceGroups/%s/providers/Microsoft.Compute/proximityPlacementGroups/%s", subscriptionID, resourceGroup, ppgName)
+}
+
+// VnetID returns the azure resource ID for a given virtual network.
+func VnetID(subscriptionID, resourceGroup, vnetName string) string {
+	return fmt.Sprintf("/subscriptions/%s/resourceGroups/%s/providers/Microsoft.Network/virtualNetworks/%s", subscriptionID, resourceGroup, vnetName)
+}
+
+// SubnetID returns the azure resource ID for a given subnet.
+func SubnetID(subscriptionID, resourceGroup, vnetName, subnetName string) string {
+	return fmt.Sprintf("/subscriptions/%s/resourceGroups/%s/providers/Microsoft.Network/virtualNetworks/%s/subnets/%s", subscriptionID, resourceGroup, vnetName, subnetName)
+}
+
+// ApplicationSecurityGroupID returns the azure resource ID for a given application security group.
+func ApplicationSecurityGroupID(subscriptionID, resourceGroup, asgName string) string {
+	return fmt.Sprintf("/subscriptions/%s/resourceGroups/%s/providers/Microsoft.Network/applicationSecurityGroups/%s", subscriptionID, resourceGroup, asgName)
+}
+
 // GetDefaultImageSKUID gets the SKU ID of the image to use for the provided version of Kubernetes.
 func getDefaultImageSKUID(k8sVersion string) (string, error) {
 	version, err := semver.ParseTolerant(k8sVersion)
@@ -85,3 +85,12 @@ func TestGetDefaultImageSKUID(t *testing.T) {
 		})
 	}
 }
+
+func TestGenerateBootDiagnosticsStorageAccountName(t *testing.T) {
+	g := NewWithT(t)
+
+	name := GenerateBootDiagnosticsStorageAccountName("123", "my-rg", "my-cluster")
+	g.Expect(name).To(MatchRegexp("^capzdiag[0-9a-f]{16}$"))
+	g.Expect(GenerateBootDiagnosticsStorageAccountName("123", "my-rg", "my-cluster")).To(Equal(name))
+	g.Expect(GenerateBootDiagnosticsStorageAccountName("123", "my-rg", "other-cluster")).NotTo(Equal(name))
+}
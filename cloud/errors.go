@@ -20,6 +20,7 @@ import (
 	"errors"
 
 	"github.com/Azure/go-autorest/autorest"
+	capierrors "sigs.k8s.io/cluster-api/errors"
 )
 
 // ResourceNotFound parses the error to check if it's a resource not found
@@ -27,3 +28,26 @@ func ResourceNotFound(err error) bool {
 	derr := autorest.DetailedError{}
 	return errors.As(err, &derr) && derr.StatusCode == 404
 }
+
+// TerminalError is an error that retrying the reconciliation won't fix, e.g. a configuration that Azure rejects.
+type TerminalError struct {
+	Reason capierrors.MachineStatusError
+	error
+}
+
+// NewTerminalError wraps an error that retrying the reconciliation won't fix.
+func NewTerminalError(reason capierrors.MachineStatusError, err error) error {
+	return TerminalError{Reason: reason, error: err}
+}
+
+// Unwrap returns the wrapped error.
+func (t TerminalError) Unwrap() error {
+	return t.error
+}
+
+// IsTerminalError returns the terminal error found in the chain of the error, if any.
+func IsTerminalError(err error) (TerminalError, bool) {
+	var terr TerminalError
+	ok := errors.As(err, &terr)
+	return terr, ok
+}
@@ -38,6 +38,8 @@ const (
 // AzureClients contains all the Azure clients used by the scopes.
 type AzureClients struct {
 	SubscriptionID             string
+	TenantID                   string
+	ClientID                   string
 	ResourceManagerEndpoint    string
 	ResourceManagerVMDNSSuffix string
 	Authorizer                 autorest.Authorizer
@@ -53,6 +55,8 @@ func (c *AzureClients) setCredentials(subscriptionID string) error {
 	if err != nil {
 		return err
 	}
+	c.TenantID = settings.Values[auth.TenantID]
+	c.ClientID = settings.Values[auth.ClientID]
 	c.ResourceManagerEndpoint = settings.Environment.ResourceManagerEndpoint
 	c.ResourceManagerVMDNSSuffix = GetAzureDNSZoneForEnvironment(settings.Environment.Name)
 	settings.Values[auth.SubscriptionID] = subscriptionID
@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"github.com/Azure/go-autorest/autorest"
+	autorestazure "github.com/Azure/go-autorest/autorest/azure"
 	"github.com/go-logr/logr"
 	"github.com/pkg/errors"
 	"k8s.io/klog/klogr"
@@ -89,6 +90,16 @@ func (s *ClusterScope) SubscriptionID() string {
 	return s.AzureClients.SubscriptionID
 }
 
+// TenantID returns the Azure AD tenant of the identity of the Azure client.
+func (s *ClusterScope) TenantID() string {
+	return s.AzureClients.TenantID
+}
+
+// ClientID returns the client ID of the identity of the Azure client.
+func (s *ClusterScope) ClientID() string {
+	return s.AzureClients.ClientID
+}
+
 // BaseURI returns the Azure ResourceManagerEndpoint.
 func (s *ClusterScope) BaseURI() string {
 	return s.ResourceManagerEndpoint
@@ -106,13 +117,160 @@ func (s *ClusterScope) Network() *infrav1.Network {
 
 // PublicIPSpec returns the public IP specs.
 func (s *ClusterScope) PublicIPSpecs() []azure.PublicIPSpec {
-	return []azure.PublicIPSpec{
+	specs := make([]azure.PublicIPSpec, 0)
+	for _, name := range s.NodeOutboundIPNames() {
+		specs = append(specs, azure.PublicIPSpec{
+			Name: name,
+			SKU:  s.LoadBalancerSKU(),
+		})
+	}
+	if s.APIServerIPSpec().ID != "" {
+		// a pre-existing API server public IP is adopted, never created or deleted
+		return specs
+	}
+	return append(specs, azure.PublicIPSpec{
+		Name:     s.Network().APIServerIP.Name,
+		DNSName:  s.Network().APIServerIP.DNSName,
+		DNSLabel: s.APIServerIPSpec().DNSLabel,
+		SKU:      s.LoadBalancerSKU(),
+	})
+}
+
+// DNSRecordSpecs returns the specification of the API server DNS record, if any.
+func (s *ClusterScope) DNSRecordSpecs() []azure.DNSRecordSpec {
+	record := s.AzureCluster.Spec.NetworkSpec.APIServerDNS
+	if record == nil {
+		return nil
+	}
+	spec := azure.DNSRecordSpec{
+		ZoneID:                record.ZoneID,
+		RecordName:            record.RecordName,
+		PublicIPName:          s.Network().APIServerIP.Name,
+		PublicIPResourceGroup: s.APIServerIPResourceGroup(),
+		InternalLBName:        azure.GenerateInternalLBName(s.ClusterName()),
+	}
+	if record.CreateVnetLink {
+		spec.VnetLinkName = azure.GenerateVnetLinkName(s.ClusterName())
+		spec.VnetID = azure.VnetID(azure.VnetAuthorizer(s).SubscriptionID(), s.Vnet().ResourceGroup, s.Vnet().Name)
+	}
+	return []azure.DNSRecordSpec{spec}
+}
+
+// APIServerHost returns the host name of the API server, which is the name of its DNS record
+// if a DNS zone is configured and the DNS name of its public IP otherwise.
+func (s *ClusterScope) APIServerHost() string {
+	record := s.AzureCluster.Spec.NetworkSpec.APIServerDNS
+	if record == nil {
+		return s.Network().APIServerIP.DNSName
+	}
+	zone, err := autorestazure.ParseResourceID(record.ZoneID)
+	if err != nil {
+		return s.Network().APIServerIP.DNSName
+	}
+	return fmt.Sprintf("%s.%s", record.RecordName, zone.ResourceName)
+}
+
+// APIServerIPResourceGroup returns the resource group of the API server public IP, which is the
+// cluster resource group unless a pre-existing public IP is used.
+func (s *ClusterScope) APIServerIPResourceGroup() string {
+	if id := s.APIServerIPSpec().ID; id != "" {
+		if res, err := autorestazure.ParseResourceID(id); err == nil {
+			return res.ResourceGroup
+		}
+	}
+	return s.ResourceGroup()
+}
+
+// APIServerIPSpec returns the configuration of the API server public IP.
+func (s *ClusterScope) APIServerIPSpec() infrav1.PublicIPSpec {
+	if s.AzureCluster.Spec.NetworkSpec.APIServerIP == nil {
+		return infrav1.PublicIPSpec{}
+	}
+	return *s.AzureCluster.Spec.NetworkSpec.APIServerIP
+}
+
+// NodeOutboundLB returns the configuration of the node outbound load balancer.
+func (s *ClusterScope) NodeOutboundLB() infrav1.NodeOutboundLBSpec {
+	if s.AzureCluster.Spec.NetworkSpec.NodeOutboundLB == nil {
+		return infrav1.NodeOutboundLBSpec{}
+	}
+	return *s.AzureCluster.Spec.NetworkSpec.NodeOutboundLB
+}
+
+// LoadBalancerSKU returns the SKU of the load balancers of the cluster and of their public IPs.
+func (s *ClusterScope) LoadBalancerSKU() infrav1.SKU {
+	return s.AzureCluster.Spec.NetworkSpec.GetLoadBalancerSKU()
+}
+
+// APIServerHealthProbe returns the configuration of the health probe of the API server load balancers,
+// or nil if the load balancers should use their default probe.
+func (s *ClusterScope) APIServerHealthProbe() *infrav1.HealthProbeSpec {
+	if s.AzureCluster.Spec.NetworkSpec.APIServerLB == nil {
+		return nil
+	}
+	return s.AzureCluster.Spec.NetworkSpec.APIServerLB.HealthProbe
+}
+
+// NodeOutboundLBEnabled returns true if the node outbound load balancer should exist.
+func (s *ClusterScope) NodeOutboundLBEnabled() bool {
+	return !s.NodeOutboundLB().Disabled && s.OutboundType() != infrav1.OutboundTypeUserDefinedRouting
+}
+
+// OutboundType returns how the machines of the cluster reach the internet.
+func (s *ClusterScope) OutboundType() infrav1.OutboundType {
+	return s.AzureCluster.Spec.NetworkSpec.GetOutboundType()
+}
+
+// NodeOutboundIPNames returns the names of the public IPs of the node outbound load balancer.
+// No public IP is needed when the load balancer is disabled or uses a public IP prefix.
+func (s *ClusterScope) NodeOutboundIPNames() []string {
+	lb := s.NodeOutboundLB()
+	if !s.NodeOutboundLBEnabled() || lb.PublicIPPrefixID != "" {
+		return nil
+	}
+	names := []string{azure.GenerateNodeOutboundIPName(s.ClusterName())}
+	if lb.FrontendIPsCount != nil {
+		for i := 1; i < int(*lb.FrontendIPsCount); i++ {
+			names = append(names, azure.GenerateAdditionalNodeOutboundIPName(s.ClusterName(), i))
+		}
+	}
+	return names
+}
+
+// PrivateEndpointSpecs returns the private endpoint specs of all the cluster subnets.
+func (s *ClusterScope) PrivateEndpointSpecs() []azure.PrivateEndpointSpec {
+	var specs []azure.PrivateEndpointSpec
+	for _, subnet := range s.Subnets() {
+		if subnet == nil {
+			continue
+		}
+		subnetID := subnet.ID
+		if subnetID == "" {
+			subnetID = azure.SubnetID(azure.VnetAuthorizer(s).SubscriptionID(), s.Vnet().ResourceGroup, s.Vnet().Name, subnet.Name)
+		}
+		for _, pe := range subnet.PrivateEndpoints {
+			specs = append(specs, azure.PrivateEndpointSpec{
+				Name:                  pe.Name,
+				SubnetID:              subnetID,
+				PrivateLinkResourceID: pe.PrivateLinkResourceID,
+				GroupIDs:              pe.GroupIDs,
+				PrivateDNSZoneID:      pe.PrivateDNSZoneID,
+			})
+		}
+	}
+	return specs
+}
+
+// ApplicationSecurityGroupSpecs returns the application security group specs, one per machine role.
+func (s *ClusterScope) ApplicationSecurityGroupSpecs() []azure.ApplicationSecurityGroupSpec {
+	return []azure.ApplicationSecurityGroupSpec{
 		{
-			Name: azure.GenerateNodeOutboundIPName(s.ClusterName()),
+			Name: azure.GenerateApplicationSecurityGroupName(s.ClusterName(), infrav1.ControlPlane),
+			Role: infrav1.ControlPlane,
 		},
 		{
-			Name:    s.Network().APIServerIP.Name,
-			DNSName: s.Network().APIServerIP.DNSName,
+			Name: azure.GenerateApplicationSecurityGroupName(s.ClusterName(), infrav1.Node),
+			Role: infrav1.Node,
 		},
 	}
 }
@@ -157,9 +315,13 @@ func (s *ClusterScope) Location() string {
 	return s.AzureCluster.Spec.Location
 }
 
-// GenerateFQDN generates a fully qualified domain name, based on the public IP name and cluster location.
+// GenerateFQDN generates a fully qualified domain name, based on the DNS label or name of the public IP and cluster location.
 func (s *ClusterScope) GenerateFQDN() string {
-	return fmt.Sprintf("%s.%s.%s", s.Network().APIServerIP.Name, s.Location(), s.AzureClients.ResourceManagerVMDNSSuffix)
+	dnsLabel := s.Network().APIServerIP.Name
+	if s.APIServerIPSpec().DNSLabel != "" {
+		dnsLabel = s.APIServerIPSpec().DNSLabel
+	}
+	return fmt.Sprintf("%s.%s.%s", dnsLabel, s.Location(), s.AzureClients.ResourceManagerVMDNSSuffix)
 }
 
 // ListOptionsLabelSelector returns a ListOptions with a label selector for clusterName.
@@ -179,6 +341,49 @@ func (s *ClusterScope) Close(ctx context.Context) error {
 	return s.patchHelper.Patch(ctx, s.AzureCluster)
 }
 
+// ProximityPlacementGroupName returns the name of the proximity placement group of the cluster, or an empty string if it has none.
+func (s *ClusterScope) ProximityPlacementGroupName() string {
+	ppg := s.AzureCluster.Spec.ProximityPlacementGroup
+	if ppg == nil {
+		return ""
+	}
+	if ppg.Name != "" {
+		return ppg.Name
+	}
+	return azure.GenerateProximityPlacementGroupName(s.ClusterName())
+}
+
+// ControlPlaneInProximityPlacementGroup returns whether the control plane machines of the cluster are placed in the
+// proximity placement group of the cluster. Control plane machines default to it, so they are assumed to be until
+// control plane AzureMachines exist.
+func (s *ClusterScope) ControlPlaneInProximityPlacementGroup(ctx context.Context) (bool, error) {
+	ppgName := s.ProximityPlacementGroupName()
+	if ppgName == "" {
+		return false, nil
+	}
+	machines := &infrav1.AzureMachineList{}
+	if err := s.client.List(ctx, machines, client.InNamespace(s.Namespace()), client.MatchingLabels{
+		clusterv1.ClusterLabelName:             s.ClusterName(),
+		clusterv1.MachineControlPlaneLabelName: "",
+	}); err != nil {
+		return false, errors.Wrap(err, "failed to list control plane AzureMachines")
+	}
+	if len(machines.Items) == 0 {
+		return true, nil
+	}
+	for _, machine := range machines.Items {
+		if name := machine.Spec.ProximityPlacementGroupName; name == nil || *name == ppgName {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// BootDiagnosticsStorageAccountName returns the name of the storage account holding the boot diagnostics of the cluster VMs.
+func (s *ClusterScope) BootDiagnosticsStorageAccountName() string {
+	return azure.GenerateBootDiagnosticsStorageAccountName(s.SubscriptionID(), s.ResourceGroup(), s.ClusterName())
+}
+
 // AdditionalTags returns AdditionalTags from the scope's AzureCluster.
 func (s *ClusterScope) AdditionalTags() infrav1.Tags {
 	tags := make(infrav1.Tags)
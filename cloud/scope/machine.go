@@ -19,6 +19,8 @@ package scope
 import (
 	"context"
 	"encoding/base64"
+	"strings"
+
 	"github.com/Azure/go-autorest/autorest"
 	"github.com/go-logr/logr"
 	"github.com/pkg/errors"
@@ -32,6 +34,7 @@ import (
 	"sigs.k8s.io/cluster-api/controllers/noderefutil"
 	capierrors "sigs.k8s.io/cluster-api/errors"
 	"sigs.k8s.io/cluster-api/util"
+	"sigs.k8s.io/cluster-api/util/conditions"
 	"sigs.k8s.io/cluster-api/util/patch"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -73,6 +76,8 @@ func NewMachineScope(params MachineScopeParams) (*MachineScope, error) {
 		Logger:       params.Logger,
 		patchHelper:  helper,
 		ClusterScope: params.ClusterScope,
+
+		nodeOutboundLBEnabled: params.ClusterScope != nil && params.ClusterScope.NodeOutboundLBEnabled(),
 	}, nil
 }
 
@@ -85,6 +90,9 @@ type MachineScope struct {
 	ClusterScope azure.ClusterDescriber
 	Machine      *clusterv1.Machine
 	AzureMachine *infrav1.AzureMachine
+
+	// nodeOutboundLBEnabled is true if the cluster has a node outbound load balancer.
+	nodeOutboundLBEnabled bool
 }
 
 // PublicIPSpec returns the public IP specs.
@@ -99,29 +107,83 @@ func (m *MachineScope) PublicIPSpecs() []azure.PublicIPSpec {
 	return spec
 }
 
+// InboundNatSpecs returns the inbound NAT specs.
+func (m *MachineScope) InboundNatSpecs() []azure.InboundNatSpec {
+	if m.Role() == infrav1.ControlPlane {
+		return []azure.InboundNatSpec{
+			{
+				Name:             m.Name(),
+				LoadBalancerName: azure.GeneratePublicLBName(m.ClusterName()),
+			},
+		}
+	}
+	return []azure.InboundNatSpec{}
+}
+
 // NICSpecs returns the network interface specs.
+// The first network interface is the primary one and is the only one attached to the load balancers and public IP.
 func (m *MachineScope) NICSpecs() []azure.NICSpec {
-	spec := azure.NICSpec{
-		Name:                  azure.GenerateNICName(m.Name()),
-		MachineName:           m.Name(),
-		MachineRole:           m.Role(),
-		VNetName:              m.ClusterScope.Vnet().Name,
-		VNetResourceGroup:     m.ClusterScope.Vnet().ResourceGroup,
-		SubnetName:            m.Subnet().Name,
-		VMSize:                m.AzureMachine.Spec.VMSize,
-		AcceleratedNetworking: m.AzureMachine.Spec.AcceleratedNetworking,
+	nics := m.AzureMachine.Spec.NetworkInterfaces
+	if len(nics) == 0 {
+		nics = []infrav1.NetworkInterface{{}}
 	}
-	if m.Role() == infrav1.ControlPlane {
-		spec.PublicLoadBalancerName = azure.GeneratePublicLBName(m.ClusterName())
-		spec.InternalLoadBalancerName = azure.GenerateInternalLBName(m.ClusterName())
-	} else if m.Role() == infrav1.Node {
-		spec.PublicLoadBalancerName = m.ClusterName()
+
+	specs := make([]azure.NICSpec, 0, len(nics))
+	for i, nic := range nics {
+		spec := azure.NICSpec{
+			Name:                     m.nicName(i),
+			MachineName:              m.Name(),
+			MachineRole:              m.Role(),
+			VNetName:                 m.ClusterScope.Vnet().Name,
+			VNetResourceGroup:        m.ClusterScope.Vnet().ResourceGroup,
+			SubnetName:               nic.SubnetName,
+			VMSize:                   m.AzureMachine.Spec.VMSize,
+			AcceleratedNetworking:    nic.AcceleratedNetworking,
+			PrivateIPConfigs:         nic.PrivateIPConfigs,
+			ApplicationSecurityGroup: azure.GenerateApplicationSecurityGroupName(m.ClusterName(), m.Role()),
+		}
+		if spec.SubnetName == "" {
+			spec.SubnetName = m.Subnet().Name
+		}
+		if spec.AcceleratedNetworking == nil {
+			spec.AcceleratedNetworking = m.AzureMachine.Spec.AcceleratedNetworking
+		}
+		if spec.PrivateIPConfigs == 0 {
+			spec.PrivateIPConfigs = 1
+		}
+		if i == 0 {
+			if m.Role() == infrav1.ControlPlane {
+				spec.PublicLoadBalancerName = azure.GeneratePublicLBName(m.ClusterName())
+				spec.InternalLoadBalancerName = azure.GenerateInternalLBName(m.ClusterName())
+			} else if m.Role() == infrav1.Node && m.nodeOutboundLBEnabled {
+				spec.PublicLoadBalancerName = m.ClusterName()
+			}
+			if m.AzureMachine.Spec.AllocatePublicIP == true {
+				spec.PublicIPName = azure.GenerateNodePublicIPName(azure.GenerateNICName(m.Name()))
+			}
+		}
+		specs = append(specs, spec)
 	}
-	if m.AzureMachine.Spec.AllocatePublicIP == true {
-		spec.PublicIPName = azure.GenerateNodePublicIPName(azure.GenerateNICName(m.Name()))
+
+	return specs
+}
+
+// NICNames returns the names of the network interfaces of the machine, the primary one first.
+func (m *MachineScope) NICNames() []string {
+	specs := m.NICSpecs()
+	names := make([]string, len(specs))
+	for i, spec := range specs {
+		names[i] = spec.Name
 	}
+	return names
+}
 
-	return []azure.NICSpec{spec}
+// nicName returns the name of the network interface with the given index.
+func (m *MachineScope) nicName(index int) string {
+	if index == 0 {
+		return azure.GenerateNICName(m.Name())
+	}
+	return azure.GenerateSecondaryNICName(m.Name(), index)
 }
 
 // Location returns the AzureCluster location.
@@ -252,6 +314,11 @@ func (m *MachineScope) SetVMState(v infrav1.VMState) {
 	m.AzureMachine.Status.VMState = &v
 }
 
+// SetSSHPort sets the frontend port of the AzureMachine SSH inbound NAT rule.
+func (m *MachineScope) SetSSHPort(port int32) {
+	m.AzureMachine.Status.SSHPort = port
+}
+
 // SetReady sets the AzureMachine Ready Status to true.
 func (m *MachineScope) SetReady() {
 	m.AzureMachine.Status.Ready = true
@@ -285,6 +352,86 @@ func (m *MachineScope) SetAddresses(addrs []corev1.NodeAddress) {
 	m.AzureMachine.Status.Addresses = addrs
 }
 
+// SetDedicatedHostID sets the ID of the dedicated host the VM is placed on.
+func (m *MachineScope) SetDedicatedHostID(id string) {
+	m.AzureMachine.Status.DedicatedHostID = id
+}
+
+// VMExtensionSpecs returns the VM extension specs, with the protected settings read from their Secrets.
+func (m *MachineScope) VMExtensionSpecs(ctx context.Context) ([]azure.VMExtensionSpec, error) {
+	return vmExtensionSpecs(ctx, m.client, m.Namespace(), m.Name(), m.AzureMachine.Spec.Extensions)
+}
+
+// BootstrapExtensionSpec returns the spec of the extension reporting the result of the bootstrap of the VM, or nil
+// if the bootstrap of the VM isn't tracked or already succeeded.
+func (m *MachineScope) BootstrapExtensionSpec() *azure.VMExtensionSpec {
+	if !conditions.Has(m.AzureMachine, infrav1.BootstrapSucceededCondition) || conditions.IsTrue(m.AzureMachine, infrav1.BootstrapSucceededCondition) {
+		return nil
+	}
+	spec := azure.GetBootstrapExtension(m.Name())
+	return &spec
+}
+
+// SetVMExtensionsReady marks the VM extensions as provisioned, or removes the condition if there are no extensions.
+func (m *MachineScope) SetVMExtensionsReady() {
+	if len(m.AzureMachine.Spec.Extensions) == 0 {
+		conditions.Delete(m.AzureMachine, infrav1.VMExtensionsReadyCondition)
+		return
+	}
+	conditions.MarkTrue(m.AzureMachine, infrav1.VMExtensionsReadyCondition)
+}
+
+// SetVMExtensionsNotReady marks the VM extensions as not provisioned.
+func (m *MachineScope) SetVMExtensionsNotReady(reason string, severity clusterv1.ConditionSeverity, message string) {
+	conditions.MarkFalse(m.AzureMachine, infrav1.VMExtensionsReadyCondition, reason, severity, message)
+}
+
+// SetBootstrapInProgress marks the bootstrap of a new VM as in progress, so that it is tracked by the bootstrap
+// extension. Only Linux VMs without a custom script extension of their own are tracked, as a VM can only have one
+// custom script extension.
+func (m *MachineScope) SetBootstrapInProgress() {
+	if m.AzureMachine.Spec.OSDisk.OSType != "Linux" {
+		return
+	}
+	for _, extension := range m.AzureMachine.Spec.Extensions {
+		if strings.EqualFold(extension.Publisher, "Microsoft.Azure.Extensions") && strings.EqualFold(extension.Type, "CustomScript") {
+			return
+		}
+	}
+	conditions.MarkFalse(m.AzureMachine, infrav1.BootstrapSucceededCondition, infrav1.BootstrapInProgressReason, clusterv1.ConditionSeverityInfo, "")
+}
+
+// SetBootstrapSucceeded marks the bootstrap of the VM as succeeded.
+func (m *MachineScope) SetBootstrapSucceeded() {
+	conditions.MarkTrue(m.AzureMachine, infrav1.BootstrapSucceededCondition)
+}
+
+// SetBootstrapFailed marks the bootstrap of the VM as failed.
+func (m *MachineScope) SetBootstrapFailed(message string) {
+	conditions.MarkFalse(m.AzureMachine, infrav1.BootstrapSucceededCondition, infrav1.BootstrapFailedReason, clusterv1.ConditionSeverityError, message)
+}
+
+// SetVMResizing marks the VM as being resized to the given size.
+func (m *MachineScope) SetVMResizing(size string) {
+	m.AzureMachine.Status.LastResizeVMSize = size
+	conditions.MarkFalse(m.AzureMachine, infrav1.VMResizedCondition, infrav1.VMResizingReason, clusterv1.ConditionSeverityInfo, "Resizing VM to %s", size)
+}
+
+// IsVMResizing returns whether the VM is being resized.
+func (m *MachineScope) IsVMResizing() bool {
+	return conditions.IsFalse(m.AzureMachine, infrav1.VMResizedCondition) && conditions.GetReason(m.AzureMachine, infrav1.VMResizedCondition) == infrav1.VMResizingReason
+}
+
+// SetVMResized marks the last in-place resize of the VM as succeeded.
+func (m *MachineScope) SetVMResized() {
+	conditions.MarkTrue(m.AzureMachine, infrav1.VMResizedCondition)
+}
+
+// SetVMResizeFailed marks the last in-place resize of the VM as failed.
+func (m *MachineScope) SetVMResizeFailed(reason string, message string) {
+	conditions.MarkFalse(m.AzureMachine, infrav1.VMResizedCondition, reason, clusterv1.ConditionSeverityError, message)
+}
+
 // PatchObject persists the machine spec and status.
 func (m *MachineScope) PatchObject(ctx context.Context) error {
 	return m.patchHelper.Patch(ctx, m.AzureMachine)
@@ -325,3 +472,36 @@ func (m *MachineScope) GetBootstrapData(ctx context.Context) (string, error) {
 	}
 	return base64.StdEncoding.EncodeToString(value), nil
 }
+
+// vmExtensionSpecs returns the specs of the VM extensions of a VM, with the data of the Secret referenced by an
+// extension as its protected settings.
+func vmExtensionSpecs(ctx context.Context, c client.Client, namespace, vmName string, extensions []infrav1.VMExtension) ([]azure.VMExtensionSpec, error) {
+	specs := make([]azure.VMExtensionSpec, 0, len(extensions))
+	for _, extension := range extensions {
+		settings, err := extension.GetSettings()
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid settings of VM extension %s", extension.Name)
+		}
+		spec := azure.VMExtensionSpec{
+			Name:      extension.Name,
+			VMName:    vmName,
+			Publisher: extension.Publisher,
+			Type:      extension.Type,
+			Version:   extension.Version,
+			Settings:  settings,
+		}
+		if extension.ProtectedSettingsSecretName != "" {
+			secret := &corev1.Secret{}
+			key := types.NamespacedName{Namespace: namespace, Name: extension.ProtectedSettingsSecretName}
+			if err := c.Get(ctx, key, secret); err != nil {
+				return nil, errors.Wrapf(err, "failed to retrieve protected settings secret of VM extension %s", extension.Name)
+			}
+			spec.ProtectedSettings = make(map[string]string, len(secret.Data))
+			for k, v := range secret.Data {
+				spec.ProtectedSettings[k] = string(v)
+			}
+		}
+		specs = append(specs, spec)
+	}
+	return specs, nil
+}
@@ -27,9 +27,11 @@ import (
 	"k8s.io/klog/klogr"
 	"k8s.io/utils/pointer"
 	azure "sigs.k8s.io/cluster-api-provider-azure/cloud"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha3"
 	"sigs.k8s.io/cluster-api/controllers/noderefutil"
 	capierrors "sigs.k8s.io/cluster-api/errors"
 	capiv1exp "sigs.k8s.io/cluster-api/exp/api/v1alpha3"
+	"sigs.k8s.io/cluster-api/util/conditions"
 	"sigs.k8s.io/cluster-api/util/patch"
 
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha3"
@@ -151,6 +153,21 @@ func (m *MachinePoolScope) SetFailureReason(v capierrors.MachineStatusError) {
 	m.AzureMachinePool.Status.FailureReason = &v
 }
 
+// SetVMExtensionsReady marks the VM extensions of the scale set instances as provisioned, or removes the condition if
+// there are no extensions.
+func (m *MachinePoolScope) SetVMExtensionsReady() {
+	if len(m.AzureMachinePool.Spec.Template.Extensions) == 0 {
+		conditions.Delete(m.AzureMachinePool, infrav1.VMExtensionsReadyCondition)
+		return
+	}
+	conditions.MarkTrue(m.AzureMachinePool, infrav1.VMExtensionsReadyCondition)
+}
+
+// SetVMExtensionsNotReady marks the VM extensions of the scale set instances as not provisioned.
+func (m *MachinePoolScope) SetVMExtensionsNotReady(reason string, severity clusterv1.ConditionSeverity, message string) {
+	conditions.MarkFalse(m.AzureMachinePool, infrav1.VMExtensionsReadyCondition, reason, severity, message)
+}
+
 // AdditionalTags merges AdditionalTags from the scope's AzureCluster and AzureMachinePool. If the same key is present in both,
 // the value from AzureMachinePool takes precedence.
 func (m *MachinePoolScope) AdditionalTags() infrav1.Tags {
@@ -192,6 +209,12 @@ func getAzureMachineTemplate(ctx context.Context, c client.Client, name, namespa
 	return m, nil
 }
 
+// VMExtensionSpecs returns the VM extension specs of the scale set instances, with the protected settings read from
+// their Secrets.
+func (m *MachinePoolScope) VMExtensionSpecs(ctx context.Context) ([]azure.VMExtensionSpec, error) {
+	return vmExtensionSpecs(ctx, m.client, m.AzureMachinePool.Namespace, m.Name(), m.AzureMachinePool.Spec.Template.Extensions)
+}
+
 // GetBootstrapData returns the bootstrap data from the secret in the Machine's bootstrap.dataSecretName.
 func (m *MachinePoolScope) GetBootstrapData(ctx context.Context) (string, error) {
 	dataSecretName := m.MachinePool.Spec.Template.Spec.Bootstrap.DataSecretName
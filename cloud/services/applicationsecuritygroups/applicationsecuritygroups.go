@@ -0,0 +1,63 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationsecuritygroups
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/pkg/errors"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha3"
+	azure "sigs.k8s.io/cluster-api-provider-azure/cloud"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/converters"
+)
+
+// Reconcile gets/creates/updates the application security groups.
+func (s *Service) Reconcile(ctx context.Context) error {
+	for _, asgSpec := range s.Scope.ApplicationSecurityGroupSpecs() {
+		s.Scope.V(2).Info("creating application security group", "application security group", asgSpec.Name)
+		err := s.Client.CreateOrUpdate(ctx, s.Scope.ResourceGroup(), asgSpec.Name, network.ApplicationSecurityGroup{
+			Location: to.StringPtr(s.Scope.Location()),
+			Tags: converters.TagsToMap(infrav1.Build(infrav1.BuildParams{
+				ClusterName: s.Scope.ClusterName(),
+				Lifecycle:   infrav1.ResourceLifecycleOwned,
+				Name:        to.StringPtr(asgSpec.Name),
+				Role:        to.StringPtr(asgSpec.Role),
+				Additional:  s.Scope.AdditionalTags(),
+			})),
+		})
+		if err != nil {
+			return errors.Wrapf(err, "failed to create application security group %s in resource group %s", asgSpec.Name, s.Scope.ResourceGroup())
+		}
+		s.Scope.V(2).Info("successfully created application security group", "application security group", asgSpec.Name)
+	}
+	return nil
+}
+
+// Delete deletes the application security groups.
+func (s *Service) Delete(ctx context.Context) error {
+	for _, asgSpec := range s.Scope.ApplicationSecurityGroupSpecs() {
+		s.Scope.V(2).Info("deleting application security group", "application security group", asgSpec.Name)
+		err := s.Client.Delete(ctx, s.Scope.ResourceGroup(), asgSpec.Name)
+		if err != nil && !azure.ResourceNotFound(err) {
+			return errors.Wrapf(err, "failed to delete application security group %s in resource group %s", asgSpec.Name, s.Scope.ResourceGroup())
+		}
+		s.Scope.V(2).Info("successfully deleted application security group", "application security group", asgSpec.Name)
+	}
+	return nil
+}
@@ -0,0 +1,215 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationsecuritygroups
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+	"k8s.io/klog/klogr"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha3"
+	azure "sigs.k8s.io/cluster-api-provider-azure/cloud"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/applicationsecuritygroups/mock_applicationsecuritygroups"
+)
+
+func TestReconcileApplicationSecurityGroups(t *testing.T) {
+	testcases := []struct {
+		name          string
+		expectedError string
+		expect        func(s *mock_applicationsecuritygroups.MockApplicationSecurityGroupScopeMockRecorder, m *mock_applicationsecuritygroups.MockClientMockRecorder)
+	}{
+		{
+			name:          "application security groups successfully created",
+			expectedError: "",
+			expect: func(s *mock_applicationsecuritygroups.MockApplicationSecurityGroupScopeMockRecorder, m *mock_applicationsecuritygroups.MockClientMockRecorder) {
+				s.ApplicationSecurityGroupSpecs().Return([]azure.ApplicationSecurityGroupSpec{
+					{
+						Name: "my-cluster-control-plane-asg",
+						Role: infrav1.ControlPlane,
+					},
+					{
+						Name: "my-cluster-node-asg",
+						Role: infrav1.Node,
+					},
+				})
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.Location().AnyTimes().Return("westus")
+				s.ClusterName().AnyTimes().Return("my-cluster")
+				s.AdditionalTags().AnyTimes().Return(infrav1.Tags{})
+				gomock.InOrder(
+					m.CreateOrUpdate(context.TODO(), "my-rg", "my-cluster-control-plane-asg", network.ApplicationSecurityGroup{
+						Location: to.StringPtr("westus"),
+						Tags: map[string]*string{
+							"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": to.StringPtr("owned"),
+							"sigs.k8s.io_cluster-api-provider-azure_role":               to.StringPtr(infrav1.ControlPlane),
+							"Name": to.StringPtr("my-cluster-control-plane-asg"),
+						},
+					}),
+					m.CreateOrUpdate(context.TODO(), "my-rg", "my-cluster-node-asg", network.ApplicationSecurityGroup{
+						Location: to.StringPtr("westus"),
+						Tags: map[string]*string{
+							"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": to.StringPtr("owned"),
+							"sigs.k8s.io_cluster-api-provider-azure_role":               to.StringPtr(infrav1.Node),
+							"Name": to.StringPtr("my-cluster-node-asg"),
+						},
+					}),
+				)
+			},
+		},
+		{
+			name:          "fail to create application security group",
+			expectedError: "failed to create application security group my-cluster-control-plane-asg in resource group my-rg: #: Internal Server Error: StatusCode=500",
+			expect: func(s *mock_applicationsecuritygroups.MockApplicationSecurityGroupScopeMockRecorder, m *mock_applicationsecuritygroups.MockClientMockRecorder) {
+				s.ApplicationSecurityGroupSpecs().Return([]azure.ApplicationSecurityGroupSpec{
+					{
+						Name: "my-cluster-control-plane-asg",
+						Role: infrav1.ControlPlane,
+					},
+				})
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.Location().AnyTimes().Return("westus")
+				s.ClusterName().AnyTimes().Return("my-cluster")
+				s.AdditionalTags().AnyTimes().Return(infrav1.Tags{})
+				m.CreateOrUpdate(context.TODO(), "my-rg", "my-cluster-control-plane-asg", gomock.AssignableToTypeOf(network.ApplicationSecurityGroup{})).
+					Return(autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error"))
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_applicationsecuritygroups.NewMockApplicationSecurityGroupScope(mockCtrl)
+			clientMock := mock_applicationsecuritygroups.NewMockClient(mockCtrl)
+
+			tc.expect(scopeMock.EXPECT(), clientMock.EXPECT())
+
+			s := &Service{
+				Scope:  scopeMock,
+				Client: clientMock,
+			}
+
+			err := s.Reconcile(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestDeleteApplicationSecurityGroups(t *testing.T) {
+	testcases := []struct {
+		name          string
+		expectedError string
+		expect        func(s *mock_applicationsecuritygroups.MockApplicationSecurityGroupScopeMockRecorder, m *mock_applicationsecuritygroups.MockClientMockRecorder)
+	}{
+		{
+			name:          "successfully delete application security groups",
+			expectedError: "",
+			expect: func(s *mock_applicationsecuritygroups.MockApplicationSecurityGroupScopeMockRecorder, m *mock_applicationsecuritygroups.MockClientMockRecorder) {
+				s.ApplicationSecurityGroupSpecs().Return([]azure.ApplicationSecurityGroupSpec{
+					{
+						Name: "my-cluster-control-plane-asg",
+						Role: infrav1.ControlPlane,
+					},
+					{
+						Name: "my-cluster-node-asg",
+						Role: infrav1.Node,
+					},
+				})
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				m.Delete(context.TODO(), "my-rg", "my-cluster-control-plane-asg")
+				m.Delete(context.TODO(), "my-rg", "my-cluster-node-asg")
+			},
+		},
+		{
+			name:          "application security group already deleted",
+			expectedError: "",
+			expect: func(s *mock_applicationsecuritygroups.MockApplicationSecurityGroupScopeMockRecorder, m *mock_applicationsecuritygroups.MockClientMockRecorder) {
+				s.ApplicationSecurityGroupSpecs().Return([]azure.ApplicationSecurityGroupSpec{
+					{
+						Name: "my-cluster-node-asg",
+						Role: infrav1.Node,
+					},
+				})
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				m.Delete(context.TODO(), "my-rg", "my-cluster-node-asg").
+					Return(autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
+			},
+		},
+		{
+			name:          "application security group deletion fails",
+			expectedError: "failed to delete application security group my-cluster-node-asg in resource group my-rg: #: Internal Server Error: StatusCode=500",
+			expect: func(s *mock_applicationsecuritygroups.MockApplicationSecurityGroupScopeMockRecorder, m *mock_applicationsecuritygroups.MockClientMockRecorder) {
+				s.ApplicationSecurityGroupSpecs().Return([]azure.ApplicationSecurityGroupSpec{
+					{
+						Name: "my-cluster-node-asg",
+						Role: infrav1.Node,
+					},
+				})
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				m.Delete(context.TODO(), "my-rg", "my-cluster-node-asg").
+					Return(autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error"))
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_applicationsecuritygroups.NewMockApplicationSecurityGroupScope(mockCtrl)
+			clientMock := mock_applicationsecuritygroups.NewMockClient(mockCtrl)
+
+			tc.expect(scopeMock.EXPECT(), clientMock.EXPECT())
+
+			s := &Service{
+				Scope:  scopeMock,
+				Client: clientMock,
+			}
+
+			err := s.Delete(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
@@ -0,0 +1,86 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationsecuritygroups
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
+	"github.com/Azure/go-autorest/autorest"
+	azure "sigs.k8s.io/cluster-api-provider-azure/cloud"
+)
+
+// Client wraps go-sdk
+type Client interface {
+	Get(context.Context, string, string) (network.ApplicationSecurityGroup, error)
+	CreateOrUpdate(context.Context, string, string, network.ApplicationSecurityGroup) error
+	Delete(context.Context, string, string) error
+}
+
+// AzureClient contains the Azure go-sdk Client
+type AzureClient struct {
+	applicationsecuritygroups network.ApplicationSecurityGroupsClient
+}
+
+var _ Client = &AzureClient{}
+
+// NewClient creates a new application security groups client from subscription ID.
+func NewClient(auth azure.Authorizer) *AzureClient {
+	c := newApplicationSecurityGroupsClient(auth.SubscriptionID(), auth.BaseURI(), auth.Authorizer())
+	return &AzureClient{c}
+}
+
+// newApplicationSecurityGroupsClient creates a new application security groups client from subscription ID.
+func newApplicationSecurityGroupsClient(subscriptionID string, baseURI string, authorizer autorest.Authorizer) network.ApplicationSecurityGroupsClient {
+	asgClient := network.NewApplicationSecurityGroupsClientWithBaseURI(baseURI, subscriptionID)
+	asgClient.Authorizer = authorizer
+	asgClient.AddToUserAgent(azure.UserAgent())
+	return asgClient
+}
+
+// Get gets the specified application security group in a specified resource group.
+func (ac *AzureClient) Get(ctx context.Context, resourceGroupName, asgName string) (network.ApplicationSecurityGroup, error) {
+	return ac.applicationsecuritygroups.Get(ctx, resourceGroupName, asgName)
+}
+
+// CreateOrUpdate creates or updates an application security group in the specified resource group.
+func (ac *AzureClient) CreateOrUpdate(ctx context.Context, resourceGroupName string, asgName string, asg network.ApplicationSecurityGroup) error {
+	future, err := ac.applicationsecuritygroups.CreateOrUpdate(ctx, resourceGroupName, asgName, asg)
+	if err != nil {
+		return err
+	}
+	err = future.WaitForCompletionRef(ctx, ac.applicationsecuritygroups.Client)
+	if err != nil {
+		return err
+	}
+	_, err = future.Result(ac.applicationsecuritygroups)
+	return err
+}
+
+// Delete deletes the specified application security group.
+func (ac *AzureClient) Delete(ctx context.Context, resourceGroupName, asgName string) error {
+	future, err := ac.applicationsecuritygroups.Delete(ctx, resourceGroupName, asgName)
+	if err != nil {
+		return err
+	}
+	err = future.WaitForCompletionRef(ctx, ac.applicationsecuritygroups.Client)
+	if err != nil {
+		return err
+	}
+	_, err = future.Result(ac.applicationsecuritygroups)
+	return err
+}
@@ -0,0 +1,301 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ../service.go
+
+// Package mock_applicationsecuritygroups is a generated GoMock package.
+package mock_applicationsecuritygroups
+
+import (
+	autorest "github.com/Azure/go-autorest/autorest"
+	logr "github.com/go-logr/logr"
+	gomock "github.com/golang/mock/gomock"
+	reflect "reflect"
+	v1alpha3 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha3"
+	azure "sigs.k8s.io/cluster-api-provider-azure/cloud"
+)
+
+// MockApplicationSecurityGroupScope is a mock of ApplicationSecurityGroupScope interface.
+type MockApplicationSecurityGroupScope struct {
+	ctrl     *gomock.Controller
+	recorder *MockApplicationSecurityGroupScopeMockRecorder
+}
+
+// MockApplicationSecurityGroupScopeMockRecorder is the mock recorder for MockApplicationSecurityGroupScope.
+type MockApplicationSecurityGroupScopeMockRecorder struct {
+	mock *MockApplicationSecurityGroupScope
+}
+
+// NewMockApplicationSecurityGroupScope creates a new mock instance.
+func NewMockApplicationSecurityGroupScope(ctrl *gomock.Controller) *MockApplicationSecurityGroupScope {
+	mock := &MockApplicationSecurityGroupScope{ctrl: ctrl}
+	mock.recorder = &MockApplicationSecurityGroupScopeMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockApplicationSecurityGroupScope) EXPECT() *MockApplicationSecurityGroupScopeMockRecorder {
+	return m.recorder
+}
+
+// Info mocks base method.
+func (m *MockApplicationSecurityGroupScope) Info(msg string, keysAndValues ...interface{}) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{msg}
+	for _, a := range keysAndValues {
+		varargs = append(varargs, a)
+	}
+	m.ctrl.Call(m, "Info", varargs...)
+}
+
+// Info indicates an expected call of Info.
+func (mr *MockApplicationSecurityGroupScopeMockRecorder) Info(msg interface{}, keysAndValues ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{msg}, keysAndValues...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Info", reflect.TypeOf((*MockApplicationSecurityGroupScope)(nil).Info), varargs...)
+}
+
+// Enabled mocks base method.
+func (m *MockApplicationSecurityGroupScope) Enabled() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Enabled")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// Enabled indicates an expected call of Enabled.
+func (mr *MockApplicationSecurityGroupScopeMockRecorder) Enabled() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Enabled", reflect.TypeOf((*MockApplicationSecurityGroupScope)(nil).Enabled))
+}
+
+// Error mocks base method.
+func (m *MockApplicationSecurityGroupScope) Error(err error, msg string, keysAndValues ...interface{}) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{err, msg}
+	for _, a := range keysAndValues {
+		varargs = append(varargs, a)
+	}
+	m.ctrl.Call(m, "Error", varargs...)
+}
+
+// Error indicates an expected call of Error.
+func (mr *MockApplicationSecurityGroupScopeMockRecorder) Error(err, msg interface{}, keysAndValues ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{err, msg}, keysAndValues...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Error", reflect.TypeOf((*MockApplicationSecurityGroupScope)(nil).Error), varargs...)
+}
+
+// V mocks base method.
+func (m *MockApplicationSecurityGroupScope) V(level int) logr.InfoLogger {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "V", level)
+	ret0, _ := ret[0].(logr.InfoLogger)
+	return ret0
+}
+
+// V indicates an expected call of V.
+func (mr *MockApplicationSecurityGroupScopeMockRecorder) V(level interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "V", reflect.TypeOf((*MockApplicationSecurityGroupScope)(nil).V), level)
+}
+
+// WithValues mocks base method.
+func (m *MockApplicationSecurityGroupScope) WithValues(keysAndValues ...interface{}) logr.Logger {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{}
+	for _, a := range keysAndValues {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "WithValues", varargs...)
+	ret0, _ := ret[0].(logr.Logger)
+	return ret0
+}
+
+// WithValues indicates an expected call of WithValues.
+func (mr *MockApplicationSecurityGroupScopeMockRecorder) WithValues(keysAndValues ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithValues", reflect.TypeOf((*MockApplicationSecurityGroupScope)(nil).WithValues), keysAndValues...)
+}
+
+// WithName mocks base method.
+func (m *MockApplicationSecurityGroupScope) WithName(name string) logr.Logger {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithName", name)
+	ret0, _ := ret[0].(logr.Logger)
+	return ret0
+}
+
+// WithName indicates an expected call of WithName.
+func (mr *MockApplicationSecurityGroupScopeMockRecorder) WithName(name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithName", reflect.TypeOf((*MockApplicationSecurityGroupScope)(nil).WithName), name)
+}
+
+// SubscriptionID mocks base method.
+func (m *MockApplicationSecurityGroupScope) SubscriptionID() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SubscriptionID")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// SubscriptionID indicates an expected call of SubscriptionID.
+func (mr *MockApplicationSecurityGroupScopeMockRecorder) SubscriptionID() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubscriptionID", reflect.TypeOf((*MockApplicationSecurityGroupScope)(nil).SubscriptionID))
+}
+
+// BaseURI mocks base method.
+func (m *MockApplicationSecurityGroupScope) BaseURI() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BaseURI")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// BaseURI indicates an expected call of BaseURI.
+func (mr *MockApplicationSecurityGroupScopeMockRecorder) BaseURI() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BaseURI", reflect.TypeOf((*MockApplicationSecurityGroupScope)(nil).BaseURI))
+}
+
+// Authorizer mocks base method.
+func (m *MockApplicationSecurityGroupScope) Authorizer() autorest.Authorizer {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Authorizer")
+	ret0, _ := ret[0].(autorest.Authorizer)
+	return ret0
+}
+
+// Authorizer indicates an expected call of Authorizer.
+func (mr *MockApplicationSecurityGroupScopeMockRecorder) Authorizer() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Authorizer", reflect.TypeOf((*MockApplicationSecurityGroupScope)(nil).Authorizer))
+}
+
+// ResourceGroup mocks base method.
+func (m *MockApplicationSecurityGroupScope) ResourceGroup() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResourceGroup")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// ResourceGroup indicates an expected call of ResourceGroup.
+func (mr *MockApplicationSecurityGroupScopeMockRecorder) ResourceGroup() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResourceGroup", reflect.TypeOf((*MockApplicationSecurityGroupScope)(nil).ResourceGroup))
+}
+
+// ClusterName mocks base method.
+func (m *MockApplicationSecurityGroupScope) ClusterName() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClusterName")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// ClusterName indicates an expected call of ClusterName.
+func (mr *MockApplicationSecurityGroupScopeMockRecorder) ClusterName() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClusterName", reflect.TypeOf((*MockApplicationSecurityGroupScope)(nil).ClusterName))
+}
+
+// Location mocks base method.
+func (m *MockApplicationSecurityGroupScope) Location() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Location")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// Location indicates an expected call of Location.
+func (mr *MockApplicationSecurityGroupScopeMockRecorder) Location() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Location", reflect.TypeOf((*MockApplicationSecurityGroupScope)(nil).Location))
+}
+
+// AdditionalTags mocks base method.
+func (m *MockApplicationSecurityGroupScope) AdditionalTags() v1alpha3.Tags {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AdditionalTags")
+	ret0, _ := ret[0].(v1alpha3.Tags)
+	return ret0
+}
+
+// AdditionalTags indicates an expected call of AdditionalTags.
+func (mr *MockApplicationSecurityGroupScopeMockRecorder) AdditionalTags() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AdditionalTags", reflect.TypeOf((*MockApplicationSecurityGroupScope)(nil).AdditionalTags))
+}
+
+// Vnet mocks base method.
+func (m *MockApplicationSecurityGroupScope) Vnet() *v1alpha3.VnetSpec {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Vnet")
+	ret0, _ := ret[0].(*v1alpha3.VnetSpec)
+	return ret0
+}
+
+// Vnet indicates an expected call of Vnet.
+func (mr *MockApplicationSecurityGroupScopeMockRecorder) Vnet() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Vnet", reflect.TypeOf((*MockApplicationSecurityGroupScope)(nil).Vnet))
+}
+
+// NodeSubnet mocks base method.
+func (m *MockApplicationSecurityGroupScope) NodeSubnet() *v1alpha3.SubnetSpec {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NodeSubnet")
+	ret0, _ := ret[0].(*v1alpha3.SubnetSpec)
+	return ret0
+}
+
+// NodeSubnet indicates an expected call of NodeSubnet.
+func (mr *MockApplicationSecurityGroupScopeMockRecorder) NodeSubnet() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NodeSubnet", reflect.TypeOf((*MockApplicationSecurityGroupScope)(nil).NodeSubnet))
+}
+
+// ControlPlaneSubnet mocks base method.
+func (m *MockApplicationSecurityGroupScope) ControlPlaneSubnet() *v1alpha3.SubnetSpec {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ControlPlaneSubnet")
+	ret0, _ := ret[0].(*v1alpha3.SubnetSpec)
+	return ret0
+}
+
+// ControlPlaneSubnet indicates an expected call of ControlPlaneSubnet.
+func (mr *MockApplicationSecurityGroupScopeMockRecorder) ControlPlaneSubnet() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ControlPlaneSubnet", reflect.TypeOf((*MockApplicationSecurityGroupScope)(nil).ControlPlaneSubnet))
+}
+
+// ApplicationSecurityGroupSpecs mocks base method.
+func (m *MockApplicationSecurityGroupScope) ApplicationSecurityGroupSpecs() []azure.ApplicationSecurityGroupSpec {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ApplicationSecurityGroupSpecs")
+	ret0, _ := ret[0].([]azure.ApplicationSecurityGroupSpec)
+	return ret0
+}
+
+// ApplicationSecurityGroupSpecs indicates an expected call of ApplicationSecurityGroupSpecs.
+func (mr *MockApplicationSecurityGroupScopeMockRecorder) ApplicationSecurityGroupSpecs() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApplicationSecurityGroupSpecs", reflect.TypeOf((*MockApplicationSecurityGroupScope)(nil).ApplicationSecurityGroupSpecs))
+}
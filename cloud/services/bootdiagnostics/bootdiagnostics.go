@@ -0,0 +1,147 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bootdiagnostics
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/services/storage/mgmt/2019-06-01/storage"
+	"github.com/Azure/go-autorest/autorest/date"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/pkg/errors"
+)
+
+const (
+	// maxSerialLogBytes is the maximum number of bytes read from the end of a serial console log.
+	maxSerialLogBytes = 256 * 1024
+	// sasValidity is how long the shared access signature used to read a serial console log is valid.
+	sasValidity = 5 * time.Minute
+	// httpTimeout is the timeout of the requests reading a serial console log.
+	httpTimeout = time.Minute
+)
+
+// Spec input specification for Get calls
+type Spec struct {
+	// VMName is the name of the VM.
+	VMName string
+	// Lines is the number of lines to return from the end of the serial console log.
+	Lines int
+}
+
+// Get returns the last lines of the serial console log of the VM of the spec.
+func (s *Service) Get(ctx context.Context, spec interface{}) (interface{}, error) {
+	logSpec, ok := spec.(*Spec)
+	if !ok {
+		return nil, errors.New("invalid boot diagnostics specification")
+	}
+
+	instanceView, err := s.VirtualMachinesClient.InstanceView(ctx, s.Scope.ResourceGroup(), logSpec.VMName)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get instance view of VM %s", logSpec.VMName)
+	}
+	if instanceView.BootDiagnostics == nil || instanceView.BootDiagnostics.SerialConsoleLogBlobURI == nil {
+		return nil, errors.Errorf("VM %s has no serial console log, boot diagnostics may be disabled", logSpec.VMName)
+	}
+
+	blobURI, err := url.Parse(*instanceView.BootDiagnostics.SerialConsoleLogBlobURI)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse serial console log URI of VM %s", logSpec.VMName)
+	}
+	accountName := strings.SplitN(blobURI.Hostname(), ".", 2)[0]
+
+	// The signature only grants read access to the serial console log blob, for a short time.
+	sas, err := s.StorageAccountsClient.ListServiceSAS(ctx, s.Scope.ResourceGroup(), accountName, storage.ServiceSasParameters{
+		CanonicalizedResource:  to.StringPtr(fmt.Sprintf("/blob/%s%s", accountName, blobURI.Path)),
+		Resource:               storage.SignedResourceB,
+		Permissions:            storage.R,
+		Protocols:              storage.HTTPS,
+		SharedAccessExpiryTime: &date.Time{Time: time.Now().Add(sasValidity)},
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get shared access signature for serial console log of VM %s", logSpec.VMName)
+	}
+	blobURI.RawQuery = sas
+
+	log, err := s.readTail(ctx, blobURI.String())
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read serial console log of VM %s", logSpec.VMName)
+	}
+	return lastLines(log, logSpec.Lines), nil
+}
+
+// readTail reads at most maxSerialLogBytes from the end of the blob at uri.
+func (s *Service) readTail(ctx context.Context, uri string) (string, error) {
+	head, err := s.do(ctx, http.MethodHead, uri, nil)
+	if err != nil {
+		return "", err
+	}
+	head.Body.Close()
+
+	headers := map[string]string{}
+	if head.ContentLength > maxSerialLogBytes {
+		headers["Range"] = fmt.Sprintf("bytes=%d-", head.ContentLength-maxSerialLogBytes)
+	}
+	resp, err := s.do(ctx, http.MethodGet, uri, headers)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	return string(body), nil
+}
+
+func (s *Service) do(ctx context.Context, method, uri string, headers map[string]string) (*http.Response, error) {
+	req, err := http.NewRequest(method, uri, nil)
+	if err != nil {
+		return nil, err
+	}
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+	resp, err := s.HTTPClient.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusPartialContent {
+		resp.Body.Close()
+		return nil, errors.Errorf("%s %s returned %s", method, req.URL.Path, resp.Status)
+	}
+	return resp, nil
+}
+
+// lastLines returns the last n lines of log, or all of it if n isn't positive.
+func lastLines(log string, n int) string {
+	log = strings.TrimRight(log, "\n")
+	if n <= 0 {
+		return log
+	}
+	lines := strings.Split(log, "\n")
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,165 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bootdiagnostics
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2019-12-01/compute"
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/bootdiagnostics/mock_bootdiagnostics"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/storageaccounts/mock_storageaccounts"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/virtualmachines/mock_virtualmachines"
+)
+
+func instanceView(blobURI string) compute.VirtualMachineInstanceView {
+	return compute.VirtualMachineInstanceView{
+		BootDiagnostics: &compute.BootDiagnosticsInstanceView{
+			SerialConsoleLogBlobURI: to.StringPtr(blobURI),
+		},
+	}
+}
+
+func TestGetSerialLog(t *testing.T) {
+	largeLog := strings.Repeat(strings.Repeat("x", 1023)+"\n", 300) + "last line\n"
+
+	testcases := []struct {
+		name          string
+		spec          *Spec
+		log           string
+		status        int
+		expectedLog   string
+		expectedRange string
+		expectedError string
+		expect        func(vm *mock_virtualmachines.MockClientMockRecorder, accounts *mock_storageaccounts.MockClientMockRecorder, blobURI string)
+	}{
+		{
+			name:        "returns the last lines of the serial console log",
+			spec:        &Spec{VMName: "my-vm", Lines: 2},
+			log:         "one\ntwo\nthree\n",
+			status:      http.StatusOK,
+			expectedLog: "two\nthree",
+			expect: func(vm *mock_virtualmachines.MockClientMockRecorder, accounts *mock_storageaccounts.MockClientMockRecorder, blobURI string) {
+				vm.InstanceView(gomock.Any(), "my-rg", "my-vm").Return(instanceView(blobURI), nil)
+				accounts.ListServiceSAS(gomock.Any(), "my-rg", "127", gomock.Any()).Return("sig=abc", nil)
+			},
+		},
+		{
+			name:          "reads only the end of large serial console logs",
+			spec:          &Spec{VMName: "my-vm", Lines: 1},
+			log:           largeLog,
+			status:        http.StatusOK,
+			expectedLog:   "last line",
+			expectedRange: fmt.Sprintf("bytes=%d-", len(largeLog)-maxSerialLogBytes),
+			expect: func(vm *mock_virtualmachines.MockClientMockRecorder, accounts *mock_storageaccounts.MockClientMockRecorder, blobURI string) {
+				vm.InstanceView(gomock.Any(), "my-rg", "my-vm").Return(instanceView(blobURI), nil)
+				accounts.ListServiceSAS(gomock.Any(), "my-rg", gomock.Any(), gomock.Any()).Return("sig=abc", nil)
+			},
+		},
+		{
+			name:          "fails when boot diagnostics are disabled",
+			spec:          &Spec{VMName: "my-vm", Lines: 2},
+			expectedError: "VM my-vm has no serial console log, boot diagnostics may be disabled",
+			expect: func(vm *mock_virtualmachines.MockClientMockRecorder, accounts *mock_storageaccounts.MockClientMockRecorder, blobURI string) {
+				vm.InstanceView(gomock.Any(), "my-rg", "my-vm").Return(compute.VirtualMachineInstanceView{}, nil)
+			},
+		},
+		{
+			name:          "fails when the shared access signature can't be created",
+			spec:          &Spec{VMName: "my-vm", Lines: 2},
+			expectedError: "failed to get shared access signature for serial console log of VM my-vm: #: Internal Server Error: StatusCode=500",
+			expect: func(vm *mock_virtualmachines.MockClientMockRecorder, accounts *mock_storageaccounts.MockClientMockRecorder, blobURI string) {
+				vm.InstanceView(gomock.Any(), "my-rg", "my-vm").Return(instanceView(blobURI), nil)
+				accounts.ListServiceSAS(gomock.Any(), "my-rg", "127", gomock.Any()).Return("", autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error"))
+			},
+		},
+		{
+			name:          "fails when the serial console log can't be read",
+			spec:          &Spec{VMName: "my-vm", Lines: 2},
+			status:        http.StatusForbidden,
+			expectedError: "failed to read serial console log of VM my-vm: HEAD /bootdiagnostics/serialconsole.log returned 403 Forbidden",
+			expect: func(vm *mock_virtualmachines.MockClientMockRecorder, accounts *mock_storageaccounts.MockClientMockRecorder, blobURI string) {
+				vm.InstanceView(gomock.Any(), "my-rg", "my-vm").Return(instanceView(blobURI), nil)
+				accounts.ListServiceSAS(gomock.Any(), "my-rg", gomock.Any(), gomock.Any()).Return("sig=abc", nil)
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			var requestedRange string
+			server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				g.Expect(r.URL.Query().Get("sig")).To(Equal("abc"))
+				if tc.status != http.StatusOK {
+					w.WriteHeader(tc.status)
+					return
+				}
+				if r.Method == http.MethodGet {
+					requestedRange = r.Header.Get("Range")
+				}
+				content := tc.log
+				if requestedRange != "" {
+					var start int
+					fmt.Sscanf(requestedRange, "bytes=%d-", &start)
+					content = content[start:]
+				}
+				w.Header().Set("Content-Length", fmt.Sprint(len(content)))
+				if r.Method == http.MethodGet {
+					w.Write([]byte(content))
+				}
+			}))
+			defer server.Close()
+
+			scopeMock := mock_bootdiagnostics.NewMockBootDiagnosticsScope(mockCtrl)
+			scopeMock.EXPECT().ResourceGroup().AnyTimes().Return("my-rg")
+			vmMock := mock_virtualmachines.NewMockClient(mockCtrl)
+			accountsMock := mock_storageaccounts.NewMockClient(mockCtrl)
+			tc.expect(vmMock.EXPECT(), accountsMock.EXPECT(), server.URL+"/bootdiagnostics/serialconsole.log")
+
+			s := &Service{
+				Scope:                 scopeMock,
+				VirtualMachinesClient: vmMock,
+				StorageAccountsClient: accountsMock,
+				HTTPClient:            server.Client(),
+			}
+
+			log, err := s.Get(context.TODO(), tc.spec)
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(log).To(Equal(tc.expectedLog))
+			g.Expect(requestedRange).To(Equal(tc.expectedRange))
+		})
+	}
+}
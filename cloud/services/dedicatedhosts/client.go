@@ -0,0 +1,73 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dedicatedhosts
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2019-12-01/compute"
+	"github.com/Azure/go-autorest/autorest"
+	azure "sigs.k8s.io/cluster-api-provider-azure/cloud"
+)
+
+// Client wraps go-sdk
+type Client interface {
+	GetHostGroup(context.Context, string, string) (compute.DedicatedHostGroup, error)
+	GetHost(context.Context, string, string, string) (compute.DedicatedHost, error)
+}
+
+// AzureClient contains the Azure go-sdk Client
+type AzureClient struct {
+	hostgroups compute.DedicatedHostGroupsClient
+	hosts      compute.DedicatedHostsClient
+}
+
+var _ Client = &AzureClient{}
+
+// NewClient creates a new dedicated hosts client from subscription ID.
+func NewClient(auth azure.Authorizer) *AzureClient {
+	return &AzureClient{
+		hostgroups: newDedicatedHostGroupsClient(auth.SubscriptionID(), auth.BaseURI(), auth.Authorizer()),
+		hosts:      newDedicatedHostsClient(auth.SubscriptionID(), auth.BaseURI(), auth.Authorizer()),
+	}
+}
+
+// newDedicatedHostGroupsClient creates a new dedicated host groups client from subscription ID.
+func newDedicatedHostGroupsClient(subscriptionID string, baseURI string, authorizer autorest.Authorizer) compute.DedicatedHostGroupsClient {
+	hostGroupsClient := compute.NewDedicatedHostGroupsClientWithBaseURI(baseURI, subscriptionID)
+	hostGroupsClient.Authorizer = authorizer
+	hostGroupsClient.AddToUserAgent(azure.UserAgent())
+	return hostGroupsClient
+}
+
+// newDedicatedHostsClient creates a new dedicated hosts client from subscription ID.
+func newDedicatedHostsClient(subscriptionID string, baseURI string, authorizer autorest.Authorizer) compute.DedicatedHostsClient {
+	hostsClient := compute.NewDedicatedHostsClientWithBaseURI(baseURI, subscriptionID)
+	hostsClient.Authorizer = authorizer
+	hostsClient.AddToUserAgent(azure.UserAgent())
+	return hostsClient
+}
+
+// GetHostGroup gets the specified dedicated host group.
+func (ac *AzureClient) GetHostGroup(ctx context.Context, resourceGroupName, hostGroupName string) (compute.DedicatedHostGroup, error) {
+	return ac.hostgroups.Get(ctx, resourceGroupName, hostGroupName)
+}
+
+// GetHost gets the specified dedicated host with its instance view, which holds its available capacity.
+func (ac *AzureClient) GetHost(ctx context.Context, resourceGroupName, hostGroupName, hostName string) (compute.DedicatedHost, error) {
+	return ac.hosts.Get(ctx, resourceGroupName, hostGroupName, hostName, compute.InstanceView)
+}
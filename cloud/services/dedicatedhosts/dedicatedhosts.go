@@ -0,0 +1,109 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dedicatedhosts
+
+import (
+	"context"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2019-12-01/compute"
+	autorestazure "github.com/Azure/go-autorest/autorest/azure"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/pkg/errors"
+)
+
+// Spec input specification for Get calls
+type Spec struct {
+	HostGroupID string
+	HostID      string
+	VMSize      string
+}
+
+// Placement is the dedicated host a VM is placed on.
+type Placement struct {
+	HostID string
+	// Zone is the availability zone of the host group, empty for a regional host group.
+	Zone string
+}
+
+// Get returns the Placement of a VM on the dedicated host, or on the first host of the host group with capacity
+// left for the VM size.
+func (s *Service) Get(ctx context.Context, spec interface{}) (interface{}, error) {
+	hostSpec, ok := spec.(*Spec)
+	if !ok {
+		return nil, errors.New("invalid dedicated host specification")
+	}
+
+	hostGroupID := hostSpec.HostGroupID
+	if hostSpec.HostID != "" {
+		hostGroupID = hostSpec.HostID[:strings.LastIndex(strings.ToLower(hostSpec.HostID), "/hosts/")]
+	}
+	hostGroup, err := autorestazure.ParseResourceID(hostGroupID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid dedicated host group ID %s", hostGroupID)
+	}
+	if !strings.EqualFold(hostGroup.SubscriptionID, s.Scope.SubscriptionID()) {
+		return nil, errors.Errorf("dedicated host group %s must be in subscription %s", hostGroupID, s.Scope.SubscriptionID())
+	}
+
+	group, err := s.Client.GetHostGroup(ctx, hostGroup.ResourceGroup, hostGroup.ResourceName)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get dedicated host group %s", hostGroupID)
+	}
+	placement := &Placement{HostID: hostSpec.HostID}
+	if group.Zones != nil && len(*group.Zones) > 0 {
+		placement.Zone = (*group.Zones)[0]
+	}
+	if placement.HostID != "" {
+		return placement, nil
+	}
+
+	if group.DedicatedHostGroupProperties != nil && group.Hosts != nil {
+		for _, ref := range *group.Hosts {
+			hostName := getResourceNameByID(to.String(ref.ID))
+			host, err := s.Client.GetHost(ctx, hostGroup.ResourceGroup, hostGroup.ResourceName, hostName)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to get dedicated host %s", to.String(ref.ID))
+			}
+			if hasCapacity(host, hostSpec.VMSize) {
+				s.Scope.V(2).Info("selected dedicated host", "host", to.String(host.ID), "vm size", hostSpec.VMSize)
+				placement.HostID = to.String(host.ID)
+				return placement, nil
+			}
+		}
+	}
+	return nil, errors.Errorf("no dedicated host of host group %s has capacity left for VM size %s", hostGroupID, hostSpec.VMSize)
+}
+
+// hasCapacity returns true if at least one more VM of the size fits on the dedicated host.
+func hasCapacity(host compute.DedicatedHost, vmSize string) bool {
+	if host.DedicatedHostProperties == nil || host.InstanceView == nil || host.InstanceView.AvailableCapacity == nil ||
+		host.InstanceView.AvailableCapacity.AllocatableVMs == nil {
+		return false
+	}
+	for _, allocatable := range *host.InstanceView.AvailableCapacity.AllocatableVMs {
+		if strings.EqualFold(to.String(allocatable.VMSize), vmSize) && allocatable.Count != nil && *allocatable.Count >= 1 {
+			return true
+		}
+	}
+	return false
+}
+
+// getResourceNameByID returns the name at the end of a resource ID.
+func getResourceNameByID(resourceID string) string {
+	return resourceID[strings.LastIndex(resourceID, "/")+1:]
+}
@@ -0,0 +1,160 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dedicatedhosts
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2019-12-01/compute"
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+	"k8s.io/klog/klogr"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/dedicatedhosts/mock_dedicatedhosts"
+)
+
+const (
+	hostGroupID = "/subscriptions/123/resourceGroups/host-rg/providers/Microsoft.Compute/hostGroups/my-group"
+	hostID      = hostGroupID + "/hosts/my-host"
+	otherHostID = hostGroupID + "/hosts/other-host"
+)
+
+func hostWithCapacity(id string, count float64) compute.DedicatedHost {
+	return compute.DedicatedHost{
+		ID: to.StringPtr(id),
+		DedicatedHostProperties: &compute.DedicatedHostProperties{
+			InstanceView: &compute.DedicatedHostInstanceView{
+				AvailableCapacity: &compute.DedicatedHostAvailableCapacity{
+					AllocatableVMs: &[]compute.DedicatedHostAllocatableVM{
+						{VMSize: to.StringPtr("Standard_D2s_v3"), Count: to.Float64Ptr(count)},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestGetDedicatedHostPlacement(t *testing.T) {
+	zonalGroup := compute.DedicatedHostGroup{
+		Zones: &[]string{"2"},
+		DedicatedHostGroupProperties: &compute.DedicatedHostGroupProperties{
+			Hosts: &[]compute.SubResourceReadOnly{{ID: to.StringPtr(hostID)}, {ID: to.StringPtr(otherHostID)}},
+		},
+	}
+
+	testcases := []struct {
+		name              string
+		spec              Spec
+		expectedPlacement *Placement
+		expectedError     string
+		expect            func(s *mock_dedicatedhosts.MockDedicatedHostScopeMockRecorder, m *mock_dedicatedhosts.MockClientMockRecorder)
+	}{
+		{
+			name:              "dedicated host in a zonal host group",
+			spec:              Spec{HostID: hostID, VMSize: "Standard_D2s_v3"},
+			expectedPlacement: &Placement{HostID: hostID, Zone: "2"},
+			expect: func(s *mock_dedicatedhosts.MockDedicatedHostScopeMockRecorder, m *mock_dedicatedhosts.MockClientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.SubscriptionID().AnyTimes().Return("123")
+				m.GetHostGroup(context.TODO(), "host-rg", "my-group").Return(zonalGroup, nil)
+			},
+		},
+		{
+			name:              "dedicated host in a regional host group",
+			spec:              Spec{HostID: hostID, VMSize: "Standard_D2s_v3"},
+			expectedPlacement: &Placement{HostID: hostID},
+			expect: func(s *mock_dedicatedhosts.MockDedicatedHostScopeMockRecorder, m *mock_dedicatedhosts.MockClientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.SubscriptionID().AnyTimes().Return("123")
+				m.GetHostGroup(context.TODO(), "host-rg", "my-group").Return(compute.DedicatedHostGroup{}, nil)
+			},
+		},
+		{
+			name:              "select the first host of the host group with capacity",
+			spec:              Spec{HostGroupID: hostGroupID, VMSize: "Standard_D2s_v3"},
+			expectedPlacement: &Placement{HostID: otherHostID, Zone: "2"},
+			expect: func(s *mock_dedicatedhosts.MockDedicatedHostScopeMockRecorder, m *mock_dedicatedhosts.MockClientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.SubscriptionID().AnyTimes().Return("123")
+				m.GetHostGroup(context.TODO(), "host-rg", "my-group").Return(zonalGroup, nil)
+				m.GetHost(context.TODO(), "host-rg", "my-group", "my-host").Return(hostWithCapacity(hostID, 0), nil)
+				m.GetHost(context.TODO(), "host-rg", "my-group", "other-host").Return(hostWithCapacity(otherHostID, 3), nil)
+			},
+		},
+		{
+			name:          "no host of the host group has capacity",
+			spec:          Spec{HostGroupID: hostGroupID, VMSize: "Standard_D2s_v3"},
+			expectedError: "no dedicated host of host group " + hostGroupID + " has capacity left for VM size Standard_D2s_v3",
+			expect: func(s *mock_dedicatedhosts.MockDedicatedHostScopeMockRecorder, m *mock_dedicatedhosts.MockClientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.SubscriptionID().AnyTimes().Return("123")
+				m.GetHostGroup(context.TODO(), "host-rg", "my-group").Return(zonalGroup, nil)
+				m.GetHost(context.TODO(), "host-rg", "my-group", "my-host").Return(hostWithCapacity(hostID, 0), nil)
+				m.GetHost(context.TODO(), "host-rg", "my-group", "other-host").Return(compute.DedicatedHost{}, nil)
+			},
+		},
+		{
+			name:          "host group in another subscription",
+			spec:          Spec{HostGroupID: "/subscriptions/456/resourceGroups/host-rg/providers/Microsoft.Compute/hostGroups/my-group"},
+			expectedError: "dedicated host group /subscriptions/456/resourceGroups/host-rg/providers/Microsoft.Compute/hostGroups/my-group must be in subscription 123",
+			expect: func(s *mock_dedicatedhosts.MockDedicatedHostScopeMockRecorder, m *mock_dedicatedhosts.MockClientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.SubscriptionID().AnyTimes().Return("123")
+			},
+		},
+		{
+			name:          "fail to get host group",
+			spec:          Spec{HostGroupID: hostGroupID},
+			expectedError: "failed to get dedicated host group " + hostGroupID + ": #: Not found: StatusCode=404",
+			expect: func(s *mock_dedicatedhosts.MockDedicatedHostScopeMockRecorder, m *mock_dedicatedhosts.MockClientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.SubscriptionID().AnyTimes().Return("123")
+				m.GetHostGroup(context.TODO(), "host-rg", "my-group").Return(compute.DedicatedHostGroup{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			scopeMock := mock_dedicatedhosts.NewMockDedicatedHostScope(mockCtrl)
+			clientMock := mock_dedicatedhosts.NewMockClient(mockCtrl)
+			tc.expect(scopeMock.EXPECT(), clientMock.EXPECT())
+
+			s := &Service{
+				Scope:  scopeMock,
+				Client: clientMock,
+			}
+
+			placement, err := s.Get(context.TODO(), &tc.spec)
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(placement).To(Equal(tc.expectedPlacement))
+			}
+		})
+	}
+}
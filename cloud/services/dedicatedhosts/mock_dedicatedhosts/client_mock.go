@@ -0,0 +1,81 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ../client.go
+
+// Package mock_dedicatedhosts is a generated GoMock package.
+package mock_dedicatedhosts
+
+import (
+	context "context"
+	compute "github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2019-12-01/compute"
+	gomock "github.com/golang/mock/gomock"
+	reflect "reflect"
+)
+
+// MockClient is a mock of Client interface.
+type MockClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockClientMockRecorder
+}
+
+// MockClientMockRecorder is the mock recorder for MockClient.
+type MockClientMockRecorder struct {
+	mock *MockClient
+}
+
+// NewMockClient creates a new mock instance.
+func NewMockClient(ctrl *gomock.Controller) *MockClient {
+	mock := &MockClient{ctrl: ctrl}
+	mock.recorder = &MockClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockClient) EXPECT() *MockClientMockRecorder {
+	return m.recorder
+}
+
+// GetHostGroup mocks base method.
+func (m *MockClient) GetHostGroup(arg0 context.Context, arg1, arg2 string) (compute.DedicatedHostGroup, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHostGroup", arg0, arg1, arg2)
+	ret0, _ := ret[0].(compute.DedicatedHostGroup)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetHostGroup indicates an expected call of GetHostGroup.
+func (mr *MockClientMockRecorder) GetHostGroup(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHostGroup", reflect.TypeOf((*MockClient)(nil).GetHostGroup), arg0, arg1, arg2)
+}
+
+// GetHost mocks base method.
+func (m *MockClient) GetHost(arg0 context.Context, arg1, arg2, arg3 string) (compute.DedicatedHost, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHost", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(compute.DedicatedHost)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetHost indicates an expected call of GetHost.
+func (mr *MockClientMockRecorder) GetHost(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHost", reflect.TypeOf((*MockClient)(nil).GetHost), arg0, arg1, arg2, arg3)
+}
@@ -0,0 +1,175 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dnsrecords
+
+import (
+	"context"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/services/dns/mgmt/2018-05-01/dns"
+	"github.com/Azure/azure-sdk-for-go/services/privatedns/mgmt/2018-09-01/privatedns"
+	autorestazure "github.com/Azure/go-autorest/autorest/azure"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/pkg/errors"
+	azure "sigs.k8s.io/cluster-api-provider-azure/cloud"
+)
+
+const (
+	// recordTTL is the TTL in seconds of the API server DNS records.
+	recordTTL = 300
+	// privateDNSLocation is the location of private DNS zones and their virtual network links.
+	privateDNSLocation = "global"
+)
+
+// Reconcile creates or updates the DNS records of the API server, and the virtual network links of private DNS zones.
+func (s *Service) Reconcile(ctx context.Context) error {
+	for _, recordSpec := range s.Scope.DNSRecordSpecs() {
+		zone, private, err := s.getZone(recordSpec)
+		if err != nil {
+			return err
+		}
+
+		if !private {
+			ip, err := s.getPublicIPAddress(ctx, recordSpec)
+			if err != nil {
+				return err
+			}
+			s.Scope.V(2).Info("creating DNS record", "DNS zone", zone.ResourceName, "record", recordSpec.RecordName, "ip", ip)
+			err = s.PublicDNSClient.CreateOrUpdateRecordSet(ctx, zone.ResourceGroup, zone.ResourceName, recordSpec.RecordName, dns.A, dns.RecordSet{
+				RecordSetProperties: &dns.RecordSetProperties{
+					TTL:      to.Int64Ptr(recordTTL),
+					ARecords: &[]dns.ARecord{{Ipv4Address: to.StringPtr(ip)}},
+				},
+			})
+			if err != nil {
+				return errors.Wrapf(err, "failed to create record %s in DNS zone %s", recordSpec.RecordName, zone.ResourceName)
+			}
+			s.Scope.V(2).Info("successfully created DNS record", "DNS zone", zone.ResourceName, "record", recordSpec.RecordName)
+			continue
+		}
+
+		if recordSpec.VnetLinkName != "" {
+			s.Scope.V(2).Info("creating private DNS zone virtual network link", "private DNS zone", zone.ResourceName, "link", recordSpec.VnetLinkName)
+			err := s.PrivateDNSClient.CreateOrUpdateVirtualNetworkLink(ctx, zone.ResourceGroup, zone.ResourceName, recordSpec.VnetLinkName, privatedns.VirtualNetworkLink{
+				Location: to.StringPtr(privateDNSLocation),
+				VirtualNetworkLinkProperties: &privatedns.VirtualNetworkLinkProperties{
+					VirtualNetwork:      &privatedns.SubResource{ID: to.StringPtr(recordSpec.VnetID)},
+					RegistrationEnabled: to.BoolPtr(false),
+				},
+			})
+			if err != nil {
+				return errors.Wrapf(err, "failed to link private DNS zone %s to virtual network %s", zone.ResourceName, recordSpec.VnetID)
+			}
+		}
+
+		ip, err := s.getInternalLBAddress(ctx, recordSpec)
+		if err != nil {
+			return err
+		}
+		s.Scope.V(2).Info("creating private DNS record", "private DNS zone", zone.ResourceName, "record", recordSpec.RecordName, "ip", ip)
+		err = s.PrivateDNSClient.CreateOrUpdateRecordSet(ctx, zone.SubscriptionID, zone.ResourceGroup, zone.ResourceName, privatedns.A, recordSpec.RecordName, privatedns.RecordSet{
+			RecordSetProperties: &privatedns.RecordSetProperties{
+				TTL:      to.Int64Ptr(recordTTL),
+				ARecords: &[]privatedns.ARecord{{Ipv4Address: to.StringPtr(ip)}},
+			},
+		})
+		if err != nil {
+			return errors.Wrapf(err, "failed to create record %s in private DNS zone %s", recordSpec.RecordName, zone.ResourceName)
+		}
+		s.Scope.V(2).Info("successfully created private DNS record", "private DNS zone", zone.ResourceName, "record", recordSpec.RecordName)
+	}
+	return nil
+}
+
+// Delete deletes the DNS records of the API server and the virtual network links created for private DNS zones.
+func (s *Service) Delete(ctx context.Context) error {
+	for _, recordSpec := range s.Scope.DNSRecordSpecs() {
+		zone, private, err := s.getZone(recordSpec)
+		if err != nil {
+			return err
+		}
+
+		if !private {
+			s.Scope.V(2).Info("deleting DNS record", "DNS zone", zone.ResourceName, "record", recordSpec.RecordName)
+			err := s.PublicDNSClient.DeleteRecordSet(ctx, zone.ResourceGroup, zone.ResourceName, recordSpec.RecordName, dns.A)
+			if err != nil && !azure.ResourceNotFound(err) {
+				return errors.Wrapf(err, "failed to delete record %s in DNS zone %s", recordSpec.RecordName, zone.ResourceName)
+			}
+			continue
+		}
+
+		s.Scope.V(2).Info("deleting private DNS record", "private DNS zone", zone.ResourceName, "record", recordSpec.RecordName)
+		err = s.PrivateDNSClient.DeleteRecordSet(ctx, zone.SubscriptionID, zone.ResourceGroup, zone.ResourceName, privatedns.A, recordSpec.RecordName)
+		if err != nil && !azure.ResourceNotFound(err) {
+			return errors.Wrapf(err, "failed to delete record %s in private DNS zone %s", recordSpec.RecordName, zone.ResourceName)
+		}
+		if recordSpec.VnetLinkName != "" {
+			s.Scope.V(2).Info("deleting private DNS zone virtual network link", "private DNS zone", zone.ResourceName, "link", recordSpec.VnetLinkName)
+			err = s.PrivateDNSClient.DeleteVirtualNetworkLink(ctx, zone.ResourceGroup, zone.ResourceName, recordSpec.VnetLinkName)
+			if err != nil && !azure.ResourceNotFound(err) {
+				return errors.Wrapf(err, "failed to delete virtual network link %s of private DNS zone %s", recordSpec.VnetLinkName, zone.ResourceName)
+			}
+		}
+	}
+	return nil
+}
+
+// getZone returns the DNS zone of a record and whether it is a private DNS zone.
+// Public zones must be in the cluster subscription, private zones in the subscription of the virtual network.
+func (s *Service) getZone(recordSpec azure.DNSRecordSpec) (autorestazure.Resource, bool, error) {
+	zone, err := autorestazure.ParseResourceID(recordSpec.ZoneID)
+	if err != nil {
+		return zone, false, errors.Wrapf(err, "failed to parse DNS zone ID %s", recordSpec.ZoneID)
+	}
+	private := strings.EqualFold(zone.ResourceType, "privateDnsZones")
+	subscriptionID := s.Scope.SubscriptionID()
+	if private {
+		subscriptionID = azure.VnetAuthorizer(s.Scope).SubscriptionID()
+	}
+	if zone.SubscriptionID != subscriptionID {
+		return zone, private, errors.Errorf("DNS zone %s must be in the subscription %s", recordSpec.ZoneID, subscriptionID)
+	}
+	return zone, private, nil
+}
+
+// getPublicIPAddress returns the address of the API server public IP.
+func (s *Service) getPublicIPAddress(ctx context.Context, recordSpec azure.DNSRecordSpec) (string, error) {
+	ip, err := s.PublicIPsClient.Get(ctx, recordSpec.PublicIPResourceGroup, recordSpec.PublicIPName)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to get public IP %s", recordSpec.PublicIPName)
+	}
+	if ip.PublicIPAddressPropertiesFormat == nil || to.String(ip.IPAddress) == "" {
+		return "", errors.Errorf("public IP %s has no address", recordSpec.PublicIPName)
+	}
+	return to.String(ip.IPAddress), nil
+}
+
+// getInternalLBAddress returns the frontend address of the internal load balancer.
+func (s *Service) getInternalLBAddress(ctx context.Context, recordSpec azure.DNSRecordSpec) (string, error) {
+	lb, err := s.InternalLBClient.Get(ctx, s.Scope.ResourceGroup(), recordSpec.InternalLBName)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to get internal load balancer %s", recordSpec.InternalLBName)
+	}
+	if lb.LoadBalancerPropertiesFormat != nil && lb.FrontendIPConfigurations != nil {
+		for _, frontend := range *lb.FrontendIPConfigurations {
+			if frontend.FrontendIPConfigurationPropertiesFormat != nil && to.String(frontend.PrivateIPAddress) != "" {
+				return to.String(frontend.PrivateIPAddress), nil
+			}
+		}
+	}
+	return "", errors.Errorf("internal load balancer %s has no frontend address", recordSpec.InternalLBName)
+}
@@ -0,0 +1,314 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dnsrecords
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/dns/mgmt/2018-05-01/dns"
+	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
+	"github.com/Azure/azure-sdk-for-go/services/privatedns/mgmt/2018-09-01/privatedns"
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+	"k8s.io/klog/klogr"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha3"
+	azure "sigs.k8s.io/cluster-api-provider-azure/cloud"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/dnsrecords/mock_dnsrecords"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/internalloadbalancers/mock_internalloadbalancers"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/privatedns/mock_privatedns"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/publicdns/mock_publicdns"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/publicips/mock_publicips"
+)
+
+const (
+	zoneID        = "/subscriptions/123/resourceGroups/dns-rg/providers/Microsoft.Network/dnszones/example.com"
+	privateZoneID = "/subscriptions/123/resourceGroups/dns-rg/providers/Microsoft.Network/privateDnsZones/example.internal"
+	vnetID        = "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/virtualNetworks/my-vnet"
+)
+
+func TestReconcileDNSRecords(t *testing.T) {
+	testcases := []struct {
+		name          string
+		expectedError string
+		expect        func(s *mock_dnsrecords.MockDNSRecordScopeMockRecorder,
+			mPublicDNS *mock_publicdns.MockClientMockRecorder,
+			mPrivateDNS *mock_privatedns.MockClientMockRecorder,
+			mIP *mock_publicips.MockClientMockRecorder,
+			mLB *mock_internalloadbalancers.MockClientMockRecorder)
+	}{
+		{
+			name:          "no DNS records",
+			expectedError: "",
+			expect: func(s *mock_dnsrecords.MockDNSRecordScopeMockRecorder,
+				mPublicDNS *mock_publicdns.MockClientMockRecorder,
+				mPrivateDNS *mock_privatedns.MockClientMockRecorder,
+				mIP *mock_publicips.MockClientMockRecorder,
+				mLB *mock_internalloadbalancers.MockClientMockRecorder) {
+				s.DNSRecordSpecs().Return(nil)
+			},
+		},
+		{
+			name:          "public DNS zone",
+			expectedError: "",
+			expect: func(s *mock_dnsrecords.MockDNSRecordScopeMockRecorder,
+				mPublicDNS *mock_publicdns.MockClientMockRecorder,
+				mPrivateDNS *mock_privatedns.MockClientMockRecorder,
+				mIP *mock_publicips.MockClientMockRecorder,
+				mLB *mock_internalloadbalancers.MockClientMockRecorder) {
+				s.DNSRecordSpecs().Return([]azure.DNSRecordSpec{
+					{
+						ZoneID:                zoneID,
+						RecordName:            "api.my-cluster",
+						PublicIPName:          "my-publicip",
+						PublicIPResourceGroup: "my-rg",
+						InternalLBName:        "my-cluster-internal-lb",
+					},
+				})
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.SubscriptionID().AnyTimes().Return("123")
+				mIP.Get(context.TODO(), "my-rg", "my-publicip").Return(network.PublicIPAddress{
+					PublicIPAddressPropertiesFormat: &network.PublicIPAddressPropertiesFormat{IPAddress: to.StringPtr("20.1.2.3")},
+				}, nil)
+				mPublicDNS.CreateOrUpdateRecordSet(context.TODO(), "dns-rg", "example.com", "api.my-cluster", dns.A, dns.RecordSet{
+					RecordSetProperties: &dns.RecordSetProperties{
+						TTL:      to.Int64Ptr(300),
+						ARecords: &[]dns.ARecord{{Ipv4Address: to.StringPtr("20.1.2.3")}},
+					},
+				})
+			},
+		},
+		{
+			name:          "private DNS zone with virtual network link",
+			expectedError: "",
+			expect: func(s *mock_dnsrecords.MockDNSRecordScopeMockRecorder,
+				mPublicDNS *mock_publicdns.MockClientMockRecorder,
+				mPrivateDNS *mock_privatedns.MockClientMockRecorder,
+				mIP *mock_publicips.MockClientMockRecorder,
+				mLB *mock_internalloadbalancers.MockClientMockRecorder) {
+				s.DNSRecordSpecs().Return([]azure.DNSRecordSpec{
+					{
+						ZoneID:         privateZoneID,
+						RecordName:     "api.my-cluster",
+						PublicIPName:   "my-publicip",
+						InternalLBName: "my-cluster-internal-lb",
+						VnetLinkName:   "my-cluster-vnet-link",
+						VnetID:         vnetID,
+					},
+				})
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.Vnet().AnyTimes().Return(&infrav1.VnetSpec{Name: "my-vnet"})
+				s.SubscriptionID().AnyTimes().Return("123")
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				mPrivateDNS.CreateOrUpdateVirtualNetworkLink(context.TODO(), "dns-rg", "example.internal", "my-cluster-vnet-link", privatedns.VirtualNetworkLink{
+					Location: to.StringPtr("global"),
+					VirtualNetworkLinkProperties: &privatedns.VirtualNetworkLinkProperties{
+						VirtualNetwork:      &privatedns.SubResource{ID: to.StringPtr(vnetID)},
+						RegistrationEnabled: to.BoolPtr(false),
+					},
+				})
+				mLB.Get(context.TODO(), "my-rg", "my-cluster-internal-lb").Return(network.LoadBalancer{
+					LoadBalancerPropertiesFormat: &network.LoadBalancerPropertiesFormat{
+						FrontendIPConfigurations: &[]network.FrontendIPConfiguration{
+							{
+								FrontendIPConfigurationPropertiesFormat: &network.FrontendIPConfigurationPropertiesFormat{
+									PrivateIPAddress: to.StringPtr("10.0.0.100"),
+								},
+							},
+						},
+					},
+				}, nil)
+				mPrivateDNS.CreateOrUpdateRecordSet(context.TODO(), "123", "dns-rg", "example.internal", privatedns.A, "api.my-cluster", privatedns.RecordSet{
+					RecordSetProperties: &privatedns.RecordSetProperties{
+						TTL:      to.Int64Ptr(300),
+						ARecords: &[]privatedns.ARecord{{Ipv4Address: to.StringPtr("10.0.0.100")}},
+					},
+				})
+			},
+		},
+		{
+			name:          "public DNS zone in another subscription",
+			expectedError: "DNS zone /subscriptions/456/resourceGroups/dns-rg/providers/Microsoft.Network/dnszones/example.com must be in the subscription 123",
+			expect: func(s *mock_dnsrecords.MockDNSRecordScopeMockRecorder,
+				mPublicDNS *mock_publicdns.MockClientMockRecorder,
+				mPrivateDNS *mock_privatedns.MockClientMockRecorder,
+				mIP *mock_publicips.MockClientMockRecorder,
+				mLB *mock_internalloadbalancers.MockClientMockRecorder) {
+				s.DNSRecordSpecs().Return([]azure.DNSRecordSpec{
+					{
+						ZoneID:     "/subscriptions/456/resourceGroups/dns-rg/providers/Microsoft.Network/dnszones/example.com",
+						RecordName: "api.my-cluster",
+					},
+				})
+				s.SubscriptionID().AnyTimes().Return("123")
+			},
+		},
+		{
+			name:          "public IP without address",
+			expectedError: "public IP my-publicip has no address",
+			expect: func(s *mock_dnsrecords.MockDNSRecordScopeMockRecorder,
+				mPublicDNS *mock_publicdns.MockClientMockRecorder,
+				mPrivateDNS *mock_privatedns.MockClientMockRecorder,
+				mIP *mock_publicips.MockClientMockRecorder,
+				mLB *mock_internalloadbalancers.MockClientMockRecorder) {
+				s.DNSRecordSpecs().Return([]azure.DNSRecordSpec{
+					{
+						ZoneID:                zoneID,
+						RecordName:            "api.my-cluster",
+						PublicIPName:          "my-publicip",
+						PublicIPResourceGroup: "my-rg",
+					},
+				})
+				s.SubscriptionID().AnyTimes().Return("123")
+				mIP.Get(context.TODO(), "my-rg", "my-publicip").Return(network.PublicIPAddress{}, nil)
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_dnsrecords.NewMockDNSRecordScope(mockCtrl)
+			publicDNSMock := mock_publicdns.NewMockClient(mockCtrl)
+			privateDNSMock := mock_privatedns.NewMockClient(mockCtrl)
+			publicIPsMock := mock_publicips.NewMockClient(mockCtrl)
+			internalLBMock := mock_internalloadbalancers.NewMockClient(mockCtrl)
+
+			tc.expect(scopeMock.EXPECT(), publicDNSMock.EXPECT(), privateDNSMock.EXPECT(), publicIPsMock.EXPECT(), internalLBMock.EXPECT())
+
+			s := &Service{
+				Scope:            scopeMock,
+				PublicDNSClient:  publicDNSMock,
+				PrivateDNSClient: privateDNSMock,
+				PublicIPsClient:  publicIPsMock,
+				InternalLBClient: internalLBMock,
+			}
+
+			err := s.Reconcile(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestDeleteDNSRecords(t *testing.T) {
+	testcases := []struct {
+		name          string
+		expectedError string
+		expect        func(s *mock_dnsrecords.MockDNSRecordScopeMockRecorder,
+			mPublicDNS *mock_publicdns.MockClientMockRecorder,
+			mPrivateDNS *mock_privatedns.MockClientMockRecorder)
+	}{
+		{
+			name:          "delete public DNS record",
+			expectedError: "",
+			expect: func(s *mock_dnsrecords.MockDNSRecordScopeMockRecorder,
+				mPublicDNS *mock_publicdns.MockClientMockRecorder,
+				mPrivateDNS *mock_privatedns.MockClientMockRecorder) {
+				s.DNSRecordSpecs().Return([]azure.DNSRecordSpec{
+					{
+						ZoneID:     zoneID,
+						RecordName: "api.my-cluster",
+					},
+				})
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.SubscriptionID().AnyTimes().Return("123")
+				mPublicDNS.DeleteRecordSet(context.TODO(), "dns-rg", "example.com", "api.my-cluster", dns.A)
+			},
+		},
+		{
+			name:          "delete private DNS record and virtual network link",
+			expectedError: "",
+			expect: func(s *mock_dnsrecords.MockDNSRecordScopeMockRecorder,
+				mPublicDNS *mock_publicdns.MockClientMockRecorder,
+				mPrivateDNS *mock_privatedns.MockClientMockRecorder) {
+				s.DNSRecordSpecs().Return([]azure.DNSRecordSpec{
+					{
+						ZoneID:       privateZoneID,
+						RecordName:   "api.my-cluster",
+						VnetLinkName: "my-cluster-vnet-link",
+						VnetID:       vnetID,
+					},
+				})
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.Vnet().AnyTimes().Return(&infrav1.VnetSpec{Name: "my-vnet"})
+				s.SubscriptionID().AnyTimes().Return("123")
+				mPrivateDNS.DeleteRecordSet(context.TODO(), "123", "dns-rg", "example.internal", privatedns.A, "api.my-cluster").
+					Return(autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
+				mPrivateDNS.DeleteVirtualNetworkLink(context.TODO(), "dns-rg", "example.internal", "my-cluster-vnet-link")
+			},
+		},
+		{
+			name:          "public DNS record deletion fails",
+			expectedError: "failed to delete record api.my-cluster in DNS zone example.com: #: Internal Server Error: StatusCode=500",
+			expect: func(s *mock_dnsrecords.MockDNSRecordScopeMockRecorder,
+				mPublicDNS *mock_publicdns.MockClientMockRecorder,
+				mPrivateDNS *mock_privatedns.MockClientMockRecorder) {
+				s.DNSRecordSpecs().Return([]azure.DNSRecordSpec{
+					{
+						ZoneID:     zoneID,
+						RecordName: "api.my-cluster",
+					},
+				})
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.SubscriptionID().AnyTimes().Return("123")
+				mPublicDNS.DeleteRecordSet(context.TODO(), "dns-rg", "example.com", "api.my-cluster", dns.A).
+					Return(autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error"))
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_dnsrecords.NewMockDNSRecordScope(mockCtrl)
+			publicDNSMock := mock_publicdns.NewMockClient(mockCtrl)
+			privateDNSMock := mock_privatedns.NewMockClient(mockCtrl)
+
+			tc.expect(scopeMock.EXPECT(), publicDNSMock.EXPECT(), privateDNSMock.EXPECT())
+
+			s := &Service{
+				Scope:            scopeMock,
+				PublicDNSClient:  publicDNSMock,
+				PrivateDNSClient: privateDNSMock,
+			}
+
+			err := s.Delete(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
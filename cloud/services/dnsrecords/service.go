@@ -0,0 +1,53 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dnsrecords
+
+import (
+	"github.com/go-logr/logr"
+	azure "sigs.k8s.io/cluster-api-provider-azure/cloud"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/internalloadbalancers"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/privatedns"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/publicdns"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/publicips"
+)
+
+// DNSRecordScope defines the scope interface for a DNS record service.
+type DNSRecordScope interface {
+	logr.Logger
+	azure.ClusterDescriber
+	DNSRecordSpecs() []azure.DNSRecordSpec
+}
+
+// Service provides operations on azure resources
+type Service struct {
+	Scope            DNSRecordScope
+	PublicDNSClient  publicdns.Client
+	PrivateDNSClient privatedns.Client
+	PublicIPsClient  publicips.Client
+	InternalLBClient internalloadbalancers.Client
+}
+
+// NewService creates a new service.
+func NewService(scope DNSRecordScope) *Service {
+	return &Service{
+		Scope:            scope,
+		PublicDNSClient:  publicdns.NewClient(scope),
+		PrivateDNSClient: privatedns.NewClient(azure.VnetAuthorizer(scope)),
+		PublicIPsClient:  publicips.NewClient(scope),
+		InternalLBClient: internalloadbalancers.NewClient(scope),
+	}
+}
@@ -0,0 +1,110 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package images
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2019-12-01/compute"
+	"k8s.io/apimachinery/pkg/util/cache"
+)
+
+// imageCacheTTL is how long image lookups are cached. New image versions are seen after at most this delay.
+const imageCacheTTL = 10 * time.Minute
+
+// imageCache holds the image lookups of all the clusters managed by the controller, as images are looked up on every
+// reconcile of every machine and rarely change.
+var imageCache = cache.NewExpiring()
+
+// cachedClient is a Client caching successful lookups in imageCache.
+type cachedClient struct {
+	Client
+	subscriptionID string
+	// identity is the tenant and client of the credentials of the client, as clusters with different credentials
+	// may not be allowed to see the same images.
+	identity string
+}
+
+var _ Client = &cachedClient{}
+
+// newCachedClient creates a Client caching the lookups of the client. Lookups are cached per identity of the client,
+// and marketplace lookups also per subscription, since the offers available to subscriptions differ.
+func newCachedClient(client Client, subscriptionID, tenantID, clientID string) *cachedClient {
+	return &cachedClient{
+		Client:         client,
+		subscriptionID: subscriptionID,
+		identity:       fmt.Sprintf("%s/%s", tenantID, clientID),
+	}
+}
+
+// ListMarketplaceImageVersions lists the versions of a marketplace image SKU available in a location.
+func (c *cachedClient) ListMarketplaceImageVersions(ctx context.Context, location, publisher, offer, sku string) ([]compute.VirtualMachineImageResource, error) {
+	key := fmt.Sprintf("marketplace-versions/%s/%s/%s/%s/%s/%s", c.identity, c.subscriptionID, location, publisher, offer, sku)
+	if cached, ok := imageCache.Get(key); ok {
+		return cached.([]compute.VirtualMachineImageResource), nil
+	}
+	versions, err := c.Client.ListMarketplaceImageVersions(ctx, location, publisher, offer, sku)
+	if err != nil {
+		return nil, err
+	}
+	imageCache.Set(key, versions, imageCacheTTL)
+	return versions, nil
+}
+
+// ListGalleryImageVersions lists the versions of an image of a shared image gallery.
+func (c *cachedClient) ListGalleryImageVersions(ctx context.Context, subscriptionID, resourceGroupName, galleryName, imageName string) ([]compute.GalleryImageVersion, error) {
+	key := fmt.Sprintf("gallery-versions/%s/%s/%s/%s/%s", c.identity, subscriptionID, resourceGroupName, galleryName, imageName)
+	if cached, ok := imageCache.Get(key); ok {
+		return cached.([]compute.GalleryImageVersion), nil
+	}
+	versions, err := c.Client.ListGalleryImageVersions(ctx, subscriptionID, resourceGroupName, galleryName, imageName)
+	if err != nil {
+		return nil, err
+	}
+	imageCache.Set(key, versions, imageCacheTTL)
+	return versions, nil
+}
+
+// GetMarketplaceImage gets a version of a marketplace image SKU available in a location.
+func (c *cachedClient) GetMarketplaceImage(ctx context.Context, location, publisher, offer, sku, version string) (compute.VirtualMachineImage, error) {
+	key := fmt.Sprintf("marketplace-image/%s/%s/%s/%s/%s/%s/%s", c.identity, c.subscriptionID, location, publisher, offer, sku, version)
+	if cached, ok := imageCache.Get(key); ok {
+		return cached.(compute.VirtualMachineImage), nil
+	}
+	image, err := c.Client.GetMarketplaceImage(ctx, location, publisher, offer, sku, version)
+	if err != nil {
+		return compute.VirtualMachineImage{}, err
+	}
+	imageCache.Set(key, image, imageCacheTTL)
+	return image, nil
+}
+
+// GetGalleryImageVersion gets a version of an image of a shared image gallery.
+func (c *cachedClient) GetGalleryImageVersion(ctx context.Context, subscriptionID, resourceGroupName, galleryName, imageName, version string) (compute.GalleryImageVersion, error) {
+	key := fmt.Sprintf("gallery-version/%s/%s/%s/%s/%s/%s", c.identity, subscriptionID, resourceGroupName, galleryName, imageName, version)
+	if cached, ok := imageCache.Get(key); ok {
+		return cached.(compute.GalleryImageVersion), nil
+	}
+	imageVersion, err := c.Client.GetGalleryImageVersion(ctx, subscriptionID, resourceGroupName, galleryName, imageName, version)
+	if err != nil {
+		return compute.GalleryImageVersion{}, err
+	}
+	imageCache.Set(key, imageVersion, imageCacheTTL)
+	return imageVersion, nil
+}
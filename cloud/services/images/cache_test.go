@@ -0,0 +1,72 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package images
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2019-12-01/compute"
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/images/mock_images"
+)
+
+func TestCachedClient(t *testing.T) {
+	g := NewWithT(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	imagesMock := mock_images.NewMockClient(mockCtrl)
+	versions := []compute.VirtualMachineImageResource{{Name: to.StringPtr("1.0.0")}}
+	gomock.InOrder(
+		imagesMock.EXPECT().ListMarketplaceImageVersions(gomock.Any(), "cache-location", "my-publisher", "my-offer", "my-sku").
+			Return(nil, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error")),
+		imagesMock.EXPECT().ListMarketplaceImageVersions(gomock.Any(), "cache-location", "my-publisher", "my-offer", "my-sku").
+			Return(versions, nil),
+	)
+	imagesMock.EXPECT().ListMarketplaceImageVersions(gomock.Any(), "cache-location", "my-publisher", "my-offer", "my-sku").
+		Times(2).Return(versions, nil)
+
+	c := newCachedClient(imagesMock, "cache-subscription", "my-tenant", "my-client")
+	otherSubscription := newCachedClient(imagesMock, "other-cache-subscription", "my-tenant", "my-client")
+	otherIdentity := newCachedClient(imagesMock, "cache-subscription", "my-tenant", "other-client")
+
+	// Errors are not cached.
+	_, err := c.ListMarketplaceImageVersions(context.TODO(), "cache-location", "my-publisher", "my-offer", "my-sku")
+	g.Expect(err).To(HaveOccurred())
+
+	for i := 0; i < 2; i++ {
+		result, err := c.ListMarketplaceImageVersions(context.TODO(), "cache-location", "my-publisher", "my-offer", "my-sku")
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(result).To(Equal(versions))
+	}
+
+	// Marketplace lookups are cached per subscription.
+	result, err := otherSubscription.ListMarketplaceImageVersions(context.TODO(), "cache-location", "my-publisher", "my-offer", "my-sku")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(result).To(Equal(versions))
+
+	// Lookups are cached per identity.
+	result, err = otherIdentity.ListMarketplaceImageVersions(context.TODO(), "cache-location", "my-publisher", "my-offer", "my-sku")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(result).To(Equal(versions))
+}
@@ -0,0 +1,110 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package images
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2019-12-01/compute"
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/pkg/errors"
+
+	azure "sigs.k8s.io/cluster-api-provider-azure/cloud"
+)
+
+// Client wraps go-sdk
+type Client interface {
+	ListMarketplaceImageVersions(context.Context, string, string, string, string) ([]compute.VirtualMachineImageResource, error)
+	ListGalleryImageVersions(context.Context, string, string, string, string) ([]compute.GalleryImageVersion, error)
+	GetMarketplaceImage(context.Context, string, string, string, string, string) (compute.VirtualMachineImage, error)
+	GetGalleryImageVersion(context.Context, string, string, string, string, string) (compute.GalleryImageVersion, error)
+}
+
+// AzureClient contains the Azure go-sdk Client
+type AzureClient struct {
+	marketplaceimages compute.VirtualMachineImagesClient
+	auth              azure.Authorizer
+}
+
+var _ Client = &AzureClient{}
+
+// NewClient creates a new images client from subscription ID.
+func NewClient(auth azure.Authorizer) *AzureClient {
+	return &AzureClient{
+		marketplaceimages: newVirtualMachineImagesClient(auth.SubscriptionID(), auth.BaseURI(), auth.Authorizer()),
+		auth:              auth,
+	}
+}
+
+// newVirtualMachineImagesClient creates a new marketplace images client from subscription ID.
+func newVirtualMachineImagesClient(subscriptionID string, baseURI string, authorizer autorest.Authorizer) compute.VirtualMachineImagesClient {
+	imagesClient := compute.NewVirtualMachineImagesClientWithBaseURI(baseURI, subscriptionID)
+	imagesClient.Authorizer = authorizer
+	imagesClient.AddToUserAgent(azure.UserAgent())
+	return imagesClient
+}
+
+// newGalleryImageVersionsClient creates a new shared gallery image versions client from subscription ID.
+func newGalleryImageVersionsClient(subscriptionID string, baseURI string, authorizer autorest.Authorizer) compute.GalleryImageVersionsClient {
+	versionsClient := compute.NewGalleryImageVersionsClientWithBaseURI(baseURI, subscriptionID)
+	versionsClient.Authorizer = authorizer
+	versionsClient.AddToUserAgent(azure.UserAgent())
+	return versionsClient
+}
+
+// ListMarketplaceImageVersions lists the versions of a marketplace image SKU available in a location.
+func (ac *AzureClient) ListMarketplaceImageVersions(ctx context.Context, location, publisher, offer, sku string) ([]compute.VirtualMachineImageResource, error) {
+	result, err := ac.marketplaceimages.List(ctx, location, publisher, offer, sku, "", nil, "")
+	if err != nil {
+		return nil, err
+	}
+	if result.Value == nil {
+		return nil, nil
+	}
+	return *result.Value, nil
+}
+
+// ListGalleryImageVersions lists the versions of an image of a shared image gallery, which may be in another
+// subscription than the one of the cluster.
+func (ac *AzureClient) ListGalleryImageVersions(ctx context.Context, subscriptionID, resourceGroupName, galleryName, imageName string) ([]compute.GalleryImageVersion, error) {
+	versionsClient := newGalleryImageVersionsClient(subscriptionID, ac.auth.BaseURI(), ac.auth.Authorizer())
+	iter, err := versionsClient.ListByGalleryImageComplete(ctx, resourceGroupName, galleryName, imageName)
+	if err != nil {
+		return nil, err
+	}
+
+	var versions []compute.GalleryImageVersion
+	for iter.NotDone() {
+		versions = append(versions, iter.Value())
+		if err := iter.NextWithContext(ctx); err != nil {
+			return versions, errors.Wrap(err, "could not iterate shared gallery image versions")
+		}
+	}
+	return versions, nil
+}
+
+// GetMarketplaceImage gets a version of a marketplace image SKU available in a location.
+func (ac *AzureClient) GetMarketplaceImage(ctx context.Context, location, publisher, offer, sku, version string) (compute.VirtualMachineImage, error) {
+	return ac.marketplaceimages.Get(ctx, location, publisher, offer, sku, version)
+}
+
+// GetGalleryImageVersion gets a version of an image of a shared image gallery, which may be in another subscription
+// than the one of the cluster.
+func (ac *AzureClient) GetGalleryImageVersion(ctx context.Context, subscriptionID, resourceGroupName, galleryName, imageName, version string) (compute.GalleryImageVersion, error) {
+	versionsClient := newGalleryImageVersionsClient(subscriptionID, ac.auth.BaseURI(), ac.auth.Authorizer())
+	return versionsClient.Get(ctx, resourceGroupName, galleryName, imageName, version, "")
+}
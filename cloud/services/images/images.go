@@ -0,0 +1,218 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package images
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2019-12-01/compute"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/pkg/errors"
+	capierrors "sigs.k8s.io/cluster-api/errors"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha3"
+	azure "sigs.k8s.io/cluster-api-provider-azure/cloud"
+)
+
+// Spec input specification for Get calls
+type Spec struct {
+	Image *infrav1.Image
+}
+
+// Get returns a copy of the marketplace or shared gallery image of the spec with its version set to the latest
+// version available in the location of the cluster. Images referred to by ID are returned unchanged. It returns a
+// terminal error when the image doesn't exist.
+func (s *Service) Get(ctx context.Context, spec interface{}) (interface{}, error) {
+	imageSpec, ok := spec.(*Spec)
+	if !ok {
+		return nil, errors.New("invalid image specification")
+	}
+
+	image := imageSpec.Image.DeepCopy()
+	switch {
+	case image.Marketplace != nil:
+		mp := image.Marketplace
+		resources, err := s.Client.ListMarketplaceImageVersions(ctx, s.Scope.Location(), mp.Publisher, mp.Offer, mp.SKU)
+		if err != nil && !azure.ResourceNotFound(err) {
+			return nil, errors.Wrapf(err, "failed to list versions of marketplace image %s:%s:%s", mp.Publisher, mp.Offer, mp.SKU)
+		}
+		versions := make([]string, 0, len(resources))
+		for _, resource := range resources {
+			versions = append(versions, to.String(resource.Name))
+		}
+		latest := latestVersion(versions)
+		if latest == "" {
+			return nil, azure.NewTerminalError(capierrors.InvalidConfigurationMachineError,
+				errors.Errorf("marketplace image with publisher %q, offer %q and SKU %q not found in location %s", mp.Publisher, mp.Offer, mp.SKU, s.Scope.Location()))
+		}
+		mp.Version = latest
+	case image.SharedGallery != nil:
+		sig := image.SharedGallery
+		galleryVersions, err := s.Client.ListGalleryImageVersions(ctx, sig.SubscriptionID, sig.ResourceGroup, sig.Gallery, sig.Name)
+		if err != nil && !azure.ResourceNotFound(err) {
+			return nil, errors.Wrapf(err, "failed to list versions of shared gallery image %s/%s", sig.Gallery, sig.Name)
+		}
+		versions := make([]string, 0, len(galleryVersions))
+		for _, version := range galleryVersions {
+			// Versions excluded from latest are never picked when creating a VM from the latest version.
+			if version.GalleryImageVersionProperties != nil && version.PublishingProfile != nil && to.Bool(version.PublishingProfile.ExcludeFromLatest) {
+				continue
+			}
+			versions = append(versions, to.String(version.Name))
+		}
+		latest := latestVersion(versions)
+		if latest == "" {
+			return nil, azure.NewTerminalError(capierrors.InvalidConfigurationMachineError,
+				errors.Errorf("shared gallery image %q of gallery %q in resource group %q of subscription %s not found or has no versions", sig.Name, sig.Gallery, sig.ResourceGroup, sig.SubscriptionID))
+		}
+		sig.Version = latest
+	}
+	return image, nil
+}
+
+// Validate checks that the version of a marketplace or shared gallery image exists. It returns a terminal error when
+// it doesn't, so that a typo in the image fails the machine before any resource is created for it.
+func (s *Service) Validate(ctx context.Context, image *infrav1.Image) error {
+	switch {
+	case image.Marketplace != nil && image.Marketplace.Version == azure.LatestVersion,
+		image.SharedGallery != nil && image.SharedGallery.Version == azure.LatestVersion:
+		_, err := s.Get(ctx, &Spec{Image: image})
+		return err
+	case image.Marketplace != nil:
+		_, err := s.getMarketplaceImage(ctx, image.Marketplace)
+		return err
+	case image.SharedGallery != nil:
+		sig := image.SharedGallery
+		_, err := s.Client.GetGalleryImageVersion(ctx, sig.SubscriptionID, sig.ResourceGroup, sig.Gallery, sig.Name, sig.Version)
+		if azure.ResourceNotFound(err) {
+			return azure.NewTerminalError(capierrors.InvalidConfigurationMachineError,
+				errors.Errorf("version %q of shared gallery image %q of gallery %q in resource group %q of subscription %s not found", sig.Version, sig.Name, sig.Gallery, sig.ResourceGroup, sig.SubscriptionID))
+		}
+		return errors.Wrapf(err, "failed to get version %s of shared gallery image %s/%s", sig.Version, sig.Gallery, sig.Name)
+	}
+	return nil
+}
+
+// GetPlan returns the purchase plan of a version of a marketplace image, or nil if the image doesn't have one. It
+// returns a terminal error when the image doesn't exist.
+func (s *Service) GetPlan(ctx context.Context, mp *infrav1.AzureMarketplaceImage) (*infrav1.ImagePlan, error) {
+	image, err := s.getMarketplaceImage(ctx, mp)
+	if err != nil {
+		return nil, err
+	}
+	if image.VirtualMachineImageProperties == nil || image.Plan == nil {
+		return nil, nil
+	}
+	return &infrav1.ImagePlan{
+		Publisher: to.String(image.Plan.Publisher),
+		Product:   to.String(image.Plan.Product),
+		Name:      to.String(image.Plan.Name),
+	}, nil
+}
+
+// getMarketplaceImage gets a version of a marketplace image, or returns a terminal error when it doesn't exist.
+func (s *Service) getMarketplaceImage(ctx context.Context, mp *infrav1.AzureMarketplaceImage) (compute.VirtualMachineImage, error) {
+	image, err := s.Client.GetMarketplaceImage(ctx, s.Scope.Location(), mp.Publisher, mp.Offer, mp.SKU, mp.Version)
+	if azure.ResourceNotFound(err) {
+		return image, azure.NewTerminalError(capierrors.InvalidConfigurationMachineError,
+			errors.Errorf("marketplace image with publisher %q, offer %q, SKU %q and version %q not found in location %s", mp.Publisher, mp.Offer, mp.SKU, mp.Version, s.Scope.Location()))
+	}
+	return image, errors.Wrapf(err, "failed to get marketplace image %s:%s:%s:%s", mp.Publisher, mp.Offer, mp.SKU, mp.Version)
+}
+
+// Version returns the version of a marketplace or shared gallery image, or an empty string for an image referred
+// to by ID.
+func Version(image *infrav1.Image) string {
+	switch {
+	case image == nil:
+		return ""
+	case image.Marketplace != nil:
+		return image.Marketplace.Version
+	case image.SharedGallery != nil:
+		return image.SharedGallery.Version
+	}
+	return ""
+}
+
+// IsPinnedVersionOf returns whether the pinned image is the image with its version resolved, i.e. it is the same
+// marketplace SKU or shared gallery image and the image refers either to its latest version or to the pinned one.
+func IsPinnedVersionOf(pinned, image *infrav1.Image) bool {
+	if pinned == nil || image == nil {
+		return false
+	}
+	switch {
+	case pinned.Marketplace != nil && image.Marketplace != nil:
+		if !strings.EqualFold(pinned.Marketplace.Publisher, image.Marketplace.Publisher) ||
+			!strings.EqualFold(pinned.Marketplace.Offer, image.Marketplace.Offer) ||
+			!strings.EqualFold(pinned.Marketplace.SKU, image.Marketplace.SKU) {
+			return false
+		}
+	case pinned.SharedGallery != nil && image.SharedGallery != nil:
+		if !strings.EqualFold(pinned.SharedGallery.SubscriptionID, image.SharedGallery.SubscriptionID) ||
+			!strings.EqualFold(pinned.SharedGallery.ResourceGroup, image.SharedGallery.ResourceGroup) ||
+			!strings.EqualFold(pinned.SharedGallery.Gallery, image.SharedGallery.Gallery) ||
+			!strings.EqualFold(pinned.SharedGallery.Name, image.SharedGallery.Name) {
+			return false
+		}
+	default:
+		return false
+	}
+	return Version(image) == azure.LatestVersion || Version(image) == Version(pinned)
+}
+
+// CompareVersions compares two image versions in the Major.Minor.Build format, number by number. It returns a
+// negative number when a is older than b, a positive number when a is newer than b and 0 when they are equal.
+func CompareVersions(a, b string) int {
+	aParts, bParts := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(aParts) || i < len(bParts); i++ {
+		var aPart, bPart string
+		if i < len(aParts) {
+			aPart = aParts[i]
+		}
+		if i < len(bParts) {
+			bPart = bParts[i]
+		}
+		aNum, aErr := strconv.ParseUint(aPart, 10, 64)
+		bNum, bErr := strconv.ParseUint(bPart, 10, 64)
+		switch {
+		case aErr == nil && bErr == nil:
+			if aNum != bNum {
+				if aNum < bNum {
+					return -1
+				}
+				return 1
+			}
+		case aPart != bPart:
+			// Fall back to a lexical comparison for parts which aren't numbers.
+			return strings.Compare(aPart, bPart)
+		}
+	}
+	return 0
+}
+
+// latestVersion returns the latest of the versions, or an empty string if there are none.
+func latestVersion(versions []string) string {
+	var latest string
+	for _, version := range versions {
+		if latest == "" || CompareVersions(version, latest) > 0 {
+			latest = version
+		}
+	}
+	return latest
+}
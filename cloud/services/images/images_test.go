@@ -0,0 +1,363 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package images
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2019-12-01/compute"
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha3"
+	azure "sigs.k8s.io/cluster-api-provider-azure/cloud"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/images/mock_images"
+)
+
+var notFoundError = autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not Found")
+
+func marketplaceImage(version string) *infrav1.Image {
+	return &infrav1.Image{
+		Marketplace: &infrav1.AzureMarketplaceImage{
+			Publisher: "my-publisher",
+			Offer:     "my-offer",
+			SKU:       "my-sku",
+			Version:   version,
+		},
+	}
+}
+
+func galleryImage(version string) *infrav1.Image {
+	return &infrav1.Image{
+		SharedGallery: &infrav1.AzureSharedGalleryImage{
+			SubscriptionID: "456",
+			ResourceGroup:  "gallery-rg",
+			Gallery:        "my-gallery",
+			Name:           "my-image",
+			Version:        version,
+		},
+	}
+}
+
+func TestGetLatestImageVersion(t *testing.T) {
+	testcases := []struct {
+		name             string
+		image            *infrav1.Image
+		expectedImage    *infrav1.Image
+		expectedError    string
+		expectedTerminal bool
+		expect           func(m *mock_images.MockClientMockRecorder)
+	}{
+		{
+			name:          "latest version of a marketplace image",
+			image:         marketplaceImage("latest"),
+			expectedImage: marketplaceImage("18.04.202010140"),
+			expect: func(m *mock_images.MockClientMockRecorder) {
+				m.ListMarketplaceImageVersions(context.TODO(), "test-location", "my-publisher", "my-offer", "my-sku").Return([]compute.VirtualMachineImageResource{
+					{Name: to.StringPtr("18.04.202009220")},
+					{Name: to.StringPtr("18.04.202010140")},
+					{Name: to.StringPtr("18.04.20200922")},
+				}, nil)
+			},
+		},
+		{
+			name:             "marketplace image has no versions",
+			image:            marketplaceImage("latest"),
+			expectedError:    `marketplace image with publisher "my-publisher", offer "my-offer" and SKU "my-sku" not found in location test-location`,
+			expectedTerminal: true,
+			expect: func(m *mock_images.MockClientMockRecorder) {
+				m.ListMarketplaceImageVersions(context.TODO(), "test-location", "my-publisher", "my-offer", "my-sku").Return(nil, nil)
+			},
+		},
+		{
+			name:             "marketplace image not found",
+			image:            marketplaceImage("latest"),
+			expectedError:    `marketplace image with publisher "my-publisher", offer "my-offer" and SKU "my-sku" not found in location test-location`,
+			expectedTerminal: true,
+			expect: func(m *mock_images.MockClientMockRecorder) {
+				m.ListMarketplaceImageVersions(context.TODO(), "test-location", "my-publisher", "my-offer", "my-sku").Return(nil, notFoundError)
+			},
+		},
+		{
+			name:          "fail to list versions of a marketplace image",
+			image:         marketplaceImage("latest"),
+			expectedError: "failed to list versions of marketplace image my-publisher:my-offer:my-sku: #: Internal Server Error: StatusCode=500",
+			expect: func(m *mock_images.MockClientMockRecorder) {
+				m.ListMarketplaceImageVersions(context.TODO(), "test-location", "my-publisher", "my-offer", "my-sku").Return(nil, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error"))
+			},
+		},
+		{
+			name:          "latest version of a shared gallery image skips versions excluded from latest",
+			image:         galleryImage("latest"),
+			expectedImage: galleryImage("1.2.0"),
+			expect: func(m *mock_images.MockClientMockRecorder) {
+				m.ListGalleryImageVersions(context.TODO(), "456", "gallery-rg", "my-gallery", "my-image").Return([]compute.GalleryImageVersion{
+					{Name: to.StringPtr("1.10.0"), GalleryImageVersionProperties: &compute.GalleryImageVersionProperties{
+						PublishingProfile: &compute.GalleryImageVersionPublishingProfile{ExcludeFromLatest: to.BoolPtr(true)},
+					}},
+					{Name: to.StringPtr("1.2.0")},
+					{Name: to.StringPtr("1.1.0")},
+				}, nil)
+			},
+		},
+		{
+			name:             "shared gallery image not found",
+			image:            galleryImage("latest"),
+			expectedError:    `shared gallery image "my-image" of gallery "my-gallery" in resource group "gallery-rg" of subscription 456 not found or has no versions`,
+			expectedTerminal: true,
+			expect: func(m *mock_images.MockClientMockRecorder) {
+				m.ListGalleryImageVersions(context.TODO(), "456", "gallery-rg", "my-gallery", "my-image").Return(nil, notFoundError)
+			},
+		},
+		{
+			name:          "image referred to by ID",
+			image:         &infrav1.Image{ID: to.StringPtr("my-image-id")},
+			expectedImage: &infrav1.Image{ID: to.StringPtr("my-image-id")},
+			expect:        func(m *mock_images.MockClientMockRecorder) {},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			scopeMock := mock_images.NewMockImageScope(mockCtrl)
+			scopeMock.EXPECT().Location().AnyTimes().Return("test-location")
+			imagesMock := mock_images.NewMockClient(mockCtrl)
+			tc.expect(imagesMock.EXPECT())
+
+			s := &Service{
+				Scope:  scopeMock,
+				Client: imagesMock,
+			}
+
+			image, err := s.Get(context.TODO(), &Spec{Image: tc.image})
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+				_, terminal := azure.IsTerminalError(err)
+				g.Expect(terminal).To(Equal(tc.expectedTerminal))
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(image).To(Equal(tc.expectedImage))
+		})
+	}
+}
+
+func TestValidateImage(t *testing.T) {
+	testcases := []struct {
+		name          string
+		image         *infrav1.Image
+		expectedError string
+		expect        func(m *mock_images.MockClientMockRecorder)
+	}{
+		{
+			name:  "marketplace image version exists",
+			image: marketplaceImage("1.0.0"),
+			expect: func(m *mock_images.MockClientMockRecorder) {
+				m.GetMarketplaceImage(context.TODO(), "test-location", "my-publisher", "my-offer", "my-sku", "1.0.0").Return(compute.VirtualMachineImage{}, nil)
+			},
+		},
+		{
+			name:          "marketplace image version not found",
+			image:         marketplaceImage("1.0.0"),
+			expectedError: `marketplace image with publisher "my-publisher", offer "my-offer", SKU "my-sku" and version "1.0.0" not found in location test-location`,
+			expect: func(m *mock_images.MockClientMockRecorder) {
+				m.GetMarketplaceImage(context.TODO(), "test-location", "my-publisher", "my-offer", "my-sku", "1.0.0").Return(compute.VirtualMachineImage{}, notFoundError)
+			},
+		},
+		{
+			name:          "latest marketplace image not found",
+			image:         marketplaceImage("latest"),
+			expectedError: `marketplace image with publisher "my-publisher", offer "my-offer" and SKU "my-sku" not found in location test-location`,
+			expect: func(m *mock_images.MockClientMockRecorder) {
+				m.ListMarketplaceImageVersions(context.TODO(), "test-location", "my-publisher", "my-offer", "my-sku").Return(nil, notFoundError)
+			},
+		},
+		{
+			name:  "shared gallery image version exists",
+			image: galleryImage("1.0.0"),
+			expect: func(m *mock_images.MockClientMockRecorder) {
+				m.GetGalleryImageVersion(context.TODO(), "456", "gallery-rg", "my-gallery", "my-image", "1.0.0").Return(compute.GalleryImageVersion{}, nil)
+			},
+		},
+		{
+			name:          "shared gallery image version not found",
+			image:         galleryImage("1.0.0"),
+			expectedError: `version "1.0.0" of shared gallery image "my-image" of gallery "my-gallery" in resource group "gallery-rg" of subscription 456 not found`,
+			expect: func(m *mock_images.MockClientMockRecorder) {
+				m.GetGalleryImageVersion(context.TODO(), "456", "gallery-rg", "my-gallery", "my-image", "1.0.0").Return(compute.GalleryImageVersion{}, notFoundError)
+			},
+		},
+		{
+			name:   "image referred to by ID",
+			image:  &infrav1.Image{ID: to.StringPtr("my-image-id")},
+			expect: func(m *mock_images.MockClientMockRecorder) {},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			scopeMock := mock_images.NewMockImageScope(mockCtrl)
+			scopeMock.EXPECT().Location().AnyTimes().Return("test-location")
+			imagesMock := mock_images.NewMockClient(mockCtrl)
+			tc.expect(imagesMock.EXPECT())
+
+			s := &Service{
+				Scope:  scopeMock,
+				Client: imagesMock,
+			}
+
+			err := s.Validate(context.TODO(), tc.image)
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+				_, terminal := azure.IsTerminalError(err)
+				g.Expect(terminal).To(BeTrue())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+		})
+	}
+}
+
+func TestGetPlan(t *testing.T) {
+	testcases := []struct {
+		name          string
+		expectedPlan  *infrav1.ImagePlan
+		expectedError string
+		expect        func(m *mock_images.MockClientMockRecorder)
+	}{
+		{
+			name:         "marketplace image with a plan",
+			expectedPlan: &infrav1.ImagePlan{Publisher: "my-publisher", Product: "my-offer", Name: "my-plan"},
+			expect: func(m *mock_images.MockClientMockRecorder) {
+				m.GetMarketplaceImage(context.TODO(), "test-location", "my-publisher", "my-offer", "my-sku", "1.0.0").Return(compute.VirtualMachineImage{
+					VirtualMachineImageProperties: &compute.VirtualMachineImageProperties{
+						Plan: &compute.PurchasePlan{Publisher: to.StringPtr("my-publisher"), Product: to.StringPtr("my-offer"), Name: to.StringPtr("my-plan")},
+					},
+				}, nil)
+			},
+		},
+		{
+			name: "marketplace image without a plan",
+			expect: func(m *mock_images.MockClientMockRecorder) {
+				m.GetMarketplaceImage(context.TODO(), "test-location", "my-publisher", "my-offer", "my-sku", "1.0.0").Return(compute.VirtualMachineImage{
+					VirtualMachineImageProperties: &compute.VirtualMachineImageProperties{},
+				}, nil)
+			},
+		},
+		{
+			name:          "marketplace image not found",
+			expectedError: `marketplace image with publisher "my-publisher", offer "my-offer", SKU "my-sku" and version "1.0.0" not found in location test-location`,
+			expect: func(m *mock_images.MockClientMockRecorder) {
+				m.GetMarketplaceImage(context.TODO(), "test-location", "my-publisher", "my-offer", "my-sku", "1.0.0").Return(compute.VirtualMachineImage{}, notFoundError)
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			scopeMock := mock_images.NewMockImageScope(mockCtrl)
+			scopeMock.EXPECT().Location().AnyTimes().Return("test-location")
+			imagesMock := mock_images.NewMockClient(mockCtrl)
+			tc.expect(imagesMock.EXPECT())
+
+			s := &Service{
+				Scope:  scopeMock,
+				Client: imagesMock,
+			}
+
+			plan, err := s.GetPlan(context.TODO(), marketplaceImage("1.0.0").Marketplace)
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+				_, terminal := azure.IsTerminalError(err)
+				g.Expect(terminal).To(BeTrue())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(plan).To(Equal(tc.expectedPlan))
+		})
+	}
+}
+
+func TestIsPinnedVersionOf(t *testing.T) {
+	otherSKU := marketplaceImage("latest")
+	otherSKU.Marketplace.SKU = "other-sku"
+
+	testcases := []struct {
+		name     string
+		pinned   *infrav1.Image
+		image    *infrav1.Image
+		expected bool
+	}{
+		{name: "no pinned image", image: marketplaceImage("latest")},
+		{name: "latest marketplace image", pinned: marketplaceImage("1.0.0"), image: marketplaceImage("latest"), expected: true},
+		{name: "same marketplace image version", pinned: marketplaceImage("1.0.0"), image: marketplaceImage("1.0.0"), expected: true},
+		{name: "other marketplace image version", pinned: marketplaceImage("1.0.0"), image: marketplaceImage("1.1.0")},
+		{name: "other marketplace image SKU", pinned: marketplaceImage("1.0.0"), image: otherSKU},
+		{name: "latest shared gallery image", pinned: galleryImage("1.0.0"), image: galleryImage("latest"), expected: true},
+		{name: "marketplace image pinned for a shared gallery image", pinned: marketplaceImage("1.0.0"), image: galleryImage("latest")},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(IsPinnedVersionOf(tc.pinned, tc.image)).To(Equal(tc.expected))
+		})
+	}
+}
+
+func TestCompareVersions(t *testing.T) {
+	testcases := []struct {
+		a, b     string
+		expected int
+	}{
+		{a: "1.0.0", b: "1.0.0", expected: 0},
+		{a: "18.04.202010140", b: "18.04.20200922", expected: 1},
+		{a: "1.2.0", b: "1.10.0", expected: -1},
+		{a: "1.0", b: "1.0.1", expected: -1},
+		{a: "1.0.0", b: "", expected: 1},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.a+" "+tc.b, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(CompareVersions(tc.a, tc.b)).To(Equal(tc.expected))
+		})
+	}
+}
@@ -0,0 +1,111 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ../client.go
+
+// Package mock_images is a generated GoMock package.
+package mock_images
+
+import (
+	context "context"
+	compute "github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2019-12-01/compute"
+	gomock "github.com/golang/mock/gomock"
+	reflect "reflect"
+)
+
+// MockClient is a mock of Client interface.
+type MockClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockClientMockRecorder
+}
+
+// MockClientMockRecorder is the mock recorder for MockClient.
+type MockClientMockRecorder struct {
+	mock *MockClient
+}
+
+// NewMockClient creates a new mock instance.
+func NewMockClient(ctrl *gomock.Controller) *MockClient {
+	mock := &MockClient{ctrl: ctrl}
+	mock.recorder = &MockClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockClient) EXPECT() *MockClientMockRecorder {
+	return m.recorder
+}
+
+// ListMarketplaceImageVersions mocks base method.
+func (m *MockClient) ListMarketplaceImageVersions(arg0 context.Context, arg1, arg2, arg3, arg4 string) ([]compute.VirtualMachineImageResource, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListMarketplaceImageVersions", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].([]compute.VirtualMachineImageResource)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListMarketplaceImageVersions indicates an expected call of ListMarketplaceImageVersions.
+func (mr *MockClientMockRecorder) ListMarketplaceImageVersions(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListMarketplaceImageVersions", reflect.TypeOf((*MockClient)(nil).ListMarketplaceImageVersions), arg0, arg1, arg2, arg3, arg4)
+}
+
+// ListGalleryImageVersions mocks base method.
+func (m *MockClient) ListGalleryImageVersions(arg0 context.Context, arg1, arg2, arg3, arg4 string) ([]compute.GalleryImageVersion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListGalleryImageVersions", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].([]compute.GalleryImageVersion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListGalleryImageVersions indicates an expected call of ListGalleryImageVersions.
+func (mr *MockClientMockRecorder) ListGalleryImageVersions(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListGalleryImageVersions", reflect.TypeOf((*MockClient)(nil).ListGalleryImageVersions), arg0, arg1, arg2, arg3, arg4)
+}
+
+// GetMarketplaceImage mocks base method.
+func (m *MockClient) GetMarketplaceImage(arg0 context.Context, arg1, arg2, arg3, arg4, arg5 string) (compute.VirtualMachineImage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMarketplaceImage", arg0, arg1, arg2, arg3, arg4, arg5)
+	ret0, _ := ret[0].(compute.VirtualMachineImage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMarketplaceImage indicates an expected call of GetMarketplaceImage.
+func (mr *MockClientMockRecorder) GetMarketplaceImage(arg0, arg1, arg2, arg3, arg4, arg5 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMarketplaceImage", reflect.TypeOf((*MockClient)(nil).GetMarketplaceImage), arg0, arg1, arg2, arg3, arg4, arg5)
+}
+
+// GetGalleryImageVersion mocks base method.
+func (m *MockClient) GetGalleryImageVersion(arg0 context.Context, arg1, arg2, arg3, arg4, arg5 string) (compute.GalleryImageVersion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGalleryImageVersion", arg0, arg1, arg2, arg3, arg4, arg5)
+	ret0, _ := ret[0].(compute.GalleryImageVersion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGalleryImageVersion indicates an expected call of GetGalleryImageVersion.
+func (mr *MockClientMockRecorder) GetGalleryImageVersion(arg0, arg1, arg2, arg3, arg4, arg5 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGalleryImageVersion", reflect.TypeOf((*MockClient)(nil).GetGalleryImageVersion), arg0, arg1, arg2, arg3, arg4, arg5)
+}
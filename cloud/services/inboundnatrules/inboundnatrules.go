@@ -0,0 +1,120 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package inboundnatrules
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/pkg/errors"
+	azure "sigs.k8s.io/cluster-api-provider-azure/cloud"
+)
+
+const (
+	// sshPort is the backend port that inbound NAT rules forward to.
+	sshPort int32 = 22
+	// minFallbackPort and maxFallbackPort are the inclusive bounds of the frontend ports used once port 22 is taken.
+	minFallbackPort int32 = 2201
+	maxFallbackPort int32 = 2220
+)
+
+// Reconcile gets/creates/updates an inbound NAT rule.
+func (s *Service) Reconcile(ctx context.Context) error {
+	for _, natSpec := range s.Scope.InboundNatSpecs() {
+		s.Scope.V(2).Info("creating inbound NAT rule", "NAT rule", natSpec.Name)
+
+		lb, err := s.LoadBalancersClient.Get(ctx, s.Scope.ResourceGroup(), natSpec.LoadBalancerName)
+		if err != nil {
+			return errors.Wrapf(err, "failed to get load balancer %s", natSpec.LoadBalancerName)
+		}
+		if lb.LoadBalancerPropertiesFormat == nil || lb.FrontendIPConfigurations == nil || lb.InboundNatRules == nil {
+			return errors.Errorf("could not get existing inbound NAT rules from load balancer %s properties", natSpec.LoadBalancerName)
+		}
+
+		ports := make(map[int32]struct{})
+		var existingPort *int32
+		for _, rule := range *lb.InboundNatRules {
+			if rule.InboundNatRulePropertiesFormat == nil || rule.FrontendPort == nil {
+				continue
+			}
+			if to.String(rule.Name) == natSpec.Name {
+				existingPort = rule.FrontendPort
+				break
+			}
+			ports[*rule.FrontendPort] = struct{}{}
+		}
+		if existingPort != nil {
+			// Inbound NAT rule already exists, only publish its port.
+			s.Scope.V(2).Info("inbound NAT rule already exists", "NAT rule", natSpec.Name, "port", *existingPort)
+			s.Scope.SetSSHPort(*existingPort)
+			continue
+		}
+
+		frontendPort, err := getAvailablePort(ports)
+		if err != nil {
+			return errors.Wrapf(err, "failed to find available SSH frontend port for NAT rule %s in load balancer %s", natSpec.Name, natSpec.LoadBalancerName)
+		}
+
+		rule := network.InboundNatRule{
+			Name: to.StringPtr(natSpec.Name),
+			InboundNatRulePropertiesFormat: &network.InboundNatRulePropertiesFormat{
+				BackendPort:          to.Int32Ptr(sshPort),
+				EnableFloatingIP:     to.BoolPtr(false),
+				IdleTimeoutInMinutes: to.Int32Ptr(4),
+				FrontendIPConfiguration: &network.SubResource{
+					ID: (*lb.FrontendIPConfigurations)[0].ID,
+				},
+				Protocol:     network.TransportProtocolTCP,
+				FrontendPort: to.Int32Ptr(frontendPort),
+			},
+		}
+		err = s.Client.CreateOrUpdate(ctx, s.Scope.ResourceGroup(), natSpec.LoadBalancerName, natSpec.Name, rule)
+		if err != nil {
+			return errors.Wrapf(err, "failed to create inbound NAT rule %s in load balancer %s", natSpec.Name, natSpec.LoadBalancerName)
+		}
+		s.Scope.SetSSHPort(frontendPort)
+		s.Scope.V(2).Info("successfully created inbound NAT rule", "NAT rule", natSpec.Name, "port", frontendPort)
+	}
+	return nil
+}
+
+// Delete deletes the inbound NAT rule with the provided name.
+func (s *Service) Delete(ctx context.Context) error {
+	for _, natSpec := range s.Scope.InboundNatSpecs() {
+		s.Scope.V(2).Info("deleting inbound NAT rule", "NAT rule", natSpec.Name)
+		err := s.Client.Delete(ctx, s.Scope.ResourceGroup(), natSpec.LoadBalancerName, natSpec.Name)
+		if err != nil && !azure.ResourceNotFound(err) {
+			return errors.Wrapf(err, "failed to delete inbound NAT rule %s in load balancer %s", natSpec.Name, natSpec.LoadBalancerName)
+		}
+		s.Scope.V(2).Info("successfully deleted inbound NAT rule", "NAT rule", natSpec.Name)
+	}
+	return nil
+}
+
+// getAvailablePort returns port 22 if it is free, or the first free port in the fallback range otherwise.
+func getAvailablePort(ports map[int32]struct{}) (int32, error) {
+	if _, ok := ports[sshPort]; !ok {
+		return sshPort, nil
+	}
+	for port := minFallbackPort; port <= maxFallbackPort; port++ {
+		if _, ok := ports[port]; !ok {
+			return port, nil
+		}
+	}
+	return 0, errors.New("no available ports")
+}
@@ -0,0 +1,347 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package inboundnatrules
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+	"k8s.io/klog/klogr"
+	azure "sigs.k8s.io/cluster-api-provider-azure/cloud"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/inboundnatrules/mock_inboundnatrules"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/publicloadbalancers/mock_publicloadbalancers"
+)
+
+func TestReconcileInboundNATRule(t *testing.T) {
+	testcases := []struct {
+		name          string
+		expectedError string
+		expect        func(s *mock_inboundnatrules.MockInboundNatScopeMockRecorder,
+			m *mock_inboundnatrules.MockClientMockRecorder,
+			mLoadBalancer *mock_publicloadbalancers.MockClientMockRecorder)
+	}{
+		{
+			name:          "NAT rule successfully created with port 22",
+			expectedError: "",
+			expect: func(s *mock_inboundnatrules.MockInboundNatScopeMockRecorder,
+				m *mock_inboundnatrules.MockClientMockRecorder,
+				mLoadBalancer *mock_publicloadbalancers.MockClientMockRecorder) {
+				s.InboundNatSpecs().Return([]azure.InboundNatSpec{
+					{
+						Name:             "my-machine",
+						LoadBalancerName: "my-public-lb",
+					},
+				})
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				gomock.InOrder(
+					mLoadBalancer.Get(context.TODO(), "my-rg", "my-public-lb").Return(getFakeLoadBalancer(), nil),
+					m.CreateOrUpdate(context.TODO(), "my-rg", "my-public-lb", "my-machine", network.InboundNatRule{
+						Name: to.StringPtr("my-machine"),
+						InboundNatRulePropertiesFormat: &network.InboundNatRulePropertiesFormat{
+							FrontendPort:         to.Int32Ptr(22),
+							BackendPort:          to.Int32Ptr(22),
+							EnableFloatingIP:     to.BoolPtr(false),
+							IdleTimeoutInMinutes: to.Int32Ptr(4),
+							FrontendIPConfiguration: &network.SubResource{
+								ID: to.StringPtr("frontend-ip-config-id"),
+							},
+							Protocol: network.TransportProtocolTCP,
+						},
+					}),
+					s.SetSSHPort(int32(22)),
+				)
+			},
+		},
+		{
+			name:          "NAT rule successfully created with next available port",
+			expectedError: "",
+			expect: func(s *mock_inboundnatrules.MockInboundNatScopeMockRecorder,
+				m *mock_inboundnatrules.MockClientMockRecorder,
+				mLoadBalancer *mock_publicloadbalancers.MockClientMockRecorder) {
+				s.InboundNatSpecs().Return([]azure.InboundNatSpec{
+					{
+						Name:             "my-machine",
+						LoadBalancerName: "my-public-lb",
+					},
+				})
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				gomock.InOrder(
+					mLoadBalancer.Get(context.TODO(), "my-rg", "my-public-lb").Return(getFakeLoadBalancer(
+						getFakeNatRule("other-machine-nat-rule", 22),
+						getFakeNatRule("other-machine-nat-rule-2", 2201),
+					), nil),
+					m.CreateOrUpdate(context.TODO(), "my-rg", "my-public-lb", "my-machine", network.InboundNatRule{
+						Name: to.StringPtr("my-machine"),
+						InboundNatRulePropertiesFormat: &network.InboundNatRulePropertiesFormat{
+							FrontendPort:         to.Int32Ptr(2202),
+							BackendPort:          to.Int32Ptr(22),
+							EnableFloatingIP:     to.BoolPtr(false),
+							IdleTimeoutInMinutes: to.Int32Ptr(4),
+							FrontendIPConfiguration: &network.SubResource{
+								ID: to.StringPtr("frontend-ip-config-id"),
+							},
+							Protocol: network.TransportProtocolTCP,
+						},
+					}),
+					s.SetSSHPort(int32(2202)),
+				)
+			},
+		},
+		{
+			name:          "NAT rule already exists",
+			expectedError: "",
+			expect: func(s *mock_inboundnatrules.MockInboundNatScopeMockRecorder,
+				m *mock_inboundnatrules.MockClientMockRecorder,
+				mLoadBalancer *mock_publicloadbalancers.MockClientMockRecorder) {
+				s.InboundNatSpecs().Return([]azure.InboundNatSpec{
+					{
+						Name:             "my-machine",
+						LoadBalancerName: "my-public-lb",
+					},
+				})
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				gomock.InOrder(
+					mLoadBalancer.Get(context.TODO(), "my-rg", "my-public-lb").Return(getFakeLoadBalancer(
+						getFakeNatRule("other-machine-nat-rule", 22),
+						getFakeNatRule("my-machine", 2201),
+					), nil),
+					s.SetSSHPort(int32(2201)),
+				)
+			},
+		},
+		{
+			name:          "fail to get load balancer",
+			expectedError: "failed to get load balancer my-public-lb: #: Internal Server Error: StatusCode=500",
+			expect: func(s *mock_inboundnatrules.MockInboundNatScopeMockRecorder,
+				m *mock_inboundnatrules.MockClientMockRecorder,
+				mLoadBalancer *mock_publicloadbalancers.MockClientMockRecorder) {
+				s.InboundNatSpecs().Return([]azure.InboundNatSpec{
+					{
+						Name:             "my-machine",
+						LoadBalancerName: "my-public-lb",
+					},
+				})
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				mLoadBalancer.Get(context.TODO(), "my-rg", "my-public-lb").
+					Return(network.LoadBalancer{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error"))
+			},
+		},
+		{
+			name:          "no available SSH port",
+			expectedError: "failed to find available SSH frontend port for NAT rule my-machine in load balancer my-public-lb: no available ports",
+			expect: func(s *mock_inboundnatrules.MockInboundNatScopeMockRecorder,
+				m *mock_inboundnatrules.MockClientMockRecorder,
+				mLoadBalancer *mock_publicloadbalancers.MockClientMockRecorder) {
+				s.InboundNatSpecs().Return([]azure.InboundNatSpec{
+					{
+						Name:             "my-machine",
+						LoadBalancerName: "my-public-lb",
+					},
+				})
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				rules := []network.InboundNatRule{getFakeNatRule("rule-22", 22)}
+				for port := minFallbackPort; port <= maxFallbackPort; port++ {
+					rules = append(rules, getFakeNatRule("rule", port))
+				}
+				mLoadBalancer.Get(context.TODO(), "my-rg", "my-public-lb").Return(getFakeLoadBalancer(rules...), nil)
+			},
+		},
+		{
+			name:          "fail to create NAT rule",
+			expectedError: "failed to create inbound NAT rule my-machine in load balancer my-public-lb: #: Internal Server Error: StatusCode=500",
+			expect: func(s *mock_inboundnatrules.MockInboundNatScopeMockRecorder,
+				m *mock_inboundnatrules.MockClientMockRecorder,
+				mLoadBalancer *mock_publicloadbalancers.MockClientMockRecorder) {
+				s.InboundNatSpecs().Return([]azure.InboundNatSpec{
+					{
+						Name:             "my-machine",
+						LoadBalancerName: "my-public-lb",
+					},
+				})
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				gomock.InOrder(
+					mLoadBalancer.Get(context.TODO(), "my-rg", "my-public-lb").Return(getFakeLoadBalancer(), nil),
+					m.CreateOrUpdate(context.TODO(), "my-rg", "my-public-lb", "my-machine", gomock.AssignableToTypeOf(network.InboundNatRule{})).
+						Return(autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error")),
+				)
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_inboundnatrules.NewMockInboundNatScope(mockCtrl)
+			clientMock := mock_inboundnatrules.NewMockClient(mockCtrl)
+			loadBalancerMock := mock_publicloadbalancers.NewMockClient(mockCtrl)
+
+			tc.expect(scopeMock.EXPECT(), clientMock.EXPECT(), loadBalancerMock.EXPECT())
+
+			s := &Service{
+				Scope:               scopeMock,
+				Client:              clientMock,
+				LoadBalancersClient: loadBalancerMock,
+			}
+
+			err := s.Reconcile(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestDeleteInboundNATRule(t *testing.T) {
+	testcases := []struct {
+		name          string
+		expectedError string
+		expect        func(s *mock_inboundnatrules.MockInboundNatScopeMockRecorder, m *mock_inboundnatrules.MockClientMockRecorder)
+	}{
+		{
+			name:          "successfully delete an existing NAT rule",
+			expectedError: "",
+			expect: func(s *mock_inboundnatrules.MockInboundNatScopeMockRecorder, m *mock_inboundnatrules.MockClientMockRecorder) {
+				s.InboundNatSpecs().Return([]azure.InboundNatSpec{
+					{
+						Name:             "azure-test1",
+						LoadBalancerName: "my-public-lb",
+					},
+				})
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				m.Delete(context.TODO(), "my-rg", "my-public-lb", "azure-test1")
+			},
+		},
+		{
+			name:          "NAT rule already deleted",
+			expectedError: "",
+			expect: func(s *mock_inboundnatrules.MockInboundNatScopeMockRecorder, m *mock_inboundnatrules.MockClientMockRecorder) {
+				s.InboundNatSpecs().Return([]azure.InboundNatSpec{
+					{
+						Name:             "azure-test1",
+						LoadBalancerName: "my-public-lb",
+					},
+				})
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				m.Delete(context.TODO(), "my-rg", "my-public-lb", "azure-test1").
+					Return(autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
+			},
+		},
+		{
+			name:          "NAT rule deletion fails",
+			expectedError: "failed to delete inbound NAT rule azure-test1 in load balancer my-public-lb: #: Internal Server Error: StatusCode=500",
+			expect: func(s *mock_inboundnatrules.MockInboundNatScopeMockRecorder, m *mock_inboundnatrules.MockClientMockRecorder) {
+				s.InboundNatSpecs().Return([]azure.InboundNatSpec{
+					{
+						Name:             "azure-test1",
+						LoadBalancerName: "my-public-lb",
+					},
+				})
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				m.Delete(context.TODO(), "my-rg", "my-public-lb", "azure-test1").
+					Return(autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error"))
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_inboundnatrules.NewMockInboundNatScope(mockCtrl)
+			clientMock := mock_inboundnatrules.NewMockClient(mockCtrl)
+
+			tc.expect(scopeMock.EXPECT(), clientMock.EXPECT())
+
+			s := &Service{
+				Scope:  scopeMock,
+				Client: clientMock,
+			}
+
+			err := s.Delete(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestGetAvailablePort(t *testing.T) {
+	g := NewWithT(t)
+
+	ports := map[int32]struct{}{sshPort: {}}
+	for port := minFallbackPort; port < maxFallbackPort; port++ {
+		ports[port] = struct{}{}
+	}
+	port, err := getAvailablePort(ports)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(port).To(Equal(maxFallbackPort))
+
+	ports[maxFallbackPort] = struct{}{}
+	_, err = getAvailablePort(ports)
+	g.Expect(err).To(MatchError("no available ports"))
+}
+
+func getFakeLoadBalancer(rules ...network.InboundNatRule) network.LoadBalancer {
+	return network.LoadBalancer{
+		Name: to.StringPtr("my-public-lb"),
+		ID:   to.StringPtr("my-public-lb-id"),
+		LoadBalancerPropertiesFormat: &network.LoadBalancerPropertiesFormat{
+			FrontendIPConfigurations: &[]network.FrontendIPConfiguration{
+				{
+					ID: to.StringPtr("frontend-ip-config-id"),
+				},
+			},
+			InboundNatRules: &rules,
+		}}
+}
+
+func getFakeNatRule(name string, port int32) network.InboundNatRule {
+	return network.InboundNatRule{
+		Name: to.StringPtr(name),
+		InboundNatRulePropertiesFormat: &network.InboundNatRulePropertiesFormat{
+			FrontendPort: to.Int32Ptr(port),
+		},
+	}
+}
@@ -26,6 +26,8 @@ import (
 	"k8s.io/klog"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha3"
 	azure "sigs.k8s.io/cluster-api-provider-azure/cloud"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/converters"
+	"sigs.k8s.io/cluster-api-provider-azure/internal/ipam"
 )
 
 // Spec specification for internal load balancer
@@ -35,6 +37,10 @@ type Spec struct {
 	SubnetCidr string
 	VnetName   string
 	IPAddress  string
+	// SKU is the SKU of the load balancer. Defaults to Standard.
+	SKU infrav1.SKU
+	// HealthProbe is the probe of the API server load balancing rule. Defaults to an HTTPS probe of /healthz.
+	HealthProbe *infrav1.HealthProbeSpec
 }
 
 // Reconcile gets/creates/updates an internal load balancer.
@@ -50,6 +56,10 @@ func (s *Service) Reconcile(ctx context.Context, spec interface{}) error {
 	idPrefix := fmt.Sprintf("/subscriptions/%s/resourceGroups/%s/providers/Microsoft.Network/loadBalancers", s.Scope.SubscriptionID(), s.Scope.ResourceGroup())
 	lbName := internalLBSpec.Name
 	var privateIP string
+	probe := infrav1.HealthProbeSpec{Protocol: infrav1.HealthProbeProtocolHTTPS, RequestPath: "/healthz"}
+	if internalLBSpec.HealthProbe != nil {
+		probe = *internalLBSpec.HealthProbe
+	}
 
 	internalLB, err := s.Client.Get(ctx, s.Scope.ResourceGroup(), internalLBSpec.Name)
 	if err == nil {
@@ -76,12 +86,17 @@ func (s *Service) Reconcile(ctx context.Context, spec interface{}) error {
 
 	s.Scope.Logger.V(2).Info("successfully got subnet", "subnet", internalLBSpec.SubnetName)
 
+	sku := network.LoadBalancerSkuNameStandard
+	if internalLBSpec.SKU == infrav1.SKUBasic {
+		sku = network.LoadBalancerSkuNameBasic
+	}
+
 	// https://docs.microsoft.com/en-us/azure/load-balancer/load-balancer-standard-availability-zones#zone-redundant-by-default
 	err = s.Client.CreateOrUpdate(ctx,
 		s.Scope.ResourceGroup(),
 		lbName,
 		network.LoadBalancer{
-			Sku:      &network.LoadBalancerSku{Name: network.LoadBalancerSkuNameStandard},
+			Sku:      &network.LoadBalancerSku{Name: sku},
 			Location: to.StringPtr(s.Scope.Location()),
 			LoadBalancerPropertiesFormat: &network.LoadBalancerPropertiesFormat{
 				FrontendIPConfigurations: &[]network.FrontendIPConfiguration{
@@ -100,16 +115,7 @@ func (s *Service) Reconcile(ctx context.Context, spec interface{}) error {
 					},
 				},
 				Probes: &[]network.Probe{
-					{
-						Name: &probeName,
-						ProbePropertiesFormat: &network.ProbePropertiesFormat{
-							Protocol:          network.ProbeProtocolHTTPS,
-							RequestPath:       to.StringPtr("/healthz"),
-							Port:              to.Int32Ptr(s.Scope.APIServerPort()),
-							IntervalInSeconds: to.Int32Ptr(15),
-							NumberOfProbes:    to.Int32Ptr(4),
-						},
-					},
+					converters.HealthProbeToSDK(probeName, s.Scope.APIServerPort(), internalLBSpec.SKU, probe),
 				},
 				LoadBalancingRules: &[]network.LoadBalancingRule{
 					{
@@ -168,10 +174,10 @@ func (s *Service) Delete(ctx context.Context, spec interface{}) error {
 func (s *Service) getAvailablePrivateIP(ctx context.Context, resourceGroup, vnetName, subnetCIDR, PreferredIPAddress string) (string, error) {
 	ip := PreferredIPAddress
 	if ip == "" {
-		ip = azure.DefaultInternalLBIPAddress
-		if subnetCIDR != infrav1.DefaultControlPlaneSubnetCIDR {
-			// If the user provided a custom subnet CIDR without providing a private IP, try finding an available IP in the subnet space
-			ip = subnetCIDR[0:7] + "0"
+		var err error
+		ip, err = ipam.InternalLBIPAddress(subnetCIDR)
+		if err != nil {
+			return "", errors.Wrap(err, "failed to pick internal LB IP address")
 		}
 	}
 	result, err := s.VirtualNetworksClient.CheckIPAddressAvailability(ctx, resourceGroup, vnetName, ip)
@@ -126,6 +126,24 @@ func TestReconcileInternalLoadBalancer(t *testing.T) {
 				m.CreateOrUpdate(context.TODO(), "my-rg", "my-lb", gomock.AssignableToTypeOf(network.LoadBalancer{}))
 			},
 		},
+		{
+			name: "internal load balancer does not exist and no IP is specified",
+			internalLBSpec: Spec{
+				Name:       "my-lb",
+				SubnetCidr: "192.168.1.0/24",
+				SubnetName: "my-subnet",
+				VnetName:   "my-vnet",
+			},
+			expectedError: "",
+			expect: func(m *mock_internalloadbalancers.MockClientMockRecorder,
+				mVnet *mock_virtualnetworks.MockClientMockRecorder,
+				mSubnet *mock_subnets.MockClientMockRecorder) {
+				m.Get(context.TODO(), "my-rg", "my-lb").Return(network.LoadBalancer{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
+				mVnet.CheckIPAddressAvailability(context.TODO(), "my-rg", "my-vnet", "192.168.1.100").Return(network.IPAddressAvailabilityResult{Available: to.BoolPtr(true)}, nil)
+				mSubnet.Get(context.TODO(), "my-rg", "my-vnet", "my-subnet").Return(network.Subnet{}, nil)
+				m.CreateOrUpdate(context.TODO(), "my-rg", "my-lb", gomock.AssignableToTypeOf(network.LoadBalancer{}))
+			},
+		},
 		{
 			name: "internal load balancer retrieval fails",
 			internalLBSpec: Spec{
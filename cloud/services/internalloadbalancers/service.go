@@ -17,6 +17,7 @@ limitations under the License.
 package internalloadbalancers
 
 import (
+	azure "sigs.k8s.io/cluster-api-provider-azure/cloud"
 	"sigs.k8s.io/cluster-api-provider-azure/cloud/scope"
 	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/subnets"
 	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/virtualnetworks"
@@ -35,7 +36,7 @@ func NewService(scope *scope.ClusterScope) *Service {
 	return &Service{
 		Scope:                 scope,
 		Client:                NewClient(scope),
-		SubnetsClient:         subnets.NewClient(scope),
-		VirtualNetworksClient: virtualnetworks.NewClient(scope),
+		SubnetsClient:         subnets.NewClient(azure.VnetAuthorizer(scope)),
+		VirtualNetworksClient: virtualnetworks.NewClient(azure.VnetAuthorizer(scope)),
 	}
 }
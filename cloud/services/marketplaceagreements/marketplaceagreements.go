@@ -0,0 +1,75 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package marketplaceagreements
+
+import (
+	"context"
+
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/pkg/errors"
+	capierrors "sigs.k8s.io/cluster-api/errors"
+
+	azure "sigs.k8s.io/cluster-api-provider-azure/cloud"
+)
+
+// Spec input specification for Reconcile calls
+type Spec struct {
+	Publisher string
+	Product   string
+	Plan      string
+	// Accept accepts the terms when they aren't accepted yet for the subscription.
+	Accept bool
+}
+
+// Reconcile makes sure the marketplace terms of an image plan are accepted for the subscription. Terms that aren't
+// accepted yet are accepted when the spec allows it, and are a terminal error otherwise.
+func (s *Service) Reconcile(ctx context.Context, spec interface{}) error {
+	agreementSpec, ok := spec.(*Spec)
+	if !ok {
+		return errors.New("invalid marketplace agreement specification")
+	}
+
+	terms, err := s.Client.Get(ctx, agreementSpec.Publisher, agreementSpec.Product, agreementSpec.Plan)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get marketplace terms of plan %s:%s:%s", agreementSpec.Publisher, agreementSpec.Product, agreementSpec.Plan)
+	}
+	if terms.AgreementProperties == nil {
+		return errors.Errorf("marketplace terms of plan %s:%s:%s have no properties", agreementSpec.Publisher, agreementSpec.Product, agreementSpec.Plan)
+	}
+	if to.Bool(terms.Accepted) {
+		return nil
+	}
+	if !agreementSpec.Accept {
+		return azure.NewTerminalError(capierrors.InvalidConfigurationMachineError, errors.Errorf(
+			"marketplace terms of plan %s:%s:%s are not accepted for subscription %s, accept them with `az vm image terms accept --publisher %s --offer %s --plan %s` or start the controller manager with --accept-marketplace-terms",
+			agreementSpec.Publisher, agreementSpec.Product, agreementSpec.Plan, s.Scope.SubscriptionID(),
+			agreementSpec.Publisher, agreementSpec.Product, agreementSpec.Plan))
+	}
+
+	s.Scope.V(2).Info("accepting marketplace terms", "publisher", agreementSpec.Publisher, "product", agreementSpec.Product, "plan", agreementSpec.Plan)
+	terms.Accepted = to.BoolPtr(true)
+	if _, err := s.Client.Create(ctx, agreementSpec.Publisher, agreementSpec.Product, agreementSpec.Plan, terms); err != nil {
+		return errors.Wrapf(err, "failed to accept marketplace terms of plan %s:%s:%s", agreementSpec.Publisher, agreementSpec.Product, agreementSpec.Plan)
+	}
+	s.Scope.V(2).Info("successfully accepted marketplace terms", "publisher", agreementSpec.Publisher, "product", agreementSpec.Product, "plan", agreementSpec.Plan)
+	return nil
+}
+
+// Delete is a no-op, accepted marketplace terms are kept for other machines of the subscription.
+func (s *Service) Delete(ctx context.Context, spec interface{}) error {
+	return nil
+}
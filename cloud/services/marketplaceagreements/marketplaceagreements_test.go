@@ -0,0 +1,118 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package marketplaceagreements
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/marketplaceordering/mgmt/2015-06-01/marketplaceordering"
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+	"k8s.io/klog/klogr"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/marketplaceagreements/mock_marketplaceagreements"
+)
+
+func terms(accepted bool) marketplaceordering.AgreementTerms {
+	return marketplaceordering.AgreementTerms{
+		AgreementProperties: &marketplaceordering.AgreementProperties{
+			Publisher: to.StringPtr("my-publisher"),
+			Product:   to.StringPtr("my-product"),
+			Plan:      to.StringPtr("my-plan"),
+			Signature: to.StringPtr("my-signature"),
+			Accepted:  to.BoolPtr(accepted),
+		},
+	}
+}
+
+func TestReconcileMarketplaceAgreement(t *testing.T) {
+	testcases := []struct {
+		name          string
+		accept        bool
+		expectedError string
+		expect        func(s *mock_marketplaceagreements.MockMarketplaceAgreementScopeMockRecorder, m *mock_marketplaceagreements.MockClientMockRecorder)
+	}{
+		{
+			name: "terms already accepted",
+			expect: func(s *mock_marketplaceagreements.MockMarketplaceAgreementScopeMockRecorder, m *mock_marketplaceagreements.MockClientMockRecorder) {
+				m.Get(context.TODO(), "my-publisher", "my-product", "my-plan").Return(terms(true), nil)
+			},
+		},
+		{
+			name:   "accept terms",
+			accept: true,
+			expect: func(s *mock_marketplaceagreements.MockMarketplaceAgreementScopeMockRecorder, m *mock_marketplaceagreements.MockClientMockRecorder) {
+				m.Get(context.TODO(), "my-publisher", "my-product", "my-plan").Return(terms(false), nil)
+				m.Create(context.TODO(), "my-publisher", "my-product", "my-plan", terms(true)).Return(terms(true), nil)
+			},
+		},
+		{
+			name:          "fail to get terms",
+			expectedError: "failed to get marketplace terms of plan my-publisher:my-product:my-plan: #: Internal Server Error: StatusCode=500",
+			expect: func(s *mock_marketplaceagreements.MockMarketplaceAgreementScopeMockRecorder, m *mock_marketplaceagreements.MockClientMockRecorder) {
+				m.Get(context.TODO(), "my-publisher", "my-product", "my-plan").Return(marketplaceordering.AgreementTerms{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error"))
+			},
+		},
+		{
+			name:          "terms not accepted",
+			expectedError: "marketplace terms of plan my-publisher:my-product:my-plan are not accepted for subscription 123, accept them with `az vm image terms accept --publisher my-publisher --offer my-product --plan my-plan` or start the controller manager with --accept-marketplace-terms",
+			expect: func(s *mock_marketplaceagreements.MockMarketplaceAgreementScopeMockRecorder, m *mock_marketplaceagreements.MockClientMockRecorder) {
+				s.SubscriptionID().Return("123")
+				m.Get(context.TODO(), "my-publisher", "my-product", "my-plan").Return(terms(false), nil)
+			},
+		},
+		{
+			name:          "fail to accept terms",
+			accept:        true,
+			expectedError: "failed to accept marketplace terms of plan my-publisher:my-product:my-plan: #: Forbidden: StatusCode=403",
+			expect: func(s *mock_marketplaceagreements.MockMarketplaceAgreementScopeMockRecorder, m *mock_marketplaceagreements.MockClientMockRecorder) {
+				m.Get(context.TODO(), "my-publisher", "my-product", "my-plan").Return(terms(false), nil)
+				m.Create(context.TODO(), "my-publisher", "my-product", "my-plan", terms(true)).Return(marketplaceordering.AgreementTerms{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 403}, "Forbidden"))
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			scopeMock := mock_marketplaceagreements.NewMockMarketplaceAgreementScope(mockCtrl)
+			scopeMock.EXPECT().V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+			clientMock := mock_marketplaceagreements.NewMockClient(mockCtrl)
+			tc.expect(scopeMock.EXPECT(), clientMock.EXPECT())
+
+			s := &Service{
+				Scope:  scopeMock,
+				Client: clientMock,
+			}
+
+			err := s.Reconcile(context.TODO(), &Spec{Publisher: "my-publisher", Product: "my-product", Plan: "my-plan", Accept: tc.accept})
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
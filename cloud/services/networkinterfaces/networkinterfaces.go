@@ -39,6 +39,13 @@ func (s *Service) Reconcile(ctx context.Context) error {
 
 		nicConfig.Subnet = &network.Subnet{ID: subnet.ID}
 		nicConfig.PrivateIPAllocationMethod = network.Dynamic
+		var asgs *[]network.ApplicationSecurityGroup
+		if nicSpec.ApplicationSecurityGroup != "" {
+			asgs = &[]network.ApplicationSecurityGroup{
+				{ID: to.StringPtr(azure.ApplicationSecurityGroupID(s.Scope.SubscriptionID(), s.Scope.ResourceGroup(), nicSpec.ApplicationSecurityGroup))},
+			}
+			nicConfig.ApplicationSecurityGroups = asgs
+		}
 		if nicSpec.StaticIPAddress != "" {
 			nicConfig.PrivateIPAllocationMethod = network.Static
 			nicConfig.PrivateIPAddress = to.StringPtr(nicSpec.StaticIPAddress)
@@ -57,12 +64,8 @@ func (s *Service) Reconcile(ctx context.Context) error {
 				})
 
 			if nicSpec.MachineRole == infrav1.ControlPlane {
+				// the inbound NAT rule is created by the inbound NAT rules service
 				ruleName := nicSpec.MachineName
-				naterr := s.createInboundNatRule(ctx, lb, ruleName)
-				if naterr != nil {
-					return errors.Wrap(naterr, "failed to create NAT rule")
-				}
-
 				nicConfig.LoadBalancerInboundNatRules = &[]network.InboundNatRule{
 					{
 						ID: to.StringPtr(fmt.Sprintf("%s/inboundNatRules/%s", to.String(lb.ID), ruleName)),
@@ -102,18 +105,35 @@ func (s *Service) Reconcile(ctx context.Context) error {
 			nicSpec.AcceleratedNetworking = to.BoolPtr(accelNet)
 		}
 
+		ipConfigs := []network.InterfaceIPConfiguration{
+			{
+				Name:                                     to.StringPtr("pipConfig"),
+				InterfaceIPConfigurationPropertiesFormat: nicConfig,
+			},
+		}
+		// secondary IP configurations only provide additional private IP addresses in the same subnet
+		for i := 1; i < nicSpec.PrivateIPConfigs; i++ {
+			ipConfigs = append(ipConfigs, network.InterfaceIPConfiguration{
+				Name: to.StringPtr(fmt.Sprintf("ipConfig%d", i)),
+				InterfaceIPConfigurationPropertiesFormat: &network.InterfaceIPConfigurationPropertiesFormat{
+					Subnet:                    &network.Subnet{ID: subnet.ID},
+					PrivateIPAllocationMethod: network.Dynamic,
+					Primary:                   to.BoolPtr(false),
+					ApplicationSecurityGroups: asgs,
+				},
+			})
+		}
+		if len(ipConfigs) > 1 {
+			nicConfig.Primary = to.BoolPtr(true)
+		}
+
 		err = s.Client.CreateOrUpdate(ctx,
 			s.Scope.ResourceGroup(),
 			nicSpec.Name,
 			network.Interface{
 				Location: to.StringPtr(s.Scope.Location()),
 				InterfacePropertiesFormat: &network.InterfacePropertiesFormat{
-					IPConfigurations: &[]network.InterfaceIPConfiguration{
-						{
-							Name:                                     to.StringPtr("pipConfig"),
-							InterfaceIPConfigurationPropertiesFormat: nicConfig,
-						},
-					},
+					IPConfigurations:            &ipConfigs,
 					EnableAcceleratedNetworking: nicSpec.AcceleratedNetworking,
 				},
 			})
@@ -134,57 +154,7 @@ func (s *Service) Delete(ctx context.Context) error {
 		if err != nil && !azure.ResourceNotFound(err) {
 			return errors.Wrapf(err, "failed to delete network interface %s in resource group %s", nicSpec.Name, s.Scope.ResourceGroup())
 		}
-		NATRuleName := nicSpec.MachineName
-		err = s.InboundNATRulesClient.Delete(ctx, s.Scope.ResourceGroup(), nicSpec.PublicLoadBalancerName, NATRuleName)
-		if err != nil && !azure.ResourceNotFound(err) {
-			return errors.Wrapf(err, "failed to delete inbound NAT rule %s in load balancer %s", NATRuleName, nicSpec.PublicLoadBalancerName)
-		}
-		s.Scope.V(2).Info("successfully deleted NIC and NAT rule", "network interface", nicSpec.Name, "NAT rule", NATRuleName)
+		s.Scope.V(2).Info("successfully deleted network interface", "network interface", nicSpec.Name)
 	}
 	return nil
 }
-
-func (s *Service) createInboundNatRule(ctx context.Context, lb network.LoadBalancer, ruleName string) error {
-	var sshFrontendPort int32 = 22
-	ports := make(map[int32]struct{})
-	if lb.LoadBalancerPropertiesFormat == nil || lb.InboundNatRules == nil {
-		return errors.Errorf("Could not get existing inbound NAT rules from load balancer %s properties", to.String(lb.Name))
-	}
-	for _, v := range *lb.InboundNatRules {
-		if to.String(v.Name) == ruleName {
-			// Inbound NAT Rule already exists, nothing to do here.
-			s.Scope.V(2).Info("NAT rule already exists", "NAT rule", ruleName)
-			return nil
-		}
-		ports[*v.InboundNatRulePropertiesFormat.FrontendPort] = struct{}{}
-	}
-	if _, ok := ports[22]; ok {
-		var i int32
-		found := false
-		for i = 2201; i < 2220; i++ {
-			if _, ok := ports[i]; !ok {
-				sshFrontendPort = i
-				found = true
-				break
-			}
-		}
-		if !found {
-			return errors.Errorf("Failed to find available SSH Frontend port for NAT Rule in load balancer %s for AzureMachine %s", to.String(lb.Name), ruleName)
-		}
-	}
-	rule := network.InboundNatRule{
-		Name: to.StringPtr(ruleName),
-		InboundNatRulePropertiesFormat: &network.InboundNatRulePropertiesFormat{
-			BackendPort:          to.Int32Ptr(22),
-			EnableFloatingIP:     to.BoolPtr(false),
-			IdleTimeoutInMinutes: to.Int32Ptr(4),
-			FrontendIPConfiguration: &network.SubResource{
-				ID: (*lb.FrontendIPConfigurations)[0].ID,
-			},
-			Protocol:     network.TransportProtocolTCP,
-			FrontendPort: &sshFrontendPort,
-		},
-	}
-	s.Scope.V(3).Info("Creating rule %s using port %d", "NAT rule", ruleName, "port", sshFrontendPort)
-	return s.InboundNATRulesClient.CreateOrUpdate(ctx, s.Scope.ResourceGroup(), to.String(lb.Name), ruleName, rule)
-}
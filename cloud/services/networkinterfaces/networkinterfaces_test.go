@@ -30,7 +30,6 @@ import (
 	"github.com/Azure/go-autorest/autorest/to"
 	"github.com/golang/mock/gomock"
 	. "github.com/onsi/gomega"
-	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/inboundnatrules/mock_inboundnatrules"
 	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/internalloadbalancers/mock_internalloadbalancers"
 	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/networkinterfaces/mock_networkinterfaces"
 	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/publicips/mock_publicips"
@@ -50,7 +49,6 @@ func TestReconcileNetworkInterface(t *testing.T) {
 			m *mock_networkinterfaces.MockClientMockRecorder,
 			mSubnet *mock_subnets.MockClientMockRecorder,
 			mPublicLoadBalancer *mock_publicloadbalancers.MockClientMockRecorder,
-			mInboundNATRules *mock_inboundnatrules.MockClientMockRecorder,
 			mInternalLoadBalancer *mock_internalloadbalancers.MockClientMockRecorder,
 			mPublicIP *mock_publicips.MockClientMockRecorder,
 			mResourceSku *mock_resourceskus.MockClientMockRecorder)
@@ -62,7 +60,6 @@ func TestReconcileNetworkInterface(t *testing.T) {
 				m *mock_networkinterfaces.MockClientMockRecorder,
 				mSubnet *mock_subnets.MockClientMockRecorder,
 				mPublicLoadBalancer *mock_publicloadbalancers.MockClientMockRecorder,
-				mInboundNATRules *mock_inboundnatrules.MockClientMockRecorder,
 				mInternalLoadBalancer *mock_internalloadbalancers.MockClientMockRecorder,
 				mPublicIP *mock_publicips.MockClientMockRecorder,
 				mResourceSku *mock_resourceskus.MockClientMockRecorder) {
@@ -87,7 +84,6 @@ func TestReconcileNetworkInterface(t *testing.T) {
 				m *mock_networkinterfaces.MockClientMockRecorder,
 				mSubnet *mock_subnets.MockClientMockRecorder,
 				mPublicLoadBalancer *mock_publicloadbalancers.MockClientMockRecorder,
-				mInboundNATRules *mock_inboundnatrules.MockClientMockRecorder,
 				mInternalLoadBalancer *mock_internalloadbalancers.MockClientMockRecorder,
 				mPublicIP *mock_publicips.MockClientMockRecorder,
 				mResourceSku *mock_resourceskus.MockClientMockRecorder) {
@@ -122,7 +118,6 @@ func TestReconcileNetworkInterface(t *testing.T) {
 				m *mock_networkinterfaces.MockClientMockRecorder,
 				mSubnet *mock_subnets.MockClientMockRecorder,
 				mPublicLoadBalancer *mock_publicloadbalancers.MockClientMockRecorder,
-				mInboundNATRules *mock_inboundnatrules.MockClientMockRecorder,
 				mInternalLoadBalancer *mock_internalloadbalancers.MockClientMockRecorder,
 				mPublicIP *mock_publicips.MockClientMockRecorder,
 				mResourceSku *mock_resourceskus.MockClientMockRecorder) {
@@ -170,7 +165,6 @@ func TestReconcileNetworkInterface(t *testing.T) {
 				m *mock_networkinterfaces.MockClientMockRecorder,
 				mSubnet *mock_subnets.MockClientMockRecorder,
 				mPublicLoadBalancer *mock_publicloadbalancers.MockClientMockRecorder,
-				mInboundNATRules *mock_inboundnatrules.MockClientMockRecorder,
 				mInternalLoadBalancer *mock_internalloadbalancers.MockClientMockRecorder,
 				mPublicIP *mock_publicips.MockClientMockRecorder,
 				mResourceSku *mock_resourceskus.MockClientMockRecorder) {
@@ -209,6 +203,133 @@ func TestReconcileNetworkInterface(t *testing.T) {
 					})))
 			},
 		},
+		{
+			name:          "network interface with secondary IP configurations successfully created",
+			expectedError: "",
+			expect: func(s *mock_networkinterfaces.MockNICScopeMockRecorder,
+				m *mock_networkinterfaces.MockClientMockRecorder,
+				mSubnet *mock_subnets.MockClientMockRecorder,
+				mPublicLoadBalancer *mock_publicloadbalancers.MockClientMockRecorder,
+				mInternalLoadBalancer *mock_internalloadbalancers.MockClientMockRecorder,
+				mPublicIP *mock_publicips.MockClientMockRecorder,
+				mResourceSku *mock_resourceskus.MockClientMockRecorder) {
+				s.NICSpecs().Return([]azure.NICSpec{
+					{
+						Name:                  "my-net-interface-1",
+						MachineName:           "azure-test1",
+						MachineRole:           infrav1.Node,
+						SubnetName:            "my-other-subnet",
+						VNetName:              "my-vnet",
+						VNetResourceGroup:     "my-rg",
+						VMSize:                "Standard_D2v2",
+						AcceleratedNetworking: to.BoolPtr(true),
+						PrivateIPConfigs:      3,
+					},
+				})
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.Location().AnyTimes().Return("fake-location")
+				gomock.InOrder(
+					mSubnet.Get(context.TODO(), "my-rg", "my-vnet", "my-other-subnet").
+						Return(network.Subnet{ID: to.StringPtr("my-other-subnet-id")}, nil),
+					m.CreateOrUpdate(context.TODO(), "my-rg", "my-net-interface-1", matchers.DiffEq(network.Interface{
+						Location: to.StringPtr("fake-location"),
+						InterfacePropertiesFormat: &network.InterfacePropertiesFormat{
+							EnableAcceleratedNetworking: to.BoolPtr(true),
+							IPConfigurations: &[]network.InterfaceIPConfiguration{
+								{
+									Name: to.StringPtr("pipConfig"),
+									InterfaceIPConfigurationPropertiesFormat: &network.InterfaceIPConfigurationPropertiesFormat{
+										Subnet:                          &network.Subnet{ID: to.StringPtr("my-other-subnet-id")},
+										PrivateIPAllocationMethod:       network.Dynamic,
+										LoadBalancerBackendAddressPools: &[]network.BackendAddressPool{},
+										Primary:                         to.BoolPtr(true),
+									},
+								},
+								{
+									Name: to.StringPtr("ipConfig1"),
+									InterfaceIPConfigurationPropertiesFormat: &network.InterfaceIPConfigurationPropertiesFormat{
+										Subnet:                    &network.Subnet{ID: to.StringPtr("my-other-subnet-id")},
+										PrivateIPAllocationMethod: network.Dynamic,
+										Primary:                   to.BoolPtr(false),
+									},
+								},
+								{
+									Name: to.StringPtr("ipConfig2"),
+									InterfaceIPConfigurationPropertiesFormat: &network.InterfaceIPConfigurationPropertiesFormat{
+										Subnet:                    &network.Subnet{ID: to.StringPtr("my-other-subnet-id")},
+										PrivateIPAllocationMethod: network.Dynamic,
+										Primary:                   to.BoolPtr(false),
+									},
+								},
+							},
+						},
+					})))
+			},
+		},
+		{
+			name:          "network interface with application security group successfully created",
+			expectedError: "",
+			expect: func(s *mock_networkinterfaces.MockNICScopeMockRecorder,
+				m *mock_networkinterfaces.MockClientMockRecorder,
+				mSubnet *mock_subnets.MockClientMockRecorder,
+				mPublicLoadBalancer *mock_publicloadbalancers.MockClientMockRecorder,
+				mInternalLoadBalancer *mock_internalloadbalancers.MockClientMockRecorder,
+				mPublicIP *mock_publicips.MockClientMockRecorder,
+				mResourceSku *mock_resourceskus.MockClientMockRecorder) {
+				s.NICSpecs().Return([]azure.NICSpec{
+					{
+						Name:                     "my-net-interface",
+						MachineName:              "azure-test1",
+						MachineRole:              infrav1.Node,
+						SubnetName:               "my-subnet",
+						VNetName:                 "my-vnet",
+						VNetResourceGroup:        "my-rg",
+						VMSize:                   "Standard_D2v2",
+						AcceleratedNetworking:    to.BoolPtr(false),
+						PrivateIPConfigs:         2,
+						ApplicationSecurityGroup: "my-cluster-node-asg",
+					},
+				})
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.SubscriptionID().AnyTimes().Return("123")
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.Location().AnyTimes().Return("fake-location")
+				asgs := &[]network.ApplicationSecurityGroup{
+					{ID: to.StringPtr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/applicationSecurityGroups/my-cluster-node-asg")},
+				}
+				gomock.InOrder(
+					mSubnet.Get(context.TODO(), "my-rg", "my-vnet", "my-subnet").
+						Return(network.Subnet{ID: to.StringPtr("my-subnet-id")}, nil),
+					m.CreateOrUpdate(context.TODO(), "my-rg", "my-net-interface", matchers.DiffEq(network.Interface{
+						Location: to.StringPtr("fake-location"),
+						InterfacePropertiesFormat: &network.InterfacePropertiesFormat{
+							EnableAcceleratedNetworking: to.BoolPtr(false),
+							IPConfigurations: &[]network.InterfaceIPConfiguration{
+								{
+									Name: to.StringPtr("pipConfig"),
+									InterfaceIPConfigurationPropertiesFormat: &network.InterfaceIPConfigurationPropertiesFormat{
+										Subnet:                          &network.Subnet{ID: to.StringPtr("my-subnet-id")},
+										PrivateIPAllocationMethod:       network.Dynamic,
+										LoadBalancerBackendAddressPools: &[]network.BackendAddressPool{},
+										ApplicationSecurityGroups:       asgs,
+										Primary:                         to.BoolPtr(true),
+									},
+								},
+								{
+									Name: to.StringPtr("ipConfig1"),
+									InterfaceIPConfigurationPropertiesFormat: &network.InterfaceIPConfigurationPropertiesFormat{
+										Subnet:                    &network.Subnet{ID: to.StringPtr("my-subnet-id")},
+										PrivateIPAllocationMethod: network.Dynamic,
+										ApplicationSecurityGroups: asgs,
+										Primary:                   to.BoolPtr(false),
+									},
+								},
+							},
+						},
+					})))
+			},
+		},
 		{
 			name:          "control plane network interface successfully created",
 			expectedError: "",
@@ -216,7 +337,6 @@ func TestReconcileNetworkInterface(t *testing.T) {
 				m *mock_networkinterfaces.MockClientMockRecorder,
 				mSubnet *mock_subnets.MockClientMockRecorder,
 				mPublicLoadBalancer *mock_publicloadbalancers.MockClientMockRecorder,
-				mInboundNATRules *mock_inboundnatrules.MockClientMockRecorder,
 				mInternalLoadBalancer *mock_internalloadbalancers.MockClientMockRecorder,
 				mPublicIP *mock_publicips.MockClientMockRecorder,
 				mResourceSku *mock_resourceskus.MockClientMockRecorder) {
@@ -255,19 +375,6 @@ func TestReconcileNetworkInterface(t *testing.T) {
 							},
 							InboundNatRules: &[]network.InboundNatRule{},
 						}}, nil),
-					mInboundNATRules.CreateOrUpdate(context.TODO(), "my-rg", "my-public-lb", "azure-test1", network.InboundNatRule{
-						Name: pointer.StringPtr("azure-test1"),
-						InboundNatRulePropertiesFormat: &network.InboundNatRulePropertiesFormat{
-							FrontendPort:         to.Int32Ptr(22),
-							BackendPort:          to.Int32Ptr(22),
-							EnableFloatingIP:     to.BoolPtr(false),
-							IdleTimeoutInMinutes: to.Int32Ptr(4),
-							FrontendIPConfiguration: &network.SubResource{
-								ID: to.StringPtr("frontend-ip-config-id"),
-							},
-							Protocol: network.TransportProtocolTCP,
-						},
-					}),
 					mInternalLoadBalancer.Get(context.TODO(), "my-rg", "my-internal-lb").
 						Return(network.LoadBalancer{
 							ID: pointer.StringPtr("my-internal-lb-id"),
@@ -305,7 +412,6 @@ func TestReconcileNetworkInterface(t *testing.T) {
 				m *mock_networkinterfaces.MockClientMockRecorder,
 				mSubnet *mock_subnets.MockClientMockRecorder,
 				mPublicLoadBalancer *mock_publicloadbalancers.MockClientMockRecorder,
-				mInboundNATRules *mock_inboundnatrules.MockClientMockRecorder,
 				mInternalLoadBalancer *mock_internalloadbalancers.MockClientMockRecorder,
 				mPublicIP *mock_publicips.MockClientMockRecorder,
 				mResourceSku *mock_resourceskus.MockClientMockRecorder) {
@@ -331,82 +437,6 @@ func TestReconcileNetworkInterface(t *testing.T) {
 						Return(network.LoadBalancer{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error")))
 			},
 		},
-		{
-			name:          "control plane network interface fail to create NAT rule",
-			expectedError: "failed to create NAT rule: #: Internal Server Error: StatusCode=500",
-			expect: func(s *mock_networkinterfaces.MockNICScopeMockRecorder,
-				m *mock_networkinterfaces.MockClientMockRecorder,
-				mSubnet *mock_subnets.MockClientMockRecorder,
-				mPublicLoadBalancer *mock_publicloadbalancers.MockClientMockRecorder,
-				mInboundNATRules *mock_inboundnatrules.MockClientMockRecorder,
-				mInternalLoadBalancer *mock_internalloadbalancers.MockClientMockRecorder,
-				mPublicIP *mock_publicips.MockClientMockRecorder,
-				mResourceSku *mock_resourceskus.MockClientMockRecorder) {
-				s.NICSpecs().Return([]azure.NICSpec{
-					{
-						Name:                     "my-net-interface",
-						MachineName:              "azure-test1",
-						MachineRole:              infrav1.ControlPlane,
-						SubnetName:               "my-subnet",
-						VNetName:                 "my-vnet",
-						VNetResourceGroup:        "my-rg",
-						PublicLoadBalancerName:   "my-public-lb",
-						InternalLoadBalancerName: "my-internal-lb",
-						VMSize:                   "Standard_D2v2",
-						AcceleratedNetworking:    nil,
-					},
-				})
-				s.ResourceGroup().AnyTimes().Return("my-rg")
-				s.Location().AnyTimes().Return("fake-location")
-				gomock.InOrder(
-					mSubnet.Get(context.TODO(), "my-rg", "my-vnet", "my-subnet").Return(network.Subnet{}, nil),
-					mPublicLoadBalancer.Get(context.TODO(), "my-rg", "my-public-lb").Return(network.LoadBalancer{
-						Name: to.StringPtr("my-public-lb"),
-						ID:   pointer.StringPtr("my-public-lb-id"),
-						LoadBalancerPropertiesFormat: &network.LoadBalancerPropertiesFormat{
-							FrontendIPConfigurations: &[]network.FrontendIPConfiguration{
-								{
-									ID: to.StringPtr("frontend-ip-config-id"),
-								},
-							},
-							BackendAddressPools: &[]network.BackendAddressPool{
-								{
-									ID: pointer.StringPtr("my-backend-pool-id"),
-								},
-							},
-							InboundNatRules: &[]network.InboundNatRule{
-								{
-									Name: pointer.StringPtr("other-machine-nat-rule"),
-									ID:   pointer.StringPtr("some-natrules-id"),
-									InboundNatRulePropertiesFormat: &network.InboundNatRulePropertiesFormat{
-										FrontendPort: to.Int32Ptr(22),
-									},
-								},
-								{
-									Name: pointer.StringPtr("other-machine-nat-rule-2"),
-									ID:   pointer.StringPtr("some-natrules-id-2"),
-									InboundNatRulePropertiesFormat: &network.InboundNatRulePropertiesFormat{
-										FrontendPort: to.Int32Ptr(2201),
-									},
-								},
-							},
-						}}, nil),
-					mInboundNATRules.CreateOrUpdate(context.TODO(), "my-rg", "my-public-lb", "azure-test1", network.InboundNatRule{
-						Name: pointer.StringPtr("azure-test1"),
-						InboundNatRulePropertiesFormat: &network.InboundNatRulePropertiesFormat{
-							FrontendPort:         to.Int32Ptr(2202),
-							BackendPort:          to.Int32Ptr(22),
-							EnableFloatingIP:     to.BoolPtr(false),
-							IdleTimeoutInMinutes: to.Int32Ptr(4),
-							FrontendIPConfiguration: &network.SubResource{
-								ID: to.StringPtr("frontend-ip-config-id"),
-							},
-							Protocol: network.TransportProtocolTCP,
-						},
-					}).
-						Return(autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error")))
-			},
-		},
 		{
 			name:          "control plane network interface fail to get internal LB",
 			expectedError: "failed to get internalLB: #: Internal Server Error: StatusCode=500",
@@ -414,7 +444,6 @@ func TestReconcileNetworkInterface(t *testing.T) {
 				m *mock_networkinterfaces.MockClientMockRecorder,
 				mSubnet *mock_subnets.MockClientMockRecorder,
 				mPublicLoadBalancer *mock_publicloadbalancers.MockClientMockRecorder,
-				mInboundNATRules *mock_inboundnatrules.MockClientMockRecorder,
 				mInternalLoadBalancer *mock_internalloadbalancers.MockClientMockRecorder,
 				mPublicIP *mock_publicips.MockClientMockRecorder,
 				mResourceSku *mock_resourceskus.MockClientMockRecorder) {
@@ -447,7 +476,6 @@ func TestReconcileNetworkInterface(t *testing.T) {
 				m *mock_networkinterfaces.MockClientMockRecorder,
 				mSubnet *mock_subnets.MockClientMockRecorder,
 				mPublicLoadBalancer *mock_publicloadbalancers.MockClientMockRecorder,
-				mInboundNATRules *mock_inboundnatrules.MockClientMockRecorder,
 				mInternalLoadBalancer *mock_internalloadbalancers.MockClientMockRecorder,
 				mPublicIP *mock_publicips.MockClientMockRecorder,
 				mResourceSku *mock_resourceskus.MockClientMockRecorder) {
@@ -483,7 +511,6 @@ func TestReconcileNetworkInterface(t *testing.T) {
 				m *mock_networkinterfaces.MockClientMockRecorder,
 				mSubnet *mock_subnets.MockClientMockRecorder,
 				mPublicLoadBalancer *mock_publicloadbalancers.MockClientMockRecorder,
-				mInboundNATRules *mock_inboundnatrules.MockClientMockRecorder,
 				mInternalLoadBalancer *mock_internalloadbalancers.MockClientMockRecorder,
 				mPublicIP *mock_publicips.MockClientMockRecorder,
 				mResourceSku *mock_resourceskus.MockClientMockRecorder) {
@@ -517,7 +544,6 @@ func TestReconcileNetworkInterface(t *testing.T) {
 				m *mock_networkinterfaces.MockClientMockRecorder,
 				mSubnet *mock_subnets.MockClientMockRecorder,
 				mPublicLoadBalancer *mock_publicloadbalancers.MockClientMockRecorder,
-				mInboundNATRules *mock_inboundnatrules.MockClientMockRecorder,
 				mInternalLoadBalancer *mock_internalloadbalancers.MockClientMockRecorder,
 				mPublicIP *mock_publicips.MockClientMockRecorder,
 				mResourceSku *mock_resourceskus.MockClientMockRecorder) {
@@ -565,7 +591,6 @@ func TestReconcileNetworkInterface(t *testing.T) {
 				m *mock_networkinterfaces.MockClientMockRecorder,
 				mSubnet *mock_subnets.MockClientMockRecorder,
 				mPublicLoadBalancer *mock_publicloadbalancers.MockClientMockRecorder,
-				mInboundNATRules *mock_inboundnatrules.MockClientMockRecorder,
 				mInternalLoadBalancer *mock_internalloadbalancers.MockClientMockRecorder,
 				mPublicIP *mock_publicips.MockClientMockRecorder,
 				mResourceSku *mock_resourceskus.MockClientMockRecorder) {
@@ -613,7 +638,6 @@ func TestReconcileNetworkInterface(t *testing.T) {
 				m *mock_networkinterfaces.MockClientMockRecorder,
 				mSubnet *mock_subnets.MockClientMockRecorder,
 				mPublicLoadBalancer *mock_publicloadbalancers.MockClientMockRecorder,
-				mInboundNATRules *mock_inboundnatrules.MockClientMockRecorder,
 				mInternalLoadBalancer *mock_internalloadbalancers.MockClientMockRecorder,
 				mPublicIP *mock_publicips.MockClientMockRecorder,
 				mResourceSku *mock_resourceskus.MockClientMockRecorder) {
@@ -652,13 +676,12 @@ func TestReconcileNetworkInterface(t *testing.T) {
 			clientMock := mock_networkinterfaces.NewMockClient(mockCtrl)
 			subnetMock := mock_subnets.NewMockClient(mockCtrl)
 			publicLoadBalancerMock := mock_publicloadbalancers.NewMockClient(mockCtrl)
-			inboundNatRulesMock := mock_inboundnatrules.NewMockClient(mockCtrl)
 			internalLoadBalancerMock := mock_internalloadbalancers.NewMockClient(mockCtrl)
 			publicIPsMock := mock_publicips.NewMockClient(mockCtrl)
 			resourceSkusMock := mock_resourceskus.NewMockClient(mockCtrl)
 
 			tc.expect(scopeMock.EXPECT(), clientMock.EXPECT(), subnetMock.EXPECT(),
-				publicLoadBalancerMock.EXPECT(), inboundNatRulesMock.EXPECT(),
+				publicLoadBalancerMock.EXPECT(),
 				internalLoadBalancerMock.EXPECT(), publicIPsMock.EXPECT(),
 				resourceSkusMock.EXPECT())
 
@@ -667,7 +690,6 @@ func TestReconcileNetworkInterface(t *testing.T) {
 				Client:                      clientMock,
 				SubnetsClient:               subnetMock,
 				PublicLoadBalancersClient:   publicLoadBalancerMock,
-				InboundNATRulesClient:       inboundNatRulesMock,
 				InternalLoadBalancersClient: internalLoadBalancerMock,
 				PublicIPsClient:             publicIPsMock,
 				ResourceSkusClient:          resourceSkusMock,
@@ -689,13 +711,13 @@ func TestDeleteNetworkInterface(t *testing.T) {
 		name          string
 		expectedError string
 		expect        func(s *mock_networkinterfaces.MockNICScopeMockRecorder,
-			m *mock_networkinterfaces.MockClientMockRecorder, mInboundNATRules *mock_inboundnatrules.MockClientMockRecorder, mPublicIP *mock_publicips.MockClientMockRecorder)
+			m *mock_networkinterfaces.MockClientMockRecorder, mPublicIP *mock_publicips.MockClientMockRecorder)
 	}{
 		{
 			name:          "successfully delete an existing network interface",
 			expectedError: "",
 			expect: func(s *mock_networkinterfaces.MockNICScopeMockRecorder,
-				m *mock_networkinterfaces.MockClientMockRecorder, mInboundNATRules *mock_inboundnatrules.MockClientMockRecorder, mPublicIP *mock_publicips.MockClientMockRecorder) {
+				m *mock_networkinterfaces.MockClientMockRecorder, mPublicIP *mock_publicips.MockClientMockRecorder) {
 				s.NICSpecs().Return([]azure.NICSpec{
 					{
 						Name:                   "my-net-interface",
@@ -706,14 +728,13 @@ func TestDeleteNetworkInterface(t *testing.T) {
 				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
 				s.ResourceGroup().AnyTimes().Return("my-rg")
 				m.Delete(context.TODO(), "my-rg", "my-net-interface")
-				mInboundNATRules.Delete(context.TODO(), "my-rg", "my-public-lb", "azure-test1")
 			},
 		},
 		{
 			name:          "network interface already deleted",
 			expectedError: "",
 			expect: func(s *mock_networkinterfaces.MockNICScopeMockRecorder,
-				m *mock_networkinterfaces.MockClientMockRecorder, mInboundNATRules *mock_inboundnatrules.MockClientMockRecorder, mPublicIP *mock_publicips.MockClientMockRecorder) {
+				m *mock_networkinterfaces.MockClientMockRecorder, mPublicIP *mock_publicips.MockClientMockRecorder) {
 				s.NICSpecs().Return([]azure.NICSpec{
 					{
 						Name:                   "my-net-interface",
@@ -725,14 +746,13 @@ func TestDeleteNetworkInterface(t *testing.T) {
 				s.ResourceGroup().AnyTimes().Return("my-rg")
 				m.Delete(context.TODO(), "my-rg", "my-net-interface").
 					Return(autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
-				mInboundNATRules.Delete(context.TODO(), "my-rg", "my-public-lb", "azure-test1")
 			},
 		},
 		{
 			name:          "network interface deletion fails",
 			expectedError: "failed to delete network interface my-net-interface in resource group my-rg: #: Internal Server Error: StatusCode=500",
 			expect: func(s *mock_networkinterfaces.MockNICScopeMockRecorder,
-				m *mock_networkinterfaces.MockClientMockRecorder, mInboundNATRules *mock_inboundnatrules.MockClientMockRecorder, mPublicIP *mock_publicips.MockClientMockRecorder) {
+				m *mock_networkinterfaces.MockClientMockRecorder, mPublicIP *mock_publicips.MockClientMockRecorder) {
 				s.NICSpecs().Return([]azure.NICSpec{
 					{
 						Name:                   "my-net-interface",
@@ -746,44 +766,6 @@ func TestDeleteNetworkInterface(t *testing.T) {
 					Return(autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error"))
 			},
 		},
-		{
-			name:          "NAT rule already deleted",
-			expectedError: "",
-			expect: func(s *mock_networkinterfaces.MockNICScopeMockRecorder,
-				m *mock_networkinterfaces.MockClientMockRecorder, mInboundNATRules *mock_inboundnatrules.MockClientMockRecorder, mPublicIP *mock_publicips.MockClientMockRecorder) {
-				s.NICSpecs().Return([]azure.NICSpec{
-					{
-						Name:                   "my-net-interface",
-						PublicLoadBalancerName: "my-public-lb",
-						MachineName:            "azure-test1",
-					},
-				})
-				s.ResourceGroup().AnyTimes().Return("my-rg")
-				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
-				m.Delete(context.TODO(), "my-rg", "my-net-interface").
-					Return(autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
-				mInboundNATRules.Delete(context.TODO(), "my-rg", "my-public-lb", "azure-test1")
-			},
-		},
-		{
-			name:          "NAT rule deletion fails",
-			expectedError: "failed to delete inbound NAT rule azure-test1 in load balancer my-public-lb: #: Internal Server Error: StatusCode=500",
-			expect: func(s *mock_networkinterfaces.MockNICScopeMockRecorder,
-				m *mock_networkinterfaces.MockClientMockRecorder, mInboundNATRules *mock_inboundnatrules.MockClientMockRecorder, mPublicIP *mock_publicips.MockClientMockRecorder) {
-				s.NICSpecs().Return([]azure.NICSpec{
-					{
-						Name:                   "my-net-interface",
-						PublicLoadBalancerName: "my-public-lb",
-						MachineName:            "azure-test1",
-					},
-				})
-				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
-				s.ResourceGroup().AnyTimes().Return("my-rg")
-				m.Delete(context.TODO(), "my-rg", "my-net-interface")
-				mInboundNATRules.Delete(context.TODO(), "my-rg", "my-public-lb", "azure-test1").
-					Return(autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error"))
-			},
-		},
 	}
 
 	for _, tc := range testcases {
@@ -794,16 +776,14 @@ func TestDeleteNetworkInterface(t *testing.T) {
 			defer mockCtrl.Finish()
 			scopeMock := mock_networkinterfaces.NewMockNICScope(mockCtrl)
 			clientMock := mock_networkinterfaces.NewMockClient(mockCtrl)
-			inboundNatRulesMock := mock_inboundnatrules.NewMockClient(mockCtrl)
 			publicIPMock := mock_publicips.NewMockClient(mockCtrl)
 
-			tc.expect(scopeMock.EXPECT(), clientMock.EXPECT(), inboundNatRulesMock.EXPECT(), publicIPMock.EXPECT())
+			tc.expect(scopeMock.EXPECT(), clientMock.EXPECT(), publicIPMock.EXPECT())
 
 			s := &Service{
-				Scope:                 scopeMock,
-				Client:                clientMock,
-				InboundNATRulesClient: inboundNatRulesMock,
-				PublicIPsClient:       publicIPMock,
+				Scope:           scopeMock,
+				Client:          clientMock,
+				PublicIPsClient: publicIPMock,
 			}
 
 			err := s.Delete(context.TODO())
@@ -19,7 +19,6 @@ package networkinterfaces
 import (
 	"github.com/go-logr/logr"
 	azure "sigs.k8s.io/cluster-api-provider-azure/cloud"
-	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/inboundnatrules"
 	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/internalloadbalancers"
 	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/publicips"
 	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/publicloadbalancers"
@@ -42,7 +41,6 @@ type Service struct {
 	PublicLoadBalancersClient   publicloadbalancers.Client
 	InternalLoadBalancersClient internalloadbalancers.Client
 	PublicIPsClient             publicips.Client
-	InboundNATRulesClient       inboundnatrules.Client
 	ResourceSkusClient          resourceskus.Client
 }
 
@@ -51,11 +49,10 @@ func NewService(scope NICScope) *Service {
 	return &Service{
 		Scope:                       scope,
 		Client:                      NewClient(scope),
-		SubnetsClient:               subnets.NewClient(scope),
+		SubnetsClient:               subnets.NewClient(azure.VnetAuthorizer(scope)),
 		PublicLoadBalancersClient:   publicloadbalancers.NewClient(scope),
 		InternalLoadBalancersClient: internalloadbalancers.NewClient(scope),
 		PublicIPsClient:             publicips.NewClient(scope),
-		InboundNATRulesClient:       inboundnatrules.NewClient(scope),
 		ResourceSkusClient:          resourceskus.NewClient(scope),
 	}
 }
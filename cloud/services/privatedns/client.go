@@ -0,0 +1,107 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package privatedns
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/services/privatedns/mgmt/2018-09-01/privatedns"
+	"github.com/Azure/go-autorest/autorest"
+	azure "sigs.k8s.io/cluster-api-provider-azure/cloud"
+)
+
+// Client wraps go-sdk
+type Client interface {
+	CreateOrUpdateRecordSet(context.Context, string, string, string, privatedns.RecordType, string, privatedns.RecordSet) error
+	DeleteRecordSet(context.Context, string, string, string, privatedns.RecordType, string) error
+	CreateOrUpdateVirtualNetworkLink(context.Context, string, string, string, privatedns.VirtualNetworkLink) error
+	DeleteVirtualNetworkLink(context.Context, string, string, string) error
+}
+
+// AzureClient contains the Azure go-sdk Client
+type AzureClient struct {
+	auth      azure.Authorizer
+	vnetlinks privatedns.VirtualNetworkLinksClient
+}
+
+var _ Client = &AzureClient{}
+
+// NewClient creates a new private DNS client from subscription ID.
+func NewClient(auth azure.Authorizer) *AzureClient {
+	l := newVirtualNetworkLinksClient(auth.SubscriptionID(), auth.BaseURI(), auth.Authorizer())
+	return &AzureClient{auth, l}
+}
+
+// newRecordSetsClient creates a new private DNS record sets client from subscription ID.
+func newRecordSetsClient(subscriptionID string, baseURI string, authorizer autorest.Authorizer) privatedns.RecordSetsClient {
+	recordSetsClient := privatedns.NewRecordSetsClientWithBaseURI(baseURI, subscriptionID)
+	recordSetsClient.Authorizer = authorizer
+	recordSetsClient.AddToUserAgent(azure.UserAgent())
+	return recordSetsClient
+}
+
+// newVirtualNetworkLinksClient creates a new private DNS virtual network links client from subscription ID.
+func newVirtualNetworkLinksClient(subscriptionID string, baseURI string, authorizer autorest.Authorizer) privatedns.VirtualNetworkLinksClient {
+	linksClient := privatedns.NewVirtualNetworkLinksClientWithBaseURI(baseURI, subscriptionID)
+	linksClient.Authorizer = authorizer
+	linksClient.AddToUserAgent(azure.UserAgent())
+	return linksClient
+}
+
+// CreateOrUpdateRecordSet creates or updates a record set in a private DNS zone, which may be in another
+// subscription than the one of the client.
+func (ac *AzureClient) CreateOrUpdateRecordSet(ctx context.Context, subscriptionID, resourceGroupName string, zoneName string, recordType privatedns.RecordType, name string, set privatedns.RecordSet) error {
+	recordSetsClient := newRecordSetsClient(subscriptionID, ac.auth.BaseURI(), ac.auth.Authorizer())
+	_, err := recordSetsClient.CreateOrUpdate(ctx, resourceGroupName, zoneName, recordType, name, set, "", "")
+	return err
+}
+
+// DeleteRecordSet deletes a record set from a private DNS zone, which may be in another subscription than the one
+// of the client.
+func (ac *AzureClient) DeleteRecordSet(ctx context.Context, subscriptionID, resourceGroupName string, zoneName string, recordType privatedns.RecordType, name string) error {
+	recordSetsClient := newRecordSetsClient(subscriptionID, ac.auth.BaseURI(), ac.auth.Authorizer())
+	_, err := recordSetsClient.Delete(ctx, resourceGroupName, zoneName, recordType, name, "")
+	return err
+}
+
+// CreateOrUpdateVirtualNetworkLink creates or updates a link between a private DNS zone and a virtual network.
+func (ac *AzureClient) CreateOrUpdateVirtualNetworkLink(ctx context.Context, resourceGroupName string, zoneName string, name string, link privatedns.VirtualNetworkLink) error {
+	future, err := ac.vnetlinks.CreateOrUpdate(ctx, resourceGroupName, zoneName, name, link, "", "")
+	if err != nil {
+		return err
+	}
+	err = future.WaitForCompletionRef(ctx, ac.vnetlinks.Client)
+	if err != nil {
+		return err
+	}
+	_, err = future.Result(ac.vnetlinks)
+	return err
+}
+
+// DeleteVirtualNetworkLink deletes a link between a private DNS zone and a virtual network.
+func (ac *AzureClient) DeleteVirtualNetworkLink(ctx context.Context, resourceGroupName string, zoneName string, name string) error {
+	future, err := ac.vnetlinks.Delete(ctx, resourceGroupName, zoneName, name, "")
+	if err != nil {
+		return err
+	}
+	err = future.WaitForCompletionRef(ctx, ac.vnetlinks.Client)
+	if err != nil {
+		return err
+	}
+	_, err = future.Result(ac.vnetlinks)
+	return err
+}
@@ -0,0 +1,107 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ../client.go
+
+// Package mock_privatedns is a generated GoMock package.
+package mock_privatedns
+
+import (
+	context "context"
+	privatedns "github.com/Azure/azure-sdk-for-go/services/privatedns/mgmt/2018-09-01/privatedns"
+	gomock "github.com/golang/mock/gomock"
+	reflect "reflect"
+)
+
+// MockClient is a mock of Client interface.
+type MockClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockClientMockRecorder
+}
+
+// MockClientMockRecorder is the mock recorder for MockClient.
+type MockClientMockRecorder struct {
+	mock *MockClient
+}
+
+// NewMockClient creates a new mock instance.
+func NewMockClient(ctrl *gomock.Controller) *MockClient {
+	mock := &MockClient{ctrl: ctrl}
+	mock.recorder = &MockClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockClient) EXPECT() *MockClientMockRecorder {
+	return m.recorder
+}
+
+// CreateOrUpdateRecordSet mocks base method.
+func (m *MockClient) CreateOrUpdateRecordSet(arg0 context.Context, arg1, arg2, arg3 string, arg4 privatedns.RecordType, arg5 string, arg6 privatedns.RecordSet) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateOrUpdateRecordSet", arg0, arg1, arg2, arg3, arg4, arg5, arg6)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateOrUpdateRecordSet indicates an expected call of CreateOrUpdateRecordSet.
+func (mr *MockClientMockRecorder) CreateOrUpdateRecordSet(arg0, arg1, arg2, arg3, arg4, arg5, arg6 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOrUpdateRecordSet", reflect.TypeOf((*MockClient)(nil).CreateOrUpdateRecordSet), arg0, arg1, arg2, arg3, arg4, arg5, arg6)
+}
+
+// DeleteRecordSet mocks base method.
+func (m *MockClient) DeleteRecordSet(arg0 context.Context, arg1, arg2, arg3 string, arg4 privatedns.RecordType, arg5 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteRecordSet", arg0, arg1, arg2, arg3, arg4, arg5)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteRecordSet indicates an expected call of DeleteRecordSet.
+func (mr *MockClientMockRecorder) DeleteRecordSet(arg0, arg1, arg2, arg3, arg4, arg5 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteRecordSet", reflect.TypeOf((*MockClient)(nil).DeleteRecordSet), arg0, arg1, arg2, arg3, arg4, arg5)
+}
+
+// CreateOrUpdateVirtualNetworkLink mocks base method.
+func (m *MockClient) CreateOrUpdateVirtualNetworkLink(arg0 context.Context, arg1, arg2, arg3 string, arg4 privatedns.VirtualNetworkLink) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateOrUpdateVirtualNetworkLink", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateOrUpdateVirtualNetworkLink indicates an expected call of CreateOrUpdateVirtualNetworkLink.
+func (mr *MockClientMockRecorder) CreateOrUpdateVirtualNetworkLink(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOrUpdateVirtualNetworkLink", reflect.TypeOf((*MockClient)(nil).CreateOrUpdateVirtualNetworkLink), arg0, arg1, arg2, arg3, arg4)
+}
+
+// DeleteVirtualNetworkLink mocks base method.
+func (m *MockClient) DeleteVirtualNetworkLink(arg0 context.Context, arg1, arg2, arg3 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteVirtualNetworkLink", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteVirtualNetworkLink indicates an expected call of DeleteVirtualNetworkLink.
+func (mr *MockClientMockRecorder) DeleteVirtualNetworkLink(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteVirtualNetworkLink", reflect.TypeOf((*MockClient)(nil).DeleteVirtualNetworkLink), arg0, arg1, arg2, arg3)
+}
@@ -0,0 +1,105 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package privateendpoints
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/pkg/errors"
+	azure "sigs.k8s.io/cluster-api-provider-azure/cloud"
+)
+
+// Client wraps go-sdk
+type Client interface {
+	Get(context.Context, string, string) (network.PrivateEndpoint, error)
+	List(context.Context, string) ([]network.PrivateEndpoint, error)
+	CreateOrUpdate(context.Context, string, string, network.PrivateEndpoint) error
+	Delete(context.Context, string, string) error
+}
+
+// AzureClient contains the Azure go-sdk Client
+type AzureClient struct {
+	privateendpoints network.PrivateEndpointsClient
+}
+
+var _ Client = &AzureClient{}
+
+// NewClient creates a new private endpoints client from subscription ID.
+func NewClient(auth azure.Authorizer) *AzureClient {
+	c := newPrivateEndpointsClient(auth.SubscriptionID(), auth.BaseURI(), auth.Authorizer())
+	return &AzureClient{c}
+}
+
+// newPrivateEndpointsClient creates a new private endpoints client from subscription ID.
+func newPrivateEndpointsClient(subscriptionID string, baseURI string, authorizer autorest.Authorizer) network.PrivateEndpointsClient {
+	privateEndpointsClient := network.NewPrivateEndpointsClientWithBaseURI(baseURI, subscriptionID)
+	privateEndpointsClient.Authorizer = authorizer
+	privateEndpointsClient.AddToUserAgent(azure.UserAgent())
+	return privateEndpointsClient
+}
+
+// Get gets the specified private endpoint.
+func (ac *AzureClient) Get(ctx context.Context, resourceGroupName, privateEndpointName string) (network.PrivateEndpoint, error) {
+	return ac.privateendpoints.Get(ctx, resourceGroupName, privateEndpointName, "")
+}
+
+// List lists the private endpoints in a resource group.
+func (ac *AzureClient) List(ctx context.Context, resourceGroupName string) ([]network.PrivateEndpoint, error) {
+	iter, err := ac.privateendpoints.ListComplete(ctx, resourceGroupName)
+	if err != nil {
+		return nil, err
+	}
+
+	var privateEndpoints []network.PrivateEndpoint
+	for iter.NotDone() {
+		privateEndpoints = append(privateEndpoints, iter.Value())
+		if err := iter.NextWithContext(ctx); err != nil {
+			return privateEndpoints, errors.Wrap(err, "could not iterate private endpoints")
+		}
+	}
+	return privateEndpoints, nil
+}
+
+// CreateOrUpdate creates or updates a private endpoint.
+func (ac *AzureClient) CreateOrUpdate(ctx context.Context, resourceGroupName string, privateEndpointName string, privateEndpoint network.PrivateEndpoint) error {
+	future, err := ac.privateendpoints.CreateOrUpdate(ctx, resourceGroupName, privateEndpointName, privateEndpoint)
+	if err != nil {
+		return err
+	}
+	err = future.WaitForCompletionRef(ctx, ac.privateendpoints.Client)
+	if err != nil {
+		return err
+	}
+	_, err = future.Result(ac.privateendpoints)
+	return err
+}
+
+// Delete deletes the specified private endpoint.
+func (ac *AzureClient) Delete(ctx context.Context, resourceGroupName, privateEndpointName string) error {
+	future, err := ac.privateendpoints.Delete(ctx, resourceGroupName, privateEndpointName)
+	if err != nil {
+		return err
+	}
+	err = future.WaitForCompletionRef(ctx, ac.privateendpoints.Client)
+	if err != nil {
+		return err
+	}
+	_, err = future.Result(ac.privateendpoints)
+	return err
+}
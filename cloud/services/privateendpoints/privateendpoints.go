@@ -0,0 +1,256 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package privateendpoints
+
+import (
+	"context"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
+	"github.com/Azure/azure-sdk-for-go/services/privatedns/mgmt/2018-09-01/privatedns"
+	autorestazure "github.com/Azure/go-autorest/autorest/azure"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/pkg/errors"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha3"
+	azure "sigs.k8s.io/cluster-api-provider-azure/cloud"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/converters"
+)
+
+// recordTTL is the TTL in seconds of the private DNS records of private endpoints.
+const recordTTL = 10
+
+// privateDNSZoneTag is the tag recording the private DNS zone in which a private endpoint is registered, so that its
+// record can be deleted along with the private endpoint once it is removed from the spec.
+const privateDNSZoneTag = infrav1.NameAzureProviderPrefix + "private-dns-zone"
+
+// Reconcile gets/creates/updates the private endpoints of the cluster subnets, and deletes the private endpoints
+// owned by the cluster which are no longer part of the spec.
+// Private endpoints are created in the resource group of the virtual network.
+func (s *Service) Reconcile(ctx context.Context) error {
+	existing, err := s.listOwned(ctx)
+	if err != nil {
+		return err
+	}
+
+	wanted := make(map[string]bool)
+	for _, peSpec := range s.Scope.PrivateEndpointSpecs() {
+		wanted[strings.ToLower(peSpec.Name)] = true
+		if pe, ok := existing[strings.ToLower(peSpec.Name)]; ok {
+			if zoneID := to.String(pe.Tags[privateDNSZoneTag]); zoneID != "" && zoneID != peSpec.PrivateDNSZoneID {
+				if err := s.deleteDNSRecord(ctx, zoneID, peSpec.PrivateLinkResourceID); err != nil {
+					return err
+				}
+			}
+		}
+
+		s.Scope.V(2).Info("creating private endpoint", "private endpoint", peSpec.Name)
+		var groupIDs *[]string
+		if len(peSpec.GroupIDs) > 0 {
+			groupIDs = &peSpec.GroupIDs
+		}
+		additionalTags := make(infrav1.Tags)
+		additionalTags.Merge(s.Scope.AdditionalTags())
+		if peSpec.PrivateDNSZoneID != "" {
+			additionalTags[privateDNSZoneTag] = peSpec.PrivateDNSZoneID
+		}
+		err := s.Client.CreateOrUpdate(ctx, s.Scope.Vnet().ResourceGroup, peSpec.Name, network.PrivateEndpoint{
+			Location: to.StringPtr(s.Scope.Location()),
+			Tags: converters.TagsToMap(infrav1.Build(infrav1.BuildParams{
+				ClusterName: s.Scope.ClusterName(),
+				Lifecycle:   infrav1.ResourceLifecycleOwned,
+				Name:        to.StringPtr(peSpec.Name),
+				Additional:  additionalTags,
+			})),
+			PrivateEndpointProperties: &network.PrivateEndpointProperties{
+				Subnet: &network.Subnet{ID: to.StringPtr(peSpec.SubnetID)},
+				PrivateLinkServiceConnections: &[]network.PrivateLinkServiceConnection{
+					{
+						Name: to.StringPtr(peSpec.Name),
+						PrivateLinkServiceConnectionProperties: &network.PrivateLinkServiceConnectionProperties{
+							PrivateLinkServiceID: to.StringPtr(peSpec.PrivateLinkResourceID),
+							GroupIds:             groupIDs,
+						},
+					},
+				},
+			},
+		})
+		if err != nil {
+			return errors.Wrapf(err, "failed to create private endpoint %s in resource group %s", peSpec.Name, s.Scope.Vnet().ResourceGroup)
+		}
+
+		if peSpec.PrivateDNSZoneID != "" {
+			if err := s.reconcileDNSRecord(ctx, peSpec); err != nil {
+				return errors.Wrapf(err, "failed to register private endpoint %s in private DNS zone", peSpec.Name)
+			}
+		}
+		s.Scope.V(2).Info("successfully created private endpoint", "private endpoint", peSpec.Name)
+	}
+
+	for name, pe := range existing {
+		if wanted[name] {
+			continue
+		}
+		if err := s.deletePrivateEndpoint(ctx, pe); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Delete deletes the private endpoints owned by the cluster and their private DNS records.
+func (s *Service) Delete(ctx context.Context) error {
+	existing, err := s.listOwned(ctx)
+	if err != nil {
+		return err
+	}
+	for _, pe := range existing {
+		if err := s.deletePrivateEndpoint(ctx, pe); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// listOwned returns the private endpoints owned by the cluster in the resource group of the virtual network, by
+// lowercase name.
+func (s *Service) listOwned(ctx context.Context) (map[string]network.PrivateEndpoint, error) {
+	privateEndpoints, err := s.Client.List(ctx, s.Scope.Vnet().ResourceGroup)
+	if err != nil && !azure.ResourceNotFound(err) {
+		return nil, errors.Wrapf(err, "failed to list private endpoints in resource group %s", s.Scope.Vnet().ResourceGroup)
+	}
+	owned := make(map[string]network.PrivateEndpoint)
+	for _, pe := range privateEndpoints {
+		if converters.MapToTags(pe.Tags).HasOwned(s.Scope.ClusterName()) {
+			owned[strings.ToLower(to.String(pe.Name))] = pe
+		}
+	}
+	return owned, nil
+}
+
+// deletePrivateEndpoint deletes a private endpoint and the record registering it in a private DNS zone.
+func (s *Service) deletePrivateEndpoint(ctx context.Context, pe network.PrivateEndpoint) error {
+	name := to.String(pe.Name)
+	if zoneID := to.String(pe.Tags[privateDNSZoneTag]); zoneID != "" && pe.PrivateEndpointProperties != nil && pe.PrivateLinkServiceConnections != nil {
+		for _, connection := range *pe.PrivateLinkServiceConnections {
+			if connection.PrivateLinkServiceConnectionProperties == nil {
+				continue
+			}
+			if err := s.deleteDNSRecord(ctx, zoneID, to.String(connection.PrivateLinkServiceID)); err != nil {
+				return err
+			}
+		}
+	}
+
+	s.Scope.V(2).Info("deleting private endpoint", "private endpoint", name)
+	err := s.Client.Delete(ctx, s.Scope.Vnet().ResourceGroup, name)
+	if err != nil && !azure.ResourceNotFound(err) {
+		return errors.Wrapf(err, "failed to delete private endpoint %s in resource group %s", name, s.Scope.Vnet().ResourceGroup)
+	}
+	s.Scope.V(2).Info("successfully deleted private endpoint", "private endpoint", name)
+	return nil
+}
+
+// deleteDNSRecord deletes the record of the target resource of a private endpoint in a private DNS zone.
+func (s *Service) deleteDNSRecord(ctx context.Context, zoneID, privateLinkResourceID string) error {
+	zone, recordName, err := getDNSRecordLocation(zoneID, privateLinkResourceID)
+	if err != nil {
+		return err
+	}
+	s.Scope.V(2).Info("deleting private DNS record", "private DNS zone", zone.ResourceName, "record", recordName)
+	err = s.PrivateDNSClient.DeleteRecordSet(ctx, zone.SubscriptionID, zone.ResourceGroup, zone.ResourceName, privatedns.A, recordName)
+	if err != nil && !azure.ResourceNotFound(err) {
+		return errors.Wrapf(err, "failed to delete record %s in private DNS zone %s", recordName, zone.ResourceName)
+	}
+	return nil
+}
+
+// reconcileDNSRecord creates an A record for the private IP addresses of the private endpoint in its private DNS zone.
+func (s *Service) reconcileDNSRecord(ctx context.Context, peSpec azure.PrivateEndpointSpec) error {
+	zone, recordName, err := getDNSRecordLocation(peSpec.PrivateDNSZoneID, peSpec.PrivateLinkResourceID)
+	if err != nil {
+		return err
+	}
+
+	pe, err := s.Client.Get(ctx, s.Scope.Vnet().ResourceGroup, peSpec.Name)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get private endpoint %s", peSpec.Name)
+	}
+	ips, err := s.getPrivateIPAddresses(ctx, pe)
+	if err != nil {
+		return err
+	}
+	if len(ips) == 0 {
+		return errors.Errorf("private endpoint %s has no private IP address", peSpec.Name)
+	}
+
+	records := make([]privatedns.ARecord, 0, len(ips))
+	for _, ip := range ips {
+		records = append(records, privatedns.ARecord{Ipv4Address: to.StringPtr(ip)})
+	}
+	s.Scope.V(2).Info("creating private DNS record", "private DNS zone", zone.ResourceName, "record", recordName)
+	err = s.PrivateDNSClient.CreateOrUpdateRecordSet(ctx, zone.SubscriptionID, zone.ResourceGroup, zone.ResourceName, privatedns.A, recordName, privatedns.RecordSet{
+		RecordSetProperties: &privatedns.RecordSetProperties{
+			TTL:      to.Int64Ptr(recordTTL),
+			ARecords: &records,
+		},
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to create record %s in private DNS zone %s", recordName, zone.ResourceName)
+	}
+	return nil
+}
+
+// getDNSRecordLocation returns the private DNS zone of a private endpoint and the name of its record, which is the
+// name of the target resource. The zone may be in any subscription, e.g. in the one of a hub network.
+func getDNSRecordLocation(zoneID, privateLinkResourceID string) (autorestazure.Resource, string, error) {
+	zone, err := autorestazure.ParseResourceID(zoneID)
+	if err != nil {
+		return zone, "", errors.Wrapf(err, "failed to parse private DNS zone ID %s", zoneID)
+	}
+	target, err := autorestazure.ParseResourceID(privateLinkResourceID)
+	if err != nil {
+		return zone, "", errors.Wrapf(err, "failed to parse private link resource ID %s", privateLinkResourceID)
+	}
+	return zone, target.ResourceName, nil
+}
+
+// getPrivateIPAddresses returns the private IP addresses of the network interfaces of a private endpoint.
+func (s *Service) getPrivateIPAddresses(ctx context.Context, pe network.PrivateEndpoint) ([]string, error) {
+	var ips []string
+	if pe.PrivateEndpointProperties == nil || pe.NetworkInterfaces == nil {
+		return ips, nil
+	}
+	for _, nicRef := range *pe.NetworkInterfaces {
+		nicID, err := autorestazure.ParseResourceID(to.String(nicRef.ID))
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to parse network interface ID %s", to.String(nicRef.ID))
+		}
+		nic, err := s.InterfacesClient.Get(ctx, nicID.ResourceGroup, nicID.ResourceName)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get network interface %s", nicID.ResourceName)
+		}
+		if nic.InterfacePropertiesFormat == nil || nic.IPConfigurations == nil {
+			continue
+		}
+		for _, ipConfig := range *nic.IPConfigurations {
+			if ipConfig.InterfaceIPConfigurationPropertiesFormat != nil && ipConfig.PrivateIPAddress != nil {
+				ips = append(ips, to.String(ipConfig.PrivateIPAddress))
+			}
+		}
+	}
+	return ips, nil
+}
@@ -0,0 +1,379 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package privateendpoints
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
+	"github.com/Azure/azure-sdk-for-go/services/privatedns/mgmt/2018-09-01/privatedns"
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+	"k8s.io/klog/klogr"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha3"
+	azure "sigs.k8s.io/cluster-api-provider-azure/cloud"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/networkinterfaces/mock_networkinterfaces"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/privatedns/mock_privatedns"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/privateendpoints/mock_privateendpoints"
+)
+
+const (
+	storageAccountID    = "/subscriptions/123/resourceGroups/storage-rg/providers/Microsoft.Storage/storageAccounts/mystorage"
+	privateDNSZoneID    = "/subscriptions/123/resourceGroups/dns-rg/providers/Microsoft.Network/privateDnsZones/privatelink.blob.core.windows.net"
+	hubPrivateDNSZoneID = "/subscriptions/789/resourceGroups/dns-rg/providers/Microsoft.Network/privateDnsZones/privatelink.blob.core.windows.net"
+)
+
+// ownedPrivateEndpoint returns a private endpoint of the storage account owned by my-cluster, registered in the
+// given private DNS zone.
+func ownedPrivateEndpoint(name, zoneID string) network.PrivateEndpoint {
+	tags := map[string]*string{
+		"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": to.StringPtr("owned"),
+	}
+	if zoneID != "" {
+		tags["sigs.k8s.io_cluster-api-provider-azure_private-dns-zone"] = to.StringPtr(zoneID)
+	}
+	return network.PrivateEndpoint{
+		Name: to.StringPtr(name),
+		Tags: tags,
+		PrivateEndpointProperties: &network.PrivateEndpointProperties{
+			PrivateLinkServiceConnections: &[]network.PrivateLinkServiceConnection{
+				{
+					PrivateLinkServiceConnectionProperties: &network.PrivateLinkServiceConnectionProperties{
+						PrivateLinkServiceID: to.StringPtr(storageAccountID),
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestReconcilePrivateEndpoints(t *testing.T) {
+	testcases := []struct {
+		name          string
+		expectedError string
+		expect        func(s *mock_privateendpoints.MockPrivateEndpointScopeMockRecorder,
+			m *mock_privateendpoints.MockClientMockRecorder,
+			mNIC *mock_networkinterfaces.MockClientMockRecorder,
+			mDNS *mock_privatedns.MockClientMockRecorder)
+	}{
+		{
+			name:          "private endpoint without private DNS zone",
+			expectedError: "",
+			expect: func(s *mock_privateendpoints.MockPrivateEndpointScopeMockRecorder,
+				m *mock_privateendpoints.MockClientMockRecorder,
+				mNIC *mock_networkinterfaces.MockClientMockRecorder,
+				mDNS *mock_privatedns.MockClientMockRecorder) {
+				s.PrivateEndpointSpecs().Return([]azure.PrivateEndpointSpec{
+					{
+						Name:                  "my-pe",
+						SubnetID:              "subnet-id",
+						PrivateLinkResourceID: storageAccountID,
+						GroupIDs:              []string{"blob"},
+					},
+				})
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.Vnet().AnyTimes().Return(&infrav1.VnetSpec{Name: "my-vnet", ResourceGroup: "vnet-rg"})
+				s.Location().AnyTimes().Return("westus")
+				s.ClusterName().AnyTimes().Return("my-cluster")
+				s.AdditionalTags().AnyTimes().Return(infrav1.Tags{})
+				m.List(context.TODO(), "vnet-rg")
+				m.CreateOrUpdate(context.TODO(), "vnet-rg", "my-pe", network.PrivateEndpoint{
+					Location: to.StringPtr("westus"),
+					Tags: map[string]*string{
+						"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": to.StringPtr("owned"),
+						"Name": to.StringPtr("my-pe"),
+					},
+					PrivateEndpointProperties: &network.PrivateEndpointProperties{
+						Subnet: &network.Subnet{ID: to.StringPtr("subnet-id")},
+						PrivateLinkServiceConnections: &[]network.PrivateLinkServiceConnection{
+							{
+								Name: to.StringPtr("my-pe"),
+								PrivateLinkServiceConnectionProperties: &network.PrivateLinkServiceConnectionProperties{
+									PrivateLinkServiceID: to.StringPtr(storageAccountID),
+									GroupIds:             &[]string{"blob"},
+								},
+							},
+						},
+					},
+				})
+			},
+		},
+		{
+			name:          "private endpoint registered in private DNS zone of another subscription",
+			expectedError: "",
+			expect: func(s *mock_privateendpoints.MockPrivateEndpointScopeMockRecorder,
+				m *mock_privateendpoints.MockClientMockRecorder,
+				mNIC *mock_networkinterfaces.MockClientMockRecorder,
+				mDNS *mock_privatedns.MockClientMockRecorder) {
+				s.PrivateEndpointSpecs().Return([]azure.PrivateEndpointSpec{
+					{
+						Name:                  "my-pe",
+						SubnetID:              "subnet-id",
+						PrivateLinkResourceID: storageAccountID,
+						GroupIDs:              []string{"blob"},
+						PrivateDNSZoneID:      hubPrivateDNSZoneID,
+					},
+				})
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.Location().AnyTimes().Return("westus")
+				s.ClusterName().AnyTimes().Return("my-cluster")
+				s.AdditionalTags().AnyTimes().Return(infrav1.Tags{})
+				s.Vnet().AnyTimes().Return(&infrav1.VnetSpec{Name: "my-vnet", ResourceGroup: "vnet-rg"})
+				gomock.InOrder(
+					m.List(context.TODO(), "vnet-rg"),
+					m.CreateOrUpdate(context.TODO(), "vnet-rg", "my-pe", gomock.AssignableToTypeOf(network.PrivateEndpoint{})).
+						Do(func(_ context.Context, _, _ string, pe network.PrivateEndpoint) {
+							g := NewWithT(t)
+							g.Expect(pe.Tags).To(HaveKeyWithValue("sigs.k8s.io_cluster-api-provider-azure_private-dns-zone", to.StringPtr(hubPrivateDNSZoneID)))
+						}),
+					m.Get(context.TODO(), "vnet-rg", "my-pe").Return(network.PrivateEndpoint{
+						PrivateEndpointProperties: &network.PrivateEndpointProperties{
+							NetworkInterfaces: &[]network.Interface{
+								{ID: to.StringPtr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/networkInterfaces/my-pe.nic.1234")},
+							},
+						},
+					}, nil),
+					mNIC.Get(context.TODO(), "my-rg", "my-pe.nic.1234").Return(network.Interface{
+						InterfacePropertiesFormat: &network.InterfacePropertiesFormat{
+							IPConfigurations: &[]network.InterfaceIPConfiguration{
+								{
+									InterfaceIPConfigurationPropertiesFormat: &network.InterfaceIPConfigurationPropertiesFormat{
+										PrivateIPAddress: to.StringPtr("10.1.0.5"),
+									},
+								},
+							},
+						},
+					}, nil),
+					mDNS.CreateOrUpdateRecordSet(context.TODO(), "789", "dns-rg", "privatelink.blob.core.windows.net", privatedns.A, "mystorage", privatedns.RecordSet{
+						RecordSetProperties: &privatedns.RecordSetProperties{
+							TTL:      to.Int64Ptr(10),
+							ARecords: &[]privatedns.ARecord{{Ipv4Address: to.StringPtr("10.1.0.5")}},
+						},
+					}),
+				)
+			},
+		},
+		{
+			name:          "delete private endpoints removed from the spec",
+			expectedError: "",
+			expect: func(s *mock_privateendpoints.MockPrivateEndpointScopeMockRecorder,
+				m *mock_privateendpoints.MockClientMockRecorder,
+				mNIC *mock_networkinterfaces.MockClientMockRecorder,
+				mDNS *mock_privatedns.MockClientMockRecorder) {
+				s.PrivateEndpointSpecs().Return(nil)
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.ClusterName().AnyTimes().Return("my-cluster")
+				s.Vnet().AnyTimes().Return(&infrav1.VnetSpec{Name: "my-vnet", ResourceGroup: "vnet-rg"})
+				m.List(context.TODO(), "vnet-rg").Return([]network.PrivateEndpoint{
+					ownedPrivateEndpoint("my-pe", privateDNSZoneID),
+					{
+						Name: to.StringPtr("other-pe"),
+						Tags: map[string]*string{
+							"sigs.k8s.io_cluster-api-provider-azure_cluster_other-cluster": to.StringPtr("owned"),
+						},
+					},
+				}, nil)
+				gomock.InOrder(
+					mDNS.DeleteRecordSet(context.TODO(), "123", "dns-rg", "privatelink.blob.core.windows.net", privatedns.A, "mystorage"),
+					m.Delete(context.TODO(), "vnet-rg", "my-pe"),
+				)
+			},
+		},
+		{
+			name:          "move private endpoint to another private DNS zone",
+			expectedError: "",
+			expect: func(s *mock_privateendpoints.MockPrivateEndpointScopeMockRecorder,
+				m *mock_privateendpoints.MockClientMockRecorder,
+				mNIC *mock_networkinterfaces.MockClientMockRecorder,
+				mDNS *mock_privatedns.MockClientMockRecorder) {
+				s.PrivateEndpointSpecs().Return([]azure.PrivateEndpointSpec{
+					{
+						Name:                  "my-pe",
+						SubnetID:              "subnet-id",
+						PrivateLinkResourceID: storageAccountID,
+					},
+				})
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.Location().AnyTimes().Return("westus")
+				s.ClusterName().AnyTimes().Return("my-cluster")
+				s.AdditionalTags().AnyTimes().Return(infrav1.Tags{})
+				s.Vnet().AnyTimes().Return(&infrav1.VnetSpec{Name: "my-vnet", ResourceGroup: "vnet-rg"})
+				gomock.InOrder(
+					m.List(context.TODO(), "vnet-rg").Return([]network.PrivateEndpoint{ownedPrivateEndpoint("my-pe", privateDNSZoneID)}, nil),
+					mDNS.DeleteRecordSet(context.TODO(), "123", "dns-rg", "privatelink.blob.core.windows.net", privatedns.A, "mystorage"),
+					m.CreateOrUpdate(context.TODO(), "vnet-rg", "my-pe", gomock.AssignableToTypeOf(network.PrivateEndpoint{})),
+				)
+			},
+		},
+		{
+			name:          "fail to create private endpoint",
+			expectedError: "failed to create private endpoint my-pe in resource group my-rg: #: Internal Server Error: StatusCode=500",
+			expect: func(s *mock_privateendpoints.MockPrivateEndpointScopeMockRecorder,
+				m *mock_privateendpoints.MockClientMockRecorder,
+				mNIC *mock_networkinterfaces.MockClientMockRecorder,
+				mDNS *mock_privatedns.MockClientMockRecorder) {
+				s.PrivateEndpointSpecs().Return([]azure.PrivateEndpointSpec{
+					{
+						Name:                  "my-pe",
+						SubnetID:              "subnet-id",
+						PrivateLinkResourceID: storageAccountID,
+					},
+				})
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.Vnet().AnyTimes().Return(&infrav1.VnetSpec{Name: "my-vnet", ResourceGroup: "my-rg"})
+				s.Location().AnyTimes().Return("westus")
+				s.ClusterName().AnyTimes().Return("my-cluster")
+				s.AdditionalTags().AnyTimes().Return(infrav1.Tags{})
+				m.List(context.TODO(), "my-rg")
+				m.CreateOrUpdate(context.TODO(), "my-rg", "my-pe", gomock.AssignableToTypeOf(network.PrivateEndpoint{})).
+					Return(autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error"))
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_privateendpoints.NewMockPrivateEndpointScope(mockCtrl)
+			clientMock := mock_privateendpoints.NewMockClient(mockCtrl)
+			nicMock := mock_networkinterfaces.NewMockClient(mockCtrl)
+			dnsMock := mock_privatedns.NewMockClient(mockCtrl)
+
+			tc.expect(scopeMock.EXPECT(), clientMock.EXPECT(), nicMock.EXPECT(), dnsMock.EXPECT())
+
+			s := &Service{
+				Scope:            scopeMock,
+				Client:           clientMock,
+				InterfacesClient: nicMock,
+				PrivateDNSClient: dnsMock,
+			}
+
+			err := s.Reconcile(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestDeletePrivateEndpoints(t *testing.T) {
+	testcases := []struct {
+		name          string
+		expectedError string
+		expect        func(s *mock_privateendpoints.MockPrivateEndpointScopeMockRecorder,
+			m *mock_privateendpoints.MockClientMockRecorder,
+			mDNS *mock_privatedns.MockClientMockRecorder)
+	}{
+		{
+			name:          "successfully delete private endpoint and DNS record",
+			expectedError: "",
+			expect: func(s *mock_privateendpoints.MockPrivateEndpointScopeMockRecorder,
+				m *mock_privateendpoints.MockClientMockRecorder,
+				mDNS *mock_privatedns.MockClientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.ClusterName().AnyTimes().Return("my-cluster")
+				s.Vnet().AnyTimes().Return(&infrav1.VnetSpec{Name: "my-vnet", ResourceGroup: "my-rg"})
+				gomock.InOrder(
+					m.List(context.TODO(), "my-rg").Return([]network.PrivateEndpoint{ownedPrivateEndpoint("my-pe", hubPrivateDNSZoneID)}, nil),
+					mDNS.DeleteRecordSet(context.TODO(), "789", "dns-rg", "privatelink.blob.core.windows.net", privatedns.A, "mystorage"),
+					m.Delete(context.TODO(), "my-rg", "my-pe"),
+				)
+			},
+		},
+		{
+			name:          "resource group already deleted",
+			expectedError: "",
+			expect: func(s *mock_privateendpoints.MockPrivateEndpointScopeMockRecorder,
+				m *mock_privateendpoints.MockClientMockRecorder,
+				mDNS *mock_privatedns.MockClientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.ClusterName().AnyTimes().Return("my-cluster")
+				s.Vnet().AnyTimes().Return(&infrav1.VnetSpec{Name: "my-vnet", ResourceGroup: "my-rg"})
+				m.List(context.TODO(), "my-rg").
+					Return(nil, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
+			},
+		},
+		{
+			name:          "private endpoint already deleted",
+			expectedError: "",
+			expect: func(s *mock_privateendpoints.MockPrivateEndpointScopeMockRecorder,
+				m *mock_privateendpoints.MockClientMockRecorder,
+				mDNS *mock_privatedns.MockClientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.ClusterName().AnyTimes().Return("my-cluster")
+				s.Vnet().AnyTimes().Return(&infrav1.VnetSpec{Name: "my-vnet", ResourceGroup: "my-rg"})
+				m.List(context.TODO(), "my-rg").Return([]network.PrivateEndpoint{ownedPrivateEndpoint("my-pe", "")}, nil)
+				m.Delete(context.TODO(), "my-rg", "my-pe").
+					Return(autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
+			},
+		},
+		{
+			name:          "private endpoint deletion fails",
+			expectedError: "failed to delete private endpoint my-pe in resource group my-rg: #: Internal Server Error: StatusCode=500",
+			expect: func(s *mock_privateendpoints.MockPrivateEndpointScopeMockRecorder,
+				m *mock_privateendpoints.MockClientMockRecorder,
+				mDNS *mock_privatedns.MockClientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.ClusterName().AnyTimes().Return("my-cluster")
+				s.Vnet().AnyTimes().Return(&infrav1.VnetSpec{Name: "my-vnet", ResourceGroup: "my-rg"})
+				m.List(context.TODO(), "my-rg").Return([]network.PrivateEndpoint{ownedPrivateEndpoint("my-pe", "")}, nil)
+				m.Delete(context.TODO(), "my-rg", "my-pe").
+					Return(autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error"))
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_privateendpoints.NewMockPrivateEndpointScope(mockCtrl)
+			clientMock := mock_privateendpoints.NewMockClient(mockCtrl)
+			dnsMock := mock_privatedns.NewMockClient(mockCtrl)
+
+			tc.expect(scopeMock.EXPECT(), clientMock.EXPECT(), dnsMock.EXPECT())
+
+			s := &Service{
+				Scope:            scopeMock,
+				Client:           clientMock,
+				PrivateDNSClient: dnsMock,
+			}
+
+			err := s.Delete(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
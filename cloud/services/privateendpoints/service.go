@@ -0,0 +1,50 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package privateendpoints
+
+import (
+	"github.com/go-logr/logr"
+	azure "sigs.k8s.io/cluster-api-provider-azure/cloud"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/networkinterfaces"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/privatedns"
+)
+
+// PrivateEndpointScope defines the scope interface for a private endpoint service.
+type PrivateEndpointScope interface {
+	logr.Logger
+	azure.ClusterDescriber
+	PrivateEndpointSpecs() []azure.PrivateEndpointSpec
+}
+
+// Service provides operations on azure resources
+type Service struct {
+	Scope PrivateEndpointScope
+	Client
+	InterfacesClient networkinterfaces.Client
+	PrivateDNSClient privatedns.Client
+}
+
+// NewService creates a new service. Private endpoints are created in the subscription of the virtual network, as
+// Azure requires.
+func NewService(scope PrivateEndpointScope) *Service {
+	return &Service{
+		Scope:            scope,
+		Client:           NewClient(azure.VnetAuthorizer(scope)),
+		InterfacesClient: networkinterfaces.NewClient(azure.VnetAuthorizer(scope)),
+		PrivateDNSClient: privatedns.NewClient(scope),
+	}
+}
@@ -0,0 +1,220 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package proximityplacementgroups
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2019-12-01/compute"
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+	"k8s.io/klog/klogr"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/proximityplacementgroups/mock_proximityplacementgroups"
+)
+
+func TestReconcileProximityPlacementGroups(t *testing.T) {
+	testcases := []struct {
+		name          string
+		expectedError string
+		expect        func(s *mock_proximityplacementgroups.MockProximityPlacementGroupScopeMockRecorder, m *mock_proximityplacementgroups.MockClientMockRecorder)
+	}{
+		{
+			name:          "proximity placement group already exists",
+			expectedError: "",
+			expect: func(s *mock_proximityplacementgroups.MockProximityPlacementGroupScopeMockRecorder, m *mock_proximityplacementgroups.MockClientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.ClusterName().AnyTimes().Return("test-cluster")
+				m.Get(context.TODO(), "my-rg", "my-ppg").Return(compute.ProximityPlacementGroup{Name: to.StringPtr("my-ppg")}, nil)
+			},
+		},
+		{
+			name:          "create proximity placement group",
+			expectedError: "",
+			expect: func(s *mock_proximityplacementgroups.MockProximityPlacementGroupScopeMockRecorder, m *mock_proximityplacementgroups.MockClientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.ClusterName().AnyTimes().Return("test-cluster")
+				s.Location().AnyTimes().Return("test-location")
+				s.AdditionalTags().AnyTimes()
+				m.Get(context.TODO(), "my-rg", "my-ppg").Return(compute.ProximityPlacementGroup{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
+				m.CreateOrUpdate(context.TODO(), "my-rg", "my-ppg", compute.ProximityPlacementGroup{
+					Location: to.StringPtr("test-location"),
+					Tags: map[string]*string{
+						"Name": to.StringPtr("my-ppg"),
+						"sigs.k8s.io_cluster-api-provider-azure_cluster_test-cluster": to.StringPtr("owned"),
+					},
+					ProximityPlacementGroupProperties: &compute.ProximityPlacementGroupProperties{
+						ProximityPlacementGroupType: compute.Standard,
+					},
+				})
+			},
+		},
+		{
+			name:          "fail to get proximity placement group",
+			expectedError: "failed to get proximity placement group my-ppg in my-rg: #: Internal Server Error: StatusCode=500",
+			expect: func(s *mock_proximityplacementgroups.MockProximityPlacementGroupScopeMockRecorder, m *mock_proximityplacementgroups.MockClientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.ClusterName().AnyTimes().Return("test-cluster")
+				m.Get(context.TODO(), "my-rg", "my-ppg").Return(compute.ProximityPlacementGroup{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error"))
+			},
+		},
+		{
+			name:          "fail to create proximity placement group",
+			expectedError: "failed to create proximity placement group my-ppg in resource group my-rg: #: Internal Server Error: StatusCode=500",
+			expect: func(s *mock_proximityplacementgroups.MockProximityPlacementGroupScopeMockRecorder, m *mock_proximityplacementgroups.MockClientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.ClusterName().AnyTimes().Return("test-cluster")
+				s.Location().AnyTimes().Return("test-location")
+				s.AdditionalTags().AnyTimes()
+				m.Get(context.TODO(), "my-rg", "my-ppg").Return(compute.ProximityPlacementGroup{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
+				m.CreateOrUpdate(context.TODO(), "my-rg", "my-ppg", gomock.AssignableToTypeOf(compute.ProximityPlacementGroup{})).Return(autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error"))
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			scopeMock := mock_proximityplacementgroups.NewMockProximityPlacementGroupScope(mockCtrl)
+			clientMock := mock_proximityplacementgroups.NewMockClient(mockCtrl)
+			tc.expect(scopeMock.EXPECT(), clientMock.EXPECT())
+
+			s := &Service{
+				Scope:  scopeMock,
+				Client: clientMock,
+			}
+
+			err := s.Reconcile(context.TODO(), &Spec{Name: "my-ppg"})
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestDeleteProximityPlacementGroups(t *testing.T) {
+	ownedTags := map[string]*string{
+		"sigs.k8s.io_cluster-api-provider-azure_cluster_test-cluster": to.StringPtr("owned"),
+	}
+
+	testcases := []struct {
+		name          string
+		expectedError string
+		expect        func(s *mock_proximityplacementgroups.MockProximityPlacementGroupScopeMockRecorder, m *mock_proximityplacementgroups.MockClientMockRecorder)
+	}{
+		{
+			name:          "proximity placement group already deleted",
+			expectedError: "",
+			expect: func(s *mock_proximityplacementgroups.MockProximityPlacementGroupScopeMockRecorder, m *mock_proximityplacementgroups.MockClientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.ClusterName().AnyTimes().Return("test-cluster")
+				m.Get(context.TODO(), "my-rg", "my-ppg").Return(compute.ProximityPlacementGroup{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
+			},
+		},
+		{
+			name:          "delete empty owned proximity placement group",
+			expectedError: "",
+			expect: func(s *mock_proximityplacementgroups.MockProximityPlacementGroupScopeMockRecorder, m *mock_proximityplacementgroups.MockClientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.ClusterName().AnyTimes().Return("test-cluster")
+				m.Get(context.TODO(), "my-rg", "my-ppg").Return(compute.ProximityPlacementGroup{
+					Tags:                              ownedTags,
+					ProximityPlacementGroupProperties: &compute.ProximityPlacementGroupProperties{},
+				}, nil)
+				m.Delete(context.TODO(), "my-rg", "my-ppg")
+			},
+		},
+		{
+			name:          "do not delete proximity placement group with VMs",
+			expectedError: "",
+			expect: func(s *mock_proximityplacementgroups.MockProximityPlacementGroupScopeMockRecorder, m *mock_proximityplacementgroups.MockClientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.ClusterName().AnyTimes().Return("test-cluster")
+				m.Get(context.TODO(), "my-rg", "my-ppg").Return(compute.ProximityPlacementGroup{
+					Tags: ownedTags,
+					ProximityPlacementGroupProperties: &compute.ProximityPlacementGroupProperties{
+						VirtualMachines: &[]compute.SubResourceWithColocationStatus{{ID: to.StringPtr("my-vm")}},
+					},
+				}, nil)
+			},
+		},
+		{
+			name:          "do not delete unmanaged proximity placement group",
+			expectedError: "",
+			expect: func(s *mock_proximityplacementgroups.MockProximityPlacementGroupScopeMockRecorder, m *mock_proximityplacementgroups.MockClientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.ClusterName().AnyTimes().Return("test-cluster")
+				m.Get(context.TODO(), "my-rg", "my-ppg").Return(compute.ProximityPlacementGroup{}, nil)
+			},
+		},
+		{
+			name:          "fail to delete proximity placement group",
+			expectedError: "failed to delete proximity placement group my-ppg in resource group my-rg: #: Internal Server Error: StatusCode=500",
+			expect: func(s *mock_proximityplacementgroups.MockProximityPlacementGroupScopeMockRecorder, m *mock_proximityplacementgroups.MockClientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.ClusterName().AnyTimes().Return("test-cluster")
+				m.Get(context.TODO(), "my-rg", "my-ppg").Return(compute.ProximityPlacementGroup{Tags: ownedTags}, nil)
+				m.Delete(context.TODO(), "my-rg", "my-ppg").Return(autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error"))
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			scopeMock := mock_proximityplacementgroups.NewMockProximityPlacementGroupScope(mockCtrl)
+			clientMock := mock_proximityplacementgroups.NewMockClient(mockCtrl)
+			tc.expect(scopeMock.EXPECT(), clientMock.EXPECT())
+
+			s := &Service{
+				Scope:  scopeMock,
+				Client: clientMock,
+			}
+
+			err := s.Delete(context.TODO(), &Spec{Name: "my-ppg"})
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package publicdns
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/services/dns/mgmt/2018-05-01/dns"
+	"github.com/Azure/go-autorest/autorest"
+	azure "sigs.k8s.io/cluster-api-provider-azure/cloud"
+)
+
+// Client wraps go-sdk
+type Client interface {
+	CreateOrUpdateRecordSet(context.Context, string, string, string, dns.RecordType, dns.RecordSet) error
+	DeleteRecordSet(context.Context, string, string, string, dns.RecordType) error
+}
+
+// AzureClient contains the Azure go-sdk Client
+type AzureClient struct {
+	recordsets dns.RecordSetsClient
+}
+
+var _ Client = &AzureClient{}
+
+// NewClient creates a new DNS client from subscription ID.
+func NewClient(auth azure.Authorizer) *AzureClient {
+	c := newRecordSetsClient(auth.SubscriptionID(), auth.BaseURI(), auth.Authorizer())
+	return &AzureClient{c}
+}
+
+// newRecordSetsClient creates a new DNS record sets client from subscription ID.
+func newRecordSetsClient(subscriptionID string, baseURI string, authorizer autorest.Authorizer) dns.RecordSetsClient {
+	recordSetsClient := dns.NewRecordSetsClientWithBaseURI(baseURI, subscriptionID)
+	recordSetsClient.Authorizer = authorizer
+	recordSetsClient.AddToUserAgent(azure.UserAgent())
+	return recordSetsClient
+}
+
+// CreateOrUpdateRecordSet creates or updates a record set in a DNS zone.
+func (ac *AzureClient) CreateOrUpdateRecordSet(ctx context.Context, resourceGroupName string, zoneName string, name string, recordType dns.RecordType, set dns.RecordSet) error {
+	_, err := ac.recordsets.CreateOrUpdate(ctx, resourceGroupName, zoneName, name, recordType, set, "", "")
+	return err
+}
+
+// DeleteRecordSet deletes a record set from a DNS zone.
+func (ac *AzureClient) DeleteRecordSet(ctx context.Context, resourceGroupName string, zoneName string, name string, recordType dns.RecordType) error {
+	_, err := ac.recordsets.Delete(ctx, resourceGroupName, zoneName, name, recordType, "")
+	return err
+}
@@ -21,19 +21,24 @@ import (
 
 	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
 	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/pkg/errors"
 	azure "sigs.k8s.io/cluster-api-provider-azure/cloud"
 )
 
 // Client wraps go-sdk
 type Client interface {
 	Get(context.Context, string, string) (network.PublicIPAddress, error)
+	List(context.Context, string) ([]network.PublicIPAddress, error)
 	CreateOrUpdate(context.Context, string, string, network.PublicIPAddress) error
 	Delete(context.Context, string, string) error
+	CheckDNSNameAvailability(context.Context, string, string) (bool, error)
 }
 
 // AzureClient contains the Azure go-sdk Client
 type AzureClient struct {
 	publicips network.PublicIPAddressesClient
+	network   network.BaseClient
 }
 
 var _ Client = &AzureClient{}
@@ -41,7 +46,8 @@ var _ Client = &AzureClient{}
 // NewClient creates a new public IP client from subscription ID.
 func NewClient(auth azure.Authorizer) *AzureClient {
 	c := newPublicIPAddressesClient(auth.SubscriptionID(), auth.BaseURI(), auth.Authorizer())
-	return &AzureClient{c}
+	n := newNetworkClient(auth.SubscriptionID(), auth.BaseURI(), auth.Authorizer())
+	return &AzureClient{c, n}
 }
 
 // newPublicIPAddressesClient creates a new public IP client from subscription ID.
@@ -52,11 +58,36 @@ func newPublicIPAddressesClient(subscriptionID string, baseURI string, authorize
 	return publicIPsClient
 }
 
+// newNetworkClient creates a new network client from subscription ID.
+func newNetworkClient(subscriptionID string, baseURI string, authorizer autorest.Authorizer) network.BaseClient {
+	networkClient := network.NewWithBaseURI(baseURI, subscriptionID)
+	networkClient.Authorizer = authorizer
+	networkClient.AddToUserAgent(azure.UserAgent())
+	return networkClient
+}
+
 // Get gets the specified public IP address in a specified resource group.
 func (ac *AzureClient) Get(ctx context.Context, resourceGroupName, ipName string) (network.PublicIPAddress, error) {
 	return ac.publicips.Get(ctx, resourceGroupName, ipName, "")
 }
 
+// List lists the public IP addresses in a resource group.
+func (ac *AzureClient) List(ctx context.Context, resourceGroupName string) ([]network.PublicIPAddress, error) {
+	iter, err := ac.publicips.ListComplete(ctx, resourceGroupName)
+	if err != nil {
+		return nil, err
+	}
+
+	var ips []network.PublicIPAddress
+	for iter.NotDone() {
+		ips = append(ips, iter.Value())
+		if err := iter.NextWithContext(ctx); err != nil {
+			return ips, errors.Wrap(err, "could not iterate public IPs")
+		}
+	}
+	return ips, nil
+}
+
 // CreateOrUpdate creates or updates a static or dynamic public IP address.
 func (ac *AzureClient) CreateOrUpdate(ctx context.Context, resourceGroupName string, ipName string, ip network.PublicIPAddress) error {
 	future, err := ac.publicips.CreateOrUpdate(ctx, resourceGroupName, ipName, ip)
@@ -84,3 +115,12 @@ func (ac *AzureClient) Delete(ctx context.Context, resourceGroupName, ipName str
 	_, err = future.Result(ac.publicips)
 	return err
 }
+
+// CheckDNSNameAvailability checks whether a domain name label is available in the specified location.
+func (ac *AzureClient) CheckDNSNameAvailability(ctx context.Context, location, domainNameLabel string) (bool, error) {
+	result, err := ac.network.CheckDNSNameAvailability(ctx, location, domainNameLabel)
+	if err != nil {
+		return false, err
+	}
+	return to.Bool(result.Available), nil
+}
@@ -24,6 +24,7 @@ import (
 	"github.com/Azure/go-autorest/autorest/to"
 	"github.com/pkg/errors"
 	"k8s.io/klog"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha3"
 	azure "sigs.k8s.io/cluster-api-provider-azure/cloud"
 )
 
@@ -32,19 +33,33 @@ func (s *Service) Reconcile(ctx context.Context) error {
 	for _, ip := range s.Scope.PublicIPSpecs() {
 		klog.V(2).Infof("creating public IP %s", ip.Name)
 
+		dnsLabel := strings.ToLower(ip.Name)
+		if ip.DNSLabel != "" {
+			dnsLabel = ip.DNSLabel
+			if err := s.checkDNSLabelAvailability(ctx, ip.Name, dnsLabel); err != nil {
+				return err
+			}
+		}
+
+		// Standard load balancers require static Standard public IPs, Basic load balancers dynamic Basic ones
+		sku, allocationMethod := network.PublicIPAddressSkuNameStandard, network.Static
+		if ip.SKU == infrav1.SKUBasic {
+			sku, allocationMethod = network.PublicIPAddressSkuNameBasic, network.Dynamic
+		}
+
 		err := s.Client.CreateOrUpdate(
 			ctx,
 			s.Scope.ResourceGroup(),
 			ip.Name,
 			network.PublicIPAddress{
-				Sku:      &network.PublicIPAddressSku{Name: network.PublicIPAddressSkuNameStandard},
+				Sku:      &network.PublicIPAddressSku{Name: sku},
 				Name:     to.StringPtr(ip.Name),
 				Location: to.StringPtr(s.Scope.Location()),
 				PublicIPAddressPropertiesFormat: &network.PublicIPAddressPropertiesFormat{
 					PublicIPAddressVersion:   network.IPv4,
-					PublicIPAllocationMethod: network.Static,
+					PublicIPAllocationMethod: allocationMethod,
 					DNSSettings: &network.PublicIPAddressDNSSettings{
-						DomainNameLabel: to.StringPtr(strings.ToLower(ip.Name)),
+						DomainNameLabel: to.StringPtr(dnsLabel),
 						Fqdn:            to.StringPtr(ip.DNSName),
 					},
 				},
@@ -66,7 +81,7 @@ func (s *Service) Delete(ctx context.Context) error {
 		err := s.Client.Delete(ctx, s.Scope.ResourceGroup(), ip.Name)
 		if err != nil && azure.ResourceNotFound(err) {
 			// already deleted
-			return nil
+			continue
 		}
 		if err != nil {
 			return errors.Wrapf(err, "failed to delete public IP %s in resource group %s", ip.Name, s.Scope.ResourceGroup())
@@ -76,3 +91,24 @@ func (s *Service) Delete(ctx context.Context) error {
 	}
 	return nil
 }
+
+// checkDNSLabelAvailability returns an error if the custom DNS label of a public IP that doesn't exist yet
+// is already in use in the location of the cluster.
+func (s *Service) checkDNSLabelAvailability(ctx context.Context, name, dnsLabel string) error {
+	_, err := s.Client.Get(ctx, s.Scope.ResourceGroup(), name)
+	if err == nil {
+		// the public IP already holds the label
+		return nil
+	}
+	if !azure.ResourceNotFound(err) {
+		return errors.Wrapf(err, "failed to get public IP %s", name)
+	}
+	available, err := s.Client.CheckDNSNameAvailability(ctx, s.Scope.Location(), dnsLabel)
+	if err != nil {
+		return errors.Wrapf(err, "failed to check availability of DNS label %s", dnsLabel)
+	}
+	if !available {
+		return errors.Errorf("DNS label %s is not available in location %s", dnsLabel, s.Scope.Location())
+	}
+	return nil
+}
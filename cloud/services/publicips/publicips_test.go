@@ -21,13 +21,16 @@ import (
 	"net/http"
 	"testing"
 
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha3"
 	azure "sigs.k8s.io/cluster-api-provider-azure/cloud"
 
 	. "github.com/onsi/gomega"
 	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/publicips/mock_publicips"
 
 	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/to"
 	"github.com/golang/mock/gomock"
+	"sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers"
 
 	network "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
 	"k8s.io/client-go/kubernetes/scheme"
@@ -68,6 +71,96 @@ func TestReconcilePublicIP(t *testing.T) {
 				m.CreateOrUpdate(context.TODO(), "my-rg", "my-publicip-3", gomock.AssignableToTypeOf(network.PublicIPAddress{}))
 			},
 		},
+		{
+			name:          "can create public IP with available custom DNS label",
+			expectedError: "",
+			expect: func(s *mock_publicips.MockPublicIPScopeMockRecorder, m *mock_publicips.MockClientMockRecorder) {
+				s.PublicIPSpecs().Return([]azure.PublicIPSpec{
+					{
+						Name:     "my-publicip",
+						DNSName:  "my-api.testlocation.cloudapp.azure.com",
+						DNSLabel: "my-api",
+					},
+				})
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.Location().AnyTimes().Return("testlocation")
+				m.Get(context.TODO(), "my-rg", "my-publicip").Return(network.PublicIPAddress{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
+				m.CheckDNSNameAvailability(context.TODO(), "testlocation", "my-api").Return(true, nil)
+				m.CreateOrUpdate(context.TODO(), "my-rg", "my-publicip", matchers.DiffEq(network.PublicIPAddress{
+					Sku:      &network.PublicIPAddressSku{Name: network.PublicIPAddressSkuNameStandard},
+					Name:     to.StringPtr("my-publicip"),
+					Location: to.StringPtr("testlocation"),
+					PublicIPAddressPropertiesFormat: &network.PublicIPAddressPropertiesFormat{
+						PublicIPAddressVersion:   network.IPv4,
+						PublicIPAllocationMethod: network.Static,
+						DNSSettings: &network.PublicIPAddressDNSSettings{
+							DomainNameLabel: to.StringPtr("my-api"),
+							Fqdn:            to.StringPtr("my-api.testlocation.cloudapp.azure.com"),
+						},
+					},
+				}))
+			},
+		},
+		{
+			name:          "can create a dynamic Basic public IP",
+			expectedError: "",
+			expect: func(s *mock_publicips.MockPublicIPScopeMockRecorder, m *mock_publicips.MockClientMockRecorder) {
+				s.PublicIPSpecs().Return([]azure.PublicIPSpec{
+					{
+						Name:    "my-publicip",
+						DNSName: "my-publicip.testlocation.cloudapp.azure.com",
+						SKU:     infrav1.SKUBasic,
+					},
+				})
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.Location().AnyTimes().Return("testlocation")
+				m.CreateOrUpdate(context.TODO(), "my-rg", "my-publicip", matchers.DiffEq(network.PublicIPAddress{
+					Sku:      &network.PublicIPAddressSku{Name: network.PublicIPAddressSkuNameBasic},
+					Name:     to.StringPtr("my-publicip"),
+					Location: to.StringPtr("testlocation"),
+					PublicIPAddressPropertiesFormat: &network.PublicIPAddressPropertiesFormat{
+						PublicIPAddressVersion:   network.IPv4,
+						PublicIPAllocationMethod: network.Dynamic,
+						DNSSettings: &network.PublicIPAddressDNSSettings{
+							DomainNameLabel: to.StringPtr("my-publicip"),
+							Fqdn:            to.StringPtr("my-publicip.testlocation.cloudapp.azure.com"),
+						},
+					},
+				}))
+			},
+		},
+		{
+			name:          "existing public IP keeps its custom DNS label",
+			expectedError: "",
+			expect: func(s *mock_publicips.MockPublicIPScopeMockRecorder, m *mock_publicips.MockClientMockRecorder) {
+				s.PublicIPSpecs().Return([]azure.PublicIPSpec{
+					{
+						Name:     "my-publicip",
+						DNSLabel: "my-api",
+					},
+				})
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.Location().AnyTimes().Return("testlocation")
+				m.Get(context.TODO(), "my-rg", "my-publicip").Return(network.PublicIPAddress{}, nil)
+				m.CreateOrUpdate(context.TODO(), "my-rg", "my-publicip", gomock.AssignableToTypeOf(network.PublicIPAddress{}))
+			},
+		},
+		{
+			name:          "custom DNS label is not available",
+			expectedError: "DNS label my-api is not available in location testlocation",
+			expect: func(s *mock_publicips.MockPublicIPScopeMockRecorder, m *mock_publicips.MockClientMockRecorder) {
+				s.PublicIPSpecs().Return([]azure.PublicIPSpec{
+					{
+						Name:     "my-publicip",
+						DNSLabel: "my-api",
+					},
+				})
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.Location().AnyTimes().Return("testlocation")
+				m.Get(context.TODO(), "my-rg", "my-publicip").Return(network.PublicIPAddress{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
+				m.CheckDNSNameAvailability(context.TODO(), "testlocation", "my-api").Return(false, nil)
+			},
+		},
 		{
 			name:          "fail to create a public IP",
 			expectedError: "cannot create public IP: #: Internal Server Error: StatusCode=500",
@@ -144,10 +237,14 @@ func TestDeletePublicIP(t *testing.T) {
 					{
 						Name: "my-publicip",
 					},
+					{
+						Name: "my-publicip-2",
+					},
 				})
 				s.ResourceGroup().AnyTimes().Return("my-rg")
 				m.Delete(context.TODO(), "my-rg", "my-publicip").
 					Return(autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
+				m.Delete(context.TODO(), "my-rg", "my-publicip-2")
 			},
 		},
 		{
@@ -34,8 +34,26 @@ type Spec struct {
 	Name         string
 	PublicIPName string
 	Role         string
+	// PublicIPResourceGroup is the resource group of the public IPs. Defaults to the cluster resource group.
+	PublicIPResourceGroup string
+	// AdditionalPublicIPNames are the public IPs of the frontends added after the one of PublicIPName.
+	AdditionalPublicIPNames []string
+	// PublicIPPrefixID is the ID of a public IP prefix used as the only frontend instead of public IPs.
+	PublicIPPrefixID       string
+	AllocatedOutboundPorts *int32
+	IdleTimeoutInMinutes   *int32
+	EnableTCPReset         *bool
+	// SKU is the SKU of the load balancer. Defaults to Standard.
+	SKU infrav1.SKU
+	// HealthProbe is the probe of the API server load balancing rule. Defaults to a TCP probe of the API server port.
+	HealthProbe *infrav1.HealthProbeSpec
+	// DisableOutboundRule skips the outbound rule, e.g. when egress goes through user-defined routes instead.
+	DisableOutboundRule bool
 }
 
+// defaultOutboundIdleTimeoutInMinutes is the idle timeout of the outbound rule when none is specified.
+const defaultOutboundIdleTimeoutInMinutes = 4
+
 // Reconcile gets/creates/updates a public load balancer.
 func (s *Service) Reconcile(ctx context.Context, spec interface{}) error {
 	publicLBSpec, ok := spec.(*Spec)
@@ -52,17 +70,29 @@ func (s *Service) Reconcile(ctx context.Context, spec interface{}) error {
 
 	s.Scope.Logger.V(2).Info("creating public load balancer", "load balancer", lbName)
 
-	s.Scope.Logger.V(2).Info("getting public ip", "public ip", publicLBSpec.PublicIPName)
-	publicIP, err := s.PublicIPsClient.Get(ctx, s.Scope.ResourceGroup(), publicLBSpec.PublicIPName)
-	if err != nil && azure.ResourceNotFound(err) {
-		return errors.Wrap(err, fmt.Sprintf("public ip %s not found in RG %s", publicLBSpec.PublicIPName, s.Scope.ResourceGroup()))
-	} else if err != nil {
-		return errors.Wrap(err, "failed to look for existing public IP")
+	frontendIPConfigs, err := s.getFrontendIPConfigs(ctx, publicLBSpec, frontEndIPConfigName)
+	if err != nil {
+		return err
+	}
+	frontendIDs := make([]network.SubResource, 0, len(frontendIPConfigs))
+	for _, frontendIPConfig := range frontendIPConfigs {
+		frontendIDs = append(frontendIDs, network.SubResource{
+			ID: to.StringPtr(fmt.Sprintf("/%s/%s/frontendIPConfigurations/%s", idPrefix, lbName, to.String(frontendIPConfig.Name))),
+		})
+	}
+
+	idleTimeout := publicLBSpec.IdleTimeoutInMinutes
+	if idleTimeout == nil {
+		idleTimeout = to.Int32Ptr(defaultOutboundIdleTimeoutInMinutes)
+	}
+
+	sku := network.LoadBalancerSkuNameStandard
+	if publicLBSpec.SKU == infrav1.SKUBasic {
+		sku = network.LoadBalancerSkuNameBasic
 	}
-	s.Scope.Logger.V(2).Info("successfully got public ip", "public ip", publicLBSpec.PublicIPName)
 
 	lb := network.LoadBalancer{
-		Sku:      &network.LoadBalancerSku{Name: network.LoadBalancerSkuNameStandard},
+		Sku:      &network.LoadBalancerSku{Name: sku},
 		Location: to.StringPtr(s.Scope.Location()),
 		Tags: converters.TagsToMap(infrav1.Build(infrav1.BuildParams{
 			ClusterName: s.Scope.ClusterName(),
@@ -71,60 +101,56 @@ func (s *Service) Reconcile(ctx context.Context, spec interface{}) error {
 			Additional:  s.Scope.AdditionalTags(),
 		})),
 		LoadBalancerPropertiesFormat: &network.LoadBalancerPropertiesFormat{
-			FrontendIPConfigurations: &[]network.FrontendIPConfiguration{
-				{
-					Name: &frontEndIPConfigName,
-					FrontendIPConfigurationPropertiesFormat: &network.FrontendIPConfigurationPropertiesFormat{
-						PrivateIPAllocationMethod: network.Dynamic,
-						PublicIPAddress:           &publicIP,
-					},
-				},
-			},
+			FrontendIPConfigurations: &frontendIPConfigs,
 			BackendAddressPools: &[]network.BackendAddressPool{
 				{
 					Name: &backEndAddressPoolName,
 				},
 			},
-			OutboundRules: &[]network.OutboundRule{
-				{
-					Name: to.StringPtr("OutboundNATAllProtocols"),
-					OutboundRulePropertiesFormat: &network.OutboundRulePropertiesFormat{
-						Protocol:             network.LoadBalancerOutboundRuleProtocolAll,
-						IdleTimeoutInMinutes: to.Int32Ptr(4),
-						FrontendIPConfigurations: &[]network.SubResource{
-							{
-								ID: to.StringPtr(fmt.Sprintf("/%s/%s/frontendIPConfigurations/%s", idPrefix, lbName, frontEndIPConfigName)),
-							},
-						},
-						BackendAddressPool: &network.SubResource{
-							ID: to.StringPtr(fmt.Sprintf("/%s/%s/backendAddressPools/%s", idPrefix, lbName, backEndAddressPoolName)),
-						},
+		},
+	}
+
+	// Basic load balancers don't support outbound rules, machines in their backend pool get outbound
+	// connectivity from the load balancing rules or from the default outbound access of Azure.
+	if sku == network.LoadBalancerSkuNameStandard && !publicLBSpec.DisableOutboundRule {
+		lb.LoadBalancerPropertiesFormat.OutboundRules = &[]network.OutboundRule{
+			{
+				Name: to.StringPtr("OutboundNATAllProtocols"),
+				OutboundRulePropertiesFormat: &network.OutboundRulePropertiesFormat{
+					Protocol:                 network.LoadBalancerOutboundRuleProtocolAll,
+					IdleTimeoutInMinutes:     idleTimeout,
+					AllocatedOutboundPorts:   publicLBSpec.AllocatedOutboundPorts,
+					EnableTCPReset:           publicLBSpec.EnableTCPReset,
+					FrontendIPConfigurations: &frontendIDs,
+					BackendAddressPool: &network.SubResource{
+						ID: to.StringPtr(fmt.Sprintf("/%s/%s/backendAddressPools/%s", idPrefix, lbName, backEndAddressPoolName)),
 					},
 				},
 			},
-		},
+		}
 	}
 
 	if publicLBSpec.Role == infrav1.APIServerRole {
+		// the probe keeps its name when its protocol changes so that existing load balancers are updated in place
 		probeName := "tcpHTTPSProbe"
+		probe := infrav1.HealthProbeSpec{Protocol: infrav1.HealthProbeProtocolTCP}
+		if publicLBSpec.HealthProbe != nil {
+			probe = *publicLBSpec.HealthProbe
+		}
 		lb.LoadBalancerPropertiesFormat.Probes = &[]network.Probe{
-			{
-				Name: to.StringPtr(probeName),
-				ProbePropertiesFormat: &network.ProbePropertiesFormat{
-					Protocol:          network.ProbeProtocolTCP,
-					Port:              to.Int32Ptr(s.Scope.APIServerPort()),
-					IntervalInSeconds: to.Int32Ptr(15),
-					NumberOfProbes:    to.Int32Ptr(4),
-				},
-			},
+			converters.HealthProbeToSDK(probeName, s.Scope.APIServerPort(), publicLBSpec.SKU, probe),
 		}
 		// We disable outbound SNAT explicitly in the HTTPS LB rule and enable TCP and UDP outbound NAT with an outbound rule.
 		// For more information on Standard LB outbound connections see https://docs.microsoft.com/en-us/azure/load-balancer/load-balancer-outbound-connections.
+		var disableOutboundSnat *bool
+		if sku == network.LoadBalancerSkuNameStandard {
+			disableOutboundSnat = to.BoolPtr(true)
+		}
 		lb.LoadBalancerPropertiesFormat.LoadBalancingRules = &[]network.LoadBalancingRule{
 			{
 				Name: to.StringPtr("LBRuleHTTPS"),
 				LoadBalancingRulePropertiesFormat: &network.LoadBalancingRulePropertiesFormat{
-					DisableOutboundSnat:  to.BoolPtr(true),
+					DisableOutboundSnat:  disableOutboundSnat,
 					Protocol:             network.TransportProtocolTCP,
 					FrontendPort:         to.Int32Ptr(s.Scope.APIServerPort()),
 					BackendPort:          to.Int32Ptr(s.Scope.APIServerPort()),
@@ -155,6 +181,52 @@ func (s *Service) Reconcile(ctx context.Context, spec interface{}) error {
 	return nil
 }
 
+// getFrontendIPConfigs returns the frontend IP configurations of the load balancer, either a single one
+// using the public IP prefix or one per public IP.
+func (s *Service) getFrontendIPConfigs(ctx context.Context, publicLBSpec *Spec, frontEndIPConfigName string) ([]network.FrontendIPConfiguration, error) {
+	if publicLBSpec.PublicIPPrefixID != "" {
+		return []network.FrontendIPConfiguration{
+			{
+				Name: to.StringPtr(frontEndIPConfigName),
+				FrontendIPConfigurationPropertiesFormat: &network.FrontendIPConfigurationPropertiesFormat{
+					PrivateIPAllocationMethod: network.Dynamic,
+					PublicIPPrefix:            &network.SubResource{ID: to.StringPtr(publicLBSpec.PublicIPPrefixID)},
+				},
+			},
+		}, nil
+	}
+
+	publicIPResourceGroup := s.Scope.ResourceGroup()
+	if publicLBSpec.PublicIPResourceGroup != "" {
+		publicIPResourceGroup = publicLBSpec.PublicIPResourceGroup
+	}
+	publicIPNames := append([]string{publicLBSpec.PublicIPName}, publicLBSpec.AdditionalPublicIPNames...)
+	frontendIPConfigs := make([]network.FrontendIPConfiguration, 0, len(publicIPNames))
+	for i, publicIPName := range publicIPNames {
+		s.Scope.Logger.V(2).Info("getting public ip", "public ip", publicIPName)
+		publicIP, err := s.PublicIPsClient.Get(ctx, publicIPResourceGroup, publicIPName)
+		if err != nil && azure.ResourceNotFound(err) {
+			return nil, errors.Wrap(err, fmt.Sprintf("public ip %s not found in RG %s", publicIPName, publicIPResourceGroup))
+		} else if err != nil {
+			return nil, errors.Wrap(err, "failed to look for existing public IP")
+		}
+		s.Scope.Logger.V(2).Info("successfully got public ip", "public ip", publicIPName)
+
+		name := frontEndIPConfigName
+		if i > 0 {
+			name = fmt.Sprintf("%s-%d", frontEndIPConfigName, i)
+		}
+		frontendIPConfigs = append(frontendIPConfigs, network.FrontendIPConfiguration{
+			Name: to.StringPtr(name),
+			FrontendIPConfigurationPropertiesFormat: &network.FrontendIPConfigurationPropertiesFormat{
+				PrivateIPAllocationMethod: network.Dynamic,
+				PublicIPAddress:           &publicIP,
+			},
+		})
+	}
+	return frontendIPConfigs, nil
+}
+
 // Delete deletes the public load balancer with the provided name.
 func (s *Service) Delete(ctx context.Context, spec interface{}) error {
 	publicLBSpec, ok := spec.(*Spec)
@@ -143,6 +143,20 @@ func TestReconcilePublicLoadBalancer(t *testing.T) {
 				publicIP.Get(context.TODO(), "my-rg", "my-publicip").Return(network.PublicIPAddress{}, nil)
 			},
 		},
+		{
+			name: "successfully create a public LB with a public IP in another resource group",
+			publicLBSpec: Spec{
+				Name:                  "my-publiclb",
+				PublicIPName:          "my-publicip",
+				PublicIPResourceGroup: "my-ip-rg",
+			},
+			expectedError: "",
+			expect: func(m *mock_publicloadbalancers.MockClientMockRecorder,
+				publicIP *mock_publicips.MockClientMockRecorder) {
+				m.CreateOrUpdate(context.TODO(), "my-rg", "my-publiclb", gomock.AssignableToTypeOf(network.LoadBalancer{})).Return(nil)
+				publicIP.Get(context.TODO(), "my-ip-rg", "my-publicip").Return(network.PublicIPAddress{}, nil)
+			},
+		},
 		{
 			name: "fail to create a public LB",
 			publicLBSpec: Spec{
@@ -243,6 +257,172 @@ func TestReconcilePublicLoadBalancer(t *testing.T) {
 					})).Return(nil))
 			},
 		},
+		{
+			name: "create Basic apiserver LB",
+			publicLBSpec: Spec{
+				Name:         "my-publiclb",
+				PublicIPName: "my-publicip",
+				Role:         infrav1.APIServerRole,
+				SKU:          infrav1.SKUBasic,
+			},
+			expectedError: "",
+			expect: func(m *mock_publicloadbalancers.MockClientMockRecorder,
+				publicIP *mock_publicips.MockClientMockRecorder) {
+				gomock.InOrder(
+					publicIP.Get(context.TODO(), "my-rg", "my-publicip").Return(network.PublicIPAddress{Name: to.StringPtr("my-publicip")}, nil),
+					m.CreateOrUpdate(context.TODO(), "my-rg", "my-publiclb", matchers.DiffEq(network.LoadBalancer{
+						Tags: map[string]*string{
+							"sigs.k8s.io_cluster-api-provider-azure_cluster_test-cluster": to.StringPtr("owned"),
+							"sigs.k8s.io_cluster-api-provider-azure_role":                 to.StringPtr(infrav1.APIServerRole),
+						},
+						Sku: &network.LoadBalancerSku{Name: network.LoadBalancerSkuNameBasic},
+						Location: to.StringPtr("test-location"),
+						LoadBalancerPropertiesFormat: &network.LoadBalancerPropertiesFormat{
+							FrontendIPConfigurations: &[]network.FrontendIPConfiguration{
+								{
+									Name: to.StringPtr("my-publiclb-frontEnd"),
+									FrontendIPConfigurationPropertiesFormat: &network.FrontendIPConfigurationPropertiesFormat{
+										PrivateIPAllocationMethod: network.Dynamic,
+										PublicIPAddress:           &network.PublicIPAddress{Name: to.StringPtr("my-publicip")},
+									},
+								},
+							},
+							BackendAddressPools: &[]network.BackendAddressPool{
+								{
+									Name: to.StringPtr("my-publiclb-backendPool"),
+								},
+							},
+							LoadBalancingRules: &[]network.LoadBalancingRule{
+								{
+									Name: to.StringPtr("LBRuleHTTPS"),
+									LoadBalancingRulePropertiesFormat: &network.LoadBalancingRulePropertiesFormat{
+										Protocol:             network.TransportProtocolTCP,
+										FrontendPort:         to.Int32Ptr(6443),
+										BackendPort:          to.Int32Ptr(6443),
+										IdleTimeoutInMinutes: to.Int32Ptr(4),
+										EnableFloatingIP:     to.BoolPtr(false),
+										LoadDistribution:     network.LoadDistributionDefault,
+										FrontendIPConfiguration: &network.SubResource{
+											ID: to.StringPtr("//subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/loadBalancers/my-publiclb/frontendIPConfigurations/my-publiclb-frontEnd"),
+										},
+										BackendAddressPool: &network.SubResource{
+											ID: to.StringPtr("//subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/loadBalancers/my-publiclb/backendAddressPools/my-publiclb-backendPool"),
+										},
+										Probe: &network.SubResource{
+											ID: to.StringPtr("//subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/loadBalancers/my-publiclb/probes/tcpHTTPSProbe"),
+										},
+									},
+								},
+							},
+							Probes: &[]network.Probe{
+								{
+									Name: to.StringPtr("tcpHTTPSProbe"),
+									ProbePropertiesFormat: &network.ProbePropertiesFormat{
+										Protocol:          network.ProbeProtocolTCP,
+										Port:              to.Int32Ptr(6443),
+										IntervalInSeconds: to.Int32Ptr(15),
+										NumberOfProbes:    to.Int32Ptr(4),
+									},
+								},
+							},
+						},
+					})).Return(nil))
+			},
+		},
+		{
+			name: "create apiserver LB with an HTTPS health probe",
+			publicLBSpec: Spec{
+				Name:         "my-publiclb",
+				PublicIPName: "my-publicip",
+				Role:         infrav1.APIServerRole,
+				HealthProbe: &infrav1.HealthProbeSpec{
+					Protocol:          infrav1.HealthProbeProtocolHTTPS,
+					RequestPath:       "/readyz",
+					IntervalInSeconds: to.Int32Ptr(5),
+					NumberOfProbes:    to.Int32Ptr(2),
+				},
+			},
+			expectedError: "",
+			expect: func(m *mock_publicloadbalancers.MockClientMockRecorder,
+				publicIP *mock_publicips.MockClientMockRecorder) {
+				gomock.InOrder(
+					publicIP.Get(context.TODO(), "my-rg", "my-publicip").Return(network.PublicIPAddress{Name: to.StringPtr("my-publicip")}, nil),
+					m.CreateOrUpdate(context.TODO(), "my-rg", "my-publiclb", matchers.DiffEq(network.LoadBalancer{
+						Tags: map[string]*string{
+							"sigs.k8s.io_cluster-api-provider-azure_cluster_test-cluster": to.StringPtr("owned"),
+							"sigs.k8s.io_cluster-api-provider-azure_role":                 to.StringPtr(infrav1.APIServerRole),
+						},
+						Sku: &network.LoadBalancerSku{Name: network.LoadBalancerSkuNameStandard},
+						Location: to.StringPtr("test-location"),
+						LoadBalancerPropertiesFormat: &network.LoadBalancerPropertiesFormat{
+							FrontendIPConfigurations: &[]network.FrontendIPConfiguration{
+								{
+									Name: to.StringPtr("my-publiclb-frontEnd"),
+									FrontendIPConfigurationPropertiesFormat: &network.FrontendIPConfigurationPropertiesFormat{
+										PrivateIPAllocationMethod: network.Dynamic,
+										PublicIPAddress:           &network.PublicIPAddress{Name: to.StringPtr("my-publicip")},
+									},
+								},
+							},
+							BackendAddressPools: &[]network.BackendAddressPool{
+								{
+									Name: to.StringPtr("my-publiclb-backendPool"),
+								},
+							},
+							LoadBalancingRules: &[]network.LoadBalancingRule{
+								{
+									Name: to.StringPtr("LBRuleHTTPS"),
+									LoadBalancingRulePropertiesFormat: &network.LoadBalancingRulePropertiesFormat{
+										DisableOutboundSnat:  to.BoolPtr(true),
+										Protocol:             network.TransportProtocolTCP,
+										FrontendPort:         to.Int32Ptr(6443),
+										BackendPort:          to.Int32Ptr(6443),
+										IdleTimeoutInMinutes: to.Int32Ptr(4),
+										EnableFloatingIP:     to.BoolPtr(false),
+										LoadDistribution:     network.LoadDistributionDefault,
+										FrontendIPConfiguration: &network.SubResource{
+											ID: to.StringPtr("//subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/loadBalancers/my-publiclb/frontendIPConfigurations/my-publiclb-frontEnd"),
+										},
+										BackendAddressPool: &network.SubResource{
+											ID: to.StringPtr("//subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/loadBalancers/my-publiclb/backendAddressPools/my-publiclb-backendPool"),
+										},
+										Probe: &network.SubResource{
+											ID: to.StringPtr("//subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/loadBalancers/my-publiclb/probes/tcpHTTPSProbe"),
+										},
+									},
+								},
+							},
+							Probes: &[]network.Probe{
+								{
+									Name: to.StringPtr("tcpHTTPSProbe"),
+									ProbePropertiesFormat: &network.ProbePropertiesFormat{
+										Protocol:          network.ProbeProtocolHTTPS,
+										RequestPath:       to.StringPtr("/readyz"),
+										Port:              to.Int32Ptr(6443),
+										IntervalInSeconds: to.Int32Ptr(5),
+										NumberOfProbes:    to.Int32Ptr(2),
+									},
+								},
+							},
+							OutboundRules: &[]network.OutboundRule{
+								{
+									Name: to.StringPtr("OutboundNATAllProtocols"),
+									OutboundRulePropertiesFormat: &network.OutboundRulePropertiesFormat{
+										FrontendIPConfigurations: &[]network.SubResource{
+											{ID: to.StringPtr("//subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/loadBalancers/my-publiclb/frontendIPConfigurations/my-publiclb-frontEnd")},
+										},
+										BackendAddressPool: &network.SubResource{
+											ID: to.StringPtr("//subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/loadBalancers/my-publiclb/backendAddressPools/my-publiclb-backendPool"),
+										},
+										Protocol:             network.LoadBalancerOutboundRuleProtocolAll,
+										IdleTimeoutInMinutes: to.Int32Ptr(4),
+									},
+								},
+							},
+						},
+					})).Return(nil))
+			},
+		},
 		{
 			name: "create node outbound LB",
 			publicLBSpec: Spec{
@@ -296,6 +476,125 @@ func TestReconcilePublicLoadBalancer(t *testing.T) {
 					})).Return(nil))
 			},
 		},
+		{
+			name: "create node outbound LB with several public IPs and outbound settings",
+			publicLBSpec: Spec{
+				Name:                    "cluster-name",
+				PublicIPName:            "outbound-publicip",
+				AdditionalPublicIPNames: []string{"outbound-publicip-1"},
+				Role:                    infrav1.NodeOutboundRole,
+				AllocatedOutboundPorts:  to.Int32Ptr(1024),
+				IdleTimeoutInMinutes:    to.Int32Ptr(30),
+				EnableTCPReset:          to.BoolPtr(true),
+			},
+			expectedError: "",
+			expect: func(m *mock_publicloadbalancers.MockClientMockRecorder,
+				publicIP *mock_publicips.MockClientMockRecorder) {
+				gomock.InOrder(
+					publicIP.Get(context.TODO(), "my-rg", "outbound-publicip").Return(network.PublicIPAddress{Name: to.StringPtr("outbound-publicip")}, nil),
+					publicIP.Get(context.TODO(), "my-rg", "outbound-publicip-1").Return(network.PublicIPAddress{Name: to.StringPtr("outbound-publicip-1")}, nil),
+					m.CreateOrUpdate(context.TODO(), "my-rg", "cluster-name", matchers.DiffEq(network.LoadBalancer{
+						Tags: map[string]*string{
+							"sigs.k8s.io_cluster-api-provider-azure_cluster_test-cluster": to.StringPtr("owned"),
+							"sigs.k8s.io_cluster-api-provider-azure_role":                 to.StringPtr(infrav1.NodeOutboundRole),
+						},
+						Sku:      &network.LoadBalancerSku{Name: network.LoadBalancerSkuNameStandard},
+						Location: to.StringPtr("test-location"),
+						LoadBalancerPropertiesFormat: &network.LoadBalancerPropertiesFormat{
+							FrontendIPConfigurations: &[]network.FrontendIPConfiguration{
+								{
+									Name: to.StringPtr("cluster-name-frontEnd"),
+									FrontendIPConfigurationPropertiesFormat: &network.FrontendIPConfigurationPropertiesFormat{
+										PrivateIPAllocationMethod: network.Dynamic,
+										PublicIPAddress:           &network.PublicIPAddress{Name: to.StringPtr("outbound-publicip")},
+									},
+								},
+								{
+									Name: to.StringPtr("cluster-name-frontEnd-1"),
+									FrontendIPConfigurationPropertiesFormat: &network.FrontendIPConfigurationPropertiesFormat{
+										PrivateIPAllocationMethod: network.Dynamic,
+										PublicIPAddress:           &network.PublicIPAddress{Name: to.StringPtr("outbound-publicip-1")},
+									},
+								},
+							},
+							BackendAddressPools: &[]network.BackendAddressPool{
+								{
+									Name: to.StringPtr("cluster-name-outboundBackendPool"),
+								},
+							},
+							OutboundRules: &[]network.OutboundRule{
+								{
+									Name: to.StringPtr("OutboundNATAllProtocols"),
+									OutboundRulePropertiesFormat: &network.OutboundRulePropertiesFormat{
+										FrontendIPConfigurations: &[]network.SubResource{
+											{ID: to.StringPtr("//subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/loadBalancers/cluster-name/frontendIPConfigurations/cluster-name-frontEnd")},
+											{ID: to.StringPtr("//subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/loadBalancers/cluster-name/frontendIPConfigurations/cluster-name-frontEnd-1")},
+										},
+										BackendAddressPool: &network.SubResource{
+											ID: to.StringPtr("//subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/loadBalancers/cluster-name/backendAddressPools/cluster-name-outboundBackendPool"),
+										},
+										Protocol:               network.LoadBalancerOutboundRuleProtocolAll,
+										IdleTimeoutInMinutes:   to.Int32Ptr(30),
+										AllocatedOutboundPorts: to.Int32Ptr(1024),
+										EnableTCPReset:         to.BoolPtr(true),
+									},
+								},
+							},
+						},
+					})).Return(nil))
+			},
+		},
+		{
+			name: "create node outbound LB with a public IP prefix",
+			publicLBSpec: Spec{
+				Name:             "cluster-name",
+				PublicIPPrefixID: "my-prefix-id",
+				Role:             infrav1.NodeOutboundRole,
+			},
+			expectedError: "",
+			expect: func(m *mock_publicloadbalancers.MockClientMockRecorder,
+				publicIP *mock_publicips.MockClientMockRecorder) {
+				m.CreateOrUpdate(context.TODO(), "my-rg", "cluster-name", matchers.DiffEq(network.LoadBalancer{
+					Tags: map[string]*string{
+						"sigs.k8s.io_cluster-api-provider-azure_cluster_test-cluster": to.StringPtr("owned"),
+						"sigs.k8s.io_cluster-api-provider-azure_role":                 to.StringPtr(infrav1.NodeOutboundRole),
+					},
+					Sku:      &network.LoadBalancerSku{Name: network.LoadBalancerSkuNameStandard},
+					Location: to.StringPtr("test-location"),
+					LoadBalancerPropertiesFormat: &network.LoadBalancerPropertiesFormat{
+						FrontendIPConfigurations: &[]network.FrontendIPConfiguration{
+							{
+								Name: to.StringPtr("cluster-name-frontEnd"),
+								FrontendIPConfigurationPropertiesFormat: &network.FrontendIPConfigurationPropertiesFormat{
+									PrivateIPAllocationMethod: network.Dynamic,
+									PublicIPPrefix:            &network.SubResource{ID: to.StringPtr("my-prefix-id")},
+								},
+							},
+						},
+						BackendAddressPools: &[]network.BackendAddressPool{
+							{
+								Name: to.StringPtr("cluster-name-outboundBackendPool"),
+							},
+						},
+						OutboundRules: &[]network.OutboundRule{
+							{
+								Name: to.StringPtr("OutboundNATAllProtocols"),
+								OutboundRulePropertiesFormat: &network.OutboundRulePropertiesFormat{
+									FrontendIPConfigurations: &[]network.SubResource{
+										{ID: to.StringPtr("//subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/loadBalancers/cluster-name/frontendIPConfigurations/cluster-name-frontEnd")},
+									},
+									BackendAddressPool: &network.SubResource{
+										ID: to.StringPtr("//subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/loadBalancers/cluster-name/backendAddressPools/cluster-name-outboundBackendPool"),
+									},
+									Protocol:             network.LoadBalancerOutboundRuleProtocolAll,
+									IdleTimeoutInMinutes: to.Int32Ptr(4),
+								},
+							},
+						},
+					},
+				})).Return(nil)
+			},
+		},
 	}
 
 	for _, tc := range testcases {
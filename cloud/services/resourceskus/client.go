@@ -18,10 +18,12 @@ package resourceskus
 
 import (
 	"context"
+	"fmt"
 	"strings"
 
 	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2019-12-01/compute"
 	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/to"
 	"github.com/pkg/errors"
 
 	azure "sigs.k8s.io/cluster-api-provider-azure/cloud"
@@ -31,6 +33,7 @@ import (
 type Client interface {
 	List(context.Context, string) ([]compute.ResourceSku, error)
 	HasAcceleratedNetworking(context.Context, string) (bool, error)
+	IsVMSizeAvailable(context.Context, string, string, string) (bool, error)
 }
 
 // AzureClient contains the Azure go-sdk Client
@@ -98,3 +101,49 @@ func (ac *AzureClient) HasAcceleratedNetworking(ctx context.Context, name string
 	}
 	return false, nil
 }
+
+// IsVMSizeAvailable returns whether the given VM size is offered to the subscription in the given location and, when
+// set, in the given availability zone.
+func (ac *AzureClient) IsVMSizeAvailable(ctx context.Context, location, zone, name string) (bool, error) {
+	skus, err := ac.List(ctx, fmt.Sprintf("location eq '%s'", location))
+	if err != nil {
+		return false, err
+	}
+	for _, sku := range skus {
+		if !strings.EqualFold(to.String(sku.ResourceType), "virtualMachines") || !strings.EqualFold(to.String(sku.Name), name) {
+			continue
+		}
+		if sku.Restrictions != nil {
+			for _, restriction := range *sku.Restrictions {
+				switch restriction.Type {
+				case compute.Location:
+					return false, nil
+				case compute.Zone:
+					if zone != "" && restriction.RestrictionInfo != nil && containsZone(restriction.RestrictionInfo.Zones, zone) {
+						return false, nil
+					}
+				}
+			}
+		}
+		if zone == "" || sku.LocationInfo == nil {
+			return true, nil
+		}
+		for _, info := range *sku.LocationInfo {
+			if strings.EqualFold(to.String(info.Location), location) && containsZone(info.Zones, zone) {
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+	return false, nil
+}
+
+// containsZone returns whether the given availability zone is in the list.
+func containsZone(zones *[]string, zone string) bool {
+	for _, z := range to.StringSlice(zones) {
+		if z == zone {
+			return true
+		}
+	}
+	return false
+}
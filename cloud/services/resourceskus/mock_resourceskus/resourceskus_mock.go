@@ -79,3 +79,18 @@ func (mr *MockClientMockRecorder) HasAcceleratedNetworking(arg0, arg1 interface{
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HasAcceleratedNetworking", reflect.TypeOf((*MockClient)(nil).HasAcceleratedNetworking), arg0, arg1)
 }
+
+// IsVMSizeAvailable mocks base method.
+func (m *MockClient) IsVMSizeAvailable(arg0 context.Context, arg1, arg2, arg3 string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsVMSizeAvailable", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsVMSizeAvailable indicates an expected call of IsVMSizeAvailable.
+func (mr *MockClientMockRecorder) IsVMSizeAvailable(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsVMSizeAvailable", reflect.TypeOf((*MockClient)(nil).IsVMSizeAvailable), arg0, arg1, arg2, arg3)
+}
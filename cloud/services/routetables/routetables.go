@@ -29,8 +29,14 @@ import (
 // Spec specification for route table.
 type Spec struct {
 	Name string
+	// DefaultRouteNextHopIP is the IP of the virtual appliance that the default route of the table sends traffic to.
+	// No default route is managed when empty.
+	DefaultRouteNextHopIP string
 }
 
+// defaultRouteName is the name of the route sending all egress traffic to DefaultRouteNextHopIP.
+const defaultRouteName = "default"
+
 // Reconcile gets/creates/updates a route table.
 func (s *Service) Reconcile(ctx context.Context, spec interface{}) error {
 	if !s.Scope.Vnet().IsManaged(s.Scope.ClusterName()) {
@@ -57,9 +63,36 @@ func (s *Service) Reconcile(ctx context.Context, spec interface{}) error {
 		s.Scope.ControlPlaneSubnet().RouteTable.Name = to.String(existingRouteTable.Name)
 		s.Scope.ControlPlaneSubnet().RouteTable.ID = to.String(existingRouteTable.ID)
 
+		if routeTableSpec.DefaultRouteNextHopIP == "" || DefaultRouteNextHopIP(existingRouteTable) == routeTableSpec.DefaultRouteNextHopIP {
+			return nil
+		}
+
+		s.Scope.Logger.V(2).Info("updating default route of route table", "route table", routeTableSpec.Name, "next hop", routeTableSpec.DefaultRouteNextHopIP)
+		if existingRouteTable.RouteTablePropertiesFormat == nil {
+			existingRouteTable.RouteTablePropertiesFormat = &network.RouteTablePropertiesFormat{}
+		}
+		routes := []network.Route{defaultRoute(routeTableSpec.DefaultRouteNextHopIP)}
+		if existingRouteTable.Routes != nil {
+			for _, route := range *existingRouteTable.Routes {
+				// a route table can't have two routes with the same address prefix
+				if to.String(route.Name) == defaultRouteName || (route.RoutePropertiesFormat != nil && to.String(route.AddressPrefix) == "0.0.0.0/0") {
+					continue
+				}
+				routes = append(routes, route)
+			}
+		}
+		existingRouteTable.Routes = &routes
+		if err := s.Client.CreateOrUpdate(ctx, s.Scope.ResourceGroup(), routeTableSpec.Name, existingRouteTable); err != nil {
+			return errors.Wrapf(err, "failed to update default route of route table %s in resource group %s", routeTableSpec.Name, s.Scope.ResourceGroup())
+		}
 		return nil
 	}
 
+	properties := &network.RouteTablePropertiesFormat{}
+	if routeTableSpec.DefaultRouteNextHopIP != "" {
+		properties.Routes = &[]network.Route{defaultRoute(routeTableSpec.DefaultRouteNextHopIP)}
+	}
+
 	s.Scope.Logger.V(2).Info("creating route table", "route table", routeTableSpec.Name)
 	err = s.Client.CreateOrUpdate(
 		ctx,
@@ -67,7 +100,7 @@ func (s *Service) Reconcile(ctx context.Context, spec interface{}) error {
 		routeTableSpec.Name,
 		network.RouteTable{
 			Location:                   to.StringPtr(s.Scope.Location()),
-			RouteTablePropertiesFormat: &network.RouteTablePropertiesFormat{},
+			RouteTablePropertiesFormat: properties,
 		},
 	)
 	if err != nil {
@@ -101,3 +134,32 @@ func (s *Service) Delete(ctx context.Context, spec interface{}) error {
 	klog.V(2).Infof("successfully deleted route table %s", routeTableSpec.Name)
 	return nil
 }
+
+// defaultRoute returns the route sending all egress traffic to the virtual appliance with the given IP.
+func defaultRoute(nextHopIP string) network.Route {
+	return network.Route{
+		Name: to.StringPtr(defaultRouteName),
+		RoutePropertiesFormat: &network.RoutePropertiesFormat{
+			AddressPrefix:    to.StringPtr("0.0.0.0/0"),
+			NextHopType:      network.RouteNextHopTypeVirtualAppliance,
+			NextHopIPAddress: to.StringPtr(nextHopIP),
+		},
+	}
+}
+
+// DefaultRouteNextHopIP returns the IP of the virtual appliance that the route table sends all egress traffic to,
+// or an empty string if the route table has no such default route.
+func DefaultRouteNextHopIP(routeTable network.RouteTable) string {
+	if routeTable.RouteTablePropertiesFormat == nil || routeTable.Routes == nil {
+		return ""
+	}
+	for _, route := range *routeTable.Routes {
+		if route.RoutePropertiesFormat == nil || to.String(route.AddressPrefix) != "0.0.0.0/0" {
+			continue
+		}
+		if route.NextHopType == network.RouteNextHopTypeVirtualAppliance {
+			return to.String(route.NextHopIPAddress)
+		}
+	}
+	return ""
+}
@@ -69,7 +69,7 @@ func TestInvalidRouteTableSpec(t *testing.T) {
 		Cluster: cluster,
 		AzureCluster: &infrav1.AzureCluster{
 			Spec: infrav1.AzureClusterSpec{
-				Location: "test-location",
+				Location:       "test-location",
 				ResourceGroup:  "my-rg",
 				SubscriptionID: subscriptionID,
 				NetworkSpec: infrav1.NetworkSpec{
@@ -156,6 +156,87 @@ func TestReconcileRouteTables(t *testing.T) {
 				m.CreateOrUpdate(context.TODO(), gomock.Any(), gomock.Any(), gomock.AssignableToTypeOf(network.RouteTable{})).Times(0)
 			},
 		},
+		{
+			name: "route table with a default route create successfully",
+			routetableSpec: Spec{
+				Name:                  "my-routetable",
+				DefaultRouteNextHopIP: "10.0.0.4",
+			},
+			tags: infrav1.Tags{
+				"Name": "my-vnet",
+				"sigs.k8s.io_cluster-api-provider-azure_cluster_test-cluster": "owned",
+				"sigs.k8s.io_cluster-api-provider-azure_role":                 "common",
+			},
+			expectedError: "",
+			expect: func(m *mock_routetables.MockClientMockRecorder) {
+				m.Get(context.TODO(), "my-rg", "my-routetable").Return(network.RouteTable{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
+				m.CreateOrUpdate(context.TODO(), "my-rg", "my-routetable", network.RouteTable{
+					Location: to.StringPtr("test-location"),
+					RouteTablePropertiesFormat: &network.RouteTablePropertiesFormat{
+						Routes: &[]network.Route{defaultRoute("10.0.0.4")},
+					},
+				})
+			},
+		},
+		{
+			name: "do not update route table if the default route already exists",
+			routetableSpec: Spec{
+				Name:                  "my-routetable",
+				DefaultRouteNextHopIP: "10.0.0.4",
+			},
+			tags: infrav1.Tags{
+				"Name": "my-vnet",
+				"sigs.k8s.io_cluster-api-provider-azure_cluster_test-cluster": "owned",
+				"sigs.k8s.io_cluster-api-provider-azure_role":                 "common",
+			},
+			expectedError: "",
+			expect: func(m *mock_routetables.MockClientMockRecorder) {
+				m.Get(context.TODO(), "my-rg", "my-routetable").Return(network.RouteTable{
+					Name: to.StringPtr("my-routetable"),
+					ID:   to.StringPtr("1"),
+					RouteTablePropertiesFormat: &network.RouteTablePropertiesFormat{
+						Routes: &[]network.Route{defaultRoute("10.0.0.4")},
+					},
+				}, nil)
+				m.CreateOrUpdate(context.TODO(), gomock.Any(), gomock.Any(), gomock.AssignableToTypeOf(network.RouteTable{})).Times(0)
+			},
+		},
+		{
+			name: "replace the default route of an existing route table",
+			routetableSpec: Spec{
+				Name:                  "my-routetable",
+				DefaultRouteNextHopIP: "10.0.0.4",
+			},
+			tags: infrav1.Tags{
+				"Name": "my-vnet",
+				"sigs.k8s.io_cluster-api-provider-azure_cluster_test-cluster": "owned",
+				"sigs.k8s.io_cluster-api-provider-azure_role":                 "common",
+			},
+			expectedError: "",
+			expect: func(m *mock_routetables.MockClientMockRecorder) {
+				otherRoute := network.Route{
+					Name: to.StringPtr("on-prem"),
+					RoutePropertiesFormat: &network.RoutePropertiesFormat{
+						AddressPrefix: to.StringPtr("192.168.0.0/16"),
+						NextHopType:   network.RouteNextHopTypeVirtualNetworkGateway,
+					},
+				}
+				m.Get(context.TODO(), "my-rg", "my-routetable").Return(network.RouteTable{
+					Name: to.StringPtr("my-routetable"),
+					ID:   to.StringPtr("1"),
+					RouteTablePropertiesFormat: &network.RouteTablePropertiesFormat{
+						Routes: &[]network.Route{defaultRoute("10.0.0.5"), otherRoute},
+					},
+				}, nil)
+				m.CreateOrUpdate(context.TODO(), "my-rg", "my-routetable", network.RouteTable{
+					Name: to.StringPtr("my-routetable"),
+					ID:   to.StringPtr("1"),
+					RouteTablePropertiesFormat: &network.RouteTablePropertiesFormat{
+						Routes: &[]network.Route{defaultRoute("10.0.0.4"), otherRoute},
+					},
+				})
+			},
+		},
 		{
 			name: "fail when getting existing route table",
 			routetableSpec: Spec{
@@ -215,7 +296,7 @@ func TestReconcileRouteTables(t *testing.T) {
 				Cluster: cluster,
 				AzureCluster: &infrav1.AzureCluster{
 					Spec: infrav1.AzureClusterSpec{
-						Location: "test-location",
+						Location:       "test-location",
 						ResourceGroup:  "my-rg",
 						SubscriptionID: subscriptionID,
 						NetworkSpec: infrav1.NetworkSpec{
@@ -350,7 +431,7 @@ func TestDeleteRouteTable(t *testing.T) {
 				Cluster: cluster,
 				AzureCluster: &infrav1.AzureCluster{
 					Spec: infrav1.AzureClusterSpec{
-						Location: "test-location",
+						Location:       "test-location",
 						ResourceGroup:  "my-rg",
 						SubscriptionID: subscriptionID,
 						NetworkSpec: infrav1.NetworkSpec{
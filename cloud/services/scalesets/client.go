@@ -82,7 +82,7 @@ func newPublicIPsClient(subscriptionID string, baseURI string, authorizer autore
 
 // Get retrieves information about the model view of a virtual machine scale set.
 func (ac *AzureClient) ListInstances(ctx context.Context, resourceGroupName, vmssName string) ([]compute.VirtualMachineScaleSetVM, error) {
-	itr, err := ac.scalesetvms.ListComplete(ctx, resourceGroupName, vmssName, "", "", "")
+	itr, err := ac.scalesetvms.ListComplete(ctx, resourceGroupName, vmssName, "", "", string(compute.InstanceView))
 	if err != nil {
 		return nil, err
 	}
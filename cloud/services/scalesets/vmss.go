@@ -19,6 +19,7 @@ package scalesets
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	infrav1exp "sigs.k8s.io/cluster-api-provider-azure/exp/api/v1alpha3"
 
@@ -32,6 +33,10 @@ import (
 	"sigs.k8s.io/cluster-api-provider-azure/cloud/converters"
 )
 
+// vmExtensionsTag is the tag of a scale set listing the names of the VM extensions installed by the provider, so that
+// the extensions added to the scale set model by other tools are left untouched.
+const vmExtensionsTag = infrav1.NameAzureProviderPrefix + "vm-extensions"
+
 // Spec contains properties to create a managed cluster.
 // Spec input specification for Get/CreateOrUpdate/Delete calls
 type (
@@ -51,6 +56,17 @@ type (
 		PublicLoadBalancerName string
 		AdditionalTags         infrav1.Tags
 		AcceleratedNetworking  *bool
+		// ApplicationSecurityGroupID is the ID of the application security group of the scale set instances.
+		ApplicationSecurityGroupID string
+		// ProximityPlacementGroupID is the ID of the proximity placement group of the scale set, if any.
+		ProximityPlacementGroupID string
+		// Extensions are the VM extensions of the scale set instances.
+		Extensions []azure.VMExtensionSpec
+		// BootDiagnosticsStorageURI is the blob endpoint of the storage account holding the boot diagnostics of the
+		// scale set instances. Boot diagnostics are disabled when it is empty.
+		BootDiagnosticsStorageURI string
+		// SpotVMOptions runs the scale set instances on Spot VMs when set.
+		SpotVMOptions *infrav1.SpotVMOptions
 	}
 )
 
@@ -79,6 +95,11 @@ func (s *Service) Reconcile(ctx context.Context, spec interface{}) error {
 		return err
 	}
 
+	priority, evictionPolicy, billingProfile, err := converters.GetSpotVMOptions(vmssSpec.SpotVMOptions)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get Spot VM options")
+	}
+
 	// Make sure to use the MachineScope here to get the merger of AzureCluster and AzureMachine tags
 	// Set the cloud provider tag
 	if vmssSpec.AdditionalTags == nil {
@@ -95,20 +116,30 @@ func (s *Service) Reconcile(ctx context.Context, spec interface{}) error {
 		vmssSpec.AcceleratedNetworking = to.BoolPtr(accelNet)
 	}
 
-	// Get the node outbound LB backend pool ID
-	lb, lberr := s.PublicLoadBalancersClient.Get(ctx, vmssSpec.ResourceGroup, vmssSpec.PublicLoadBalancerName)
-	if lberr != nil {
-		return errors.Wrap(lberr, "failed to get cloud provider LB")
+	// Get the node outbound LB backend pool ID, unless the node outbound LB is disabled
+	backendAddressPools := []compute.SubResource{}
+	if vmssSpec.PublicLoadBalancerName != "" {
+		lb, lberr := s.PublicLoadBalancersClient.Get(ctx, vmssSpec.ResourceGroup, vmssSpec.PublicLoadBalancerName)
+		if lberr != nil {
+			return errors.Wrap(lberr, "failed to get cloud provider LB")
+		}
+		backendAddressPools = append(backendAddressPools, compute.SubResource{
+			ID: (*lb.BackendAddressPools)[0].ID,
+		})
 	}
 
-	backendAddressPools := []compute.SubResource{
-		{
-			ID: (*lb.BackendAddressPools)[0].ID,
-		},
+	var applicationSecurityGroups *[]compute.SubResource
+	if vmssSpec.ApplicationSecurityGroupID != "" {
+		applicationSecurityGroups = &[]compute.SubResource{
+			{
+				ID: to.StringPtr(vmssSpec.ApplicationSecurityGroupID),
+			},
+		}
 	}
 
 	vmss := compute.VirtualMachineScaleSet{
 		Location: to.StringPtr(vmssSpec.Location),
+		Plan:     converters.ImageToPlan(vmssSpec.Image),
 		Tags: converters.TagsToMap(infrav1.Build(infrav1.BuildParams{
 			ClusterName: vmssSpec.ClusterName,
 			Lifecycle:   infrav1.ResourceLifecycleOwned,
@@ -143,6 +174,9 @@ func (s *Service) Reconcile(ctx context.Context, spec interface{}) error {
 					},
 				},
 				StorageProfile: storageProfile,
+				Priority:       priority,
+				EvictionPolicy: evictionPolicy,
+				BillingProfile: billingProfile,
 				NetworkProfile: &compute.VirtualMachineScaleSetNetworkProfile{
 					NetworkInterfaceConfigurations: &[]compute.VirtualMachineScaleSetNetworkConfiguration{
 						{
@@ -160,6 +194,7 @@ func (s *Service) Reconcile(ctx context.Context, spec interface{}) error {
 											Primary:                         to.BoolPtr(true),
 											PrivateIPAddressVersion:         compute.IPv4,
 											LoadBalancerBackendAddressPools: &backendAddressPools,
+											ApplicationSecurityGroups:       applicationSecurityGroups,
 										},
 									},
 								},
@@ -172,11 +207,29 @@ func (s *Service) Reconcile(ctx context.Context, spec interface{}) error {
 		},
 	}
 
-	_, err = s.Client.Get(ctx, vmssSpec.ResourceGroup, vmssSpec.Name)
+	if vmssSpec.BootDiagnosticsStorageURI != "" {
+		vmss.VirtualMachineProfile.DiagnosticsProfile = &compute.DiagnosticsProfile{
+			BootDiagnostics: &compute.BootDiagnostics{
+				Enabled:    to.BoolPtr(true),
+				StorageURI: to.StringPtr(vmssSpec.BootDiagnosticsStorageURI),
+			},
+		}
+	}
+
+	if vmssSpec.ProximityPlacementGroupID != "" {
+		vmss.ProximityPlacementGroup = &compute.SubResource{
+			ID: to.StringPtr(vmssSpec.ProximityPlacementGroupID),
+		}
+	}
+
+	existing, err := s.Client.Get(ctx, vmssSpec.ResourceGroup, vmssSpec.Name)
 	if !azure.ResourceNotFound(err) {
 		if err != nil {
 			return errors.Wrapf(err, "failed to get scale set %s in %s", vmssSpec.Name, vmssSpec.ResourceGroup)
 		}
+		vmss.VirtualMachineProfile.ExtensionProfile = generateExtensionProfile(*vmssSpec, &existing)
+		vmss.Tags[vmExtensionsTag] = to.StringPtr(extensionNames(vmssSpec.Extensions))
+
 		// scale set already exists, update it
 		// we do this to avoid overwriting fields in networkProfile modified by cloud-provider
 		update, err := getVMSSUpdateFromVMSS(vmss)
@@ -187,6 +240,9 @@ func (s *Service) Reconcile(ctx context.Context, spec interface{}) error {
 		return s.Client.Update(ctx, vmssSpec.ResourceGroup, vmssSpec.Name, update)
 	}
 
+	vmss.VirtualMachineProfile.ExtensionProfile = generateExtensionProfile(*vmssSpec, nil)
+	vmss.Tags[vmExtensionsTag] = to.StringPtr(extensionNames(vmssSpec.Extensions))
+
 	err = s.Client.CreateOrUpdate(
 		ctx,
 		vmssSpec.ResourceGroup,
@@ -242,6 +298,82 @@ func generateStorageProfile(vmssSpec Spec) (*compute.VirtualMachineScaleSetStora
 	return storageProfile, nil
 }
 
+// generateExtensionProfile generates the extension profile of the scale set instances. The profile always has an
+// extensions list, so that extensions removed from the spec are removed from the scale set model on update. The
+// extensions of an existing scale set which were not installed by the provider, as recorded by its vmExtensionsTag,
+// are kept.
+func generateExtensionProfile(vmssSpec Spec, existing *compute.VirtualMachineScaleSet) *compute.VirtualMachineScaleSetExtensionProfile {
+	extensions := make([]compute.VirtualMachineScaleSetExtension, 0, len(vmssSpec.Extensions))
+	owned := ownedExtensions(existing)
+	for _, extension := range existingExtensions(existing) {
+		name := strings.ToLower(to.String(extension.Name))
+		if owned[name] || hasExtension(vmssSpec.Extensions, name) {
+			continue
+		}
+		extensions = append(extensions, extension)
+	}
+	for _, spec := range vmssSpec.Extensions {
+		extension := compute.VirtualMachineScaleSetExtension{
+			Name: to.StringPtr(spec.Name),
+			VirtualMachineScaleSetExtensionProperties: &compute.VirtualMachineScaleSetExtensionProperties{
+				Publisher:          to.StringPtr(spec.Publisher),
+				Type:               to.StringPtr(spec.Type),
+				TypeHandlerVersion: to.StringPtr(spec.Version),
+			},
+		}
+		if len(spec.Settings) > 0 {
+			extension.Settings = spec.Settings
+		}
+		if len(spec.ProtectedSettings) > 0 {
+			extension.ProtectedSettings = spec.ProtectedSettings
+		}
+		extensions = append(extensions, extension)
+	}
+	return &compute.VirtualMachineScaleSetExtensionProfile{Extensions: &extensions}
+}
+
+// existingExtensions returns the extensions of the model of an existing scale set.
+func existingExtensions(vmss *compute.VirtualMachineScaleSet) []compute.VirtualMachineScaleSetExtension {
+	if vmss == nil || vmss.VirtualMachineScaleSetProperties == nil || vmss.VirtualMachineProfile == nil ||
+		vmss.VirtualMachineProfile.ExtensionProfile == nil || vmss.VirtualMachineProfile.ExtensionProfile.Extensions == nil {
+		return nil
+	}
+	return *vmss.VirtualMachineProfile.ExtensionProfile.Extensions
+}
+
+// ownedExtensions returns the lower-cased names of the extensions of an existing scale set installed by the provider.
+func ownedExtensions(vmss *compute.VirtualMachineScaleSet) map[string]bool {
+	owned := make(map[string]bool)
+	if vmss == nil {
+		return owned
+	}
+	for _, name := range strings.Split(to.String(vmss.Tags[vmExtensionsTag]), ",") {
+		if name != "" {
+			owned[strings.ToLower(name)] = true
+		}
+	}
+	return owned
+}
+
+// hasExtension returns true if specs has an extension with the given lower-cased name.
+func hasExtension(specs []azure.VMExtensionSpec, name string) bool {
+	for _, spec := range specs {
+		if strings.ToLower(spec.Name) == name {
+			return true
+		}
+	}
+	return false
+}
+
+// extensionNames returns the names of the extensions installed by the provider, as stored in vmExtensionsTag.
+func extensionNames(specs []azure.VMExtensionSpec) string {
+	names := make([]string, len(specs))
+	for i, spec := range specs {
+		names[i] = spec.Name
+	}
+	return strings.Join(names, ",")
+}
+
 func getVMSSUpdateFromVMSS(vmss compute.VirtualMachineScaleSet) (compute.VirtualMachineScaleSetUpdate, error) {
 	json, err := vmss.MarshalJSON()
 	if err != nil {
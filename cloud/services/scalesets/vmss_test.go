@@ -62,7 +62,7 @@ func TestNewService(t *testing.T) {
 		Cluster: cluster,
 		AzureCluster: &infrav1.AzureCluster{
 			Spec: infrav1.AzureClusterSpec{
-				Location: "test-location",
+				Location:       "test-location",
 				ResourceGroup:  "my-rg",
 				SubscriptionID: "123",
 				NetworkSpec: infrav1.NetworkSpec{
@@ -295,6 +295,7 @@ func TestService_Reconcile(t *testing.T) {
 						"Name":                            to.StringPtr("capz-mp-0"),
 						"kubernetes.io_cluster_capz-mp-0": to.StringPtr("owned"),
 						"sigs.k8s.io_cluster-api-provider-azure_cluster_test-cluster": to.StringPtr("owned"),
+						"sigs.k8s.io_cluster-api-provider-azure_vm-extensions":        to.StringPtr(""),
 						"sigs.k8s.io_cluster-api-provider-azure_role":                 to.StringPtr("node"),
 					},
 					Sku: &compute.Sku{
@@ -323,7 +324,8 @@ func TestService_Reconcile(t *testing.T) {
 									DisablePasswordAuthentication: to.BoolPtr(true),
 								},
 							},
-							StorageProfile: storageProfile,
+							StorageProfile:   storageProfile,
+							ExtensionProfile: &compute.VirtualMachineScaleSetExtensionProfile{Extensions: &[]compute.VirtualMachineScaleSetExtension{}},
 							NetworkProfile: &compute.VirtualMachineScaleSetNetworkProfile{
 								NetworkInterfaceConfigurations: &[]compute.VirtualMachineScaleSetNetworkConfiguration{
 									{
@@ -409,6 +411,7 @@ func TestService_Reconcile(t *testing.T) {
 						"Name":                            to.StringPtr("capz-mp-0"),
 						"kubernetes.io_cluster_capz-mp-0": to.StringPtr("owned"),
 						"sigs.k8s.io_cluster-api-provider-azure_cluster_test-cluster": to.StringPtr("owned"),
+						"sigs.k8s.io_cluster-api-provider-azure_vm-extensions":        to.StringPtr(""),
 						"sigs.k8s.io_cluster-api-provider-azure_role":                 to.StringPtr("node"),
 					},
 					Sku: &compute.Sku{
@@ -437,7 +440,8 @@ func TestService_Reconcile(t *testing.T) {
 									DisablePasswordAuthentication: to.BoolPtr(true),
 								},
 							},
-							StorageProfile: storageProfile,
+							StorageProfile:   storageProfile,
+							ExtensionProfile: &compute.VirtualMachineScaleSetExtensionProfile{Extensions: &[]compute.VirtualMachineScaleSetExtension{}},
 							NetworkProfile: &compute.VirtualMachineScaleSetNetworkProfile{
 								NetworkInterfaceConfigurations: &[]compute.VirtualMachineScaleSetNetworkConfiguration{
 									{
@@ -523,6 +527,7 @@ func TestService_Reconcile(t *testing.T) {
 						"Name":                            to.StringPtr("capz-mp-0"),
 						"kubernetes.io_cluster_capz-mp-0": to.StringPtr("owned"),
 						"sigs.k8s.io_cluster-api-provider-azure_cluster_test-cluster": to.StringPtr("owned"),
+						"sigs.k8s.io_cluster-api-provider-azure_vm-extensions":        to.StringPtr(""),
 						"sigs.k8s.io_cluster-api-provider-azure_role":                 to.StringPtr("node"),
 					},
 					Sku: &compute.Sku{
@@ -551,7 +556,8 @@ func TestService_Reconcile(t *testing.T) {
 									DisablePasswordAuthentication: to.BoolPtr(true),
 								},
 							},
-							StorageProfile: storageProfile,
+							StorageProfile:   storageProfile,
+							ExtensionProfile: &compute.VirtualMachineScaleSetExtensionProfile{Extensions: &[]compute.VirtualMachineScaleSetExtension{}},
 							NetworkProfile: &compute.VirtualMachineScaleSetNetworkProfile{
 								NetworkInterfaceConfigurations: &[]compute.VirtualMachineScaleSetNetworkConfiguration{
 									{
@@ -586,6 +592,7 @@ func TestService_Reconcile(t *testing.T) {
 						"Name":                            to.StringPtr("capz-mp-0"),
 						"kubernetes.io_cluster_capz-mp-0": to.StringPtr("owned"),
 						"sigs.k8s.io_cluster-api-provider-azure_cluster_test-cluster": to.StringPtr("owned"),
+						"sigs.k8s.io_cluster-api-provider-azure_vm-extensions":        to.StringPtr(""),
 						"sigs.k8s.io_cluster-api-provider-azure_role":                 to.StringPtr("node"),
 					},
 					Sku: &compute.Sku{
@@ -619,6 +626,7 @@ func TestService_Reconcile(t *testing.T) {
 									ManagedDisk: &compute.VirtualMachineScaleSetManagedDiskParameters{StorageAccountType: "accountType"},
 								},
 							},
+							ExtensionProfile: &compute.VirtualMachineScaleSetExtensionProfile{Extensions: &[]compute.VirtualMachineScaleSetExtension{}},
 						},
 					},
 				}
@@ -634,6 +642,47 @@ func TestService_Reconcile(t *testing.T) {
 				g.Expect(err).ToNot(gomega.HaveOccurred())
 			},
 		},
+		{
+			Name: "WithSpotVMOptions",
+			SpecFactory: func(g *gomega.GomegaWithT, scope *scope.ClusterScope, mpScope *scope.MachinePoolScope) interface{} {
+				deletePolicy := infrav1.SpotEvictionPolicyDelete
+				return &Spec{
+					Name:          mpScope.Name(),
+					ResourceGroup: scope.AzureCluster.Spec.ResourceGroup,
+					Location:      scope.AzureCluster.Spec.Location,
+					ClusterName:   scope.Cluster.Name,
+					SubnetID:      scope.AzureCluster.Spec.NetworkSpec.Subnets[0].ID,
+					Sku:           "skuName",
+					Capacity:      2,
+					Image: &infrav1.Image{
+						ID: to.StringPtr("image"),
+					},
+					AcceleratedNetworking: to.BoolPtr(false),
+					SpotVMOptions: &infrav1.SpotVMOptions{
+						MaxPrice:       to.StringPtr("0.04"),
+						EvictionPolicy: &deletePolicy,
+					},
+				}
+			},
+			Setup: func(ctx context.Context, g *gomega.GomegaWithT, svc *Service, scope *scope.ClusterScope, mpScope *scope.MachinePoolScope, spec *Spec) *gomock.Controller {
+				mockCtrl := gomock.NewController(t)
+				vmssMock := mock_scalesets.NewMockClient(mockCtrl)
+				svc.Client = vmssMock
+
+				vmssMock.EXPECT().Get(gomock.Any(), scope.AzureCluster.Spec.ResourceGroup, spec.Name).Return(compute.VirtualMachineScaleSet{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
+				vmssMock.EXPECT().CreateOrUpdate(gomock.Any(), scope.AzureCluster.Spec.ResourceGroup, spec.Name, gomock.Any()).Do(func(_, _, _ interface{}, vmss compute.VirtualMachineScaleSet) {
+					profile := vmss.VirtualMachineProfile
+					g.Expect(profile.Priority).To(gomega.Equal(compute.Spot))
+					g.Expect(profile.EvictionPolicy).To(gomega.Equal(compute.Delete))
+					g.Expect(profile.BillingProfile).To(gomega.Equal(&compute.BillingProfile{MaxPrice: to.Float64Ptr(0.04)}))
+				}).Return(nil)
+
+				return mockCtrl
+			},
+			Expect: func(ctx context.Context, g *gomega.GomegaWithT, err error) {
+				g.Expect(err).ToNot(gomega.HaveOccurred())
+			},
+		},
 	}
 
 	for _, c := range cases {
@@ -818,6 +867,67 @@ func TestGetVMSSUpdateFromVMSS(t *testing.T) {
 	g.Expect(result).To(gomega.Equal(expectedUpdate))
 }
 
+func TestGenerateExtensionProfile(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	spec := Spec{
+		Extensions: []azure.VMExtensionSpec{
+			{
+				Name:              "my-script",
+				Publisher:         "Microsoft.Azure.Extensions",
+				Type:              "CustomScript",
+				Version:           "2.1",
+				Settings:          map[string]interface{}{"commandToExecute": "echo hello"},
+				ProtectedSettings: map[string]string{"storageAccountKey": "secret"},
+			},
+		},
+	}
+
+	expected := &compute.VirtualMachineScaleSetExtensionProfile{
+		Extensions: &[]compute.VirtualMachineScaleSetExtension{
+			{
+				Name: to.StringPtr("my-script"),
+				VirtualMachineScaleSetExtensionProperties: &compute.VirtualMachineScaleSetExtensionProperties{
+					Publisher:          to.StringPtr("Microsoft.Azure.Extensions"),
+					Type:               to.StringPtr("CustomScript"),
+					TypeHandlerVersion: to.StringPtr("2.1"),
+					Settings:           map[string]interface{}{"commandToExecute": "echo hello"},
+					ProtectedSettings:  map[string]string{"storageAccountKey": "secret"},
+				},
+			},
+		},
+	}
+	g.Expect(generateExtensionProfile(spec, nil)).To(gomega.Equal(expected))
+	g.Expect(generateExtensionProfile(Spec{}, nil)).To(gomega.Equal(&compute.VirtualMachineScaleSetExtensionProfile{
+		Extensions: &[]compute.VirtualMachineScaleSetExtension{},
+	}))
+
+	// Extensions added out of band are kept, the ones removed from the spec are dropped.
+	outOfBand := compute.VirtualMachineScaleSetExtension{
+		Name: to.StringPtr("monitoring-agent"),
+		VirtualMachineScaleSetExtensionProperties: &compute.VirtualMachineScaleSetExtensionProperties{
+			Publisher: to.StringPtr("Microsoft.Azure.Monitor"),
+		},
+	}
+	existing := &compute.VirtualMachineScaleSet{
+		Tags: map[string]*string{vmExtensionsTag: to.StringPtr("my-script,removed-script")},
+		VirtualMachineScaleSetProperties: &compute.VirtualMachineScaleSetProperties{
+			VirtualMachineProfile: &compute.VirtualMachineScaleSetVMProfile{
+				ExtensionProfile: &compute.VirtualMachineScaleSetExtensionProfile{
+					Extensions: &[]compute.VirtualMachineScaleSetExtension{
+						outOfBand,
+						{Name: to.StringPtr("my-script")},
+						{Name: to.StringPtr("removed-script")},
+					},
+				},
+			},
+		},
+	}
+	g.Expect(generateExtensionProfile(spec, existing)).To(gomega.Equal(&compute.VirtualMachineScaleSetExtensionProfile{
+		Extensions: &[]compute.VirtualMachineScaleSetExtension{outOfBand, (*expected.Extensions)[0]},
+	}))
+}
+
 func getScopes(g *gomega.GomegaWithT) (*scope.ClusterScope, *scope.MachinePoolScope) {
 	cluster := &clusterv1.Cluster{
 		ObjectMeta: metav1.ObjectMeta{Name: "test-cluster"},
@@ -831,7 +941,7 @@ func getScopes(g *gomega.GomegaWithT) (*scope.ClusterScope, *scope.MachinePoolSc
 		Cluster: cluster,
 		AzureCluster: &infrav1.AzureCluster{
 			Spec: infrav1.AzureClusterSpec{
-				Location: "test-location",
+				Location:       "test-location",
 				ResourceGroup:  "my-rg",
 				SubscriptionID: "123",
 				NetworkSpec: infrav1.NetworkSpec{
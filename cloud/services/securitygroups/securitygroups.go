@@ -64,7 +64,7 @@ func (s *Service) Reconcile(ctx context.Context, spec interface{}) error {
 		cpSubnet := s.Scope.ControlPlaneSubnet()
 		if cpSubnet != nil && len(cpSubnet.SecurityGroup.IngressRules) > 0 {
 			for _, ingressRule := range cpSubnet.SecurityGroup.IngressRules {
-				ingressRules[ingressRule.Name] = newIngressSecurityRule(*ingressRule)
+				ingressRules[ingressRule.Name] = s.newIngressSecurityRule(*ingressRule)
 			}
 		}
 	} else {
@@ -72,7 +72,7 @@ func (s *Service) Reconcile(ctx context.Context, spec interface{}) error {
 		nodeSubnet := s.Scope.NodeSubnet()
 		if nodeSubnet != nil && len(nodeSubnet.SecurityGroup.IngressRules) > 0 {
 			for _, ingressRule := range nodeSubnet.SecurityGroup.IngressRules {
-				ingressRules[ingressRule.Name] = newIngressSecurityRule(*ingressRule)
+				ingressRules[ingressRule.Name] = s.newIngressSecurityRule(*ingressRule)
 			}
 		}
 	}
@@ -141,7 +141,7 @@ func ruleExists(rules []network.SecurityRule, rule network.SecurityRule) bool {
 	return false
 }
 
-func newIngressSecurityRule(ingress infrav1.IngressRule) network.SecurityRule {
+func (s *Service) newIngressSecurityRule(ingress infrav1.IngressRule) network.SecurityRule {
 	secRule := network.SecurityRule{
 		Name: to.StringPtr(ingress.Name),
 		SecurityRulePropertiesFormat: &network.SecurityRulePropertiesFormat{
@@ -156,6 +156,16 @@ func newIngressSecurityRule(ingress infrav1.IngressRule) network.SecurityRule {
 		},
 	}
 
+	// application security groups replace the address prefixes, so that rules follow the machines of a role
+	if ingress.SourceApplicationSecurityGroup != "" {
+		secRule.SourceAddressPrefix = nil
+		secRule.SourceApplicationSecurityGroups = &[]network.ApplicationSecurityGroup{s.applicationSecurityGroup(ingress.SourceApplicationSecurityGroup)}
+	}
+	if ingress.DestinationApplicationSecurityGroup != "" {
+		secRule.DestinationAddressPrefix = nil
+		secRule.DestinationApplicationSecurityGroups = &[]network.ApplicationSecurityGroup{s.applicationSecurityGroup(ingress.DestinationApplicationSecurityGroup)}
+	}
+
 	switch ingress.Protocol {
 	case infrav1.SecurityGroupProtocolAll:
 		secRule.SecurityRulePropertiesFormat.Protocol = network.SecurityRuleProtocolAsterisk
@@ -168,6 +178,14 @@ func newIngressSecurityRule(ingress infrav1.IngressRule) network.SecurityRule {
 	return secRule
 }
 
+// applicationSecurityGroup returns a reference to the application security group of the machines with the given role.
+func (s *Service) applicationSecurityGroup(role infrav1.SecurityGroupRole) network.ApplicationSecurityGroup {
+	name := azure.GenerateApplicationSecurityGroupName(s.Scope.ClusterName(), string(role))
+	return network.ApplicationSecurityGroup{
+		ID: to.StringPtr(azure.ApplicationSecurityGroupID(s.Scope.SubscriptionID(), s.Scope.ResourceGroup(), name)),
+	}
+}
+
 // Delete deletes the network security group with the provided name.
 func (s *Service) Delete(ctx context.Context, spec interface{}) error {
 	nsgSpec, ok := spec.(*Spec)
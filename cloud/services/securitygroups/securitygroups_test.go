@@ -25,6 +25,7 @@ import (
 	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/securitygroups/mock_securitygroups"
 
 	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/to"
 	"github.com/golang/mock/gomock"
 
 	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
@@ -130,6 +131,50 @@ func TestReconcileSecurityGroups(t *testing.T) {
 	}
 }
 
+func TestNewIngressSecurityRuleWithApplicationSecurityGroups(t *testing.T) {
+	g := NewWithT(t)
+
+	cluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-cluster"},
+	}
+	clusterScope, err := scope.NewClusterScope(scope.ClusterScopeParams{
+		AzureClients: scope.AzureClients{
+			Authorizer: autorest.NullAuthorizer{},
+		},
+		Client:  fake.NewFakeClientWithScheme(scheme.Scheme, cluster),
+		Cluster: cluster,
+		AzureCluster: &infrav1.AzureCluster{
+			Spec: infrav1.AzureClusterSpec{
+				ResourceGroup:  "my-rg",
+				SubscriptionID: subscriptionID,
+			},
+		},
+	})
+	g.Expect(err).NotTo(HaveOccurred())
+
+	s := &Service{
+		Scope: clusterScope,
+	}
+	rule := s.newIngressSecurityRule(infrav1.IngressRule{
+		Name:                                "allow_ssh_from_control_plane",
+		Protocol:                            infrav1.SecurityGroupProtocolTCP,
+		Priority:                            102,
+		SourcePorts:                         to.StringPtr("*"),
+		DestinationPorts:                    to.StringPtr("22"),
+		SourceApplicationSecurityGroup:      infrav1.SecurityGroupControlPlane,
+		DestinationApplicationSecurityGroup: infrav1.SecurityGroupNode,
+	})
+
+	g.Expect(rule.SourceAddressPrefix).To(BeNil())
+	g.Expect(rule.DestinationAddressPrefix).To(BeNil())
+	g.Expect(*rule.SourceApplicationSecurityGroups).To(Equal([]network.ApplicationSecurityGroup{
+		{ID: to.StringPtr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/applicationSecurityGroups/test-cluster-control-plane-asg")},
+	}))
+	g.Expect(*rule.DestinationApplicationSecurityGroups).To(Equal([]network.ApplicationSecurityGroup{
+		{ID: to.StringPtr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/applicationSecurityGroups/test-cluster-node-asg")},
+	}))
+}
+
 func TestDeleteSecurityGroups(t *testing.T) {
 	testcases := []struct {
 		name   string
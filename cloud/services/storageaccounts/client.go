@@ -0,0 +1,89 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storageaccounts
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/services/storage/mgmt/2019-06-01/storage"
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/to"
+	azure "sigs.k8s.io/cluster-api-provider-azure/cloud"
+)
+
+// Client wraps go-sdk
+type Client interface {
+	Get(context.Context, string, string) (storage.Account, error)
+	Create(context.Context, string, string, storage.AccountCreateParameters) error
+	Delete(context.Context, string, string) error
+	ListServiceSAS(context.Context, string, string, storage.ServiceSasParameters) (string, error)
+}
+
+// AzureClient contains the Azure go-sdk Client
+type AzureClient struct {
+	accounts storage.AccountsClient
+}
+
+var _ Client = &AzureClient{}
+
+// NewClient creates a new storage accounts client from subscription ID.
+func NewClient(auth azure.Authorizer) *AzureClient {
+	c := newAccountsClient(auth.SubscriptionID(), auth.BaseURI(), auth.Authorizer())
+	return &AzureClient{c}
+}
+
+// newAccountsClient creates a new storage accounts client from subscription ID.
+func newAccountsClient(subscriptionID string, baseURI string, authorizer autorest.Authorizer) storage.AccountsClient {
+	accountsClient := storage.NewAccountsClientWithBaseURI(baseURI, subscriptionID)
+	accountsClient.Authorizer = authorizer
+	accountsClient.AddToUserAgent(azure.UserAgent())
+	return accountsClient
+}
+
+// Get gets the properties of the specified storage account.
+func (ac *AzureClient) Get(ctx context.Context, resourceGroupName, accountName string) (storage.Account, error) {
+	return ac.accounts.GetProperties(ctx, resourceGroupName, accountName, "")
+}
+
+// Create creates a storage account.
+func (ac *AzureClient) Create(ctx context.Context, resourceGroupName, accountName string, account storage.AccountCreateParameters) error {
+	future, err := ac.accounts.Create(ctx, resourceGroupName, accountName, account)
+	if err != nil {
+		return err
+	}
+	err = future.WaitForCompletionRef(ctx, ac.accounts.Client)
+	if err != nil {
+		return err
+	}
+	_, err = future.Result(ac.accounts)
+	return err
+}
+
+// Delete deletes the specified storage account.
+func (ac *AzureClient) Delete(ctx context.Context, resourceGroupName, accountName string) error {
+	_, err := ac.accounts.Delete(ctx, resourceGroupName, accountName)
+	return err
+}
+
+// ListServiceSAS returns a shared access signature token for a resource of the specified storage account.
+func (ac *AzureClient) ListServiceSAS(ctx context.Context, resourceGroupName, accountName string, parameters storage.ServiceSasParameters) (string, error) {
+	result, err := ac.accounts.ListServiceSAS(ctx, resourceGroupName, accountName, parameters)
+	if err != nil {
+		return "", err
+	}
+	return to.String(result.ServiceSasToken), nil
+}
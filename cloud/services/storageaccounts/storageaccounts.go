@@ -0,0 +1,115 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storageaccounts
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/services/storage/mgmt/2019-06-01/storage"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/pkg/errors"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha3"
+	azure "sigs.k8s.io/cluster-api-provider-azure/cloud"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/converters"
+)
+
+// Spec input specification for Get/CreateOrUpdate/Delete calls
+type Spec struct {
+	Name string
+}
+
+// Get returns the storage account of the spec in the cluster resource group.
+func (s *Service) Get(ctx context.Context, spec interface{}) (interface{}, error) {
+	accountSpec, ok := spec.(*Spec)
+	if !ok {
+		return nil, errors.New("invalid storage account specification")
+	}
+	account, err := s.Client.Get(ctx, s.Scope.ResourceGroup(), accountSpec.Name)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get storage account %s in %s", accountSpec.Name, s.Scope.ResourceGroup())
+	}
+	return &account, nil
+}
+
+// Reconcile creates the storage account of the spec in the cluster resource group if it doesn't exist.
+func (s *Service) Reconcile(ctx context.Context, spec interface{}) error {
+	accountSpec, ok := spec.(*Spec)
+	if !ok {
+		return errors.New("invalid storage account specification")
+	}
+
+	_, err := s.Client.Get(ctx, s.Scope.ResourceGroup(), accountSpec.Name)
+	if err == nil {
+		return nil
+	}
+	if !azure.ResourceNotFound(err) {
+		return errors.Wrapf(err, "failed to get storage account %s in %s", accountSpec.Name, s.Scope.ResourceGroup())
+	}
+
+	s.Scope.V(2).Info("creating storage account", "storage account", accountSpec.Name)
+	err = s.Client.Create(ctx, s.Scope.ResourceGroup(), accountSpec.Name, storage.AccountCreateParameters{
+		Sku: &storage.Sku{
+			Name: storage.StandardLRS,
+		},
+		Kind:     storage.StorageV2,
+		Location: to.StringPtr(s.Scope.Location()),
+		Tags: converters.TagsToMap(infrav1.Build(infrav1.BuildParams{
+			ClusterName: s.Scope.ClusterName(),
+			Lifecycle:   infrav1.ResourceLifecycleOwned,
+			Name:        to.StringPtr(accountSpec.Name),
+			Additional:  s.Scope.AdditionalTags(),
+		})),
+		AccountPropertiesCreateParameters: &storage.AccountPropertiesCreateParameters{
+			EnableHTTPSTrafficOnly: to.BoolPtr(true),
+		},
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to create storage account %s in resource group %s", accountSpec.Name, s.Scope.ResourceGroup())
+	}
+
+	s.Scope.V(2).Info("successfully created storage account", "storage account", accountSpec.Name)
+	return nil
+}
+
+// Delete deletes the storage account of the spec if it is owned by the cluster.
+func (s *Service) Delete(ctx context.Context, spec interface{}) error {
+	accountSpec, ok := spec.(*Spec)
+	if !ok {
+		return errors.New("invalid storage account specification")
+	}
+
+	account, err := s.Client.Get(ctx, s.Scope.ResourceGroup(), accountSpec.Name)
+	if azure.ResourceNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return errors.Wrapf(err, "failed to get storage account %s in %s", accountSpec.Name, s.Scope.ResourceGroup())
+	}
+	if !converters.MapToTags(account.Tags).HasOwned(s.Scope.ClusterName()) {
+		s.Scope.V(2).Info("skipping deletion of unmanaged storage account", "storage account", accountSpec.Name)
+		return nil
+	}
+
+	s.Scope.V(2).Info("deleting storage account", "storage account", accountSpec.Name)
+	err = s.Client.Delete(ctx, s.Scope.ResourceGroup(), accountSpec.Name)
+	if err != nil && !azure.ResourceNotFound(err) {
+		return errors.Wrapf(err, "failed to delete storage account %s in resource group %s", accountSpec.Name, s.Scope.ResourceGroup())
+	}
+
+	s.Scope.V(2).Info("successfully deleted storage account", "storage account", accountSpec.Name)
+	return nil
+}
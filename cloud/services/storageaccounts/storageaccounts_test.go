@@ -0,0 +1,206 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storageaccounts
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/storage/mgmt/2019-06-01/storage"
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+	"k8s.io/klog/klogr"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/storageaccounts/mock_storageaccounts"
+)
+
+func TestReconcileStorageAccounts(t *testing.T) {
+	testcases := []struct {
+		name          string
+		expectedError string
+		expect        func(s *mock_storageaccounts.MockStorageAccountScopeMockRecorder, m *mock_storageaccounts.MockClientMockRecorder)
+	}{
+		{
+			name:          "storage account already exists",
+			expectedError: "",
+			expect: func(s *mock_storageaccounts.MockStorageAccountScopeMockRecorder, m *mock_storageaccounts.MockClientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.ClusterName().AnyTimes().Return("test-cluster")
+				m.Get(context.TODO(), "my-rg", "mydiag").Return(storage.Account{Name: to.StringPtr("mydiag")}, nil)
+			},
+		},
+		{
+			name:          "create storage account",
+			expectedError: "",
+			expect: func(s *mock_storageaccounts.MockStorageAccountScopeMockRecorder, m *mock_storageaccounts.MockClientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.ClusterName().AnyTimes().Return("test-cluster")
+				s.Location().AnyTimes().Return("test-location")
+				s.AdditionalTags().AnyTimes()
+				m.Get(context.TODO(), "my-rg", "mydiag").Return(storage.Account{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
+				m.Create(context.TODO(), "my-rg", "mydiag", storage.AccountCreateParameters{
+					Sku: &storage.Sku{
+						Name: storage.StandardLRS,
+					},
+					Kind:     storage.StorageV2,
+					Location: to.StringPtr("test-location"),
+					Tags: map[string]*string{
+						"Name": to.StringPtr("mydiag"),
+						"sigs.k8s.io_cluster-api-provider-azure_cluster_test-cluster": to.StringPtr("owned"),
+					},
+					AccountPropertiesCreateParameters: &storage.AccountPropertiesCreateParameters{
+						EnableHTTPSTrafficOnly: to.BoolPtr(true),
+					},
+				})
+			},
+		},
+		{
+			name:          "fail to get storage account",
+			expectedError: "failed to get storage account mydiag in my-rg: #: Internal Server Error: StatusCode=500",
+			expect: func(s *mock_storageaccounts.MockStorageAccountScopeMockRecorder, m *mock_storageaccounts.MockClientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.ClusterName().AnyTimes().Return("test-cluster")
+				m.Get(context.TODO(), "my-rg", "mydiag").Return(storage.Account{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error"))
+			},
+		},
+		{
+			name:          "fail to create storage account",
+			expectedError: "failed to create storage account mydiag in resource group my-rg: #: Internal Server Error: StatusCode=500",
+			expect: func(s *mock_storageaccounts.MockStorageAccountScopeMockRecorder, m *mock_storageaccounts.MockClientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.ClusterName().AnyTimes().Return("test-cluster")
+				s.Location().AnyTimes().Return("test-location")
+				s.AdditionalTags().AnyTimes()
+				m.Get(context.TODO(), "my-rg", "mydiag").Return(storage.Account{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
+				m.Create(context.TODO(), "my-rg", "mydiag", gomock.AssignableToTypeOf(storage.AccountCreateParameters{})).Return(autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error"))
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			scopeMock := mock_storageaccounts.NewMockStorageAccountScope(mockCtrl)
+			clientMock := mock_storageaccounts.NewMockClient(mockCtrl)
+			tc.expect(scopeMock.EXPECT(), clientMock.EXPECT())
+
+			s := &Service{
+				Scope:  scopeMock,
+				Client: clientMock,
+			}
+
+			err := s.Reconcile(context.TODO(), &Spec{Name: "mydiag"})
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestDeleteStorageAccounts(t *testing.T) {
+	ownedTags := map[string]*string{
+		"sigs.k8s.io_cluster-api-provider-azure_cluster_test-cluster": to.StringPtr("owned"),
+	}
+
+	testcases := []struct {
+		name          string
+		expectedError string
+		expect        func(s *mock_storageaccounts.MockStorageAccountScopeMockRecorder, m *mock_storageaccounts.MockClientMockRecorder)
+	}{
+		{
+			name:          "storage account already deleted",
+			expectedError: "",
+			expect: func(s *mock_storageaccounts.MockStorageAccountScopeMockRecorder, m *mock_storageaccounts.MockClientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.ClusterName().AnyTimes().Return("test-cluster")
+				m.Get(context.TODO(), "my-rg", "mydiag").Return(storage.Account{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
+			},
+		},
+		{
+			name:          "delete owned storage account",
+			expectedError: "",
+			expect: func(s *mock_storageaccounts.MockStorageAccountScopeMockRecorder, m *mock_storageaccounts.MockClientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.ClusterName().AnyTimes().Return("test-cluster")
+				m.Get(context.TODO(), "my-rg", "mydiag").Return(storage.Account{Tags: ownedTags}, nil)
+				m.Delete(context.TODO(), "my-rg", "mydiag")
+			},
+		},
+		{
+			name:          "do not delete unmanaged storage account",
+			expectedError: "",
+			expect: func(s *mock_storageaccounts.MockStorageAccountScopeMockRecorder, m *mock_storageaccounts.MockClientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.ClusterName().AnyTimes().Return("test-cluster")
+				m.Get(context.TODO(), "my-rg", "mydiag").Return(storage.Account{}, nil)
+			},
+		},
+		{
+			name:          "fail to delete storage account",
+			expectedError: "failed to delete storage account mydiag in resource group my-rg: #: Internal Server Error: StatusCode=500",
+			expect: func(s *mock_storageaccounts.MockStorageAccountScopeMockRecorder, m *mock_storageaccounts.MockClientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.ClusterName().AnyTimes().Return("test-cluster")
+				m.Get(context.TODO(), "my-rg", "mydiag").Return(storage.Account{Tags: ownedTags}, nil)
+				m.Delete(context.TODO(), "my-rg", "mydiag").Return(autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error"))
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			scopeMock := mock_storageaccounts.NewMockStorageAccountScope(mockCtrl)
+			clientMock := mock_storageaccounts.NewMockClient(mockCtrl)
+			tc.expect(scopeMock.EXPECT(), clientMock.EXPECT())
+
+			s := &Service{
+				Scope:  scopeMock,
+				Client: clientMock,
+			}
+
+			err := s.Delete(context.TODO(), &Spec{Name: "mydiag"})
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package subnets
 
 import (
+	azure "sigs.k8s.io/cluster-api-provider-azure/cloud"
 	"sigs.k8s.io/cluster-api-provider-azure/cloud/scope"
 	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/routetables"
 	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/securitygroups"
@@ -34,7 +35,7 @@ type Service struct {
 func NewService(scope *scope.ClusterScope) *Service {
 	return &Service{
 		Scope:                scope,
-		Client:               NewClient(scope),
+		Client:               NewClient(azure.VnetAuthorizer(scope)),
 		SecurityGroupsClient: securitygroups.NewClient(scope),
 		RouteTablesClient:    routetables.NewClient(scope),
 	}
@@ -19,6 +19,7 @@ package subnets
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
 	"github.com/Azure/go-autorest/autorest/to"
@@ -29,6 +30,10 @@ import (
 	azure "sigs.k8s.io/cluster-api-provider-azure/cloud"
 )
 
+// privateEndpointNetworkPoliciesDisabled is the value of the private endpoint network policies of a subnet which
+// allows private endpoints in it.
+const privateEndpointNetworkPoliciesDisabled = "Disabled"
+
 // Spec input specification for Get/CreateOrUpdate/Delete calls
 type Spec struct {
 	Name                string
@@ -38,13 +43,17 @@ type Spec struct {
 	SecurityGroupName   string
 	Role                infrav1.SubnetRole
 	InternalLBIPAddress string
+	ServiceEndpoints    []infrav1.ServiceEndpointSpec
+	// HasPrivateEndpoints disables the private endpoint network policies of the subnet, as Azure requires to create
+	// private endpoints in it.
+	HasPrivateEndpoints bool
 }
 
-// getExisting provides information about an existing subnet.
-func (s *Service) getExisting(ctx context.Context, rgName string, spec *Spec) (*infrav1.SubnetSpec, error) {
+// getExisting provides information about an existing subnet, along with the subnet itself.
+func (s *Service) getExisting(ctx context.Context, rgName string, spec *Spec) (*infrav1.SubnetSpec, network.Subnet, error) {
 	subnet, err := s.Client.Get(ctx, rgName, spec.VnetName, spec.Name)
 	if err != nil {
-		return nil, errors.Wrapf(err, "failed to fetch subnet named %q in vnet %q", spec.VnetName, spec.Name)
+		return nil, subnet, errors.Wrapf(err, "failed to fetch subnet named %q in vnet %q", spec.VnetName, spec.Name)
 	}
 
 	subnetSpec := &infrav1.SubnetSpec{
@@ -54,8 +63,20 @@ func (s *Service) getExisting(ctx context.Context, rgName string, spec *Spec) (*
 		ID:                  to.String(subnet.ID),
 		CidrBlock:           to.String(subnet.SubnetPropertiesFormat.AddressPrefix),
 	}
+	if subnet.ServiceEndpoints != nil {
+		for _, endpoint := range *subnet.ServiceEndpoints {
+			var locations []string
+			if endpoint.Locations != nil {
+				locations = *endpoint.Locations
+			}
+			subnetSpec.ServiceEndpoints = append(subnetSpec.ServiceEndpoints, infrav1.ServiceEndpointSpec{
+				Service:   to.String(endpoint.Service),
+				Locations: locations,
+			})
+		}
+	}
 
-	return subnetSpec, nil
+	return subnetSpec, subnet, nil
 }
 
 // Reconcile gets/creates/updates a subnet.
@@ -64,7 +85,7 @@ func (s *Service) Reconcile(ctx context.Context, spec interface{}) error {
 	if !ok {
 		return errors.New("Invalid Subnet Specification")
 	}
-	existingSubnet, err := s.getExisting(ctx, s.Scope.Vnet().ResourceGroup, subnetSpec)
+	existingSubnet, sdkSubnet, err := s.getExisting(ctx, s.Scope.Vnet().ResourceGroup, subnetSpec)
 	if err == nil {
 		// subnet already exists, update the spec and skip creation
 		var subnet *infrav1.SubnetSpec
@@ -81,7 +102,15 @@ func (s *Service) Reconcile(ctx context.Context, spec interface{}) error {
 		subnet.CidrBlock = existingSubnet.CidrBlock
 		subnet.ID = existingSubnet.ID
 
-		return nil
+		if !s.Scope.Vnet().IsManaged(s.Scope.ClusterName()) {
+			return nil
+		}
+		policiesDisabled := strings.EqualFold(to.String(sdkSubnet.PrivateEndpointNetworkPolicies), privateEndpointNetworkPoliciesDisabled)
+		if hasServiceEndpoints(existingSubnet.ServiceEndpoints, s.defaultServiceEndpoints(subnetSpec.ServiceEndpoints)) &&
+			(!subnetSpec.HasPrivateEndpoints || policiesDisabled) {
+			return nil
+		}
+		return s.updateSubnet(ctx, subnetSpec, sdkSubnet)
 	}
 	if !azure.ResourceNotFound(err) {
 		if err != nil {
@@ -92,10 +121,22 @@ func (s *Service) Reconcile(ctx context.Context, spec interface{}) error {
 		// if the vnet is unmanaged, we expect all subnets to be created as well
 		return fmt.Errorf("vnet was provided but subnet %s is missing", subnetSpec.Name)
 	}
+	if vnetSubscriptionID := azure.VnetAuthorizer(s.Scope).SubscriptionID(); vnetSubscriptionID != s.Scope.SubscriptionID() {
+		// the route table and security group of the subnet are in the cluster subscription, and Azure cannot link
+		// them to a subnet in another subscription
+		return fmt.Errorf("cannot create subnet %s in vnet %s of subscription %s: the provider only creates subnets in the cluster subscription",
+			subnetSpec.Name, subnetSpec.VnetName, vnetSubscriptionID)
+	}
 
 	subnetProperties := network.SubnetPropertiesFormat{
 		AddressPrefix: to.StringPtr(subnetSpec.CIDR),
 	}
+	if len(subnetSpec.ServiceEndpoints) > 0 {
+		subnetProperties.ServiceEndpoints = s.serviceEndpointsToSDK(subnetSpec.ServiceEndpoints)
+	}
+	if subnetSpec.HasPrivateEndpoints {
+		subnetProperties.PrivateEndpointNetworkPolicies = to.StringPtr(privateEndpointNetworkPoliciesDisabled)
+	}
 	if subnetSpec.RouteTableName != "" {
 		s.Scope.Logger.V(2).Info("getting route table", "route table", subnetSpec.RouteTableName)
 		rt, err := s.RouteTablesClient.Get(ctx, s.Scope.ResourceGroup(), subnetSpec.RouteTableName)
@@ -133,6 +174,75 @@ func (s *Service) Reconcile(ctx context.Context, spec interface{}) error {
 	return nil
 }
 
+// updateSubnet enables the service endpoints of the spec on an existing subnet, and disables its private endpoint
+// network policies if it has private endpoints.
+func (s *Service) updateSubnet(ctx context.Context, subnetSpec *Spec, subnet network.Subnet) error {
+	s.Scope.Logger.V(2).Info("updating subnet", "subnet", subnetSpec.Name)
+	if subnet.SubnetPropertiesFormat == nil {
+		subnet.SubnetPropertiesFormat = &network.SubnetPropertiesFormat{}
+	}
+	subnet.ServiceEndpoints = s.serviceEndpointsToSDK(subnetSpec.ServiceEndpoints)
+	if subnetSpec.HasPrivateEndpoints {
+		subnet.PrivateEndpointNetworkPolicies = to.StringPtr(privateEndpointNetworkPoliciesDisabled)
+	}
+	err := s.Client.CreateOrUpdate(ctx, s.Scope.Vnet().ResourceGroup, subnetSpec.VnetName, subnetSpec.Name, subnet)
+	if err != nil {
+		return errors.Wrapf(err, "failed to update subnet %s in resource group %s", subnetSpec.Name, s.Scope.Vnet().ResourceGroup)
+	}
+	s.Scope.Logger.V(2).Info("successfully updated subnet", "subnet", subnetSpec.Name)
+	return nil
+}
+
+// defaultServiceEndpoints returns the service endpoints with their locations defaulted to the cluster location.
+func (s *Service) defaultServiceEndpoints(endpoints []infrav1.ServiceEndpointSpec) []infrav1.ServiceEndpointSpec {
+	defaulted := make([]infrav1.ServiceEndpointSpec, 0, len(endpoints))
+	for _, endpoint := range endpoints {
+		if len(endpoint.Locations) == 0 {
+			endpoint.Locations = []string{s.Scope.Location()}
+		}
+		defaulted = append(defaulted, endpoint)
+	}
+	return defaulted
+}
+
+// serviceEndpointsToSDK converts service endpoints to their SDK representation, defaulting locations to the cluster location.
+func (s *Service) serviceEndpointsToSDK(endpoints []infrav1.ServiceEndpointSpec) *[]network.ServiceEndpointPropertiesFormat {
+	sdkEndpoints := make([]network.ServiceEndpointPropertiesFormat, 0, len(endpoints))
+	for _, endpoint := range s.defaultServiceEndpoints(endpoints) {
+		locations := endpoint.Locations
+		sdkEndpoints = append(sdkEndpoints, network.ServiceEndpointPropertiesFormat{
+			Service:   to.StringPtr(endpoint.Service),
+			Locations: &locations,
+		})
+	}
+	return &sdkEndpoints
+}
+
+// hasServiceEndpoints returns true if all the desired service endpoints are enabled for at least their desired
+// locations. Azure may add locations to a service endpoint, e.g. the paired region of a storage endpoint.
+func hasServiceEndpoints(existing, desired []infrav1.ServiceEndpointSpec) bool {
+	enabled := make(map[string]map[string]bool, len(existing))
+	for _, endpoint := range existing {
+		locations := make(map[string]bool, len(endpoint.Locations))
+		for _, location := range endpoint.Locations {
+			locations[strings.ToLower(location)] = true
+		}
+		enabled[strings.ToLower(endpoint.Service)] = locations
+	}
+	for _, endpoint := range desired {
+		locations, ok := enabled[strings.ToLower(endpoint.Service)]
+		if !ok {
+			return false
+		}
+		for _, location := range endpoint.Locations {
+			if !locations[strings.ToLower(location)] && !locations["*"] {
+				return false
+			}
+		}
+	}
+	return true
+}
+
 // Delete deletes the subnet with the provided name.
 func (s *Service) Delete(ctx context.Context, spec interface{}) error {
 	if !s.Scope.Vnet().IsManaged(s.Scope.ClusterName()) {
@@ -25,6 +25,7 @@ import (
 	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/routetables/mock_routetables"
 	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/securitygroups/mock_securitygroups"
 	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/subnets/mock_subnets"
+	"sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers"
 
 	"github.com/Azure/go-autorest/autorest"
 	"github.com/Azure/go-autorest/autorest/to"
@@ -102,6 +103,24 @@ func TestReconcileSubnets(t *testing.T) {
 					Return(network.Subnet{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
 			},
 		},
+		{
+			name: "managed vnet in another subscription and subnet does not exist",
+			subnetSpec: Spec{
+				Name:              "my-subnet",
+				CIDR:              "10.0.0.0/16",
+				VnetName:          "my-vnet",
+				RouteTableName:    "my-subnet_route_table",
+				SecurityGroupName: "my-sg",
+				Role:              infrav1.SubnetNode,
+			},
+			vnetSpec:      &infrav1.VnetSpec{ResourceGroup: "my-vnet-rg", Name: "my-vnet", SubscriptionID: "456"},
+			subnets:       []*infrav1.SubnetSpec{},
+			expectedError: "cannot create subnet my-subnet in vnet my-vnet of subscription 456: the provider only creates subnets in the cluster subscription",
+			expect: func(m *mock_subnets.MockClientMockRecorder, m1 *mock_routetables.MockClientMockRecorder, m2 *mock_securitygroups.MockClientMockRecorder) {
+				m.Get(context.TODO(), "my-vnet-rg", "my-vnet", "my-subnet").
+					Return(network.Subnet{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
+			},
+		},
 		{
 			name: "vnet was provided and subnet exists",
 			subnetSpec: Spec{
@@ -138,6 +157,159 @@ func TestReconcileSubnets(t *testing.T) {
 					}, nil)
 			},
 		},
+		{
+			name: "subnet exists without the service endpoints",
+			subnetSpec: Spec{
+				Name:              "my-subnet",
+				CIDR:              "10.0.0.0/16",
+				VnetName:          "my-vnet",
+				RouteTableName:    "my-subnet_route_table",
+				SecurityGroupName: "my-sg",
+				Role:              infrav1.SubnetNode,
+				ServiceEndpoints:  []infrav1.ServiceEndpointSpec{{Service: "Microsoft.Storage", Locations: []string{"westus2"}}},
+			},
+			vnetSpec: &infrav1.VnetSpec{Name: "my-vnet"},
+			subnets: []*infrav1.SubnetSpec{{
+				Name: "my-subnet",
+				Role: infrav1.SubnetNode,
+			}},
+			expectedError: "",
+			expect: func(m *mock_subnets.MockClientMockRecorder, m1 *mock_routetables.MockClientMockRecorder, m2 *mock_securitygroups.MockClientMockRecorder) {
+				existing := network.Subnet{
+					ID:   to.StringPtr("subnet-id"),
+					Name: to.StringPtr("my-subnet"),
+					SubnetPropertiesFormat: &network.SubnetPropertiesFormat{
+						AddressPrefix: to.StringPtr("10.0.0.0/16"),
+					},
+				}
+				m.Get(context.TODO(), "", "my-vnet", "my-subnet").Return(existing, nil)
+				m.CreateOrUpdate(context.TODO(), "", "my-vnet", "my-subnet", matchers.DiffEq(network.Subnet{
+					ID:   to.StringPtr("subnet-id"),
+					Name: to.StringPtr("my-subnet"),
+					SubnetPropertiesFormat: &network.SubnetPropertiesFormat{
+						AddressPrefix: to.StringPtr("10.0.0.0/16"),
+						ServiceEndpoints: &[]network.ServiceEndpointPropertiesFormat{
+							{
+								Service:   to.StringPtr("Microsoft.Storage"),
+								Locations: &[]string{"westus2"},
+							},
+						},
+					},
+				}))
+			},
+		},
+		{
+			name: "subnet exists with the service endpoint in another location",
+			subnetSpec: Spec{
+				Name:              "my-subnet",
+				CIDR:              "10.0.0.0/16",
+				VnetName:          "my-vnet",
+				SecurityGroupName: "my-sg",
+				Role:              infrav1.SubnetNode,
+				ServiceEndpoints:  []infrav1.ServiceEndpointSpec{{Service: "Microsoft.Storage", Locations: []string{"westus2"}}},
+			},
+			vnetSpec: &infrav1.VnetSpec{Name: "my-vnet"},
+			subnets: []*infrav1.SubnetSpec{{
+				Name: "my-subnet",
+				Role: infrav1.SubnetNode,
+			}},
+			expectedError: "",
+			expect: func(m *mock_subnets.MockClientMockRecorder, m1 *mock_routetables.MockClientMockRecorder, m2 *mock_securitygroups.MockClientMockRecorder) {
+				m.Get(context.TODO(), "", "my-vnet", "my-subnet").Return(network.Subnet{
+					ID:   to.StringPtr("subnet-id"),
+					Name: to.StringPtr("my-subnet"),
+					SubnetPropertiesFormat: &network.SubnetPropertiesFormat{
+						AddressPrefix: to.StringPtr("10.0.0.0/16"),
+						ServiceEndpoints: &[]network.ServiceEndpointPropertiesFormat{
+							{
+								Service:   to.StringPtr("Microsoft.Storage"),
+								Locations: &[]string{"eastus"},
+							},
+						},
+					},
+				}, nil)
+				m.CreateOrUpdate(context.TODO(), "", "my-vnet", "my-subnet", gomock.AssignableToTypeOf(network.Subnet{})).
+					Do(func(_ context.Context, _, _, _ string, subnet network.Subnet) {
+						g := NewWithT(t)
+						g.Expect(*subnet.ServiceEndpoints).To(Equal([]network.ServiceEndpointPropertiesFormat{
+							{
+								Service:   to.StringPtr("Microsoft.Storage"),
+								Locations: &[]string{"westus2"},
+							},
+						}))
+					})
+			},
+		},
+		{
+			name: "subnet exists with the service endpoints and private endpoint network policies disabled",
+			subnetSpec: Spec{
+				Name:                "my-subnet",
+				CIDR:                "10.0.0.0/16",
+				VnetName:            "my-vnet",
+				SecurityGroupName:   "my-sg",
+				Role:                infrav1.SubnetNode,
+				ServiceEndpoints:    []infrav1.ServiceEndpointSpec{{Service: "Microsoft.Storage", Locations: []string{"westus"}}},
+				HasPrivateEndpoints: true,
+			},
+			vnetSpec: &infrav1.VnetSpec{Name: "my-vnet"},
+			subnets: []*infrav1.SubnetSpec{{
+				Name: "my-subnet",
+				Role: infrav1.SubnetNode,
+			}},
+			expectedError: "",
+			expect: func(m *mock_subnets.MockClientMockRecorder, m1 *mock_routetables.MockClientMockRecorder, m2 *mock_securitygroups.MockClientMockRecorder) {
+				m.Get(context.TODO(), "", "my-vnet", "my-subnet").Return(network.Subnet{
+					ID:   to.StringPtr("subnet-id"),
+					Name: to.StringPtr("my-subnet"),
+					SubnetPropertiesFormat: &network.SubnetPropertiesFormat{
+						AddressPrefix: to.StringPtr("10.0.0.0/16"),
+						ServiceEndpoints: &[]network.ServiceEndpointPropertiesFormat{
+							{
+								Service:   to.StringPtr("Microsoft.Storage"),
+								Locations: &[]string{"westus", "eastus"},
+							},
+						},
+						PrivateEndpointNetworkPolicies: to.StringPtr("Disabled"),
+					},
+				}, nil)
+			},
+		},
+		{
+			name: "disable private endpoint network policies of existing subnet",
+			subnetSpec: Spec{
+				Name:                "my-subnet",
+				CIDR:                "10.0.0.0/16",
+				VnetName:            "my-vnet",
+				SecurityGroupName:   "my-sg",
+				Role:                infrav1.SubnetNode,
+				HasPrivateEndpoints: true,
+			},
+			vnetSpec: &infrav1.VnetSpec{Name: "my-vnet"},
+			subnets: []*infrav1.SubnetSpec{{
+				Name: "my-subnet",
+				Role: infrav1.SubnetNode,
+			}},
+			expectedError: "",
+			expect: func(m *mock_subnets.MockClientMockRecorder, m1 *mock_routetables.MockClientMockRecorder, m2 *mock_securitygroups.MockClientMockRecorder) {
+				m.Get(context.TODO(), "", "my-vnet", "my-subnet").Return(network.Subnet{
+					ID:   to.StringPtr("subnet-id"),
+					Name: to.StringPtr("my-subnet"),
+					SubnetPropertiesFormat: &network.SubnetPropertiesFormat{
+						AddressPrefix:                  to.StringPtr("10.0.0.0/16"),
+						PrivateEndpointNetworkPolicies: to.StringPtr("Enabled"),
+					},
+				}, nil)
+				m.CreateOrUpdate(context.TODO(), "", "my-vnet", "my-subnet", matchers.DiffEq(network.Subnet{
+					ID:   to.StringPtr("subnet-id"),
+					Name: to.StringPtr("my-subnet"),
+					SubnetPropertiesFormat: &network.SubnetPropertiesFormat{
+						AddressPrefix:                  to.StringPtr("10.0.0.0/16"),
+						ServiceEndpoints:               &[]network.ServiceEndpointPropertiesFormat{},
+						PrivateEndpointNetworkPolicies: to.StringPtr("Disabled"),
+					},
+				}))
+			},
+		},
 	}
 
 	for _, tc := range testcases {
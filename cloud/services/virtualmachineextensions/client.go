@@ -27,7 +27,9 @@ import (
 // Client wraps go-sdk
 type Client interface {
 	Get(context.Context, string, string, string) (compute.VirtualMachineExtension, error)
+	List(context.Context, string, string) ([]compute.VirtualMachineExtension, error)
 	CreateOrUpdate(context.Context, string, string, string, compute.VirtualMachineExtension) error
+	CreateOrUpdateAsync(context.Context, string, string, string, compute.VirtualMachineExtension) error
 	Delete(context.Context, string, string, string) error
 }
 
@@ -52,9 +54,21 @@ func newVirtualMachineExtensionsClient(subscriptionID string, baseURI string, au
 	return vmExtClient
 }
 
-// Get the operation to get the extension.
+// Get the operation to get the extension, with its instance view.
 func (ac *AzureClient) Get(ctx context.Context, resourceGroupName, vmName, extName string) (compute.VirtualMachineExtension, error) {
-	return ac.vmextensions.Get(ctx, resourceGroupName, vmName, extName, "")
+	return ac.vmextensions.Get(ctx, resourceGroupName, vmName, extName, "instanceView")
+}
+
+// List the operation to list the extensions of a VM.
+func (ac *AzureClient) List(ctx context.Context, resourceGroupName, vmName string) ([]compute.VirtualMachineExtension, error) {
+	result, err := ac.vmextensions.List(ctx, resourceGroupName, vmName, "")
+	if err != nil {
+		return nil, err
+	}
+	if result.Value == nil {
+		return nil, nil
+	}
+	return *result.Value, nil
 }
 
 // CreateOrUpdate the operation to create or update the extension.
@@ -71,6 +85,12 @@ func (ac *AzureClient) CreateOrUpdate(ctx context.Context, resourceGroupName, vm
 	return err
 }
 
+// CreateOrUpdateAsync starts the operation to create or update the extension, without waiting for its completion.
+func (ac *AzureClient) CreateOrUpdateAsync(ctx context.Context, resourceGroupName, vmName, extName string, ext compute.VirtualMachineExtension) error {
+	_, err := ac.vmextensions.CreateOrUpdate(ctx, resourceGroupName, vmName, extName, ext)
+	return err
+}
+
 // Delete the operation to delete the extension.
 func (ac *AzureClient) Delete(ctx context.Context, resourceGroupName, vmName, extName string) error {
 	future, err := ac.vmextensions.Delete(ctx, resourceGroupName, vmName, extName)
@@ -15,6 +15,8 @@ limitations under the License.
 */
 
 // Run go generate to regenerate this mock.
-//go:generate ../../../../hack/tools/bin/mockgen -destination virtualmachineextensions_mock.go -package mock_virtualmachineextensions -source ../client.go Client
+//go:generate ../../../../hack/tools/bin/mockgen -destination client_mock.go -package mock_virtualmachineextensions -source ../client.go Client
+//go:generate ../../../../hack/tools/bin/mockgen -destination virtualmachineextensions_mock.go -package mock_virtualmachineextensions -source ../service.go VMExtensionScope
+//go:generate /usr/bin/env bash -c "cat ../../../../hack/boilerplate/boilerplate.generatego.txt client_mock.go > _client_mock.go && mv _client_mock.go client_mock.go"
 //go:generate /usr/bin/env bash -c "cat ../../../../hack/boilerplate/boilerplate.generatego.txt virtualmachineextensions_mock.go > _virtualmachineextensions_mock.go && mv _virtualmachineextensions_mock.go virtualmachineextensions_mock.go"
 package mock_virtualmachineextensions //nolint
@@ -15,80 +15,366 @@ limitations under the License.
 */
 
 // Code generated by MockGen. DO NOT EDIT.
-// Source: ../client.go
+// Source: ../service.go
 
 // Package mock_virtualmachineextensions is a generated GoMock package.
 package mock_virtualmachineextensions
 
 import (
 	context "context"
-	compute "github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2019-12-01/compute"
+	autorest "github.com/Azure/go-autorest/autorest"
+	logr "github.com/go-logr/logr"
 	gomock "github.com/golang/mock/gomock"
 	reflect "reflect"
+	v1alpha3 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha3"
+	azure "sigs.k8s.io/cluster-api-provider-azure/cloud"
+	v1alpha30 "sigs.k8s.io/cluster-api/api/v1alpha3"
 )
 
-// MockClient is a mock of Client interface.
-type MockClient struct {
+// MockVMExtensionScope is a mock of VMExtensionScope interface.
+type MockVMExtensionScope struct {
 	ctrl     *gomock.Controller
-	recorder *MockClientMockRecorder
+	recorder *MockVMExtensionScopeMockRecorder
 }
 
-// MockClientMockRecorder is the mock recorder for MockClient.
-type MockClientMockRecorder struct {
-	mock *MockClient
+// MockVMExtensionScopeMockRecorder is the mock recorder for MockVMExtensionScope.
+type MockVMExtensionScopeMockRecorder struct {
+	mock *MockVMExtensionScope
 }
 
-// NewMockClient creates a new mock instance.
-func NewMockClient(ctrl *gomock.Controller) *MockClient {
-	mock := &MockClient{ctrl: ctrl}
-	mock.recorder = &MockClientMockRecorder{mock}
+// NewMockVMExtensionScope creates a new mock instance.
+func NewMockVMExtensionScope(ctrl *gomock.Controller) *MockVMExtensionScope {
+	mock := &MockVMExtensionScope{ctrl: ctrl}
+	mock.recorder = &MockVMExtensionScopeMockRecorder{mock}
 	return mock
 }
 
 // EXPECT returns an object that allows the caller to indicate expected use.
-func (m *MockClient) EXPECT() *MockClientMockRecorder {
+func (m *MockVMExtensionScope) EXPECT() *MockVMExtensionScopeMockRecorder {
 	return m.recorder
 }
 
-// Get mocks base method.
-func (m *MockClient) Get(arg0 context.Context, arg1, arg2, arg3 string) (compute.VirtualMachineExtension, error) {
+// SubscriptionID mocks base method.
+func (m *MockVMExtensionScope) SubscriptionID() string {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Get", arg0, arg1, arg2, arg3)
-	ret0, _ := ret[0].(compute.VirtualMachineExtension)
+	ret := m.ctrl.Call(m, "SubscriptionID")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// SubscriptionID indicates an expected call of SubscriptionID.
+func (mr *MockVMExtensionScopeMockRecorder) SubscriptionID() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubscriptionID", reflect.TypeOf((*MockVMExtensionScope)(nil).SubscriptionID))
+}
+
+// BaseURI mocks base method.
+func (m *MockVMExtensionScope) BaseURI() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BaseURI")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// BaseURI indicates an expected call of BaseURI.
+func (mr *MockVMExtensionScopeMockRecorder) BaseURI() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BaseURI", reflect.TypeOf((*MockVMExtensionScope)(nil).BaseURI))
+}
+
+// Authorizer mocks base method.
+func (m *MockVMExtensionScope) Authorizer() autorest.Authorizer {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Authorizer")
+	ret0, _ := ret[0].(autorest.Authorizer)
+	return ret0
+}
+
+// Authorizer indicates an expected call of Authorizer.
+func (mr *MockVMExtensionScopeMockRecorder) Authorizer() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Authorizer", reflect.TypeOf((*MockVMExtensionScope)(nil).Authorizer))
+}
+
+// ResourceGroup mocks base method.
+func (m *MockVMExtensionScope) ResourceGroup() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResourceGroup")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// ResourceGroup indicates an expected call of ResourceGroup.
+func (mr *MockVMExtensionScopeMockRecorder) ResourceGroup() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResourceGroup", reflect.TypeOf((*MockVMExtensionScope)(nil).ResourceGroup))
+}
+
+// ClusterName mocks base method.
+func (m *MockVMExtensionScope) ClusterName() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClusterName")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// ClusterName indicates an expected call of ClusterName.
+func (mr *MockVMExtensionScopeMockRecorder) ClusterName() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClusterName", reflect.TypeOf((*MockVMExtensionScope)(nil).ClusterName))
+}
+
+// Location mocks base method.
+func (m *MockVMExtensionScope) Location() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Location")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// Location indicates an expected call of Location.
+func (mr *MockVMExtensionScopeMockRecorder) Location() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Location", reflect.TypeOf((*MockVMExtensionScope)(nil).Location))
+}
+
+// AdditionalTags mocks base method.
+func (m *MockVMExtensionScope) AdditionalTags() v1alpha3.Tags {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AdditionalTags")
+	ret0, _ := ret[0].(v1alpha3.Tags)
+	return ret0
+}
+
+// AdditionalTags indicates an expected call of AdditionalTags.
+func (mr *MockVMExtensionScopeMockRecorder) AdditionalTags() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AdditionalTags", reflect.TypeOf((*MockVMExtensionScope)(nil).AdditionalTags))
+}
+
+// Vnet mocks base method.
+func (m *MockVMExtensionScope) Vnet() *v1alpha3.VnetSpec {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Vnet")
+	ret0, _ := ret[0].(*v1alpha3.VnetSpec)
+	return ret0
+}
+
+// Vnet indicates an expected call of Vnet.
+func (mr *MockVMExtensionScopeMockRecorder) Vnet() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Vnet", reflect.TypeOf((*MockVMExtensionScope)(nil).Vnet))
+}
+
+// NodeSubnet mocks base method.
+func (m *MockVMExtensionScope) NodeSubnet() *v1alpha3.SubnetSpec {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NodeSubnet")
+	ret0, _ := ret[0].(*v1alpha3.SubnetSpec)
+	return ret0
+}
+
+// NodeSubnet indicates an expected call of NodeSubnet.
+func (mr *MockVMExtensionScopeMockRecorder) NodeSubnet() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NodeSubnet", reflect.TypeOf((*MockVMExtensionScope)(nil).NodeSubnet))
+}
+
+// ControlPlaneSubnet mocks base method.
+func (m *MockVMExtensionScope) ControlPlaneSubnet() *v1alpha3.SubnetSpec {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ControlPlaneSubnet")
+	ret0, _ := ret[0].(*v1alpha3.SubnetSpec)
+	return ret0
+}
+
+// ControlPlaneSubnet indicates an expected call of ControlPlaneSubnet.
+func (mr *MockVMExtensionScopeMockRecorder) ControlPlaneSubnet() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ControlPlaneSubnet", reflect.TypeOf((*MockVMExtensionScope)(nil).ControlPlaneSubnet))
+}
+
+// Info mocks base method.
+func (m *MockVMExtensionScope) Info(msg string, keysAndValues ...interface{}) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{msg}
+	for _, a := range keysAndValues {
+		varargs = append(varargs, a)
+	}
+	m.ctrl.Call(m, "Info", varargs...)
+}
+
+// Info indicates an expected call of Info.
+func (mr *MockVMExtensionScopeMockRecorder) Info(msg interface{}, keysAndValues ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{msg}, keysAndValues...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Info", reflect.TypeOf((*MockVMExtensionScope)(nil).Info), varargs...)
+}
+
+// Enabled mocks base method.
+func (m *MockVMExtensionScope) Enabled() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Enabled")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// Enabled indicates an expected call of Enabled.
+func (mr *MockVMExtensionScopeMockRecorder) Enabled() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Enabled", reflect.TypeOf((*MockVMExtensionScope)(nil).Enabled))
+}
+
+// Error mocks base method.
+func (m *MockVMExtensionScope) Error(err error, msg string, keysAndValues ...interface{}) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{err, msg}
+	for _, a := range keysAndValues {
+		varargs = append(varargs, a)
+	}
+	m.ctrl.Call(m, "Error", varargs...)
+}
+
+// Error indicates an expected call of Error.
+func (mr *MockVMExtensionScopeMockRecorder) Error(err, msg interface{}, keysAndValues ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{err, msg}, keysAndValues...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Error", reflect.TypeOf((*MockVMExtensionScope)(nil).Error), varargs...)
+}
+
+// V mocks base method.
+func (m *MockVMExtensionScope) V(level int) logr.InfoLogger {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "V", level)
+	ret0, _ := ret[0].(logr.InfoLogger)
+	return ret0
+}
+
+// V indicates an expected call of V.
+func (mr *MockVMExtensionScopeMockRecorder) V(level interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "V", reflect.TypeOf((*MockVMExtensionScope)(nil).V), level)
+}
+
+// WithValues mocks base method.
+func (m *MockVMExtensionScope) WithValues(keysAndValues ...interface{}) logr.Logger {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{}
+	for _, a := range keysAndValues {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "WithValues", varargs...)
+	ret0, _ := ret[0].(logr.Logger)
+	return ret0
+}
+
+// WithValues indicates an expected call of WithValues.
+func (mr *MockVMExtensionScopeMockRecorder) WithValues(keysAndValues ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithValues", reflect.TypeOf((*MockVMExtensionScope)(nil).WithValues), keysAndValues...)
+}
+
+// WithName mocks base method.
+func (m *MockVMExtensionScope) WithName(name string) logr.Logger {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithName", name)
+	ret0, _ := ret[0].(logr.Logger)
+	return ret0
+}
+
+// WithName indicates an expected call of WithName.
+func (mr *MockVMExtensionScopeMockRecorder) WithName(name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithName", reflect.TypeOf((*MockVMExtensionScope)(nil).WithName), name)
+}
+
+// Name mocks base method.
+func (m *MockVMExtensionScope) Name() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Name")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// Name indicates an expected call of Name.
+func (mr *MockVMExtensionScopeMockRecorder) Name() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Name", reflect.TypeOf((*MockVMExtensionScope)(nil).Name))
+}
+
+// VMExtensionSpecs mocks base method.
+func (m *MockVMExtensionScope) VMExtensionSpecs(arg0 context.Context) ([]azure.VMExtensionSpec, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VMExtensionSpecs", arg0)
+	ret0, _ := ret[0].([]azure.VMExtensionSpec)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// Get indicates an expected call of Get.
-func (mr *MockClientMockRecorder) Get(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+// VMExtensionSpecs indicates an expected call of VMExtensionSpecs.
+func (mr *MockVMExtensionScopeMockRecorder) VMExtensionSpecs(arg0 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockClient)(nil).Get), arg0, arg1, arg2, arg3)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VMExtensionSpecs", reflect.TypeOf((*MockVMExtensionScope)(nil).VMExtensionSpecs), arg0)
 }
 
-// CreateOrUpdate mocks base method.
-func (m *MockClient) CreateOrUpdate(arg0 context.Context, arg1, arg2, arg3 string, arg4 compute.VirtualMachineExtension) error {
+// SetVMExtensionsReady mocks base method.
+func (m *MockVMExtensionScope) SetVMExtensionsReady() {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateOrUpdate", arg0, arg1, arg2, arg3, arg4)
-	ret0, _ := ret[0].(error)
-	return ret0
+	m.ctrl.Call(m, "SetVMExtensionsReady")
+}
+
+// SetVMExtensionsReady indicates an expected call of SetVMExtensionsReady.
+func (mr *MockVMExtensionScopeMockRecorder) SetVMExtensionsReady() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetVMExtensionsReady", reflect.TypeOf((*MockVMExtensionScope)(nil).SetVMExtensionsReady))
+}
+
+// SetVMExtensionsNotReady mocks base method.
+func (m *MockVMExtensionScope) SetVMExtensionsNotReady(reason string, severity v1alpha30.ConditionSeverity, message string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetVMExtensionsNotReady", reason, severity, message)
 }
 
-// CreateOrUpdate indicates an expected call of CreateOrUpdate.
-func (mr *MockClientMockRecorder) CreateOrUpdate(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+// SetVMExtensionsNotReady indicates an expected call of SetVMExtensionsNotReady.
+func (mr *MockVMExtensionScopeMockRecorder) SetVMExtensionsNotReady(reason, severity, message interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOrUpdate", reflect.TypeOf((*MockClient)(nil).CreateOrUpdate), arg0, arg1, arg2, arg3, arg4)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetVMExtensionsNotReady", reflect.TypeOf((*MockVMExtensionScope)(nil).SetVMExtensionsNotReady), reason, severity, message)
 }
 
-// Delete mocks base method.
-func (m *MockClient) Delete(arg0 context.Context, arg1, arg2, arg3 string) error {
+// BootstrapExtensionSpec mocks base method.
+func (m *MockVMExtensionScope) BootstrapExtensionSpec() *azure.VMExtensionSpec {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Delete", arg0, arg1, arg2, arg3)
-	ret0, _ := ret[0].(error)
+	ret := m.ctrl.Call(m, "BootstrapExtensionSpec")
+	ret0, _ := ret[0].(*azure.VMExtensionSpec)
 	return ret0
 }
 
-// Delete indicates an expected call of Delete.
-func (mr *MockClientMockRecorder) Delete(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+// BootstrapExtensionSpec indicates an expected call of BootstrapExtensionSpec.
+func (mr *MockVMExtensionScopeMockRecorder) BootstrapExtensionSpec() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BootstrapExtensionSpec", reflect.TypeOf((*MockVMExtensionScope)(nil).BootstrapExtensionSpec))
+}
+
+// SetBootstrapSucceeded mocks base method.
+func (m *MockVMExtensionScope) SetBootstrapSucceeded() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetBootstrapSucceeded")
+}
+
+// SetBootstrapSucceeded indicates an expected call of SetBootstrapSucceeded.
+func (mr *MockVMExtensionScopeMockRecorder) SetBootstrapSucceeded() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetBootstrapSucceeded", reflect.TypeOf((*MockVMExtensionScope)(nil).SetBootstrapSucceeded))
+}
+
+// SetBootstrapFailed mocks base method.
+func (m *MockVMExtensionScope) SetBootstrapFailed(message string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetBootstrapFailed", message)
+}
+
+// SetBootstrapFailed indicates an expected call of SetBootstrapFailed.
+func (mr *MockVMExtensionScopeMockRecorder) SetBootstrapFailed(message interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockClient)(nil).Delete), arg0, arg1, arg2, arg3)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetBootstrapFailed", reflect.TypeOf((*MockVMExtensionScope)(nil).SetBootstrapFailed), message)
 }
@@ -17,17 +17,34 @@ limitations under the License.
 package virtualmachineextensions
 
 import (
-	"sigs.k8s.io/cluster-api-provider-azure/cloud/scope"
+	"context"
+
+	"github.com/go-logr/logr"
+	azure "sigs.k8s.io/cluster-api-provider-azure/cloud"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha3"
 )
 
+// VMExtensionScope defines the scope interface for a VM extensions service.
+type VMExtensionScope interface {
+	azure.ClusterDescriber
+	logr.Logger
+	Name() string
+	VMExtensionSpecs(context.Context) ([]azure.VMExtensionSpec, error)
+	SetVMExtensionsReady()
+	SetVMExtensionsNotReady(reason string, severity clusterv1.ConditionSeverity, message string)
+	BootstrapExtensionSpec() *azure.VMExtensionSpec
+	SetBootstrapSucceeded()
+	SetBootstrapFailed(message string)
+}
+
 // Service provides operations on azure resources
 type Service struct {
-	Scope *scope.ClusterScope
+	Scope VMExtensionScope
 	Client
 }
 
 // NewService creates a new service.
-func NewService(scope *scope.ClusterScope) *Service {
+func NewService(scope VMExtensionScope) *Service {
 	return &Service{
 		Scope:  scope,
 		Client: NewClient(scope),
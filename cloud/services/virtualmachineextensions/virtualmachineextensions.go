@@ -0,0 +1,203 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package virtualmachineextensions
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2019-12-01/compute"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/pkg/errors"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha3"
+	azure "sigs.k8s.io/cluster-api-provider-azure/cloud"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/converters"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha3"
+	capierrors "sigs.k8s.io/cluster-api/errors"
+)
+
+// maxExtensionOutputLength is the maximum length of the extension output in a failure message.
+const maxExtensionOutputLength = 2048
+
+// Reconcile installs or updates the VM extensions of the scope, and uninstalls the extensions created by the
+// provider which are no longer in the scope.
+func (s *Service) Reconcile(ctx context.Context) error {
+	specs, err := s.Scope.VMExtensionSpecs(ctx)
+	if err != nil {
+		return err
+	}
+
+	vmName := s.Scope.Name()
+	existing, err := s.Client.List(ctx, s.Scope.ResourceGroup(), vmName)
+	if err != nil {
+		return errors.Wrapf(err, "failed to list extensions of VM %s", vmName)
+	}
+	existingByName := make(map[string]compute.VirtualMachineExtension, len(existing))
+	for _, extension := range existing {
+		existingByName[strings.ToLower(to.String(extension.Name))] = extension
+	}
+
+	wanted := make(map[string]bool, len(specs))
+	for _, spec := range specs {
+		wanted[strings.ToLower(spec.Name)] = true
+		if extension, ok := existingByName[strings.ToLower(spec.Name)]; ok && isUpToDate(extension, spec) {
+			continue
+		}
+
+		s.Scope.V(2).Info("creating VM extension", "vm", vmName, "extension", spec.Name)
+		err := s.Client.CreateOrUpdate(ctx, s.Scope.ResourceGroup(), vmName, spec.Name, s.extensionFromSpec(spec))
+		if err != nil {
+			s.Scope.SetVMExtensionsNotReady(infrav1.VMExtensionsFailedReason, clusterv1.ConditionSeverityError,
+				fmt.Sprintf("extension %s failed to provision: %s", spec.Name, err.Error()))
+			return errors.Wrapf(err, "failed to create extension %s on VM %s", spec.Name, vmName)
+		}
+		s.Scope.V(2).Info("successfully created VM extension", "vm", vmName, "extension", spec.Name)
+	}
+
+	for name, extension := range existingByName {
+		if wanted[name] || strings.EqualFold(name, azure.BootstrapExtensionName) || !converters.MapToTags(extension.Tags).HasOwned(s.Scope.ClusterName()) {
+			continue
+		}
+		s.Scope.V(2).Info("deleting VM extension", "vm", vmName, "extension", to.String(extension.Name))
+		err := s.Client.Delete(ctx, s.Scope.ResourceGroup(), vmName, to.String(extension.Name))
+		if err != nil && !azure.ResourceNotFound(err) {
+			return errors.Wrapf(err, "failed to delete extension %s of VM %s", to.String(extension.Name), vmName)
+		}
+	}
+
+	s.Scope.SetVMExtensionsReady()
+	return nil
+}
+
+// ReconcileBootstrap starts the extension reporting the result of the bootstrap of the VM, and returns true once the
+// bootstrap succeeded or if it isn't tracked. The extension runs until the bootstrap completes, so it isn't waited
+// for: its provisioning state is checked again by later calls. It returns a terminal error with the output of the
+// extension when the bootstrap failed.
+func (s *Service) ReconcileBootstrap(ctx context.Context) (bool, error) {
+	spec := s.Scope.BootstrapExtensionSpec()
+	if spec == nil {
+		return true, nil
+	}
+
+	vmName := s.Scope.Name()
+	extension, err := s.Client.Get(ctx, s.Scope.ResourceGroup(), vmName, spec.Name)
+	if azure.ResourceNotFound(err) {
+		s.Scope.V(2).Info("starting bootstrap extension", "vm", vmName, "extension", spec.Name)
+		if err := s.Client.CreateOrUpdateAsync(ctx, s.Scope.ResourceGroup(), vmName, spec.Name, s.extensionFromSpec(*spec)); err != nil {
+			return false, errors.Wrapf(err, "failed to create extension %s on VM %s", spec.Name, vmName)
+		}
+		return false, nil
+	}
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to get extension %s of VM %s", spec.Name, vmName)
+	}
+
+	var state string
+	if extension.VirtualMachineExtensionProperties != nil {
+		state = to.String(extension.ProvisioningState)
+	}
+	switch state {
+	case string(infrav1.VMStateSucceeded):
+		s.Scope.SetBootstrapSucceeded()
+		return true, nil
+	case string(infrav1.VMStateFailed):
+		message := fmt.Sprintf("VM %s failed to bootstrap", vmName)
+		if output := extensionOutput(extension); output != "" {
+			message = fmt.Sprintf("%s: %s", message, output)
+		}
+		s.Scope.SetBootstrapFailed(message)
+		return false, azure.NewTerminalError(capierrors.CreateMachineError, errors.New(message))
+	default:
+		s.Scope.V(2).Info("waiting for bootstrap extension", "vm", vmName, "extension", spec.Name, "state", state)
+		return false, nil
+	}
+}
+
+// extensionOutput returns the messages of the instance view of an extension, which hold the output of a custom
+// script, limited to its last maxExtensionOutputLength characters.
+func extensionOutput(extension compute.VirtualMachineExtension) string {
+	if extension.InstanceView == nil {
+		return ""
+	}
+	var messages []string
+	for _, statuses := range []*[]compute.InstanceViewStatus{extension.InstanceView.Statuses, extension.InstanceView.Substatuses} {
+		if statuses == nil {
+			continue
+		}
+		for _, status := range *statuses {
+			if message := strings.TrimSpace(to.String(status.Message)); message != "" {
+				messages = append(messages, message)
+			}
+		}
+	}
+	output := strings.Join(messages, "\n")
+	if len(output) > maxExtensionOutputLength {
+		output = output[len(output)-maxExtensionOutputLength:]
+	}
+	return output
+}
+
+// Delete is a no-op, the extensions of a VM are deleted along with the VM.
+func (s *Service) Delete(ctx context.Context) error {
+	return nil
+}
+
+// extensionFromSpec returns the VM extension to create from its spec.
+func (s *Service) extensionFromSpec(spec azure.VMExtensionSpec) compute.VirtualMachineExtension {
+	extension := compute.VirtualMachineExtension{
+		Location: to.StringPtr(s.Scope.Location()),
+		Tags: converters.TagsToMap(infrav1.Build(infrav1.BuildParams{
+			ClusterName: s.Scope.ClusterName(),
+			Lifecycle:   infrav1.ResourceLifecycleOwned,
+			Name:        to.StringPtr(spec.Name),
+			Additional:  s.Scope.AdditionalTags(),
+		})),
+		VirtualMachineExtensionProperties: &compute.VirtualMachineExtensionProperties{
+			Publisher:          to.StringPtr(spec.Publisher),
+			Type:               to.StringPtr(spec.Type),
+			TypeHandlerVersion: to.StringPtr(spec.Version),
+		},
+	}
+	if len(spec.Settings) > 0 {
+		extension.Settings = spec.Settings
+	}
+	if len(spec.ProtectedSettings) > 0 {
+		extension.ProtectedSettings = spec.ProtectedSettings
+	}
+	return extension
+}
+
+// isUpToDate returns true if an existing extension is provisioned with the publisher, type, version and settings of
+// its spec. Protected settings are never returned by Azure and can't be compared.
+func isUpToDate(extension compute.VirtualMachineExtension, spec azure.VMExtensionSpec) bool {
+	props := extension.VirtualMachineExtensionProperties
+	if props == nil || to.String(props.ProvisioningState) != string(infrav1.VMStateSucceeded) {
+		return false
+	}
+	if !strings.EqualFold(to.String(props.Publisher), spec.Publisher) || !strings.EqualFold(to.String(props.Type), spec.Type) ||
+		to.String(props.TypeHandlerVersion) != spec.Version {
+		return false
+	}
+
+	existingSettings, _ := props.Settings.(map[string]interface{})
+	if len(existingSettings) == 0 && len(spec.Settings) == 0 {
+		return true
+	}
+	return reflect.DeepEqual(existingSettings, spec.Settings)
+}
@@ -0,0 +1,296 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package virtualmachineextensions
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2019-12-01/compute"
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+	"k8s.io/klog/klogr"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha3"
+	azure "sigs.k8s.io/cluster-api-provider-azure/cloud"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/virtualmachineextensions/mock_virtualmachineextensions"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha3"
+)
+
+func TestReconcileVMExtensions(t *testing.T) {
+	scriptSpec := azure.VMExtensionSpec{
+		Name:      "my-script",
+		VMName:    "my-vm",
+		Publisher: "Microsoft.Azure.Extensions",
+		Type:      "CustomScript",
+		Version:   "2.1",
+		Settings:  map[string]interface{}{"commandToExecute": "echo hello"},
+	}
+	ownedTags := map[string]*string{
+		"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": to.StringPtr("owned"),
+	}
+	provisionedScript := compute.VirtualMachineExtension{
+		Name: to.StringPtr("my-script"),
+		Tags: ownedTags,
+		VirtualMachineExtensionProperties: &compute.VirtualMachineExtensionProperties{
+			Publisher:          to.StringPtr("Microsoft.Azure.Extensions"),
+			Type:               to.StringPtr("CustomScript"),
+			TypeHandlerVersion: to.StringPtr("2.1"),
+			Settings:           map[string]interface{}{"commandToExecute": "echo hello"},
+			ProvisioningState:  to.StringPtr("Succeeded"),
+		},
+	}
+
+	testcases := []struct {
+		name          string
+		expectedError string
+		expect        func(s *mock_virtualmachineextensions.MockVMExtensionScopeMockRecorder, m *mock_virtualmachineextensions.MockClientMockRecorder)
+	}{
+		{
+			name:          "create VM extension",
+			expectedError: "",
+			expect: func(s *mock_virtualmachineextensions.MockVMExtensionScopeMockRecorder, m *mock_virtualmachineextensions.MockClientMockRecorder) {
+				s.VMExtensionSpecs(context.TODO()).Return([]azure.VMExtensionSpec{scriptSpec}, nil)
+				m.List(context.TODO(), "my-rg", "my-vm")
+				m.CreateOrUpdate(context.TODO(), "my-rg", "my-vm", "my-script", compute.VirtualMachineExtension{
+					Location: to.StringPtr("test-location"),
+					Tags: map[string]*string{
+						"Name": to.StringPtr("my-script"),
+						"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": to.StringPtr("owned"),
+					},
+					VirtualMachineExtensionProperties: &compute.VirtualMachineExtensionProperties{
+						Publisher:          to.StringPtr("Microsoft.Azure.Extensions"),
+						Type:               to.StringPtr("CustomScript"),
+						TypeHandlerVersion: to.StringPtr("2.1"),
+						Settings:           map[string]interface{}{"commandToExecute": "echo hello"},
+					},
+				})
+				s.SetVMExtensionsReady()
+			},
+		},
+		{
+			name:          "VM extension is up to date",
+			expectedError: "",
+			expect: func(s *mock_virtualmachineextensions.MockVMExtensionScopeMockRecorder, m *mock_virtualmachineextensions.MockClientMockRecorder) {
+				s.VMExtensionSpecs(context.TODO()).Return([]azure.VMExtensionSpec{scriptSpec}, nil)
+				m.List(context.TODO(), "my-rg", "my-vm").Return([]compute.VirtualMachineExtension{provisionedScript}, nil)
+				s.SetVMExtensionsReady()
+			},
+		},
+		{
+			name:          "update VM extension with new settings",
+			expectedError: "",
+			expect: func(s *mock_virtualmachineextensions.MockVMExtensionScopeMockRecorder, m *mock_virtualmachineextensions.MockClientMockRecorder) {
+				updatedSpec := scriptSpec
+				updatedSpec.Settings = map[string]interface{}{"commandToExecute": "echo world"}
+				s.VMExtensionSpecs(context.TODO()).Return([]azure.VMExtensionSpec{updatedSpec}, nil)
+				m.List(context.TODO(), "my-rg", "my-vm").Return([]compute.VirtualMachineExtension{provisionedScript}, nil)
+				m.CreateOrUpdate(context.TODO(), "my-rg", "my-vm", "my-script", gomock.AssignableToTypeOf(compute.VirtualMachineExtension{}))
+				s.SetVMExtensionsReady()
+			},
+		},
+		{
+			name:          "delete VM extension removed from the spec",
+			expectedError: "",
+			expect: func(s *mock_virtualmachineextensions.MockVMExtensionScopeMockRecorder, m *mock_virtualmachineextensions.MockClientMockRecorder) {
+				s.VMExtensionSpecs(context.TODO()).Return(nil, nil)
+				m.List(context.TODO(), "my-rg", "my-vm").Return([]compute.VirtualMachineExtension{
+					provisionedScript,
+					{Name: to.StringPtr("MicrosoftMonitoringAgent")},
+				}, nil)
+				m.Delete(context.TODO(), "my-rg", "my-vm", "my-script")
+				s.SetVMExtensionsReady()
+			},
+		},
+		{
+			name:          "bootstrap extension is not deleted",
+			expectedError: "",
+			expect: func(s *mock_virtualmachineextensions.MockVMExtensionScopeMockRecorder, m *mock_virtualmachineextensions.MockClientMockRecorder) {
+				s.VMExtensionSpecs(context.TODO()).Return(nil, nil)
+				m.List(context.TODO(), "my-rg", "my-vm").Return([]compute.VirtualMachineExtension{
+					{Name: to.StringPtr(azure.BootstrapExtensionName), Tags: ownedTags},
+				}, nil)
+				s.SetVMExtensionsReady()
+			},
+		},
+		{
+			name:          "VM extension fails to provision",
+			expectedError: "failed to create extension my-script on VM my-vm: #: Conflict: StatusCode=409",
+			expect: func(s *mock_virtualmachineextensions.MockVMExtensionScopeMockRecorder, m *mock_virtualmachineextensions.MockClientMockRecorder) {
+				s.VMExtensionSpecs(context.TODO()).Return([]azure.VMExtensionSpec{scriptSpec}, nil)
+				m.List(context.TODO(), "my-rg", "my-vm")
+				m.CreateOrUpdate(context.TODO(), "my-rg", "my-vm", "my-script", gomock.AssignableToTypeOf(compute.VirtualMachineExtension{})).Return(autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 409}, "Conflict"))
+				s.SetVMExtensionsNotReady(infrav1.VMExtensionsFailedReason, clusterv1.ConditionSeverityError, "extension my-script failed to provision: #: Conflict: StatusCode=409")
+			},
+		},
+		{
+			name:          "fail to list VM extensions",
+			expectedError: "failed to list extensions of VM my-vm: #: Internal Server Error: StatusCode=500",
+			expect: func(s *mock_virtualmachineextensions.MockVMExtensionScopeMockRecorder, m *mock_virtualmachineextensions.MockClientMockRecorder) {
+				s.VMExtensionSpecs(context.TODO()).Return([]azure.VMExtensionSpec{scriptSpec}, nil)
+				m.List(context.TODO(), "my-rg", "my-vm").Return(nil, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error"))
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			scopeMock := mock_virtualmachineextensions.NewMockVMExtensionScope(mockCtrl)
+			clientMock := mock_virtualmachineextensions.NewMockClient(mockCtrl)
+
+			scopeMock.EXPECT().Name().AnyTimes().Return("my-vm")
+			scopeMock.EXPECT().ResourceGroup().AnyTimes().Return("my-rg")
+			scopeMock.EXPECT().Location().AnyTimes().Return("test-location")
+			scopeMock.EXPECT().ClusterName().AnyTimes().Return("my-cluster")
+			scopeMock.EXPECT().AdditionalTags().AnyTimes().Return(infrav1.Tags{})
+			scopeMock.EXPECT().V(gomock.Any()).AnyTimes().Return(klogr.New())
+			tc.expect(scopeMock.EXPECT(), clientMock.EXPECT())
+
+			s := &Service{
+				Scope:  scopeMock,
+				Client: clientMock,
+			}
+
+			err := s.Reconcile(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestReconcileBootstrap(t *testing.T) {
+	bootstrapSpec := azure.GetBootstrapExtension("my-vm")
+	extension := func(state string) compute.VirtualMachineExtension {
+		return compute.VirtualMachineExtension{
+			Name: to.StringPtr(azure.BootstrapExtensionName),
+			VirtualMachineExtensionProperties: &compute.VirtualMachineExtensionProperties{
+				ProvisioningState: to.StringPtr(state),
+			},
+		}
+	}
+	failedExtension := extension("Failed")
+	failedExtension.InstanceView = &compute.VirtualMachineExtensionInstanceView{
+		Statuses:    &[]compute.InstanceViewStatus{{Message: to.StringPtr("Enable failed")}},
+		Substatuses: &[]compute.InstanceViewStatus{{Message: to.StringPtr("")}, {Message: to.StringPtr("bootstrap sentinel file not found\n")}},
+	}
+
+	testcases := []struct {
+		name                 string
+		expectedBootstrapped bool
+		expectedError        string
+		terminal             bool
+		expect               func(s *mock_virtualmachineextensions.MockVMExtensionScopeMockRecorder, m *mock_virtualmachineextensions.MockClientMockRecorder)
+	}{
+		{
+			name:                 "bootstrap not tracked",
+			expectedBootstrapped: true,
+			expect: func(s *mock_virtualmachineextensions.MockVMExtensionScopeMockRecorder, m *mock_virtualmachineextensions.MockClientMockRecorder) {
+				s.BootstrapExtensionSpec().Return(nil)
+			},
+		},
+		{
+			name: "start bootstrap extension",
+			expect: func(s *mock_virtualmachineextensions.MockVMExtensionScopeMockRecorder, m *mock_virtualmachineextensions.MockClientMockRecorder) {
+				s.BootstrapExtensionSpec().Return(&bootstrapSpec)
+				m.Get(context.TODO(), "my-rg", "my-vm", azure.BootstrapExtensionName).Return(compute.VirtualMachineExtension{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
+				m.CreateOrUpdateAsync(context.TODO(), "my-rg", "my-vm", azure.BootstrapExtensionName, gomock.AssignableToTypeOf(compute.VirtualMachineExtension{}))
+			},
+		},
+		{
+			name: "bootstrap in progress",
+			expect: func(s *mock_virtualmachineextensions.MockVMExtensionScopeMockRecorder, m *mock_virtualmachineextensions.MockClientMockRecorder) {
+				s.BootstrapExtensionSpec().Return(&bootstrapSpec)
+				m.Get(context.TODO(), "my-rg", "my-vm", azure.BootstrapExtensionName).Return(extension("Creating"), nil)
+			},
+		},
+		{
+			name:                 "bootstrap succeeded",
+			expectedBootstrapped: true,
+			expect: func(s *mock_virtualmachineextensions.MockVMExtensionScopeMockRecorder, m *mock_virtualmachineextensions.MockClientMockRecorder) {
+				s.BootstrapExtensionSpec().Return(&bootstrapSpec)
+				m.Get(context.TODO(), "my-rg", "my-vm", azure.BootstrapExtensionName).Return(extension("Succeeded"), nil)
+				s.SetBootstrapSucceeded()
+			},
+		},
+		{
+			name:          "bootstrap failed",
+			expectedError: "VM my-vm failed to bootstrap: Enable failed\nbootstrap sentinel file not found",
+			terminal:      true,
+			expect: func(s *mock_virtualmachineextensions.MockVMExtensionScopeMockRecorder, m *mock_virtualmachineextensions.MockClientMockRecorder) {
+				s.BootstrapExtensionSpec().Return(&bootstrapSpec)
+				m.Get(context.TODO(), "my-rg", "my-vm", azure.BootstrapExtensionName).Return(failedExtension, nil)
+				s.SetBootstrapFailed("VM my-vm failed to bootstrap: Enable failed\nbootstrap sentinel file not found")
+			},
+		},
+		{
+			name:          "fail to start bootstrap extension",
+			expectedError: "failed to create extension CAPZ.Linux.Bootstrapping on VM my-vm: #: Too Many Requests: StatusCode=429",
+			expect: func(s *mock_virtualmachineextensions.MockVMExtensionScopeMockRecorder, m *mock_virtualmachineextensions.MockClientMockRecorder) {
+				s.BootstrapExtensionSpec().Return(&bootstrapSpec)
+				m.Get(context.TODO(), "my-rg", "my-vm", azure.BootstrapExtensionName).Return(compute.VirtualMachineExtension{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
+				m.CreateOrUpdateAsync(context.TODO(), "my-rg", "my-vm", azure.BootstrapExtensionName, gomock.AssignableToTypeOf(compute.VirtualMachineExtension{})).Return(autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 429}, "Too Many Requests"))
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			scopeMock := mock_virtualmachineextensions.NewMockVMExtensionScope(mockCtrl)
+			clientMock := mock_virtualmachineextensions.NewMockClient(mockCtrl)
+
+			scopeMock.EXPECT().Name().AnyTimes().Return("my-vm")
+			scopeMock.EXPECT().ResourceGroup().AnyTimes().Return("my-rg")
+			scopeMock.EXPECT().Location().AnyTimes().Return("test-location")
+			scopeMock.EXPECT().ClusterName().AnyTimes().Return("my-cluster")
+			scopeMock.EXPECT().AdditionalTags().AnyTimes().Return(infrav1.Tags{})
+			scopeMock.EXPECT().V(gomock.Any()).AnyTimes().Return(klogr.New())
+			tc.expect(scopeMock.EXPECT(), clientMock.EXPECT())
+
+			s := &Service{
+				Scope:  scopeMock,
+				Client: clientMock,
+			}
+
+			bootstrapped, err := s.ReconcileBootstrap(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+				_, terminal := azure.IsTerminalError(err)
+				g.Expect(terminal).To(Equal(tc.terminal))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+			g.Expect(bootstrapped).To(Equal(tc.expectedBootstrapped))
+		})
+	}
+}
@@ -29,6 +29,12 @@ type Client interface {
 	Get(context.Context, string, string) (compute.VirtualMachine, error)
 	CreateOrUpdate(context.Context, string, string, compute.VirtualMachine) error
 	Delete(context.Context, string, string) error
+	InstanceView(context.Context, string, string) (compute.VirtualMachineInstanceView, error)
+	Start(context.Context, string, string) error
+	StartAsync(context.Context, string, string) error
+	DeallocateAsync(context.Context, string, string) error
+	UpdateAsync(context.Context, string, string, compute.VirtualMachineUpdate) error
+	ListAvailableSizes(context.Context, string, string) ([]compute.VirtualMachineSize, error)
 }
 
 // AzureClient contains the Azure go-sdk Client
@@ -84,3 +90,54 @@ func (ac *AzureClient) Delete(ctx context.Context, resourceGroupName, vmName str
 	_, err = future.Result(ac.virtualmachines)
 	return err
 }
+
+// InstanceView retrieves the run-time state of a virtual machine.
+func (ac *AzureClient) InstanceView(ctx context.Context, resourceGroupName, vmName string) (compute.VirtualMachineInstanceView, error) {
+	return ac.virtualmachines.InstanceView(ctx, resourceGroupName, vmName)
+}
+
+// Start starts a virtual machine.
+func (ac *AzureClient) Start(ctx context.Context, resourceGroupName, vmName string) error {
+	future, err := ac.virtualmachines.Start(ctx, resourceGroupName, vmName)
+	if err != nil {
+		return err
+	}
+	err = future.WaitForCompletionRef(ctx, ac.virtualmachines.Client)
+	if err != nil {
+		return err
+	}
+	_, err = future.Result(ac.virtualmachines)
+	return err
+}
+
+// StartAsync starts the operation to start a virtual machine, without waiting for its completion.
+func (ac *AzureClient) StartAsync(ctx context.Context, resourceGroupName, vmName string) error {
+	_, err := ac.virtualmachines.Start(ctx, resourceGroupName, vmName)
+	return err
+}
+
+// DeallocateAsync starts the operation to stop a virtual machine and release its compute resources, without waiting
+// for its completion.
+func (ac *AzureClient) DeallocateAsync(ctx context.Context, resourceGroupName, vmName string) error {
+	_, err := ac.virtualmachines.Deallocate(ctx, resourceGroupName, vmName)
+	return err
+}
+
+// UpdateAsync starts the operation to update the properties of a virtual machine, without waiting for its completion.
+func (ac *AzureClient) UpdateAsync(ctx context.Context, resourceGroupName, vmName string, parameters compute.VirtualMachineUpdate) error {
+	_, err := ac.virtualmachines.Update(ctx, resourceGroupName, vmName, parameters)
+	return err
+}
+
+// ListAvailableSizes lists the sizes a virtual machine can be resized to on its current hardware cluster, or in its
+// location when it is deallocated.
+func (ac *AzureClient) ListAvailableSizes(ctx context.Context, resourceGroupName, vmName string) ([]compute.VirtualMachineSize, error) {
+	result, err := ac.virtualmachines.ListAvailableSizes(ctx, resourceGroupName, vmName)
+	if err != nil {
+		return nil, err
+	}
+	if result.Value == nil {
+		return nil, nil
+	}
+	return *result.Value, nil
+}
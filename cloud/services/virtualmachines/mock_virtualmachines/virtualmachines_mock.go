@@ -92,3 +92,89 @@ func (mr *MockClientMockRecorder) Delete(arg0, arg1, arg2 interface{}) *gomock.C
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockClient)(nil).Delete), arg0, arg1, arg2)
 }
+
+// InstanceView mocks base method.
+func (m *MockClient) InstanceView(arg0 context.Context, arg1, arg2 string) (compute.VirtualMachineInstanceView, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InstanceView", arg0, arg1, arg2)
+	ret0, _ := ret[0].(compute.VirtualMachineInstanceView)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InstanceView indicates an expected call of InstanceView.
+func (mr *MockClientMockRecorder) InstanceView(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InstanceView", reflect.TypeOf((*MockClient)(nil).InstanceView), arg0, arg1, arg2)
+}
+
+// Start mocks base method.
+func (m *MockClient) Start(arg0 context.Context, arg1, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Start", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Start indicates an expected call of Start.
+func (mr *MockClientMockRecorder) Start(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Start", reflect.TypeOf((*MockClient)(nil).Start), arg0, arg1, arg2)
+}
+
+// StartAsync mocks base method.
+func (m *MockClient) StartAsync(arg0 context.Context, arg1, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StartAsync", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// StartAsync indicates an expected call of StartAsync.
+func (mr *MockClientMockRecorder) StartAsync(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartAsync", reflect.TypeOf((*MockClient)(nil).StartAsync), arg0, arg1, arg2)
+}
+
+// DeallocateAsync mocks base method.
+func (m *MockClient) DeallocateAsync(arg0 context.Context, arg1, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeallocateAsync", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeallocateAsync indicates an expected call of DeallocateAsync.
+func (mr *MockClientMockRecorder) DeallocateAsync(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeallocateAsync", reflect.TypeOf((*MockClient)(nil).DeallocateAsync), arg0, arg1, arg2)
+}
+
+// UpdateAsync mocks base method.
+func (m *MockClient) UpdateAsync(arg0 context.Context, arg1, arg2 string, arg3 compute.VirtualMachineUpdate) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateAsync", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateAsync indicates an expected call of UpdateAsync.
+func (mr *MockClientMockRecorder) UpdateAsync(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAsync", reflect.TypeOf((*MockClient)(nil).UpdateAsync), arg0, arg1, arg2, arg3)
+}
+
+// ListAvailableSizes mocks base method.
+func (m *MockClient) ListAvailableSizes(arg0 context.Context, arg1, arg2 string) ([]compute.VirtualMachineSize, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAvailableSizes", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]compute.VirtualMachineSize)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAvailableSizes indicates an expected call of ListAvailableSizes.
+func (mr *MockClientMockRecorder) ListAvailableSizes(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAvailableSizes", reflect.TypeOf((*MockClient)(nil).ListAvailableSizes), arg0, arg1, arg2)
+}
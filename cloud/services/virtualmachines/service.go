@@ -20,6 +20,7 @@ import (
 	"sigs.k8s.io/cluster-api-provider-azure/cloud/scope"
 	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/networkinterfaces"
 	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/publicips"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/resourceskus"
 	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/roleassignments"
 )
 
@@ -31,6 +32,7 @@ type Service struct {
 	InterfacesClient      networkinterfaces.Client
 	PublicIPsClient       publicips.Client
 	RoleAssignmentsClient roleassignments.Client
+	ResourceSkusClient    resourceskus.Client
 }
 
 // NewService creates a new service.
@@ -42,5 +44,6 @@ func NewService(scope *scope.ClusterScope, machineScope *scope.MachineScope) *Se
 		InterfacesClient:      networkinterfaces.NewClient(scope),
 		PublicIPsClient:       publicips.NewClient(scope),
 		RoleAssignmentsClient: roleassignments.NewClient(scope),
+		ResourceSkusClient:    resourceskus.NewClient(scope),
 	}
 }
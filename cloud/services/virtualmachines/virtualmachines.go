@@ -21,7 +21,6 @@ import (
 	"crypto/rand"
 	"encoding/base64"
 	"fmt"
-	"strconv"
 	"strings"
 
 	"github.com/Azure/azure-sdk-for-go/profiles/2019-03-01/authorization/mgmt/authorization"
@@ -34,14 +33,27 @@ import (
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha3"
 	azure "sigs.k8s.io/cluster-api-provider-azure/cloud"
 	"sigs.k8s.io/cluster-api-provider-azure/cloud/converters"
+	capierrors "sigs.k8s.io/cluster-api/errors"
 )
 
 const azureBuiltInContributorID = "b24988ac-6180-42a0-ab88-20f7382dd24c"
 
+const (
+	// PowerStateDeallocating is the power state of a VM being stopped and deallocated.
+	PowerStateDeallocating = "deallocating"
+	// PowerStateDeallocated is the power state of a stopped and deallocated VM.
+	PowerStateDeallocated = "deallocated"
+	// PowerStateStarting is the power state of a VM being started.
+	PowerStateStarting = "starting"
+)
+
+// ErrVMSizeNotAvailable is returned when a VM can't be resized as its new size is not offered in its location or zone.
+var ErrVMSizeNotAvailable = errors.New("VM size is not available")
+
 // Spec input specification for Get/CreateOrUpdate/Delete calls
 type Spec struct {
 	Name                   string
-	NICName                string
+	NICNames               []string
 	SSHKeyData             string
 	Size                   string
 	Zone                   string
@@ -51,6 +63,13 @@ type Spec struct {
 	CustomData             string
 	UserAssignedIdentities []infrav1.UserAssignedIdentity
 	SpotVMOptions          *infrav1.SpotVMOptions
+	// ProximityPlacementGroupID is the ID of the proximity placement group of the VM, if any.
+	ProximityPlacementGroupID string
+	// DedicatedHostID is the ID of the dedicated host to place the VM on, if any.
+	DedicatedHostID string
+	// BootDiagnosticsStorageURI is the blob endpoint of the storage account holding the boot diagnostics of the VM.
+	// Boot diagnostics are disabled when it is empty.
+	BootDiagnosticsStorageURI string
 }
 
 // Get provides information about a virtual machine.
@@ -77,6 +96,92 @@ func (s *Service) Get(ctx context.Context, vmSpec *Spec) (*infrav1.VM, error) {
 	return convertedVM, nil
 }
 
+// GetPowerState returns the power state of a virtual machine, e.g. running or deallocated, or an empty string if it
+// is unknown.
+func (s *Service) GetPowerState(ctx context.Context, vmSpec *Spec) (string, error) {
+	instanceView, err := s.Client.InstanceView(ctx, s.Scope.ResourceGroup(), vmSpec.Name)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to get instance view of VM %s", vmSpec.Name)
+	}
+	return converters.GetPowerState(instanceView.Statuses), nil
+}
+
+// Resize starts changing the size of an existing virtual machine to the size of the spec, without waiting for its
+// completion. The VM is resized in place when the new size is available on its current hardware cluster. Otherwise,
+// provided the new size is offered in its location and zone, the VM is deallocated and Resize returns true: the VM
+// must then be resized with UpdateSize and started again with StartAsync once it is deallocated. Errors that retrying
+// won't fix are terminal errors.
+func (s *Service) Resize(ctx context.Context, vmSpec *Spec) (bool, error) {
+	sizes, err := s.Client.ListAvailableSizes(ctx, s.Scope.ResourceGroup(), vmSpec.Name)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to list available sizes of VM %s", vmSpec.Name)
+	}
+	if hasVMSize(sizes, vmSpec.Size) {
+		s.Scope.Logger.V(2).Info("resizing VM", "vm", vmSpec.Name, "size", vmSpec.Size)
+		return false, s.UpdateSize(ctx, vmSpec)
+	}
+
+	available, err := s.ResourceSkusClient.IsVMSizeAvailable(ctx, s.Scope.Location(), vmSpec.Zone, vmSpec.Size)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to check availability of VM size %s", vmSpec.Size)
+	}
+	if !available {
+		where := fmt.Sprintf("location %s", s.Scope.Location())
+		if vmSpec.Zone != "" {
+			where = fmt.Sprintf("zone %s of %s", vmSpec.Zone, where)
+		}
+		return false, azure.NewTerminalError(capierrors.UpdateMachineError, errors.Wrapf(ErrVMSizeNotAvailable, "size %s is not offered in %s", vmSpec.Size, where))
+	}
+
+	s.Scope.Logger.V(2).Info("deallocating VM to resize it", "vm", vmSpec.Name, "size", vmSpec.Size)
+	if err := s.Client.DeallocateAsync(ctx, s.Scope.ResourceGroup(), vmSpec.Name); err != nil {
+		return false, errors.Wrapf(err, "failed to deallocate VM %s", vmSpec.Name)
+	}
+	return true, nil
+}
+
+// UpdateSize starts patching the hardware profile of a virtual machine with the size of the spec, without waiting for
+// its completion. A rejected size is a terminal error.
+func (s *Service) UpdateSize(ctx context.Context, vmSpec *Spec) error {
+	err := s.Client.UpdateAsync(ctx, s.Scope.ResourceGroup(), vmSpec.Name, compute.VirtualMachineUpdate{
+		VirtualMachineProperties: &compute.VirtualMachineProperties{
+			HardwareProfile: &compute.HardwareProfile{
+				VMSize: compute.VirtualMachineSizeTypes(vmSpec.Size),
+			},
+		},
+	})
+	if err != nil {
+		return azure.NewTerminalError(capierrors.UpdateMachineError, errors.Wrapf(err, "failed to resize VM %s to %s", vmSpec.Name, vmSpec.Size))
+	}
+	return nil
+}
+
+// Start starts a deallocated virtual machine.
+func (s *Service) Start(ctx context.Context, vmSpec *Spec) error {
+	if err := s.Client.Start(ctx, s.Scope.ResourceGroup(), vmSpec.Name); err != nil {
+		return errors.Wrapf(err, "failed to start VM %s", vmSpec.Name)
+	}
+	return nil
+}
+
+// StartAsync starts a deallocated virtual machine, without waiting for it to run.
+func (s *Service) StartAsync(ctx context.Context, vmSpec *Spec) error {
+	if err := s.Client.StartAsync(ctx, s.Scope.ResourceGroup(), vmSpec.Name); err != nil {
+		return errors.Wrapf(err, "failed to start VM %s", vmSpec.Name)
+	}
+	return nil
+}
+
+// hasVMSize returns whether the given VM size is in the list.
+func hasVMSize(sizes []compute.VirtualMachineSize, size string) bool {
+	for _, s := range sizes {
+		if strings.EqualFold(to.String(s.Name), size) {
+			return true
+		}
+	}
+	return false
+}
+
 // Reconcile gets/creates/updates a virtual machine.
 func (s *Service) Reconcile(ctx context.Context, spec interface{}) error {
 	vmSpec, ok := spec.(*Spec)
@@ -89,12 +194,21 @@ func (s *Service) Reconcile(ctx context.Context, spec interface{}) error {
 		return err
 	}
 
-	s.Scope.Logger.V(2).Info("getting network interface", "network interface", vmSpec.NICName)
-	nic, err := s.InterfacesClient.Get(ctx, s.Scope.ResourceGroup(), vmSpec.NICName)
-	if err != nil {
-		return err
+	nicRefs := make([]compute.NetworkInterfaceReference, 0, len(vmSpec.NICNames))
+	for i, nicName := range vmSpec.NICNames {
+		s.Scope.Logger.V(2).Info("getting network interface", "network interface", nicName)
+		nic, err := s.InterfacesClient.Get(ctx, s.Scope.ResourceGroup(), nicName)
+		if err != nil {
+			return err
+		}
+		s.Scope.Logger.V(2).Info("got network interface", "network interface", nicName)
+		nicRefs = append(nicRefs, compute.NetworkInterfaceReference{
+			ID: nic.ID,
+			NetworkInterfaceReferenceProperties: &compute.NetworkInterfaceReferenceProperties{
+				Primary: to.BoolPtr(i == 0),
+			},
+		})
 	}
-	s.Scope.Logger.V(2).Info("got network interface", "network interface", vmSpec.NICName)
 
 	s.Scope.Logger.V(2).Info("creating VM", "vm", vmSpec.Name)
 
@@ -103,13 +217,14 @@ func (s *Service) Reconcile(ctx context.Context, spec interface{}) error {
 	// Set the cloud provider tag
 	additionalTags[infrav1.ClusterAzureCloudProviderTagKey(s.MachineScope.Name())] = string(infrav1.ResourceLifecycleOwned)
 
-	priority, evictionPolicy, billingProfile, err := getSpotVMOptions(vmSpec.SpotVMOptions)
+	priority, evictionPolicy, billingProfile, err := converters.GetSpotVMOptions(vmSpec.SpotVMOptions)
 	if err != nil {
 		return errors.Wrapf(err, "failed to get Spot VM options")
 	}
 
 	virtualMachine := compute.VirtualMachine{
 		Location: to.StringPtr(s.Scope.Location()),
+		Plan:     converters.ImageToPlan(vmSpec.Image),
 		Tags: converters.TagsToMap(infrav1.Build(infrav1.BuildParams{
 			ClusterName: s.Scope.ClusterName(),
 			Lifecycle:   infrav1.ResourceLifecycleOwned,
@@ -139,14 +254,7 @@ func (s *Service) Reconcile(ctx context.Context, spec interface{}) error {
 				},
 			},
 			NetworkProfile: &compute.NetworkProfile{
-				NetworkInterfaces: &[]compute.NetworkInterfaceReference{
-					{
-						ID: nic.ID,
-						NetworkInterfaceReferenceProperties: &compute.NetworkInterfaceReferenceProperties{
-							Primary: to.BoolPtr(true),
-						},
-					},
-				},
+				NetworkInterfaces: &nicRefs,
 			},
 			Priority:       priority,
 			EvictionPolicy: evictionPolicy,
@@ -154,6 +262,27 @@ func (s *Service) Reconcile(ctx context.Context, spec interface{}) error {
 		},
 	}
 
+	if vmSpec.ProximityPlacementGroupID != "" {
+		virtualMachine.ProximityPlacementGroup = &compute.SubResource{
+			ID: to.StringPtr(vmSpec.ProximityPlacementGroupID),
+		}
+	}
+
+	if vmSpec.DedicatedHostID != "" {
+		virtualMachine.Host = &compute.SubResource{
+			ID: to.StringPtr(vmSpec.DedicatedHostID),
+		}
+	}
+
+	if vmSpec.BootDiagnosticsStorageURI != "" {
+		virtualMachine.DiagnosticsProfile = &compute.DiagnosticsProfile{
+			BootDiagnostics: &compute.BootDiagnostics{
+				Enabled:    to.BoolPtr(true),
+				StorageURI: to.StringPtr(vmSpec.BootDiagnosticsStorageURI),
+			},
+		}
+	}
+
 	s.Scope.Logger.V(2).Info("Setting zone", "zone", vmSpec.Zone)
 
 	if vmSpec.Zone != "" {
@@ -349,24 +478,6 @@ func generateStorageProfile(vmSpec Spec) (*compute.StorageProfile, error) {
 	return storageProfile, nil
 }
 
-func getSpotVMOptions(spotVMOptions *infrav1.SpotVMOptions) (compute.VirtualMachinePriorityTypes, compute.VirtualMachineEvictionPolicyTypes, *compute.BillingProfile, error) {
-	// Spot VM not requested, return zero values to apply defaults
-	if spotVMOptions == nil {
-		return compute.VirtualMachinePriorityTypes(""), compute.VirtualMachineEvictionPolicyTypes(""), nil, nil
-	}
-	var billingProfile *compute.BillingProfile
-	if spotVMOptions.MaxPrice != nil {
-		maxPrice, err := strconv.ParseFloat(*spotVMOptions.MaxPrice, 64)
-		if err != nil {
-			return compute.VirtualMachinePriorityTypes(""), compute.VirtualMachineEvictionPolicyTypes(""), nil, err
-		}
-		billingProfile = &compute.BillingProfile{
-			MaxPrice: &maxPrice,
-		}
-	}
-	return compute.Spot, compute.Deallocate, billingProfile, nil
-}
-
 // GenerateRandomString returns a URL-safe, base64 encoded
 // securely generated random string.
 // It will return an error if the system's secure random
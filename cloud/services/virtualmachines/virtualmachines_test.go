@@ -24,6 +24,7 @@ import (
 	. "github.com/onsi/gomega"
 	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/networkinterfaces/mock_networkinterfaces"
 	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/publicips/mock_publicips"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/resourceskus/mock_resourceskus"
 	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/roleassignments/mock_roleassignments"
 	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/virtualmachines/mock_virtualmachines"
 
@@ -37,6 +38,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes/scheme"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha3"
+	azure "sigs.k8s.io/cluster-api-provider-azure/cloud"
 	"sigs.k8s.io/cluster-api-provider-azure/cloud/scope"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha3"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
@@ -352,6 +354,8 @@ func TestReconcileVM(t *testing.T) {
 		},
 	}
 
+	deletePolicy := infrav1.SpotEvictionPolicyDelete
+
 	image := &infrav1.Image{
 		Marketplace: &infrav1.AzureMarketplaceImage{
 			Publisher: "test-publisher",
@@ -553,6 +557,55 @@ func TestReconcileVM(t *testing.T) {
 			},
 			expectedError: "",
 		},
+		{
+			name: "can create a vm on spot with the delete eviction policy",
+			machine: clusterv1.Machine{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{"set": "node"},
+				},
+				Spec: clusterv1.MachineSpec{
+					Bootstrap: clusterv1.Bootstrap{
+						Data: to.StringPtr("bootstrap-data"),
+					},
+					Version: to.StringPtr("1.15.7"),
+				},
+			},
+			machineConfig: &infrav1.AzureMachineSpec{
+				VMSize:        "Standard_B2ms",
+				Location:      "eastus",
+				Image:         image,
+				SpotVMOptions: &infrav1.SpotVMOptions{EvictionPolicy: &deletePolicy},
+			},
+			azureCluster: &infrav1.AzureCluster{
+				Spec: infrav1.AzureClusterSpec{
+					SubscriptionID: subscriptionID,
+					NetworkSpec: infrav1.NetworkSpec{
+						Subnets: infrav1.Subnets{
+							&infrav1.SubnetSpec{
+								Name: "subnet-1",
+							},
+							&infrav1.SubnetSpec{},
+						},
+					},
+				},
+				Status: infrav1.AzureClusterStatus{
+					Network: infrav1.Network{
+						APIServerIP: infrav1.PublicIP{
+							DNSName: "azure-test-dns",
+						},
+					},
+				},
+			},
+			expect: func(g *WithT, m *mock_virtualmachines.MockClientMockRecorder, mnic *mock_networkinterfaces.MockClientMockRecorder, mpip *mock_publicips.MockClientMockRecorder, mra *mock_roleassignments.MockClientMockRecorder) {
+				mnic.Get(gomock.Any(), gomock.Any(), gomock.Any())
+				m.CreateOrUpdate(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Do(func(_, _, _ interface{}, vm compute.VirtualMachine) {
+					g.Expect(vm.Priority).To(Equal(compute.Spot))
+					g.Expect(vm.EvictionPolicy).To(Equal(compute.Delete))
+					g.Expect(vm.BillingProfile).To(BeNil())
+				})
+			},
+			expectedError: "",
+		},
 		{
 			name: "vm creation fails",
 			machine: clusterv1.Machine{
@@ -678,7 +731,7 @@ func TestReconcileVM(t *testing.T) {
 
 			vmSpec := &Spec{
 				Name:          machineScope.Name(),
-				NICName:       "test-nic",
+				NICNames:      []string{"test-nic"},
 				SSHKeyData:    "fake-key",
 				Size:          machineScope.AzureMachine.Spec.VMSize,
 				OSDisk:        machineScope.AzureMachine.Spec.OSDisk,
@@ -789,3 +842,203 @@ func TestDeleteVM(t *testing.T) {
 		})
 	}
 }
+
+func TestGetPowerState(t *testing.T) {
+	testcases := []struct {
+		name          string
+		expectedState string
+		expectedError string
+		expect        func(m *mock_virtualmachines.MockClientMockRecorder)
+	}{
+		{
+			name:          "get power state of deallocated vm",
+			expectedState: PowerStateDeallocated,
+			expect: func(m *mock_virtualmachines.MockClientMockRecorder) {
+				m.InstanceView(context.TODO(), "my-rg", "my-vm").Return(compute.VirtualMachineInstanceView{
+					Statuses: &[]compute.InstanceViewStatus{
+						{Code: to.StringPtr("ProvisioningState/succeeded")},
+						{Code: to.StringPtr("PowerState/deallocated")},
+					},
+				}, nil)
+			},
+		},
+		{
+			name:          "power state is unknown",
+			expectedState: "",
+			expect: func(m *mock_virtualmachines.MockClientMockRecorder) {
+				m.InstanceView(context.TODO(), "my-rg", "my-vm").Return(compute.VirtualMachineInstanceView{
+					Statuses: &[]compute.InstanceViewStatus{
+						{Code: to.StringPtr("ProvisioningState/creating")},
+					},
+				}, nil)
+			},
+		},
+		{
+			name:          "fail to get instance view",
+			expectedError: "failed to get instance view of VM my-vm: #: Internal Server Error: StatusCode=500",
+			expect: func(m *mock_virtualmachines.MockClientMockRecorder) {
+				m.InstanceView(context.TODO(), "my-rg", "my-vm").Return(compute.VirtualMachineInstanceView{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error"))
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			vmMock := mock_virtualmachines.NewMockClient(mockCtrl)
+			tc.expect(vmMock.EXPECT())
+
+			cluster := &clusterv1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-cluster"},
+			}
+			clusterScope, err := scope.NewClusterScope(scope.ClusterScopeParams{
+				AzureClients: scope.AzureClients{
+					Authorizer: autorest.NullAuthorizer{},
+				},
+				Client:  fake.NewFakeClientWithScheme(scheme.Scheme, cluster),
+				Cluster: cluster,
+				AzureCluster: &infrav1.AzureCluster{
+					Spec: infrav1.AzureClusterSpec{
+						Location:       "test-location",
+						ResourceGroup:  "my-rg",
+						SubscriptionID: subscriptionID,
+					},
+				},
+			})
+			g.Expect(err).NotTo(HaveOccurred())
+
+			s := &Service{
+				Scope:  clusterScope,
+				Client: vmMock,
+			}
+
+			state, err := s.GetPowerState(context.TODO(), &Spec{Name: "my-vm"})
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(state).To(Equal(tc.expectedState))
+		})
+	}
+}
+
+func TestResize(t *testing.T) {
+	testcases := []struct {
+		name                string
+		zone                string
+		expectedDeallocated bool
+		expectedError       string
+		expect              func(m *mock_virtualmachines.MockClientMockRecorder, mSkus *mock_resourceskus.MockClientMockRecorder)
+	}{
+		{
+			name: "resize vm on its hardware cluster",
+			expect: func(m *mock_virtualmachines.MockClientMockRecorder, mSkus *mock_resourceskus.MockClientMockRecorder) {
+				m.ListAvailableSizes(context.TODO(), "my-rg", "my-vm").Return([]compute.VirtualMachineSize{
+					{Name: to.StringPtr("Standard_D2s_v3")},
+					{Name: to.StringPtr("Standard_D4s_v3")},
+				}, nil)
+				m.UpdateAsync(context.TODO(), "my-rg", "my-vm", gomock.AssignableToTypeOf(compute.VirtualMachineUpdate{})).
+					Do(func(_ context.Context, _, _ string, update compute.VirtualMachineUpdate) {
+						g := NewWithT(t)
+						g.Expect(update.HardwareProfile.VMSize).To(Equal(compute.VirtualMachineSizeTypes("Standard_D4s_v3")))
+					})
+			},
+		},
+		{
+			name:          "fail to resize vm on its hardware cluster",
+			expectedError: "failed to resize VM my-vm to Standard_D4s_v3: #: Internal Server Error: StatusCode=500",
+			expect: func(m *mock_virtualmachines.MockClientMockRecorder, mSkus *mock_resourceskus.MockClientMockRecorder) {
+				m.ListAvailableSizes(context.TODO(), "my-rg", "my-vm").Return([]compute.VirtualMachineSize{
+					{Name: to.StringPtr("Standard_D4s_v3")},
+				}, nil)
+				m.UpdateAsync(context.TODO(), "my-rg", "my-vm", gomock.AssignableToTypeOf(compute.VirtualMachineUpdate{})).
+					Return(autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error"))
+			},
+		},
+		{
+			name:                "deallocate vm to resize it",
+			expectedDeallocated: true,
+			expect: func(m *mock_virtualmachines.MockClientMockRecorder, mSkus *mock_resourceskus.MockClientMockRecorder) {
+				m.ListAvailableSizes(context.TODO(), "my-rg", "my-vm").Return([]compute.VirtualMachineSize{
+					{Name: to.StringPtr("Standard_D2s_v3")},
+				}, nil)
+				mSkus.IsVMSizeAvailable(context.TODO(), "test-location", "", "Standard_D4s_v3").Return(true, nil)
+				m.DeallocateAsync(context.TODO(), "my-rg", "my-vm")
+			},
+		},
+		{
+			name:          "vm size is not offered in the location",
+			expectedError: "size Standard_D4s_v3 is not offered in location test-location: VM size is not available",
+			expect: func(m *mock_virtualmachines.MockClientMockRecorder, mSkus *mock_resourceskus.MockClientMockRecorder) {
+				m.ListAvailableSizes(context.TODO(), "my-rg", "my-vm").Return([]compute.VirtualMachineSize{
+					{Name: to.StringPtr("Standard_D2s_v3")},
+				}, nil)
+				mSkus.IsVMSizeAvailable(context.TODO(), "test-location", "", "Standard_D4s_v3").Return(false, nil)
+			},
+		},
+		{
+			name:          "vm size is not offered in the zone",
+			zone:          "2",
+			expectedError: "size Standard_D4s_v3 is not offered in zone 2 of location test-location: VM size is not available",
+			expect: func(m *mock_virtualmachines.MockClientMockRecorder, mSkus *mock_resourceskus.MockClientMockRecorder) {
+				m.ListAvailableSizes(context.TODO(), "my-rg", "my-vm").Return(nil, nil)
+				mSkus.IsVMSizeAvailable(context.TODO(), "test-location", "2", "Standard_D4s_v3").Return(false, nil)
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			vmMock := mock_virtualmachines.NewMockClient(mockCtrl)
+			skusMock := mock_resourceskus.NewMockClient(mockCtrl)
+			tc.expect(vmMock.EXPECT(), skusMock.EXPECT())
+
+			cluster := &clusterv1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-cluster"},
+			}
+			clusterScope, err := scope.NewClusterScope(scope.ClusterScopeParams{
+				AzureClients: scope.AzureClients{
+					Authorizer: autorest.NullAuthorizer{},
+				},
+				Client:  fake.NewFakeClientWithScheme(scheme.Scheme, cluster),
+				Cluster: cluster,
+				AzureCluster: &infrav1.AzureCluster{
+					Spec: infrav1.AzureClusterSpec{
+						Location:       "test-location",
+						ResourceGroup:  "my-rg",
+						SubscriptionID: subscriptionID,
+					},
+				},
+			})
+			g.Expect(err).NotTo(HaveOccurred())
+
+			s := &Service{
+				Scope:              clusterScope,
+				Client:             vmMock,
+				ResourceSkusClient: skusMock,
+			}
+
+			deallocated, err := s.Resize(context.TODO(), &Spec{Name: "my-vm", Size: "Standard_D4s_v3", Zone: tc.zone})
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+				_, terminal := azure.IsTerminalError(err)
+				g.Expect(terminal).To(BeTrue())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(deallocated).To(Equal(tc.expectedDeallocated))
+		})
+	}
+}
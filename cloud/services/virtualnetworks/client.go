@@ -21,6 +21,8 @@ import (
 
 	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
 	"github.com/Azure/go-autorest/autorest"
+	autorestazure "github.com/Azure/go-autorest/autorest/azure"
+	"github.com/pkg/errors"
 	azure "sigs.k8s.io/cluster-api-provider-azure/cloud"
 )
 
@@ -30,11 +32,14 @@ type Client interface {
 	CreateOrUpdate(context.Context, string, string, network.VirtualNetwork) error
 	Delete(context.Context, string, string) error
 	CheckIPAddressAvailability(context.Context, string, string, string) (network.IPAddressAvailabilityResult, error)
+	GetDDoSProtectionPlan(context.Context, string) (network.DdosProtectionPlan, error)
 }
 
 // AzureClient contains the Azure go-sdk Client
 type AzureClient struct {
 	virtualnetworks network.VirtualNetworksClient
+	baseURI         string
+	authorizer      autorest.Authorizer
 }
 
 var _ Client = &AzureClient{}
@@ -42,7 +47,11 @@ var _ Client = &AzureClient{}
 // NewClient creates a new VM client from subscription ID.
 func NewClient(auth azure.Authorizer) *AzureClient {
 	c := newVirtualNetworksClient(auth.SubscriptionID(), auth.BaseURI(), auth.Authorizer())
-	return &AzureClient{c}
+	return &AzureClient{
+		virtualnetworks: c,
+		baseURI:         auth.BaseURI(),
+		authorizer:      auth.Authorizer(),
+	}
 }
 
 // newVirtualNetworksClient creates a new vnet client from subscription ID.
@@ -90,3 +99,16 @@ func (ac *AzureClient) Delete(ctx context.Context, resourceGroupName, vnetName s
 func (ac *AzureClient) CheckIPAddressAvailability(ctx context.Context, resourceGroupName, vnetName, ip string) (network.IPAddressAvailabilityResult, error) {
 	return ac.virtualnetworks.CheckIPAddressAvailability(ctx, resourceGroupName, vnetName, ip)
 }
+
+// GetDDoSProtectionPlan gets the DDoS protection plan with the specified resource ID, which can be in any
+// subscription of the tenant of the credentials.
+func (ac *AzureClient) GetDDoSProtectionPlan(ctx context.Context, id string) (network.DdosProtectionPlan, error) {
+	res, err := autorestazure.ParseResourceID(id)
+	if err != nil {
+		return network.DdosProtectionPlan{}, errors.Wrapf(err, "invalid DDoS protection plan ID %s", id)
+	}
+	plansClient := network.NewDdosProtectionPlansClientWithBaseURI(ac.baseURI, res.SubscriptionID)
+	plansClient.Authorizer = ac.authorizer
+	plansClient.AddToUserAgent(azure.UserAgent())
+	return plansClient.Get(ctx, res.ResourceGroup, res.ResourceName)
+}
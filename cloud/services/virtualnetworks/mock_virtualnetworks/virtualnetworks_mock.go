@@ -107,3 +107,18 @@ func (mr *MockClientMockRecorder) CheckIPAddressAvailability(arg0, arg1, arg2, a
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckIPAddressAvailability", reflect.TypeOf((*MockClient)(nil).CheckIPAddressAvailability), arg0, arg1, arg2, arg3)
 }
+
+// GetDDoSProtectionPlan mocks base method.
+func (m *MockClient) GetDDoSProtectionPlan(arg0 context.Context, arg1 string) (network.DdosProtectionPlan, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDDoSProtectionPlan", arg0, arg1)
+	ret0, _ := ret[0].(network.DdosProtectionPlan)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDDoSProtectionPlan indicates an expected call of GetDDoSProtectionPlan.
+func (mr *MockClientMockRecorder) GetDDoSProtectionPlan(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDDoSProtectionPlan", reflect.TypeOf((*MockClient)(nil).GetDDoSProtectionPlan), arg0, arg1)
+}
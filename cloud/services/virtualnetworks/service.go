@@ -17,6 +17,7 @@ limitations under the License.
 package virtualnetworks
 
 import (
+	azure "sigs.k8s.io/cluster-api-provider-azure/cloud"
 	"sigs.k8s.io/cluster-api-provider-azure/cloud/scope"
 )
 
@@ -30,6 +31,6 @@ type Service struct {
 func NewService(scope *scope.ClusterScope) *Service {
 	return &Service{
 		Scope:  scope,
-		Client: NewClient(scope),
+		Client: NewClient(azure.VnetAuthorizer(scope)),
 	}
 }
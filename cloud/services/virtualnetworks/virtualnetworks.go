@@ -18,6 +18,7 @@ package virtualnetworks
 
 import (
 	"context"
+	"strings"
 
 	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
 	"github.com/Azure/go-autorest/autorest/to"
@@ -26,6 +27,8 @@ import (
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha3"
 	azure "sigs.k8s.io/cluster-api-provider-azure/cloud"
 	"sigs.k8s.io/cluster-api-provider-azure/cloud/converters"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha3"
+	"sigs.k8s.io/cluster-api/util/conditions"
 )
 
 // Spec input specification for Get/CreateOrUpdate/Delete calls
@@ -33,6 +36,8 @@ type Spec struct {
 	ResourceGroup string
 	Name          string
 	CIDR          string
+	// DDoSProtectionPlanID is the resource ID of the DDoS protection plan to attach to a managed VNet.
+	DDoSProtectionPlanID string
 }
 
 // getExisting provides information about an existing virtual network.
@@ -52,11 +57,13 @@ func (s *Service) getExisting(ctx context.Context, spec *Spec) (*infrav1.VnetSpe
 		}
 	}
 	return &infrav1.VnetSpec{
-		ResourceGroup: spec.ResourceGroup,
-		ID:            to.String(vnet.ID),
-		Name:          to.String(vnet.Name),
-		CidrBlock:     cidr,
-		Tags:          converters.MapToTags(vnet.Tags),
+		ResourceGroup:        spec.ResourceGroup,
+		ID:                   to.String(vnet.ID),
+		Name:                 to.String(vnet.Name),
+		SubscriptionID:       s.Scope.Vnet().SubscriptionID,
+		CidrBlock:            cidr,
+		DDoSProtectionPlanID: s.Scope.Vnet().DDoSProtectionPlanID,
+		Tags:                 converters.MapToTags(vnet.Tags),
 	}, nil
 }
 
@@ -83,8 +90,10 @@ func (s *Service) Reconcile(ctx context.Context, spec interface{}) error {
 
 		if !existingVnet.IsManaged(s.Scope.ClusterName()) {
 			s.Scope.V(2).Info("Working on custom VNet", "vnet-id", existingVnet.ID)
+		} else if err := s.reconcileDDoSProtectionPlan(ctx, vnetSpec); err != nil {
+			return err
 		}
-		// vnet already exists, cannot update since it's immutable
+		// vnet already exists, its address space cannot be updated since it's immutable
 		existingVnet.DeepCopyInto(s.Scope.Vnet())
 		return nil
 	}
@@ -104,15 +113,81 @@ func (s *Service) Reconcile(ctx context.Context, spec interface{}) error {
 			},
 		},
 	}
+	if vnetSpec.DDoSProtectionPlanID != "" {
+		if err := s.checkDDoSProtectionPlan(ctx, vnetSpec.DDoSProtectionPlanID); err != nil {
+			return err
+		}
+		setDDoSProtectionPlan(&vnetProperties, vnetSpec.DDoSProtectionPlanID)
+	}
 	err = s.Client.CreateOrUpdate(ctx, vnetSpec.ResourceGroup, vnetSpec.Name, vnetProperties)
 	if err != nil {
+		if vnetSpec.DDoSProtectionPlanID != "" {
+			conditions.MarkFalse(s.Scope.AzureCluster, infrav1.DDoSProtectionPlanAttachedCondition, infrav1.DDoSProtectionPlanAttachFailedReason,
+				clusterv1.ConditionSeverityError, "failed to create VNet %s with DDoS protection plan %s: %v", vnetSpec.Name, vnetSpec.DDoSProtectionPlanID, err)
+		}
 		return err
 	}
+	if vnetSpec.DDoSProtectionPlanID != "" {
+		conditions.MarkTrue(s.Scope.AzureCluster, infrav1.DDoSProtectionPlanAttachedCondition)
+	}
 
 	s.Scope.Logger.V(2).Info("successfully created VNet", "VNet", vnetSpec.Name)
 	return nil
 }
 
+// reconcileDDoSProtectionPlan attaches the DDoS protection plan of the spec to an existing managed VNet.
+// A plan that is no longer in the spec is left attached.
+func (s *Service) reconcileDDoSProtectionPlan(ctx context.Context, vnetSpec *Spec) error {
+	if vnetSpec.DDoSProtectionPlanID == "" {
+		conditions.Delete(s.Scope.AzureCluster, infrav1.DDoSProtectionPlanAttachedCondition)
+		return nil
+	}
+	if err := s.checkDDoSProtectionPlan(ctx, vnetSpec.DDoSProtectionPlanID); err != nil {
+		return err
+	}
+
+	vnet, err := s.Client.Get(ctx, vnetSpec.ResourceGroup, vnetSpec.Name)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get VNet %s", vnetSpec.Name)
+	}
+	if vnet.VirtualNetworkPropertiesFormat != nil && to.Bool(vnet.EnableDdosProtection) &&
+		vnet.DdosProtectionPlan != nil && strings.EqualFold(to.String(vnet.DdosProtectionPlan.ID), vnetSpec.DDoSProtectionPlanID) {
+		conditions.MarkTrue(s.Scope.AzureCluster, infrav1.DDoSProtectionPlanAttachedCondition)
+		return nil
+	}
+
+	s.Scope.Logger.V(2).Info("attaching DDoS protection plan to VNet", "VNet", vnetSpec.Name, "plan", vnetSpec.DDoSProtectionPlanID)
+	setDDoSProtectionPlan(&vnet, vnetSpec.DDoSProtectionPlanID)
+	if err := s.Client.CreateOrUpdate(ctx, vnetSpec.ResourceGroup, vnetSpec.Name, vnet); err != nil {
+		conditions.MarkFalse(s.Scope.AzureCluster, infrav1.DDoSProtectionPlanAttachedCondition, infrav1.DDoSProtectionPlanAttachFailedReason,
+			clusterv1.ConditionSeverityError, "failed to attach DDoS protection plan %s to VNet %s: %v", vnetSpec.DDoSProtectionPlanID, vnetSpec.Name, err)
+		return errors.Wrapf(err, "failed to attach DDoS protection plan %s to VNet %s", vnetSpec.DDoSProtectionPlanID, vnetSpec.Name)
+	}
+	conditions.MarkTrue(s.Scope.AzureCluster, infrav1.DDoSProtectionPlanAttachedCondition)
+	s.Scope.Logger.V(2).Info("successfully attached DDoS protection plan to VNet", "VNet", vnetSpec.Name, "plan", vnetSpec.DDoSProtectionPlanID)
+	return nil
+}
+
+// checkDDoSProtectionPlan returns an error if the DDoS protection plan cannot be read with the credentials of
+// the cluster, which are only valid in the tenant of the cluster.
+func (s *Service) checkDDoSProtectionPlan(ctx context.Context, planID string) error {
+	if _, err := s.Client.GetDDoSProtectionPlan(ctx, planID); err != nil {
+		conditions.MarkFalse(s.Scope.AzureCluster, infrav1.DDoSProtectionPlanAttachedCondition, infrav1.DDoSProtectionPlanNotAccessibleReason,
+			clusterv1.ConditionSeverityError, "DDoS protection plan %s does not exist or is not in the tenant of the cluster: %v", planID, err)
+		return errors.Wrapf(err, "failed to get DDoS protection plan %s, it must exist in the tenant of the cluster", planID)
+	}
+	return nil
+}
+
+// setDDoSProtectionPlan enables DDoS protection on a VNet with the given plan.
+func setDDoSProtectionPlan(vnet *network.VirtualNetwork, planID string) {
+	if vnet.VirtualNetworkPropertiesFormat == nil {
+		vnet.VirtualNetworkPropertiesFormat = &network.VirtualNetworkPropertiesFormat{}
+	}
+	vnet.EnableDdosProtection = to.BoolPtr(true)
+	vnet.DdosProtectionPlan = &network.SubResource{ID: to.StringPtr(planID)}
+}
+
 // Delete deletes the virtual network with the provided name.
 func (s *Service) Delete(ctx context.Context, spec interface{}) error {
 	if !s.Scope.Vnet().IsManaged(s.Scope.ClusterName()) {
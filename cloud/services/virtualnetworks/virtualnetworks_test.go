@@ -35,7 +35,9 @@ import (
 	"k8s.io/client-go/kubernetes/scheme"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha3"
 	"sigs.k8s.io/cluster-api-provider-azure/cloud/scope"
+	"sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha3"
+	"sigs.k8s.io/cluster-api/util/conditions"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
@@ -184,6 +186,204 @@ func TestReconcileVnet(t *testing.T) {
 	}
 }
 
+func TestReconcileVnetDDoSProtectionPlan(t *testing.T) {
+	planID := "/subscriptions/456/resourceGroups/hub-rg/providers/Microsoft.Network/ddosProtectionPlans/my-plan"
+	managedTags := map[string]*string{
+		"Name": to.StringPtr("my-vnet"),
+		"sigs.k8s.io_cluster-api-provider-azure_cluster_test-cluster": to.StringPtr("owned"),
+		"sigs.k8s.io_cluster-api-provider-azure_role":                 to.StringPtr("common"),
+	}
+	// the service updates the properties of the vnet it gets, so every call returns a new one
+	managedVnet := func() network.VirtualNetwork {
+		return network.VirtualNetwork{
+			ID:   to.StringPtr("azure/fake/id"),
+			Name: to.StringPtr("my-vnet"),
+			VirtualNetworkPropertiesFormat: &network.VirtualNetworkPropertiesFormat{
+				AddressSpace: &network.AddressSpace{
+					AddressPrefixes: to.StringSlicePtr([]string{"10.0.0.0/8"}),
+				},
+			},
+			Tags: managedTags,
+		}
+	}
+	protectedVnet := network.VirtualNetwork{
+		ID:   to.StringPtr("azure/fake/id"),
+		Name: to.StringPtr("my-vnet"),
+		VirtualNetworkPropertiesFormat: &network.VirtualNetworkPropertiesFormat{
+			AddressSpace: &network.AddressSpace{
+				AddressPrefixes: to.StringSlicePtr([]string{"10.0.0.0/8"}),
+			},
+			EnableDdosProtection: to.BoolPtr(true),
+			DdosProtectionPlan:   &network.SubResource{ID: to.StringPtr(planID)},
+		},
+		Tags: managedTags,
+	}
+
+	testcases := []struct {
+		name              string
+		expectedError     string
+		expectedCondition *clusterv1.Condition
+		expect            func(m *mock_virtualnetworks.MockClientMockRecorder)
+	}{
+		{
+			name:              "new vnet with DDoS protection plan",
+			expectedError:     "",
+			expectedCondition: conditions.TrueCondition(infrav1.DDoSProtectionPlanAttachedCondition),
+			expect: func(m *mock_virtualnetworks.MockClientMockRecorder) {
+				m.Get(context.TODO(), "my-rg", "my-vnet").
+					Return(network.VirtualNetwork{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
+				m.GetDDoSProtectionPlan(context.TODO(), planID)
+				m.CreateOrUpdate(context.TODO(), "my-rg", "my-vnet", matchers.DiffEq(network.VirtualNetwork{
+					Tags: map[string]*string{
+						"Name": to.StringPtr("my-vnet"),
+						"sigs.k8s.io_cluster-api-provider-azure_cluster_test-cluster": to.StringPtr("owned"),
+						"sigs.k8s.io_cluster-api-provider-azure_role":                 to.StringPtr("common"),
+					},
+					Location: to.StringPtr("test-location"),
+					VirtualNetworkPropertiesFormat: &network.VirtualNetworkPropertiesFormat{
+						AddressSpace: &network.AddressSpace{
+							AddressPrefixes: &[]string{"10.0.0.0/8"},
+						},
+						EnableDdosProtection: to.BoolPtr(true),
+						DdosProtectionPlan:   &network.SubResource{ID: to.StringPtr(planID)},
+					},
+				}))
+			},
+		},
+		{
+			name:              "attach DDoS protection plan to existing managed vnet",
+			expectedError:     "",
+			expectedCondition: conditions.TrueCondition(infrav1.DDoSProtectionPlanAttachedCondition),
+			expect: func(m *mock_virtualnetworks.MockClientMockRecorder) {
+				m.Get(context.TODO(), "my-rg", "my-vnet").Times(2).DoAndReturn(func(context.Context, string, string) (network.VirtualNetwork, error) {
+					return managedVnet(), nil
+				})
+				m.GetDDoSProtectionPlan(context.TODO(), planID)
+				m.CreateOrUpdate(context.TODO(), "my-rg", "my-vnet", matchers.DiffEq(protectedVnet))
+			},
+		},
+		{
+			name:              "DDoS protection plan already attached",
+			expectedError:     "",
+			expectedCondition: conditions.TrueCondition(infrav1.DDoSProtectionPlanAttachedCondition),
+			expect: func(m *mock_virtualnetworks.MockClientMockRecorder) {
+				m.Get(context.TODO(), "my-rg", "my-vnet").Times(2).Return(protectedVnet, nil)
+				m.GetDDoSProtectionPlan(context.TODO(), planID)
+			},
+		},
+		{
+			name:          "DDoS protection plan in another tenant",
+			expectedError: "failed to get DDoS protection plan " + planID + ", it must exist in the tenant of the cluster: #: Forbidden: StatusCode=403",
+			expectedCondition: conditions.FalseCondition(infrav1.DDoSProtectionPlanAttachedCondition, infrav1.DDoSProtectionPlanNotAccessibleReason, clusterv1.ConditionSeverityError,
+				"DDoS protection plan %s does not exist or is not in the tenant of the cluster: #: Forbidden: StatusCode=403", planID),
+			expect: func(m *mock_virtualnetworks.MockClientMockRecorder) {
+				m.Get(context.TODO(), "my-rg", "my-vnet").Return(managedVnet(), nil)
+				m.GetDDoSProtectionPlan(context.TODO(), planID).
+					Return(network.DdosProtectionPlan{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 403}, "Forbidden"))
+			},
+		},
+		{
+			name:          "DDoS protection plan attachment fails",
+			expectedError: "failed to attach DDoS protection plan " + planID + " to VNet my-vnet: #: Internal Server Error: StatusCode=500",
+			expectedCondition: conditions.FalseCondition(infrav1.DDoSProtectionPlanAttachedCondition, infrav1.DDoSProtectionPlanAttachFailedReason, clusterv1.ConditionSeverityError,
+				"failed to attach DDoS protection plan %s to VNet my-vnet: #: Internal Server Error: StatusCode=500", planID),
+			expect: func(m *mock_virtualnetworks.MockClientMockRecorder) {
+				m.Get(context.TODO(), "my-rg", "my-vnet").Times(2).DoAndReturn(func(context.Context, string, string) (network.VirtualNetwork, error) {
+					return managedVnet(), nil
+				})
+				m.GetDDoSProtectionPlan(context.TODO(), planID)
+				m.CreateOrUpdate(context.TODO(), "my-rg", "my-vnet", gomock.AssignableToTypeOf(network.VirtualNetwork{})).
+					Return(autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error"))
+			},
+		},
+		{
+			name:              "custom vnet is left untouched",
+			expectedError:     "",
+			expectedCondition: nil,
+			expect: func(m *mock_virtualnetworks.MockClientMockRecorder) {
+				m.Get(context.TODO(), "my-rg", "my-vnet").Return(network.VirtualNetwork{
+					ID:   to.StringPtr("azure/custom-vnet/id"),
+					Name: to.StringPtr("my-vnet"),
+				}, nil)
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			vnetMock := mock_virtualnetworks.NewMockClient(mockCtrl)
+
+			cluster := &clusterv1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-cluster"},
+			}
+
+			client := fake.NewFakeClientWithScheme(scheme.Scheme, cluster)
+
+			tc.expect(vnetMock.EXPECT())
+
+			clusterScope, err := scope.NewClusterScope(scope.ClusterScopeParams{
+				AzureClients: scope.AzureClients{
+					Authorizer: autorest.NullAuthorizer{},
+				},
+				Client:  client,
+				Cluster: cluster,
+				AzureCluster: &infrav1.AzureCluster{
+					Spec: infrav1.AzureClusterSpec{
+						Location:       "test-location",
+						SubscriptionID: subscriptionID,
+						NetworkSpec: infrav1.NetworkSpec{
+							Vnet: infrav1.VnetSpec{
+								ResourceGroup:        "my-rg",
+								Name:                 "my-vnet",
+								CidrBlock:            "10.0.0.0/8",
+								DDoSProtectionPlanID: planID,
+							},
+						},
+					},
+				},
+			})
+			g.Expect(err).NotTo(HaveOccurred())
+
+			s := &Service{
+				Scope:  clusterScope,
+				Client: vnetMock,
+			}
+
+			vnetSpec := &Spec{
+				Name:                 clusterScope.Vnet().Name,
+				ResourceGroup:        clusterScope.Vnet().ResourceGroup,
+				CIDR:                 clusterScope.Vnet().CidrBlock,
+				DDoSProtectionPlanID: clusterScope.Vnet().DDoSProtectionPlanID,
+			}
+
+			err = s.Reconcile(context.TODO(), vnetSpec)
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+
+			condition := conditions.Get(clusterScope.AzureCluster, infrav1.DDoSProtectionPlanAttachedCondition)
+			if tc.expectedCondition == nil {
+				g.Expect(condition).To(BeNil())
+			} else {
+				g.Expect(condition).NotTo(BeNil())
+				g.Expect(condition.Status).To(Equal(tc.expectedCondition.Status))
+				g.Expect(condition.Reason).To(Equal(tc.expectedCondition.Reason))
+				g.Expect(condition.Severity).To(Equal(tc.expectedCondition.Severity))
+				g.Expect(condition.Message).To(Equal(tc.expectedCondition.Message))
+			}
+		})
+	}
+}
+
 func TestDeleteVnet(t *testing.T) {
 	testcases := []struct {
 		name   string
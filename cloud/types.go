@@ -16,10 +16,54 @@ limitations under the License.
 
 package azure
 
+import (
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha3"
+)
+
 // PublicIPSpec defines the specification for a public IP.
 type PublicIPSpec struct {
 	Name    string
 	DNSName string
+	// DNSLabel is a custom domain name label, which must be available in the location of the public IP.
+	// The lower case name is used when empty.
+	DNSLabel string
+	// SKU is the SKU of the load balancer the public IP is attached to, which the public IP must match.
+	SKU infrav1.SKU
+}
+
+// PrivateEndpointSpec defines the specification for a private endpoint.
+type PrivateEndpointSpec struct {
+	Name                  string
+	SubnetID              string
+	PrivateLinkResourceID string
+	GroupIDs              []string
+	PrivateDNSZoneID      string
+}
+
+// DNSRecordSpec defines the specification for an A record pointing to the API server.
+type DNSRecordSpec struct {
+	ZoneID     string
+	RecordName string
+	// PublicIPName and PublicIPResourceGroup locate the public IP whose address is used in public DNS zones.
+	PublicIPName          string
+	PublicIPResourceGroup string
+	// InternalLBName is the internal load balancer whose frontend address is used in private DNS zones.
+	InternalLBName string
+	// VnetLinkName is the name of the link between a private DNS zone and VnetID, created if not empty.
+	VnetLinkName string
+	VnetID       string
+}
+
+// ApplicationSecurityGroupSpec defines the specification for an application security group.
+type ApplicationSecurityGroupSpec struct {
+	Name string
+	Role string
+}
+
+// InboundNatSpec defines the specification for an inbound NAT rule.
+type InboundNatSpec struct {
+	Name             string
+	LoadBalancerName string
 }
 
 // NICSpec defines the specification for a network interface.
@@ -36,4 +80,18 @@ type NICSpec struct {
 	PublicIPName             string
 	VMSize                   string
 	AcceleratedNetworking    *bool
+	PrivateIPConfigs         int
+	ApplicationSecurityGroup string
+}
+
+// VMExtensionSpec defines the specification for a VM extension.
+type VMExtensionSpec struct {
+	Name      string
+	VMName    string
+	Publisher string
+	Type      string
+	Version   string
+	Settings  map[string]interface{}
+	// ProtectedSettings are read from the Secret referenced by the extension.
+	ProtectedSettings map[string]string
 }
@@ -22,11 +22,13 @@ import (
 
 	"github.com/go-logr/logr"
 	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/client-go/tools/record"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha3"
 	"sigs.k8s.io/cluster-api/util"
 	"sigs.k8s.io/cluster-api/util/annotations"
+	"sigs.k8s.io/cluster-api/util/conditions"
 	"sigs.k8s.io/cluster-api/util/predicates"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -78,6 +80,7 @@ func (r *AzureClusterReconciler) SetupWithManager(mgr ctrl.Manager, options cont
 // +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=azureclusters/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=clusters;clusters/status,verbs=get;list;watch
 // +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=azuremachinetemplates;azuremachinetemplates/status,verbs=get;list;watch
+// +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=azuremachines,verbs=get;list;watch
 
 func (r *AzureClusterReconciler) Reconcile(req ctrl.Request) (_ ctrl.Result, reterr error) {
 	ctx, cancel := context.WithTimeout(context.Background(), reconciler.DefaultedLoopTimeout(r.ReconcileTimeout))
@@ -151,6 +154,13 @@ func (r *AzureClusterReconciler) reconcileNormal(ctx context.Context, clusterSco
 		return reconcile.Result{}, err
 	}
 
+	if err := azureCluster.ValidateClusterNetwork(clusterScope.Cluster.Spec.ClusterNetwork); err != nil {
+		conditions.MarkFalse(azureCluster, infrav1.ClusterNetworkValidCondition, infrav1.ClusterNetworkInvalidReason, clusterv1.ConditionSeverityError, err.Error())
+		r.Recorder.Eventf(azureCluster, corev1.EventTypeWarning, "InvalidClusterNetwork", "Invalid cluster network configuration: %s", err.Error())
+		return reconcile.Result{}, errors.Wrap(err, "invalid cluster network configuration")
+	}
+	conditions.MarkTrue(azureCluster, infrav1.ClusterNetworkValidCondition)
+
 	err := newAzureClusterReconciler(clusterScope).Reconcile(ctx)
 	if err != nil {
 		return reconcile.Result{}, errors.Wrap(err, "failed to reconcile cluster services")
@@ -163,7 +173,7 @@ func (r *AzureClusterReconciler) reconcileNormal(ctx context.Context, clusterSco
 
 	// Set APIEndpoints so the Cluster API Cluster Controller can pull them
 	azureCluster.Spec.ControlPlaneEndpoint = clusterv1.APIEndpoint{
-		Host: azureCluster.Status.Network.APIServerIP.DNSName,
+		Host: clusterScope.APIServerHost(),
 		Port: clusterScope.APIServerPort(),
 	}
 
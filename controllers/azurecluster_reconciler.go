@@ -21,23 +21,32 @@ import (
 	"fmt"
 	"hash/fnv"
 	"strconv"
+	"strings"
 
+	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
+	autorestazure "github.com/Azure/go-autorest/autorest/azure"
 	"github.com/Azure/go-autorest/autorest/to"
 	"github.com/pkg/errors"
 	"k8s.io/klog"
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha3"
+	"sigs.k8s.io/cluster-api/util/conditions"
 
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha3"
 	azure "sigs.k8s.io/cluster-api-provider-azure/cloud"
 	"sigs.k8s.io/cluster-api-provider-azure/cloud/scope"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/applicationsecuritygroups"
 	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/availabilityzones"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/dnsrecords"
 	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/groups"
 	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/internalloadbalancers"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/privateendpoints"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/proximityplacementgroups"
 	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/publicips"
 	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/publicloadbalancers"
 	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/routetables"
 	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/securitygroups"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/storageaccounts"
 	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/subnets"
 	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/virtualnetworks"
 )
@@ -47,13 +56,20 @@ type azureClusterReconciler struct {
 	scope                *scope.ClusterScope
 	groupsSvc            azure.OldService
 	vnetSvc              azure.OldService
+	asgSvc               azure.Service
 	securityGroupSvc     azure.OldService
 	routeTableSvc        azure.OldService
 	subnetsSvc           azure.OldService
+	privateEndpointsSvc  azure.Service
 	internalLBSvc        azure.OldService
 	publicIPSvc          azure.Service
+	publicIPsClient      publicips.Client
+	subnetsClient        subnets.Client
 	publicLBSvc          azure.OldService
+	dnsRecordsSvc        azure.Service
+	ppgSvc               azure.OldService
 	availabilityZonesSvc azure.GetterService
+	storageAccountsSvc   azure.OldService
 }
 
 // newAzureClusterReconciler populates all the services based on input scope
@@ -62,20 +78,27 @@ func newAzureClusterReconciler(scope *scope.ClusterScope) *azureClusterReconcile
 		scope:                scope,
 		groupsSvc:            groups.NewService(scope),
 		vnetSvc:              virtualnetworks.NewService(scope),
+		asgSvc:               applicationsecuritygroups.NewService(scope),
 		securityGroupSvc:     securitygroups.NewService(scope),
 		routeTableSvc:        routetables.NewService(scope),
 		subnetsSvc:           subnets.NewService(scope),
+		privateEndpointsSvc:  privateendpoints.NewService(scope),
 		internalLBSvc:        internalloadbalancers.NewService(scope),
 		publicIPSvc:          publicips.NewService(scope),
+		publicIPsClient:      publicips.NewClient(scope),
+		subnetsClient:        subnets.NewClient(azure.VnetAuthorizer(scope)),
 		publicLBSvc:          publicloadbalancers.NewService(scope),
+		dnsRecordsSvc:        dnsrecords.NewService(scope),
+		ppgSvc:               proximityplacementgroups.NewService(scope),
 		availabilityZonesSvc: availabilityzones.NewService(scope),
+		storageAccountsSvc:   storageaccounts.NewService(scope),
 	}
 }
 
 // Reconcile reconciles all the services in pre determined order
 func (r *azureClusterReconciler) Reconcile(ctx context.Context) error {
 	klog.V(2).Infof("reconciling cluster %s", r.scope.ClusterName())
-	if err := r.createOrUpdateNetworkAPIServerIP(); err != nil {
+	if err := r.createOrUpdateNetworkAPIServerIP(ctx); err != nil {
 		return errors.Wrapf(err, "failed to create or update network API server IP for cluster %s in location %s", r.scope.ClusterName(), r.scope.Location())
 	}
 
@@ -87,14 +110,40 @@ func (r *azureClusterReconciler) Reconcile(ctx context.Context) error {
 		return errors.Wrapf(err, "failed to reconcile resource group for cluster %s", r.scope.ClusterName())
 	}
 
+	if ppgName := r.scope.ProximityPlacementGroupName(); ppgName != "" {
+		if err := r.ppgSvc.Reconcile(ctx, &proximityplacementgroups.Spec{Name: ppgName}); err != nil {
+			return errors.Wrapf(err, "failed to reconcile proximity placement group for cluster %s", r.scope.ClusterName())
+		}
+	}
+
 	vnetSpec := &virtualnetworks.Spec{
-		ResourceGroup: r.scope.Vnet().ResourceGroup,
-		Name:          r.scope.Vnet().Name,
-		CIDR:          r.scope.Vnet().CidrBlock,
+		ResourceGroup:        r.scope.Vnet().ResourceGroup,
+		Name:                 r.scope.Vnet().Name,
+		CIDR:                 r.scope.Vnet().CidrBlock,
+		DDoSProtectionPlanID: r.scope.Vnet().DDoSProtectionPlanID,
+	}
+	if vnetSpec.CIDR == "" {
+		vnetSpec.CIDR = infrav1.DefaultVnetCIDR
 	}
 	if err := r.vnetSvc.Reconcile(ctx, vnetSpec); err != nil {
 		return errors.Wrapf(err, "failed to reconcile virtual network for cluster %s", r.scope.ClusterName())
 	}
+	// the address space of an existing vnet is read from Azure, the one of a new vnet is the one it was created with
+	if r.scope.Vnet().CidrBlock == "" {
+		r.scope.Vnet().CidrBlock = vnetSpec.CIDR
+	}
+
+	// the subnets of a vnet with a defaulted name get their CIDR blocks from the webhook, and the subnets of
+	// a custom vnet already exist, their CIDR blocks are read from Azure
+	if r.scope.Vnet().IsManaged(r.scope.ClusterName()) && !r.scope.AzureCluster.HasDefaultVnetName() {
+		if err := r.scope.AzureCluster.Spec.NetworkSpec.AllocateSubnetCIDRs(); err != nil {
+			return errors.Wrapf(err, "failed to allocate subnet CIDR blocks for cluster %s", r.scope.ClusterName())
+		}
+	}
+
+	if err := r.asgSvc.Reconcile(ctx); err != nil {
+		return errors.Wrapf(err, "failed to reconcile application security groups for cluster %s", r.scope.ClusterName())
+	}
 
 	cpSubnet := r.scope.ControlPlaneSubnet()
 	if cpSubnet.SecurityGroup.IngressRules == nil {
@@ -120,6 +169,9 @@ func (r *azureClusterReconciler) Reconcile(ctx context.Context) error {
 	rtSpec := &routetables.Spec{
 		Name: r.scope.NodeSubnet().RouteTable.Name,
 	}
+	if r.scope.OutboundType() == infrav1.OutboundTypeUserDefinedRouting {
+		rtSpec.DefaultRouteNextHopIP = r.scope.AzureCluster.Spec.NetworkSpec.DefaultRouteNextHopIP
+	}
 	if err := r.routeTableSvc.Reconcile(ctx, rtSpec); err != nil {
 		return errors.Wrapf(err, "failed to reconcile route table %s for cluster %s", r.scope.NodeSubnet().RouteTable.Name, r.scope.ClusterName())
 	}
@@ -132,29 +184,43 @@ func (r *azureClusterReconciler) Reconcile(ctx context.Context) error {
 		Role:                r.scope.ControlPlaneSubnet().Role,
 		RouteTableName:      r.scope.ControlPlaneSubnet().RouteTable.Name,
 		InternalLBIPAddress: r.scope.ControlPlaneSubnet().InternalLBIPAddress,
+		ServiceEndpoints:    r.scope.ControlPlaneSubnet().ServiceEndpoints,
+		HasPrivateEndpoints: len(r.scope.ControlPlaneSubnet().PrivateEndpoints) > 0,
 	}
 	if err := r.subnetsSvc.Reconcile(ctx, subnetSpec); err != nil {
 		return errors.Wrapf(err, "failed to reconcile control plane subnet for cluster %s", r.scope.ClusterName())
 	}
 
 	subnetSpec = &subnets.Spec{
-		Name:              r.scope.NodeSubnet().Name,
-		CIDR:              r.scope.NodeSubnet().CidrBlock,
-		VnetName:          r.scope.Vnet().Name,
-		SecurityGroupName: r.scope.NodeSubnet().SecurityGroup.Name,
-		RouteTableName:    r.scope.NodeSubnet().RouteTable.Name,
-		Role:              r.scope.NodeSubnet().Role,
+		Name:                r.scope.NodeSubnet().Name,
+		CIDR:                r.scope.NodeSubnet().CidrBlock,
+		VnetName:            r.scope.Vnet().Name,
+		SecurityGroupName:   r.scope.NodeSubnet().SecurityGroup.Name,
+		RouteTableName:      r.scope.NodeSubnet().RouteTable.Name,
+		Role:                r.scope.NodeSubnet().Role,
+		ServiceEndpoints:    r.scope.NodeSubnet().ServiceEndpoints,
+		HasPrivateEndpoints: len(r.scope.NodeSubnet().PrivateEndpoints) > 0,
 	}
 	if err := r.subnetsSvc.Reconcile(ctx, subnetSpec); err != nil {
 		return errors.Wrapf(err, "failed to reconcile node subnet for cluster %s", r.scope.ClusterName())
 	}
 
+	if err := r.checkDefaultRoutes(ctx); err != nil {
+		return errors.Wrapf(err, "failed to check default routes for cluster %s", r.scope.ClusterName())
+	}
+
+	if err := r.privateEndpointsSvc.Reconcile(ctx); err != nil {
+		return errors.Wrapf(err, "failed to reconcile private endpoints for cluster %s", r.scope.ClusterName())
+	}
+
 	internalLBSpec := &internalloadbalancers.Spec{
-		Name:       azure.GenerateInternalLBName(r.scope.ClusterName()),
-		SubnetName: r.scope.ControlPlaneSubnet().Name,
-		SubnetCidr: r.scope.ControlPlaneSubnet().CidrBlock,
-		VnetName:   r.scope.Vnet().Name,
-		IPAddress:  r.scope.ControlPlaneSubnet().InternalLBIPAddress,
+		Name:        azure.GenerateInternalLBName(r.scope.ClusterName()),
+		SubnetName:  r.scope.ControlPlaneSubnet().Name,
+		SubnetCidr:  r.scope.ControlPlaneSubnet().CidrBlock,
+		VnetName:    r.scope.Vnet().Name,
+		IPAddress:   r.scope.ControlPlaneSubnet().InternalLBIPAddress,
+		SKU:         r.scope.LoadBalancerSKU(),
+		HealthProbe: r.scope.APIServerHealthProbe(),
 	}
 	if err := r.internalLBSvc.Reconcile(ctx, internalLBSpec); err != nil {
 		return errors.Wrapf(err, "failed to reconcile control plane internal load balancer for cluster %s", r.scope.ClusterName())
@@ -165,21 +231,45 @@ func (r *azureClusterReconciler) Reconcile(ctx context.Context) error {
 	}
 
 	publicLBSpec := &publicloadbalancers.Spec{
-		Name:         azure.GeneratePublicLBName(r.scope.ClusterName()),
-		PublicIPName: r.scope.Network().APIServerIP.Name,
-		Role:         infrav1.APIServerRole,
+		Name:                  azure.GeneratePublicLBName(r.scope.ClusterName()),
+		PublicIPName:          r.scope.Network().APIServerIP.Name,
+		Role:                  infrav1.APIServerRole,
+		PublicIPResourceGroup: r.scope.APIServerIPResourceGroup(),
+		SKU:                   r.scope.LoadBalancerSKU(),
+		HealthProbe:           r.scope.APIServerHealthProbe(),
+		DisableOutboundRule:   r.scope.OutboundType() == infrav1.OutboundTypeUserDefinedRouting,
 	}
 	if err := r.publicLBSvc.Reconcile(ctx, publicLBSpec); err != nil {
 		return errors.Wrapf(err, "failed to reconcile control plane public load balancer for cluster %s", r.scope.ClusterName())
 	}
+	r.scope.Network().APIServerLB.SKU = r.scope.LoadBalancerSKU()
+
+	if r.scope.NodeOutboundLBEnabled() {
+		nodeOutboundLB := r.scope.NodeOutboundLB()
+		nodeOutboundLBSpec := &publicloadbalancers.Spec{
+			Name:                   r.scope.ClusterName(),
+			Role:                   infrav1.NodeOutboundRole,
+			SKU:                    r.scope.LoadBalancerSKU(),
+			PublicIPPrefixID:       nodeOutboundLB.PublicIPPrefixID,
+			AllocatedOutboundPorts: nodeOutboundLB.AllocatedOutboundPorts,
+			IdleTimeoutInMinutes:   nodeOutboundLB.IdleTimeoutInMinutes,
+			EnableTCPReset:         nodeOutboundLB.EnableTCPReset,
+		}
+		if ipNames := r.scope.NodeOutboundIPNames(); len(ipNames) > 0 {
+			nodeOutboundLBSpec.PublicIPName = ipNames[0]
+			nodeOutboundLBSpec.AdditionalPublicIPNames = ipNames[1:]
+		}
+		if err := r.publicLBSvc.Reconcile(ctx, nodeOutboundLBSpec); err != nil {
+			return errors.Wrapf(err, "failed to reconcile node outbound public load balancer for cluster %s", r.scope.ClusterName())
+		}
+	}
 
-	nodeOutboundLBSpec := &publicloadbalancers.Spec{
-		Name:         r.scope.ClusterName(),
-		PublicIPName: azure.GenerateNodeOutboundIPName(r.scope.ClusterName()),
-		Role:         infrav1.NodeOutboundRole,
+	if err := r.dnsRecordsSvc.Reconcile(ctx); err != nil {
+		return errors.Wrapf(err, "failed to reconcile API server DNS records for cluster %s", r.scope.ClusterName())
 	}
-	if err := r.publicLBSvc.Reconcile(ctx, nodeOutboundLBSpec); err != nil {
-		return errors.Wrapf(err, "failed to reconcile node outbound public load balancer for cluster %s", r.scope.ClusterName())
+
+	if err := r.deleteUnusedNodeOutboundLB(ctx); err != nil {
+		return errors.Wrapf(err, "failed to delete unused node outbound resources for cluster %s", r.scope.ClusterName())
 	}
 
 	return nil
@@ -187,10 +277,18 @@ func (r *azureClusterReconciler) Reconcile(ctx context.Context) error {
 
 // Delete reconciles all the services in pre determined order
 func (r *azureClusterReconciler) Delete(ctx context.Context) error {
+	if err := r.dnsRecordsSvc.Delete(ctx); err != nil {
+		return errors.Wrapf(err, "failed to delete API server DNS records for cluster %s", r.scope.ClusterName())
+	}
+
 	if err := r.deleteLB(ctx); err != nil {
 		return errors.Wrap(err, "failed to delete load balancer")
 	}
 
+	if err := r.privateEndpointsSvc.Delete(ctx); err != nil {
+		return errors.Wrapf(err, "failed to delete private endpoints for cluster %s", r.scope.ClusterName())
+	}
+
 	if err := r.deleteSubnets(ctx); err != nil {
 		return errors.Wrap(err, "failed to delete subnets")
 	}
@@ -208,6 +306,21 @@ func (r *azureClusterReconciler) Delete(ctx context.Context) error {
 		return errors.Wrap(err, "failed to delete network security group")
 	}
 
+	if err := r.asgSvc.Delete(ctx); err != nil {
+		return errors.Wrapf(err, "failed to delete application security groups for cluster %s", r.scope.ClusterName())
+	}
+
+	if ppgName := r.scope.ProximityPlacementGroupName(); ppgName != "" {
+		if err := r.ppgSvc.Delete(ctx, &proximityplacementgroups.Spec{Name: ppgName}); err != nil {
+			return errors.Wrapf(err, "failed to delete proximity placement group for cluster %s", r.scope.ClusterName())
+		}
+	}
+
+	bootDiagnosticsSpec := &storageaccounts.Spec{Name: r.scope.BootDiagnosticsStorageAccountName()}
+	if err := r.storageAccountsSvc.Delete(ctx, bootDiagnosticsSpec); err != nil {
+		return errors.Wrapf(err, "failed to delete boot diagnostics storage account for cluster %s", r.scope.ClusterName())
+	}
+
 	vnetSpec := &virtualnetworks.Spec{
 		ResourceGroup: r.scope.Vnet().ResourceGroup,
 		Name:          r.scope.Vnet().Name,
@@ -250,6 +363,10 @@ func (r *azureClusterReconciler) deleteLB(ctx context.Context) error {
 		return errors.Wrapf(err, "failed to delete public IPs for cluster %s", r.scope.ClusterName())
 	}
 
+	if err := r.deleteOrphanedNodeOutboundIPs(ctx); err != nil {
+		return errors.Wrapf(err, "failed to delete node outbound public IPs for cluster %s", r.scope.ClusterName())
+	}
+
 	internalLBSpec := &internalloadbalancers.Spec{
 		Name: azure.GenerateInternalLBName(r.scope.ClusterName()),
 	}
@@ -262,6 +379,62 @@ func (r *azureClusterReconciler) deleteLB(ctx context.Context) error {
 	return nil
 }
 
+// deleteUnusedNodeOutboundLB deletes the node outbound load balancer when it is disabled, and the node outbound
+// public IPs it no longer uses, e.g. after lowering frontendIPsCount or switching to a public IP prefix.
+// Nodes keep the load balancer in use until their network interfaces are updated, so its deletion is retried until then.
+func (r *azureClusterReconciler) deleteUnusedNodeOutboundLB(ctx context.Context) error {
+	if !r.scope.NodeOutboundLBEnabled() {
+		nodeOutboundLBSpec := &publicloadbalancers.Spec{
+			Name: r.scope.ClusterName(),
+		}
+		if err := r.publicLBSvc.Delete(ctx, nodeOutboundLBSpec); err != nil {
+			return errors.Wrapf(err, "failed to delete node outbound load balancer %s", nodeOutboundLBSpec.Name)
+		}
+	}
+	return r.deleteOrphanedNodeOutboundIPs(ctx)
+}
+
+// deleteOrphanedNodeOutboundIPs deletes the node outbound public IPs of the cluster which are no longer needed.
+func (r *azureClusterReconciler) deleteOrphanedNodeOutboundIPs(ctx context.Context) error {
+	ips, err := r.publicIPsClient.List(ctx, r.scope.ResourceGroup())
+	if err != nil {
+		if azure.ResourceNotFound(err) {
+			return nil
+		}
+		return errors.Wrapf(err, "failed to list public IPs in resource group %s", r.scope.ResourceGroup())
+	}
+
+	wanted := make(map[string]bool)
+	for _, name := range r.scope.NodeOutboundIPNames() {
+		wanted[name] = true
+	}
+	prefix := azure.GenerateNodeOutboundIPName(r.scope.ClusterName())
+	for _, ip := range ips {
+		name := to.String(ip.Name)
+		if wanted[name] || !isNodeOutboundIPName(name, prefix) {
+			continue
+		}
+		klog.V(2).Infof("deleting unused node outbound public IP %s", name)
+		if err := r.publicIPsClient.Delete(ctx, r.scope.ResourceGroup(), name); err != nil && !azure.ResourceNotFound(err) {
+			return errors.Wrapf(err, "failed to delete public IP %s in resource group %s", name, r.scope.ResourceGroup())
+		}
+	}
+	return nil
+}
+
+// isNodeOutboundIPName returns true if name is the name of the first or of an additional node outbound public IP.
+func isNodeOutboundIPName(name, prefix string) bool {
+	if name == prefix {
+		return true
+	}
+	index := strings.TrimPrefix(name, prefix+"-")
+	if index == name {
+		return false
+	}
+	_, err := strconv.Atoi(index)
+	return err == nil
+}
+
 func (r *azureClusterReconciler) deleteSubnets(ctx context.Context) error {
 	for _, s := range r.scope.Subnets() {
 		subnetSpec := &subnets.Spec{
@@ -299,7 +472,11 @@ func (r *azureClusterReconciler) deleteNSG(ctx context.Context) error {
 }
 
 // CreateOrUpdateNetworkAPIServerIP creates or updates public ip name and dns name
-func (r *azureClusterReconciler) createOrUpdateNetworkAPIServerIP() error {
+func (r *azureClusterReconciler) createOrUpdateNetworkAPIServerIP(ctx context.Context) error {
+	if r.scope.APIServerIPSpec().ID != "" {
+		return r.adoptAPIServerIP(ctx)
+	}
+
 	if r.scope.Network().APIServerIP.Name == "" {
 		h := fnv.New32a()
 		if _, err := h.Write([]byte(fmt.Sprintf("%s/%s/%s", r.scope.SubscriptionID(), r.scope.ResourceGroup(), r.scope.ClusterName()))); err != nil {
@@ -312,6 +489,91 @@ func (r *azureClusterReconciler) createOrUpdateNetworkAPIServerIP() error {
 	return nil
 }
 
+// adoptAPIServerIP records the name and DNS name of a pre-existing API server public IP
+func (r *azureClusterReconciler) adoptAPIServerIP(ctx context.Context) error {
+	id := r.scope.APIServerIPSpec().ID
+	res, err := autorestazure.ParseResourceID(id)
+	if err != nil {
+		return errors.Wrapf(err, "invalid API server public IP ID %s", id)
+	}
+	if !strings.EqualFold(res.SubscriptionID, r.scope.SubscriptionID()) {
+		return errors.Errorf("API server public IP %s must be in subscription %s", id, r.scope.SubscriptionID())
+	}
+
+	ip, err := r.publicIPsClient.Get(ctx, res.ResourceGroup, res.ResourceName)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get API server public IP %s", id)
+	}
+	if sku := r.scope.LoadBalancerSKU(); ip.Sku == nil || string(ip.Sku.Name) != string(sku) {
+		return errors.Errorf("API server public IP %s must have the %s SKU of the load balancers", id, sku)
+	}
+	if ip.PublicIPAddressPropertiesFormat == nil {
+		return errors.Errorf("API server public IP %s has no properties", id)
+	}
+	// Basic public IPs may use dynamic allocation, like the ones created for Basic load balancers, but their
+	// address is only assigned once attached, so the control plane endpoint must then use their DNS name.
+	if ip.PublicIPAllocationMethod != network.Static {
+		if r.scope.LoadBalancerSKU() != infrav1.SKUBasic {
+			return errors.Errorf("API server public IP %s must use static allocation", id)
+		}
+		if ip.DNSSettings == nil || to.String(ip.DNSSettings.Fqdn) == "" {
+			return errors.Errorf("API server public IP %s must have a DNS name since it uses dynamic allocation", id)
+		}
+	}
+
+	r.scope.Network().APIServerIP.ID = id
+	r.scope.Network().APIServerIP.Name = res.ResourceName
+	r.scope.Network().APIServerIP.IPAddress = to.String(ip.IPAddress)
+	r.scope.Network().APIServerIP.DNSName = to.String(ip.IPAddress)
+	if ip.DNSSettings != nil && to.String(ip.DNSSettings.Fqdn) != "" {
+		r.scope.Network().APIServerIP.DNSName = to.String(ip.DNSSettings.Fqdn)
+	}
+	return nil
+}
+
+// checkDefaultRoutes verifies that the subnets of a cluster using user-defined routing send their egress traffic
+// to a virtual appliance, since machines have no other way to reach the internet.
+func (r *azureClusterReconciler) checkDefaultRoutes(ctx context.Context) error {
+	if r.scope.OutboundType() != infrav1.OutboundTypeUserDefinedRouting {
+		conditions.Delete(r.scope.AzureCluster, infrav1.DefaultRouteReadyCondition)
+		return nil
+	}
+
+	for _, subnet := range []*infrav1.SubnetSpec{r.scope.ControlPlaneSubnet(), r.scope.NodeSubnet()} {
+		if err := r.checkDefaultRoute(ctx, subnet.Name); err != nil {
+			conditions.MarkFalse(r.scope.AzureCluster, infrav1.DefaultRouteReadyCondition, infrav1.DefaultRouteMissingReason, clusterv1.ConditionSeverityError, err.Error())
+			return err
+		}
+	}
+	conditions.MarkTrue(r.scope.AzureCluster, infrav1.DefaultRouteReadyCondition)
+	return nil
+}
+
+// checkDefaultRoute verifies that the route table of the subnet has a default route to a virtual appliance.
+func (r *azureClusterReconciler) checkDefaultRoute(ctx context.Context, subnetName string) error {
+	subnet, err := r.subnetsClient.Get(ctx, r.scope.Vnet().ResourceGroup, r.scope.Vnet().Name, subnetName)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get subnet %s", subnetName)
+	}
+	if subnet.SubnetPropertiesFormat == nil || subnet.RouteTable == nil || to.String(subnet.RouteTable.ID) == "" {
+		return errors.Errorf("subnet %s has no route table", subnetName)
+	}
+
+	id := to.String(subnet.RouteTable.ID)
+	res, err := autorestazure.ParseResourceID(id)
+	if err != nil {
+		return errors.Wrapf(err, "invalid route table ID %s", id)
+	}
+	routeTable, err := routetables.NewClient(azure.WithSubscriptionID(r.scope, res.SubscriptionID)).Get(ctx, res.ResourceGroup, res.ResourceName)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get route table %s", id)
+	}
+	if routetables.DefaultRouteNextHopIP(routeTable) == "" {
+		return errors.Errorf("route table %s of subnet %s has no 0.0.0.0/0 route to a virtual appliance", res.ResourceName, subnetName)
+	}
+	return nil
+}
+
 func (r *azureClusterReconciler) setFailureDomainsForLocation(ctx context.Context) error {
 	spec := &availabilityzones.Spec{}
 	zonesInterface, err := r.availabilityZonesSvc.Get(ctx, spec)
@@ -320,9 +582,19 @@ func (r *azureClusterReconciler) setFailureDomainsForLocation(ctx context.Contex
 	}
 
 	zones := zonesInterface.([]string)
+	// Basic load balancers do not support availability zones, so the cluster has no failure domain.
+	if r.scope.LoadBalancerSKU() == infrav1.SKUBasic {
+		return nil
+	}
+	// Spreading the control plane across zones would split the proximity placement group of the cluster, so its
+	// machines all land in the first zone instead when they use it.
+	inPPG, err := r.scope.ControlPlaneInProximityPlacementGroup(ctx)
+	if err != nil {
+		return err
+	}
 	for _, zone := range zones {
 		r.scope.SetFailureDomain(zone, clusterv1.FailureDomainSpec{
-			ControlPlane: true,
+			ControlPlane: !inPPG,
 		})
 	}
 
@@ -341,24 +613,24 @@ func (r *azureClusterReconciler) generateControlPlaneIngressRules() infrav1.Ingr
 
 	return infrav1.IngressRules{
 		&infrav1.IngressRule{
-			Name:             "allow_ssh",
-			Description:      "Allow SSH",
-			Priority:         100,
-			Protocol:         infrav1.SecurityGroupProtocolTCP,
-			Source:           to.StringPtr("*"),
-			SourcePorts:      to.StringPtr("*"),
-			Destination:      to.StringPtr("*"),
-			DestinationPorts: to.StringPtr("22"),
+			Name:                                "allow_ssh",
+			Description:                         "Allow SSH",
+			Priority:                            100,
+			Protocol:                            infrav1.SecurityGroupProtocolTCP,
+			Source:                              to.StringPtr("*"),
+			SourcePorts:                         to.StringPtr("*"),
+			DestinationApplicationSecurityGroup: infrav1.SecurityGroupControlPlane,
+			DestinationPorts:                    to.StringPtr("22"),
 		},
 		&infrav1.IngressRule{
-			Name:             "allow_apiserver",
-			Description:      "Allow K8s API Server",
-			Priority:         101,
-			Protocol:         infrav1.SecurityGroupProtocolTCP,
-			Source:           to.StringPtr("*"),
-			SourcePorts:      to.StringPtr("*"),
-			Destination:      to.StringPtr("*"),
-			DestinationPorts: to.StringPtr(apiPort),
+			Name:                                "allow_apiserver",
+			Description:                         "Allow K8s API Server",
+			Priority:                            101,
+			Protocol:                            infrav1.SecurityGroupProtocolTCP,
+			Source:                              to.StringPtr("*"),
+			SourcePorts:                         to.StringPtr("*"),
+			DestinationApplicationSecurityGroup: infrav1.SecurityGroupControlPlane,
+			DestinationPorts:                    to.StringPtr(apiPort),
 		},
 	}
 }
@@ -0,0 +1,243 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha3"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha3"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/mocks"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/scope"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/publicips/mock_publicips"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/publicloadbalancers"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/publicloadbalancers/mock_publicloadbalancers"
+)
+
+func TestDeleteUnusedNodeOutboundLB(t *testing.T) {
+	publicIPs := func(names ...string) []network.PublicIPAddress {
+		ips := make([]network.PublicIPAddress, len(names))
+		for i, name := range names {
+			ips[i] = network.PublicIPAddress{Name: to.StringPtr(name)}
+		}
+		return ips
+	}
+
+	testcases := []struct {
+		name           string
+		nodeOutboundLB *infrav1.NodeOutboundLBSpec
+		outboundType   infrav1.OutboundType
+		expect         func(ip *mock_publicips.MockClientMockRecorder, lb *mock_publicloadbalancers.MockClientMockRecorder)
+	}{
+		{
+			name:           "all public IPs in use",
+			nodeOutboundLB: &infrav1.NodeOutboundLBSpec{FrontendIPsCount: to.Int32Ptr(2)},
+			expect: func(ip *mock_publicips.MockClientMockRecorder, lb *mock_publicloadbalancers.MockClientMockRecorder) {
+				ip.List(gomock.Any(), "my-rg").Return(publicIPs("pip-my-cluster-node-outbound", "pip-my-cluster-node-outbound-1", "pip-my-cluster-apiserver"), nil)
+			},
+		},
+		{
+			name:           "frontendIPsCount lowered",
+			nodeOutboundLB: &infrav1.NodeOutboundLBSpec{FrontendIPsCount: to.Int32Ptr(1)},
+			expect: func(ip *mock_publicips.MockClientMockRecorder, lb *mock_publicloadbalancers.MockClientMockRecorder) {
+				ip.List(gomock.Any(), "my-rg").Return(publicIPs("pip-my-cluster-node-outbound", "pip-my-cluster-node-outbound-1", "pip-my-cluster-node-outbound-2"), nil)
+				ip.Delete(gomock.Any(), "my-rg", "pip-my-cluster-node-outbound-1")
+				ip.Delete(gomock.Any(), "my-rg", "pip-my-cluster-node-outbound-2")
+			},
+		},
+		{
+			name:           "public IP prefix used",
+			nodeOutboundLB: &infrav1.NodeOutboundLBSpec{PublicIPPrefixID: "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/publicIPPrefixes/my-prefix"},
+			expect: func(ip *mock_publicips.MockClientMockRecorder, lb *mock_publicloadbalancers.MockClientMockRecorder) {
+				ip.List(gomock.Any(), "my-rg").Return(publicIPs("pip-my-cluster-node-outbound", "pip-my-cluster-node-outbound-extra"), nil)
+				ip.Delete(gomock.Any(), "my-rg", "pip-my-cluster-node-outbound")
+			},
+		},
+		{
+			name:           "node outbound load balancer disabled",
+			nodeOutboundLB: &infrav1.NodeOutboundLBSpec{Disabled: true},
+			expect: func(ip *mock_publicips.MockClientMockRecorder, lb *mock_publicloadbalancers.MockClientMockRecorder) {
+				lb.Delete(gomock.Any(), "my-rg", "my-cluster")
+				ip.List(gomock.Any(), "my-rg").Return(publicIPs("pip-my-cluster-node-outbound"), nil)
+				ip.Delete(gomock.Any(), "my-rg", "pip-my-cluster-node-outbound")
+			},
+		},
+		{
+			name:           "user defined routing outbound type",
+			nodeOutboundLB: &infrav1.NodeOutboundLBSpec{FrontendIPsCount: to.Int32Ptr(2)},
+			outboundType:   infrav1.OutboundTypeUserDefinedRouting,
+			expect: func(ip *mock_publicips.MockClientMockRecorder, lb *mock_publicloadbalancers.MockClientMockRecorder) {
+				lb.Delete(gomock.Any(), "my-rg", "my-cluster")
+				ip.List(gomock.Any(), "my-rg").Return(publicIPs("pip-my-cluster-node-outbound", "pip-my-cluster-node-outbound-1", "pip-my-cluster-apiserver"), nil)
+				ip.Delete(gomock.Any(), "my-rg", "pip-my-cluster-node-outbound")
+				ip.Delete(gomock.Any(), "my-rg", "pip-my-cluster-node-outbound-1")
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			clusterScope := &scope.ClusterScope{
+				Cluster: &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "my-cluster"}},
+				AzureCluster: &infrav1.AzureCluster{
+					Spec: infrav1.AzureClusterSpec{
+						ResourceGroup: "my-rg",
+						NetworkSpec:   infrav1.NetworkSpec{NodeOutboundLB: tc.nodeOutboundLB, OutboundType: tc.outboundType},
+					},
+				},
+			}
+			publicIPsMock := mock_publicips.NewMockClient(mockCtrl)
+			publicLBMock := mock_publicloadbalancers.NewMockClient(mockCtrl)
+			tc.expect(publicIPsMock.EXPECT(), publicLBMock.EXPECT())
+
+			r := &azureClusterReconciler{
+				scope:           clusterScope,
+				publicIPsClient: publicIPsMock,
+				publicLBSvc: &publicloadbalancers.Service{
+					Scope:  clusterScope,
+					Client: publicLBMock,
+				},
+			}
+			g.Expect(r.deleteUnusedNodeOutboundLB(context.TODO())).To(Succeed())
+		})
+	}
+}
+
+func TestSetFailureDomainsForLocation(t *testing.T) {
+	controlPlaneMachine := func(name string, ppgName *string) *infrav1.AzureMachine {
+		return &infrav1.AzureMachine{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+				Labels: map[string]string{
+					clusterv1.ClusterLabelName:             "my-cluster",
+					clusterv1.MachineControlPlaneLabelName: "",
+				},
+			},
+			Spec: infrav1.AzureMachineSpec{ProximityPlacementGroupName: ppgName},
+		}
+	}
+
+	testcases := []struct {
+		name                 string
+		ppg                  *infrav1.ProximityPlacementGroupSpec
+		machines             []runtime.Object
+		expectedControlPlane bool
+	}{
+		{
+			name:                 "no proximity placement group",
+			expectedControlPlane: true,
+		},
+		{
+			name:                 "proximity placement group without control plane machines",
+			ppg:                  &infrav1.ProximityPlacementGroupSpec{},
+			expectedControlPlane: false,
+		},
+		{
+			name:                 "control plane machines in the proximity placement group",
+			ppg:                  &infrav1.ProximityPlacementGroupSpec{},
+			machines:             []runtime.Object{controlPlaneMachine("my-cluster-cp-0", nil)},
+			expectedControlPlane: false,
+		},
+		{
+			name:                 "control plane machines opted out of the proximity placement group",
+			ppg:                  &infrav1.ProximityPlacementGroupSpec{},
+			machines:             []runtime.Object{controlPlaneMachine("my-cluster-cp-0", to.StringPtr(""))},
+			expectedControlPlane: true,
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			s := runtime.NewScheme()
+			g.Expect(clusterv1.AddToScheme(s)).To(Succeed())
+			g.Expect(infrav1.AddToScheme(s)).To(Succeed())
+
+			clusterScope, err := scope.NewClusterScope(scope.ClusterScopeParams{
+				AzureClients: scope.AzureClients{
+					Authorizer: autorest.NullAuthorizer{},
+				},
+				Client:  fake.NewFakeClientWithScheme(s, tc.machines...),
+				Cluster: &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "my-cluster", Namespace: "default"}},
+				AzureCluster: &infrav1.AzureCluster{
+					ObjectMeta: metav1.ObjectMeta{Name: "my-cluster", Namespace: "default"},
+					Spec: infrav1.AzureClusterSpec{
+						Location:                "test-location",
+						ResourceGroup:           "my-rg",
+						SubscriptionID:          "123",
+						ProximityPlacementGroup: tc.ppg,
+					},
+				},
+			})
+			g.Expect(err).NotTo(HaveOccurred())
+
+			zonesMock := mocks.NewMockGetterService(mockCtrl)
+			zonesMock.EXPECT().Get(gomock.Any(), gomock.Any()).Return([]string{"1", "2"}, nil)
+
+			r := &azureClusterReconciler{
+				scope:                clusterScope,
+				availabilityZonesSvc: zonesMock,
+			}
+			g.Expect(r.setFailureDomainsForLocation(context.TODO())).To(Succeed())
+			g.Expect(clusterScope.AzureCluster.Status.FailureDomains).To(HaveLen(2))
+			for _, fd := range clusterScope.AzureCluster.Status.FailureDomains {
+				g.Expect(fd.ControlPlane).To(Equal(tc.expectedControlPlane))
+			}
+		})
+	}
+}
+
+func TestGenerateControlPlaneIngressRules(t *testing.T) {
+	g := NewWithT(t)
+
+	r := &azureClusterReconciler{
+		scope: &scope.ClusterScope{
+			Cluster: &clusterv1.Cluster{
+				Spec: clusterv1.ClusterSpec{
+					ClusterNetwork: &clusterv1.ClusterNetwork{APIServerPort: to.Int32Ptr(443)},
+				},
+			},
+		},
+	}
+	rules := r.generateControlPlaneIngressRules()
+	g.Expect(rules).To(HaveLen(2))
+	for _, rule := range rules {
+		g.Expect(rule.Destination).To(BeNil())
+		g.Expect(rule.DestinationApplicationSecurityGroup).To(Equal(infrav1.SecurityGroupControlPlane))
+	}
+	g.Expect(rules[1].DestinationPorts).To(Equal(to.StringPtr("443")))
+}
@@ -40,16 +40,25 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha3"
+	azure "sigs.k8s.io/cluster-api-provider-azure/cloud"
 	"sigs.k8s.io/cluster-api-provider-azure/cloud/scope"
 	"sigs.k8s.io/cluster-api-provider-azure/util/reconciler"
 )
 
+// bootstrapPollInterval is the interval at which the bootstrap extension of a bootstrapping VM is checked.
+const bootstrapPollInterval = 30 * time.Second
+
 // AzureMachineReconciler reconciles a AzureMachine object
 type AzureMachineReconciler struct {
 	client.Client
 	Log              logr.Logger
 	Recorder         record.EventRecorder
 	ReconcileTimeout time.Duration
+	// AcceptMarketplaceTerms accepts the marketplace terms of the purchase plans of machine images.
+	AcceptMarketplaceTerms bool
+	// BootstrapSentinelExtension tracks the bootstrap of new Linux VMs with the bootstrap sentinel extension, which
+	// requires a bootstrap provider writing the bootstrap sentinel file.
+	BootstrapSentinelExtension bool
 }
 
 func (r *AzureMachineReconciler) SetupWithManager(mgr ctrl.Manager, options controller.Options) error {
@@ -106,6 +115,7 @@ func (r *AzureMachineReconciler) SetupWithManager(mgr ctrl.Manager, options cont
 // +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=machines;machines/status,verbs=get;list;watch
 // +kubebuilder:rbac:groups="",resources=events,verbs=get;list;watch;create;update;patch
 // +kubebuilder:rbac:groups="",resources=secrets;,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch;create;update;patch
 
 func (r *AzureMachineReconciler) Reconcile(req ctrl.Request) (_ ctrl.Result, reterr error) {
 	ctx, cancel := context.WithTimeout(context.Background(), reconciler.DefaultedLoopTimeout(r.ReconcileTimeout))
@@ -215,6 +225,10 @@ func (r *AzureMachineReconciler) findVM(ctx context.Context, scope *scope.Machin
 
 func (r *AzureMachineReconciler) reconcileNormal(ctx context.Context, machineScope *scope.MachineScope, clusterScope *scope.ClusterScope) (reconcile.Result, error) {
 	machineScope.Info("Reconciling AzureMachine")
+	ams := newAzureMachineService(machineScope, clusterScope)
+
+	r.reconcileSerialLog(ctx, machineScope, ams)
+
 	// If the AzureMachine is in an error state, return early.
 	if machineScope.AzureMachine.Status.FailureReason != nil || machineScope.AzureMachine.Status.FailureMessage != nil {
 		machineScope.Info("Error state detected, skipping reconciliation")
@@ -251,11 +265,24 @@ func (r *AzureMachineReconciler) reconcileNormal(ctx context.Context, machineSco
 		}
 	}
 
-	ams := newAzureMachineService(machineScope, clusterScope)
+	// The image of an existing VM is pinned from the VM itself once it is retrieved.
+	if machineScope.GetVMID() == nil {
+		if err := r.reconcileImage(ctx, machineScope, ams); err != nil {
+			if terr, ok := azure.IsTerminalError(err); ok {
+				SetTerminalFailure(r.Recorder, machineScope.AzureMachine, machineScope, terr.Reason, err)
+				return reconcile.Result{}, nil
+			}
+			return reconcile.Result{}, errors.Wrap(err, "failed to resolve VM image")
+		}
+	}
 
 	// Get or create the virtual machine.
 	vm, err := r.getOrCreate(ctx, machineScope, ams)
 	if err != nil {
+		if terr, ok := azure.IsTerminalError(err); ok {
+			SetTerminalFailure(r.Recorder, machineScope.AzureMachine, machineScope, terr.Reason, err)
+			return reconcile.Result{}, nil
+		}
 		return reconcile.Result{}, err
 	}
 
@@ -266,11 +293,49 @@ func (r *AzureMachineReconciler) reconcileNormal(ctx context.Context, machineSco
 
 	machineScope.SetAddresses(vm.Addresses)
 
+	machineScope.SetDedicatedHostID(vm.HostID)
+
+	r.reconcileVMImage(ctx, machineScope, ams, vm)
+
 	// Proceed to reconcile the AzureMachine state.
 	machineScope.SetVMState(vm.State)
 
+	bootstrapped := false
+
 	switch vm.State {
 	case infrav1.VMStateSucceeded:
+		if machineScope.AzureMachine.Spec.SpotVMOptions != nil {
+			result, running, err := r.reconcileSpotVM(ctx, machineScope, ams)
+			if err != nil {
+				return reconcile.Result{}, errors.Wrap(err, "failed to reconcile spot VM")
+			}
+			if !running {
+				return result, nil
+			}
+		}
+		resized, err := r.reconcileVMSize(ctx, machineScope, ams, vm)
+		if err != nil {
+			return reconcile.Result{}, errors.Wrap(err, "failed to resize VM")
+		}
+		if !resized {
+			machineScope.V(2).Info("VM is resizing", "id", *machineScope.GetVMID())
+			machineScope.SetNotReady()
+			break
+		}
+		bootstrapped, err = ams.vmExtensionsSvc.ReconcileBootstrap(ctx)
+		if err != nil {
+			machineScope.SetNotReady()
+			if terr, ok := azure.IsTerminalError(err); ok {
+				SetTerminalFailure(r.Recorder, machineScope.AzureMachine, machineScope, terr.Reason, err)
+				return reconcile.Result{}, nil
+			}
+			return reconcile.Result{}, errors.Wrap(err, "failed to reconcile bootstrap extension")
+		}
+		if !bootstrapped {
+			machineScope.V(2).Info("VM is bootstrapping", "id", *machineScope.GetVMID())
+			machineScope.SetNotReady()
+			break
+		}
 		machineScope.V(2).Info("VM is running", "id", *machineScope.GetVMID())
 		machineScope.SetReady()
 	case infrav1.VMStateCreating:
@@ -297,12 +362,29 @@ func (r *AzureMachineReconciler) reconcileNormal(ctx context.Context, machineSco
 		machineScope.SetFailureMessage(errors.Errorf("Azure VM state %q is undefined", vm.State))
 	}
 
+	// Install the VM extensions once the VM is running and bootstrapped, as Azure runs one extension operation at a
+	// time on a VM.
+	if bootstrapped {
+		if err := ams.vmExtensionsSvc.Reconcile(ctx); err != nil {
+			r.Recorder.Eventf(machineScope.AzureMachine, corev1.EventTypeWarning, "FailedVMExtensions", err.Error())
+			return reconcile.Result{}, errors.Wrap(err, "failed to reconcile VM extensions")
+		}
+	}
+
 	// Ensure that the tags are correct.
 	err = r.reconcileTags(ctx, machineScope, clusterScope, machineScope.AdditionalTags())
 	if err != nil {
 		return reconcile.Result{}, errors.Errorf("failed to ensure tags: %+v", err)
 	}
 
+	// Check the bootstrap of the VM again until it completes, the VM is updating while the bootstrap extension runs.
+	if !bootstrapped && machineScope.BootstrapExtensionSpec() != nil && (vm.State == infrav1.VMStateSucceeded || vm.State == infrav1.VMStateUpdating) {
+		return reconcile.Result{RequeueAfter: bootstrapPollInterval}, nil
+	}
+	// Likewise, check a resize of the VM again until it completes.
+	if machineScope.IsVMResizing() && (vm.State == infrav1.VMStateSucceeded || vm.State == infrav1.VMStateUpdating) {
+		return reconcile.Result{RequeueAfter: vmResizePollInterval}, nil
+	}
 	return reconcile.Result{}, nil
 }
 
@@ -313,7 +395,14 @@ func (r *AzureMachineReconciler) getOrCreate(ctx context.Context, scope *scope.M
 	}
 
 	if vm == nil {
-		// Create a new VM if we couldn't find a running VM.
+		// A Spot VM is deleted by Azure when evicted with the Delete eviction policy, its machine must be replaced.
+		if scope.GetVMID() != nil && scope.AzureMachine.Spec.SpotVMOptions != nil {
+			r.Recorder.Eventf(scope.AzureMachine, corev1.EventTypeWarning, "SpotVMEvicted", "Spot VM %s was evicted and deleted", scope.Name())
+			return nil, azure.NewTerminalError(capierrors.UpdateMachineError, errors.Errorf("Spot VM %s was evicted and deleted", scope.Name()))
+		}
+
+		// Create a new VM if we couldn't find a running VM, and track its bootstrap when enabled.
+		r.trackBootstrap(scope)
 		vm, err = ams.Reconcile(ctx)
 		if err != nil {
 			return nil, errors.Wrapf(err, "failed to reconcile AzureMachine")
@@ -323,6 +412,14 @@ func (r *AzureMachineReconciler) getOrCreate(ctx context.Context, scope *scope.M
 	return vm, nil
 }
 
+// trackBootstrap marks the bootstrap of a new VM as in progress when the bootstrap sentinel extension is enabled.
+// Otherwise, the machine is ready as soon as its VM is provisioned.
+func (r *AzureMachineReconciler) trackBootstrap(machineScope *scope.MachineScope) {
+	if r.BootstrapSentinelExtension {
+		machineScope.SetBootstrapInProgress()
+	}
+}
+
 func (r *AzureMachineReconciler) reconcileDelete(ctx context.Context, machineScope *scope.MachineScope, clusterScope *scope.ClusterScope) (_ reconcile.Result, reterr error) {
 	machineScope.Info("Handling deleted AzureMachine")
 
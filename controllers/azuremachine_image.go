@@ -0,0 +1,88 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha3"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/scope"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/images"
+)
+
+// reconcileImage pins the image of a machine without a VM in its status, resolving a latest version to the concrete
+// version available when the VM is created. It returns a terminal error when the image doesn't exist, or when the
+// marketplace terms of the image aren't accepted and accepting them before creating the VM isn't enabled.
+func (r *AzureMachineReconciler) reconcileImage(ctx context.Context, machineScope *scope.MachineScope, ams *azureMachineService) error {
+	image, err := getVMImage(machineScope)
+	if err != nil {
+		return err
+	}
+
+	resolved, _, err := ResolveImage(ctx, ams.imagesSvc, image, machineScope.AzureMachine.Status.Image)
+	if err != nil {
+		return err
+	}
+
+	// Make sure the image exists before creating the network interfaces and the VM.
+	if err := ams.imagesSvc.Validate(ctx, resolved); err != nil {
+		return err
+	}
+	if err := ReconcileMarketplaceTerms(ctx, ams.marketplaceAgreementsSvc, resolved, r.AcceptMarketplaceTerms); err != nil {
+		return err
+	}
+	machineScope.AzureMachine.Status.Image = resolved
+	return nil
+}
+
+// reconcileVMImage pins the image of an existing VM in the status of the machine when it isn't pinned yet, e.g. for VMs
+// created by an earlier version of the provider, using the version the VM was created from. It emits an event when a
+// newer version of the image is available. Looking up the image is best effort since it doesn't affect the VM.
+func (r *AzureMachineReconciler) reconcileVMImage(ctx context.Context, machineScope *scope.MachineScope, ams *azureMachineService, vm *infrav1.VM) {
+	image, err := getVMImage(machineScope)
+	if err != nil {
+		machineScope.Error(err, "failed to get VM image")
+		return
+	}
+
+	pinned := machineScope.AzureMachine.Status.Image
+	if pinned == nil {
+		if image.ID != nil {
+			pinned = image
+		} else if images.IsPinnedVersionOf(&vm.Image, image) {
+			pinned = vm.Image.DeepCopy()
+		} else {
+			machineScope.V(2).Info("Unable to determine the image version of the VM", "image", vm.Image)
+			return
+		}
+		machineScope.AzureMachine.Status.Image = pinned
+	}
+	if pinned.ID != nil {
+		return
+	}
+
+	latestInterface, err := ams.imagesSvc.Get(ctx, &images.Spec{Image: image})
+	if err != nil {
+		machineScope.V(2).Info("Unable to look up the latest version of the image", "reason", err.Error())
+		return
+	}
+	if latest, ok := latestInterface.(*infrav1.Image); ok && images.CompareVersions(images.Version(latest), images.Version(pinned)) > 0 {
+		r.Recorder.Eventf(machineScope.AzureMachine, corev1.EventTypeNormal, "NewerImageVersionAvailable", "Version %s of the image is available, the machine uses version %s", images.Version(latest), images.Version(pinned))
+	}
+}
@@ -0,0 +1,339 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2019-12-01/compute"
+	"github.com/Azure/azure-sdk-for-go/services/marketplaceordering/mgmt/2015-06-01/marketplaceordering"
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+	"k8s.io/client-go/tools/record"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha3"
+	azure "sigs.k8s.io/cluster-api-provider-azure/cloud"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/images"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/images/mock_images"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/marketplaceagreements"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/marketplaceagreements/mock_marketplaceagreements"
+)
+
+func TestReconcileImage(t *testing.T) {
+	marketplaceImage := func(version string) *infrav1.Image {
+		return &infrav1.Image{
+			Marketplace: &infrav1.AzureMarketplaceImage{
+				Publisher: "my-publisher",
+				Offer:     "my-offer",
+				SKU:       "my-sku",
+				Version:   version,
+			},
+		}
+	}
+	plan := &infrav1.ImagePlan{Publisher: "my-publisher", Product: "my-offer", Name: "my-plan"}
+	marketplaceImageWithPlan := func(version string) *infrav1.Image {
+		image := marketplaceImage(version)
+		image.Marketplace.Plan = plan
+		return image
+	}
+	imageWithPlan := compute.VirtualMachineImage{
+		VirtualMachineImageProperties: &compute.VirtualMachineImageProperties{
+			Plan: &compute.PurchasePlan{Publisher: to.StringPtr("my-publisher"), Product: to.StringPtr("my-offer"), Name: to.StringPtr("my-plan")},
+		},
+	}
+	terms := func(accepted bool) marketplaceordering.AgreementTerms {
+		return marketplaceordering.AgreementTerms{AgreementProperties: &marketplaceordering.AgreementProperties{Accepted: to.BoolPtr(accepted)}}
+	}
+	versions := func(names ...string) []compute.VirtualMachineImageResource {
+		resources := make([]compute.VirtualMachineImageResource, 0, len(names))
+		for _, name := range names {
+			resources = append(resources, compute.VirtualMachineImageResource{Name: to.StringPtr(name)})
+		}
+		return resources
+	}
+
+	notFoundError := autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not Found")
+
+	testcases := []struct {
+		name          string
+		image         *infrav1.Image
+		pinned        *infrav1.Image
+		acceptTerms   bool
+		expectedImage *infrav1.Image
+		expectedError string
+		expect        func(m *mock_images.MockClientMockRecorder)
+		expectTerms   func(m *mock_marketplaceagreements.MockClientMockRecorder)
+	}{
+		{
+			name:          "pin latest image version",
+			image:         marketplaceImage("latest"),
+			expectedImage: marketplaceImage("1.1.0"),
+			expect: func(m *mock_images.MockClientMockRecorder) {
+				m.ListMarketplaceImageVersions(gomock.Any(), "test-location", "my-publisher", "my-offer", "my-sku").Return(versions("1.0.0", "1.1.0"), nil)
+				m.GetMarketplaceImage(gomock.Any(), "test-location", "my-publisher", "my-offer", "my-sku", "1.1.0").Return(compute.VirtualMachineImage{}, nil).Times(2)
+			},
+		},
+		{
+			name:          "keep pinned image version when a newer version is available",
+			image:         marketplaceImage("latest"),
+			pinned:        marketplaceImage("1.0.0"),
+			expectedImage: marketplaceImage("1.0.0"),
+			expect: func(m *mock_images.MockClientMockRecorder) {
+				m.ListMarketplaceImageVersions(gomock.Any(), "test-location", "my-publisher", "my-offer", "my-sku").Return(versions("1.0.0", "1.1.0"), nil)
+				m.GetMarketplaceImage(gomock.Any(), "test-location", "my-publisher", "my-offer", "my-sku", "1.0.0").Return(compute.VirtualMachineImage{}, nil).Times(2)
+			},
+		},
+		{
+			name:          "pin new image version of the spec",
+			image:         marketplaceImage("1.1.0"),
+			pinned:        marketplaceImage("1.0.0"),
+			expectedImage: marketplaceImage("1.1.0"),
+			expect: func(m *mock_images.MockClientMockRecorder) {
+				m.ListMarketplaceImageVersions(gomock.Any(), "test-location", "my-publisher", "my-offer", "my-sku").Return(versions("1.0.0", "1.1.0"), nil)
+				m.GetMarketplaceImage(gomock.Any(), "test-location", "my-publisher", "my-offer", "my-sku", "1.1.0").Return(compute.VirtualMachineImage{}, nil).Times(2)
+			},
+		},
+		{
+			name:          "image not found",
+			image:         marketplaceImage("1.2.0"),
+			expectedError: `marketplace image with publisher "my-publisher", offer "my-offer", SKU "my-sku" and version "1.2.0" not found in location test-location`,
+			expect: func(m *mock_images.MockClientMockRecorder) {
+				m.ListMarketplaceImageVersions(gomock.Any(), "test-location", "my-publisher", "my-offer", "my-sku").Return(versions("1.0.0", "1.1.0"), nil)
+				m.GetMarketplaceImage(gomock.Any(), "test-location", "my-publisher", "my-offer", "my-sku", "1.2.0").Return(compute.VirtualMachineImage{}, notFoundError)
+			},
+		},
+		{
+			name:          "read plan of a marketplace image",
+			image:         marketplaceImage("1.1.0"),
+			expectedImage: marketplaceImageWithPlan("1.1.0"),
+			expect: func(m *mock_images.MockClientMockRecorder) {
+				m.ListMarketplaceImageVersions(gomock.Any(), "test-location", "my-publisher", "my-offer", "my-sku").Return(versions("1.0.0", "1.1.0"), nil)
+				m.GetMarketplaceImage(gomock.Any(), "test-location", "my-publisher", "my-offer", "my-sku", "1.1.0").Return(imageWithPlan, nil).Times(2)
+			},
+			expectTerms: func(m *mock_marketplaceagreements.MockClientMockRecorder) {
+				m.Get(gomock.Any(), "my-publisher", "my-offer", "my-plan").Return(terms(true), nil)
+			},
+		},
+		{
+			name:          "plan of the spec is kept",
+			image:         marketplaceImageWithPlan("1.1.0"),
+			expectedImage: marketplaceImageWithPlan("1.1.0"),
+			expect: func(m *mock_images.MockClientMockRecorder) {
+				m.ListMarketplaceImageVersions(gomock.Any(), "test-location", "my-publisher", "my-offer", "my-sku").Return(versions("1.0.0", "1.1.0"), nil)
+				m.GetMarketplaceImage(gomock.Any(), "test-location", "my-publisher", "my-offer", "my-sku", "1.1.0").Return(imageWithPlan, nil)
+			},
+			expectTerms: func(m *mock_marketplaceagreements.MockClientMockRecorder) {
+				m.Get(gomock.Any(), "my-publisher", "my-offer", "my-plan").Return(terms(true), nil)
+			},
+		},
+		{
+			name:          "accept marketplace terms",
+			image:         marketplaceImage("1.1.0"),
+			acceptTerms:   true,
+			expectedImage: marketplaceImageWithPlan("1.1.0"),
+			expect: func(m *mock_images.MockClientMockRecorder) {
+				m.ListMarketplaceImageVersions(gomock.Any(), "test-location", "my-publisher", "my-offer", "my-sku").Return(versions("1.0.0", "1.1.0"), nil)
+				m.GetMarketplaceImage(gomock.Any(), "test-location", "my-publisher", "my-offer", "my-sku", "1.1.0").Return(imageWithPlan, nil).Times(2)
+			},
+			expectTerms: func(m *mock_marketplaceagreements.MockClientMockRecorder) {
+				m.Get(gomock.Any(), "my-publisher", "my-offer", "my-plan").Return(terms(false), nil)
+				m.Create(gomock.Any(), "my-publisher", "my-offer", "my-plan", terms(true)).Return(terms(true), nil)
+			},
+		},
+		{
+			name:          "marketplace terms already accepted when accepting is disabled",
+			image:         marketplaceImage("1.1.0"),
+			expectedImage: marketplaceImageWithPlan("1.1.0"),
+			expect: func(m *mock_images.MockClientMockRecorder) {
+				m.ListMarketplaceImageVersions(gomock.Any(), "test-location", "my-publisher", "my-offer", "my-sku").Return(versions("1.0.0", "1.1.0"), nil)
+				m.GetMarketplaceImage(gomock.Any(), "test-location", "my-publisher", "my-offer", "my-sku", "1.1.0").Return(imageWithPlan, nil).Times(2)
+			},
+			expectTerms: func(m *mock_marketplaceagreements.MockClientMockRecorder) {
+				m.Get(gomock.Any(), "my-publisher", "my-offer", "my-plan").Return(terms(true), nil)
+			},
+		},
+		{
+			name:          "marketplace terms not accepted when accepting is disabled",
+			image:         marketplaceImage("1.1.0"),
+			expectedError: "marketplace terms of plan my-publisher:my-offer:my-plan are not accepted for subscription 123, accept them with `az vm image terms accept --publisher my-publisher --offer my-offer --plan my-plan` or start the controller manager with --accept-marketplace-terms",
+			expect: func(m *mock_images.MockClientMockRecorder) {
+				m.ListMarketplaceImageVersions(gomock.Any(), "test-location", "my-publisher", "my-offer", "my-sku").Return(versions("1.0.0", "1.1.0"), nil)
+				m.GetMarketplaceImage(gomock.Any(), "test-location", "my-publisher", "my-offer", "my-sku", "1.1.0").Return(imageWithPlan, nil).Times(2)
+			},
+			expectTerms: func(m *mock_marketplaceagreements.MockClientMockRecorder) {
+				m.Get(gomock.Any(), "my-publisher", "my-offer", "my-plan").Return(terms(false), nil)
+			},
+		},
+		{
+			name:          "image referred to by ID",
+			image:         &infrav1.Image{ID: to.StringPtr("my-image-id")},
+			expectedImage: &infrav1.Image{ID: to.StringPtr("my-image-id")},
+			expect:        func(m *mock_images.MockClientMockRecorder) {},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			imagesMock := mock_images.NewMockClient(mockCtrl)
+			tc.expect(imagesMock.EXPECT())
+			agreementsMock := mock_marketplaceagreements.NewMockClient(mockCtrl)
+			if tc.expectTerms != nil {
+				tc.expectTerms(agreementsMock.EXPECT())
+			}
+
+			machineScope, clusterScope := newSpotMachineScopes(g, infrav1.AzureMachineStatus{Image: tc.pinned})
+			machineScope.AzureMachine.Spec.Image = tc.image
+			machineScope.AzureMachine.Spec.ProviderID = nil
+			recorder := record.NewFakeRecorder(10)
+			r := &AzureMachineReconciler{Recorder: recorder, AcceptMarketplaceTerms: tc.acceptTerms}
+			ams := &azureMachineService{
+				machineScope:             machineScope,
+				clusterScope:             clusterScope,
+				imagesSvc:                &images.Service{Scope: clusterScope, Client: imagesMock},
+				marketplaceAgreementsSvc: &marketplaceagreements.Service{Scope: clusterScope, Client: agreementsMock},
+			}
+
+			err := r.reconcileImage(context.TODO(), machineScope, ams)
+			if tc.expectedError != "" {
+				g.Expect(err).To(MatchError(tc.expectedError))
+				_, terminal := azure.IsTerminalError(err)
+				g.Expect(terminal).To(BeTrue())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(machineScope.AzureMachine.Status.Image).To(Equal(tc.expectedImage))
+			}
+		})
+	}
+}
+
+func TestReconcileVMImage(t *testing.T) {
+	marketplaceImage := func(version string) *infrav1.Image {
+		return &infrav1.Image{
+			Marketplace: &infrav1.AzureMarketplaceImage{
+				Publisher: "my-publisher",
+				Offer:     "my-offer",
+				SKU:       "my-sku",
+				Version:   version,
+			},
+		}
+	}
+	versions := func(names ...string) []compute.VirtualMachineImageResource {
+		resources := make([]compute.VirtualMachineImageResource, 0, len(names))
+		for _, name := range names {
+			resources = append(resources, compute.VirtualMachineImageResource{Name: to.StringPtr(name)})
+		}
+		return resources
+	}
+
+	testcases := []struct {
+		name           string
+		image          *infrav1.Image
+		pinned         *infrav1.Image
+		vmImage        infrav1.Image
+		expectedImage  *infrav1.Image
+		expectedEvents []string
+		expect         func(m *mock_images.MockClientMockRecorder)
+	}{
+		{
+			name:           "keep pinned image version when a newer version is available",
+			image:          marketplaceImage("latest"),
+			pinned:         marketplaceImage("1.0.0"),
+			vmImage:        *marketplaceImage("1.0.0"),
+			expectedImage:  marketplaceImage("1.0.0"),
+			expectedEvents: []string{"NewerImageVersionAvailable"},
+			expect: func(m *mock_images.MockClientMockRecorder) {
+				m.ListMarketplaceImageVersions(gomock.Any(), "test-location", "my-publisher", "my-offer", "my-sku").Return(versions("1.0.0", "1.1.0"), nil)
+			},
+		},
+		{
+			name:          "pin image version of an existing VM",
+			image:         marketplaceImage("latest"),
+			vmImage:       *marketplaceImage("1.0.0"),
+			expectedImage: marketplaceImage("1.0.0"),
+			expect: func(m *mock_images.MockClientMockRecorder) {
+				m.ListMarketplaceImageVersions(gomock.Any(), "test-location", "my-publisher", "my-offer", "my-sku").Return(versions("1.0.0"), nil)
+			},
+		},
+		{
+			name:    "image of an existing VM is not pinned when it differs from the spec",
+			image:   marketplaceImage("latest"),
+			vmImage: infrav1.Image{ID: to.StringPtr("my-image-id")},
+			expect:  func(m *mock_images.MockClientMockRecorder) {},
+		},
+		{
+			name:          "image of an existing VM no longer available",
+			image:         marketplaceImage("latest"),
+			pinned:        marketplaceImage("1.0.0"),
+			vmImage:       *marketplaceImage("1.0.0"),
+			expectedImage: marketplaceImage("1.0.0"),
+			expect: func(m *mock_images.MockClientMockRecorder) {
+				m.ListMarketplaceImageVersions(gomock.Any(), "test-location", "my-publisher", "my-offer", "my-sku").Return(nil, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not Found"))
+			},
+		},
+		{
+			name:          "image of an existing VM referred to by ID",
+			image:         &infrav1.Image{ID: to.StringPtr("my-image-id")},
+			vmImage:       infrav1.Image{ID: to.StringPtr("my-image-id")},
+			expectedImage: &infrav1.Image{ID: to.StringPtr("my-image-id")},
+			expect:        func(m *mock_images.MockClientMockRecorder) {},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			imagesMock := mock_images.NewMockClient(mockCtrl)
+			tc.expect(imagesMock.EXPECT())
+
+			machineScope, clusterScope := newSpotMachineScopes(g, infrav1.AzureMachineStatus{Image: tc.pinned})
+			machineScope.AzureMachine.Spec.Image = tc.image
+			recorder := record.NewFakeRecorder(10)
+			r := &AzureMachineReconciler{Recorder: recorder}
+			ams := &azureMachineService{
+				machineScope: machineScope,
+				clusterScope: clusterScope,
+				imagesSvc:    &images.Service{Scope: clusterScope, Client: imagesMock},
+			}
+
+			r.reconcileVMImage(context.TODO(), machineScope, ams, &infrav1.VM{Image: tc.vmImage})
+			g.Expect(machineScope.AzureMachine.Status.Image).To(Equal(tc.expectedImage))
+
+			close(recorder.Events)
+			var events []string
+			for event := range recorder.Events {
+				events = append(events, event)
+			}
+			g.Expect(events).To(HaveLen(len(tc.expectedEvents)))
+			for i, reason := range tc.expectedEvents {
+				g.Expect(events[i]).To(ContainSubstring(reason))
+			}
+		})
+	}
+}
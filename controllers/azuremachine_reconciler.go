@@ -27,51 +27,104 @@ import (
 	azure "sigs.k8s.io/cluster-api-provider-azure/cloud"
 	"sigs.k8s.io/cluster-api-provider-azure/cloud/scope"
 	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/availabilityzones"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/bootdiagnostics"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/dedicatedhosts"
 	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/disks"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/images"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/inboundnatrules"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/marketplaceagreements"
 	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/networkinterfaces"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/proximityplacementgroups"
 	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/publicips"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/storageaccounts"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/virtualmachineextensions"
 	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/virtualmachines"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha3"
+	capierrors "sigs.k8s.io/cluster-api/errors"
 	"sigs.k8s.io/cluster-api/util"
 )
 
 // azureMachineService is the group of services called by the AzureMachine controller
 type azureMachineService struct {
-	machineScope         *scope.MachineScope
-	clusterScope         *scope.ClusterScope
-	availabilityZonesSvc azure.GetterService
-	networkInterfacesSvc azure.Service
-	virtualMachinesSvc   *virtualmachines.Service
-	disksSvc             azure.OldService
-	publicIPsSvc         azure.Service
+	machineScope             *scope.MachineScope
+	clusterScope             *scope.ClusterScope
+	availabilityZonesSvc     azure.GetterService
+	networkInterfacesSvc     azure.Service
+	inboundNatRulesSvc       azure.Service
+	virtualMachinesSvc       *virtualmachines.Service
+	disksSvc                 azure.OldService
+	publicIPsSvc             azure.Service
+	ppgSvc                   azure.OldService
+	dedicatedHostsSvc        azure.GetterService
+	imagesSvc                *images.Service
+	marketplaceAgreementsSvc azure.OldService
+	vmExtensionsSvc          *virtualmachineextensions.Service
+	storageAccountsSvc       *storageaccounts.Service
+	bootDiagnosticsSvc       azure.GetterService
 }
 
 // newAzureMachineService populates all the services based on input scope
 func newAzureMachineService(machineScope *scope.MachineScope, clusterScope *scope.ClusterScope) *azureMachineService {
 	return &azureMachineService{
-		machineScope:         machineScope,
-		clusterScope:         clusterScope,
-		availabilityZonesSvc: availabilityzones.NewService(clusterScope),
-		networkInterfacesSvc: networkinterfaces.NewService(machineScope),
-		virtualMachinesSvc:   virtualmachines.NewService(clusterScope, machineScope),
-		disksSvc:             disks.NewService(clusterScope),
-		publicIPsSvc:         publicips.NewService(machineScope),
+		machineScope:             machineScope,
+		clusterScope:             clusterScope,
+		availabilityZonesSvc:     availabilityzones.NewService(clusterScope),
+		networkInterfacesSvc:     networkinterfaces.NewService(machineScope),
+		inboundNatRulesSvc:       inboundnatrules.NewService(machineScope),
+		virtualMachinesSvc:       virtualmachines.NewService(clusterScope, machineScope),
+		disksSvc:                 disks.NewService(clusterScope),
+		publicIPsSvc:             publicips.NewService(machineScope),
+		ppgSvc:                   proximityplacementgroups.NewService(clusterScope),
+		dedicatedHostsSvc:        dedicatedhosts.NewService(clusterScope),
+		imagesSvc:                images.NewService(clusterScope),
+		marketplaceAgreementsSvc: marketplaceagreements.NewService(clusterScope),
+		vmExtensionsSvc:          virtualmachineextensions.NewService(machineScope),
+		storageAccountsSvc:       storageaccounts.NewService(clusterScope),
+		bootDiagnosticsSvc:       bootdiagnostics.NewService(clusterScope),
 	}
 }
 
 // Reconcile reconciles all the services in pre determined order
 func (s *azureMachineService) Reconcile(ctx context.Context) (*infrav1.VM, error) {
+	// Check the dedicated host placement first, so that a machine whose failure domain does not match
+	// its host group fails before any of its resources are created.
+	var placement *dedicatedhosts.Placement
+	if dedicatedHost := s.machineScope.AzureMachine.Spec.DedicatedHost; dedicatedHost != nil {
+		var err error
+		placement, err = s.getDedicatedHostPlacement(ctx, dedicatedHost)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Basic load balancers only support VMs without availability zone in their backend pools.
+	if s.clusterScope.LoadBalancerSKU() == infrav1.SKUBasic {
+		if zone := s.machineScope.AvailabilityZone(); zone != "" {
+			return nil, azure.NewTerminalError(capierrors.InvalidConfigurationMachineError,
+				errors.Errorf("failure domain %s of the machine cannot be used with the Basic load balancers of the cluster", zone))
+		}
+		if placement != nil && placement.Zone != "" {
+			return nil, azure.NewTerminalError(capierrors.InvalidConfigurationMachineError,
+				errors.Errorf("zonal dedicated host group of the machine cannot be used with the Basic load balancers of the cluster"))
+		}
+	}
+
 	err := s.publicIPsSvc.Reconcile(ctx)
 	if err != nil {
 		return nil, errors.Wrap(err, "unable to create public IPs")
 	}
 
+	err = s.inboundNatRulesSvc.Reconcile(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create inbound NAT rule")
+	}
+
 	err = s.networkInterfacesSvc.Reconcile(ctx)
 	if err != nil {
 		return nil, errors.Wrap(err, "unable to create VM network interface")
 	}
 
-	vm, vmErr := s.reconcileVirtualMachine(ctx, azure.GenerateNICName(s.machineScope.Name()))
+	vm, vmErr := s.reconcileVirtualMachine(ctx, s.machineScope.NICNames(), placement)
 	if vmErr != nil {
 		return nil, errors.Wrapf(vmErr, "failed to create VM %s ", s.machineScope.Name())
 	}
@@ -95,6 +148,11 @@ func (s *azureMachineService) Delete(ctx context.Context) error {
 		return errors.Wrapf(err, "Unable to delete network interface")
 	}
 
+	err = s.inboundNatRulesSvc.Delete(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to delete inbound NAT rules")
+	}
+
 	err = s.publicIPsSvc.Delete(ctx)
 	if err != nil {
 		return errors.Wrap(err, "failed to delete public IPs")
@@ -108,6 +166,14 @@ func (s *azureMachineService) Delete(ctx context.Context) error {
 		return errors.Wrapf(err, "Failed to delete OS disk of machine %s", s.machineScope.Name())
 	}
 
+	// the proximity placement group of the cluster is only deleted with the cluster
+	if ppgName := s.proximityPlacementGroupName(); ppgName != "" && ppgName != s.clusterScope.ProximityPlacementGroupName() {
+		err = s.ppgSvc.Delete(ctx, &proximityplacementgroups.Spec{Name: ppgName})
+		if err != nil {
+			return errors.Wrapf(err, "failed to delete proximity placement group of machine %s", s.machineScope.Name())
+		}
+	}
+
 	return nil
 }
 
@@ -136,6 +202,39 @@ func (s *azureMachineService) VMIfExists(ctx context.Context, id *string) (*infr
 	return vm, nil
 }
 
+// VMPowerState returns the power state of the VM of the machine.
+func (s *azureMachineService) VMPowerState(ctx context.Context) (string, error) {
+	return s.virtualMachinesSvc.GetPowerState(ctx, &virtualmachines.Spec{Name: s.machineScope.Name()})
+}
+
+// StartVM starts the VM of the machine.
+func (s *azureMachineService) StartVM(ctx context.Context) error {
+	return s.virtualMachinesSvc.Start(ctx, &virtualmachines.Spec{Name: s.machineScope.Name()})
+}
+
+// ResizeVM starts resizing the VM of the machine in the given zone to the given size. It returns whether the VM is
+// deallocated first, in which case it must be resized with UpdateVMSize and started again once it is deallocated.
+func (s *azureMachineService) ResizeVM(ctx context.Context, size, zone string) (bool, error) {
+	return s.virtualMachinesSvc.Resize(ctx, &virtualmachines.Spec{
+		Name: s.machineScope.Name(),
+		Size: size,
+		Zone: zone,
+	})
+}
+
+// UpdateVMSize starts changing the size of the deallocated VM of the machine.
+func (s *azureMachineService) UpdateVMSize(ctx context.Context, size string) error {
+	return s.virtualMachinesSvc.UpdateSize(ctx, &virtualmachines.Spec{
+		Name: s.machineScope.Name(),
+		Size: size,
+	})
+}
+
+// StartVMAsync starts the deallocated VM of the machine, without waiting for it to run.
+func (s *azureMachineService) StartVMAsync(ctx context.Context) error {
+	return s.virtualMachinesSvc.StartAsync(ctx, &virtualmachines.Spec{Name: s.machineScope.Name()})
+}
+
 // getVirtualMachineZone gets a random availability zones from available set,
 // this will hopefully be an input from upstream machinesets so all the vms are balanced
 func (s *azureMachineService) getVirtualMachineZone(ctx context.Context) (string, error) {
@@ -188,14 +287,14 @@ func (s *azureMachineService) getVirtualMachineZone(ctx context.Context) (string
 	return selectedZone, nil
 }
 
-func (s *azureMachineService) reconcileVirtualMachine(ctx context.Context, nicName string) (*infrav1.VM, error) {
+func (s *azureMachineService) reconcileVirtualMachine(ctx context.Context, nicNames []string, placement *dedicatedhosts.Placement) (*infrav1.VM, error) {
 	decoded, err := base64.StdEncoding.DecodeString(s.machineScope.AzureMachine.Spec.SSHPublicKey)
 	if err != nil {
 		return nil, errors.Wrapf(err, "failed to decode ssh public key")
 	}
 
 	var vmZone string
-	azSupported := s.isAvailabilityZoneSupported()
+	azSupported := s.isAvailabilityZoneSupported() && s.clusterScope.LoadBalancerSKU() != infrav1.SKUBasic
 	if azSupported {
 		useAZ := true
 
@@ -212,9 +311,18 @@ func (s *azureMachineService) reconcileVirtualMachine(ctx context.Context, nicNa
 		}
 	}
 
-	image, err := getVMImage(s.machineScope)
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to get VM image")
+	var hostID string
+	if placement != nil {
+		hostID = placement.HostID
+		vmZone = placement.Zone
+	}
+
+	// Use the image version pinned in the status, if any.
+	image := s.machineScope.AzureMachine.Status.Image
+	if image == nil {
+		if image, err = getVMImage(s.machineScope); err != nil {
+			return nil, errors.Wrap(err, "failed to get VM image")
+		}
 	}
 
 	bootstrapData, err := s.machineScope.GetBootstrapData(ctx)
@@ -222,18 +330,37 @@ func (s *azureMachineService) reconcileVirtualMachine(ctx context.Context, nicNa
 		return nil, errors.Wrap(err, "failed to retrieve bootstrap data")
 	}
 
+	var ppgID string
+	if ppgName := s.proximityPlacementGroupName(); ppgName != "" {
+		if err := s.ppgSvc.Reconcile(ctx, &proximityplacementgroups.Spec{Name: ppgName}); err != nil {
+			return nil, errors.Wrapf(err, "failed to reconcile proximity placement group %s", ppgName)
+		}
+		ppgID = azure.ProximityPlacementGroupID(s.clusterScope.SubscriptionID(), s.clusterScope.ResourceGroup(), ppgName)
+	}
+
+	var bootDiagnosticsStorageURI string
+	if s.machineScope.AzureMachine.Spec.BootDiagnostics.IsEnabled() {
+		bootDiagnosticsStorageURI, err = ReconcileBootDiagnosticsStorageAccount(ctx, s.storageAccountsSvc, s.clusterScope)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to reconcile boot diagnostics storage account")
+		}
+	}
+
 	vmSpec := &virtualmachines.Spec{
-		Name:                   s.machineScope.Name(),
-		NICName:                nicName,
-		SSHKeyData:             string(decoded),
-		Size:                   s.machineScope.AzureMachine.Spec.VMSize,
-		OSDisk:                 s.machineScope.AzureMachine.Spec.OSDisk,
-		Image:                  image,
-		CustomData:             bootstrapData,
-		Zone:                   vmZone,
-		Identity:               s.machineScope.AzureMachine.Spec.Identity,
-		UserAssignedIdentities: s.machineScope.AzureMachine.Spec.UserAssignedIdentities,
-		SpotVMOptions:          s.machineScope.AzureMachine.Spec.SpotVMOptions,
+		Name:                      s.machineScope.Name(),
+		NICNames:                  nicNames,
+		SSHKeyData:                string(decoded),
+		Size:                      s.machineScope.AzureMachine.Spec.VMSize,
+		OSDisk:                    s.machineScope.AzureMachine.Spec.OSDisk,
+		Image:                     image,
+		CustomData:                bootstrapData,
+		Zone:                      vmZone,
+		Identity:                  s.machineScope.AzureMachine.Spec.Identity,
+		UserAssignedIdentities:    s.machineScope.AzureMachine.Spec.UserAssignedIdentities,
+		SpotVMOptions:             s.machineScope.AzureMachine.Spec.SpotVMOptions,
+		ProximityPlacementGroupID: ppgID,
+		DedicatedHostID:           hostID,
+		BootDiagnosticsStorageURI: bootDiagnosticsStorageURI,
 	}
 
 	err = s.virtualMachinesSvc.Reconcile(ctx, vmSpec)
@@ -260,6 +387,18 @@ func (s *azureMachineService) reconcileVirtualMachine(ctx context.Context, nicNa
 	return newVM, nil
 }
 
+// proximityPlacementGroupName returns the name of the proximity placement group of the machine, if any.
+// Control plane machines default to the proximity placement group of the cluster.
+func (s *azureMachineService) proximityPlacementGroupName() string {
+	if name := s.machineScope.AzureMachine.Spec.ProximityPlacementGroupName; name != nil {
+		return *name
+	}
+	if s.machineScope.IsControlPlane() {
+		return s.clusterScope.ProximityPlacementGroupName()
+	}
+	return ""
+}
+
 // GetControlPlaneMachines retrieves all non-deleted control plane nodes from a MachineList
 func GetControlPlaneMachines(machineList *clusterv1.MachineList) []*clusterv1.Machine {
 	var cpm []*clusterv1.Machine
@@ -272,6 +411,26 @@ func GetControlPlaneMachines(machineList *clusterv1.MachineList) []*clusterv1.Ma
 	return cpm
 }
 
+// getDedicatedHostPlacement gets the dedicated host to place the VM on. The VM is created in the availability zone
+// of the host group, which must match the failure domain of the machine, if any.
+func (s *azureMachineService) getDedicatedHostPlacement(ctx context.Context, dedicatedHost *infrav1.DedicatedHostSpec) (*dedicatedhosts.Placement, error) {
+	placementInterface, err := s.dedicatedHostsSvc.Get(ctx, &dedicatedhosts.Spec{
+		HostGroupID: dedicatedHost.HostGroupID,
+		HostID:      dedicatedHost.HostID,
+		VMSize:      s.machineScope.AzureMachine.Spec.VMSize,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get dedicated host")
+	}
+	placement := placementInterface.(*dedicatedhosts.Placement)
+
+	if zone := s.machineScope.AvailabilityZone(); zone != "" && zone != placement.Zone {
+		return nil, azure.NewTerminalError(capierrors.InvalidConfigurationMachineError,
+			errors.Errorf("failure domain %s of the machine does not match the availability zone %q of its dedicated host group", zone, placement.Zone))
+	}
+	return placement, nil
+}
+
 // isAvailabilityZoneSupported determines if Availability Zones are supported in a selected location
 // based on SupportedAvailabilityZoneLocations. Returns true if supported.
 func (s *azureMachineService) isAvailabilityZoneSupported() bool {
@@ -17,8 +17,14 @@ limitations under the License.
 package controllers
 
 import (
+	"context"
+
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/golang/mock/gomock"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha3"
 	azure "sigs.k8s.io/cluster-api-provider-azure/cloud"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/mocks"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/dedicatedhosts"
 	"testing"
 
 	. "github.com/onsi/gomega"
@@ -26,6 +32,7 @@ import (
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/cluster-api-provider-azure/cloud/scope"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha3"
+	"sigs.k8s.io/cluster-api/util/conditions"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 )
 
@@ -147,3 +154,113 @@ func TestIsAvailabilityZoneSupported(t *testing.T) {
 		g.Expect(s.isAvailabilityZoneSupported()).To(BeFalse())
 	}
 }
+
+func TestReconcileDedicatedHostZoneMismatch(t *testing.T) {
+	g := NewWithT(t)
+
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	hostGroupID := "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/hostGroups/my-group"
+
+	dedicatedHostsMock := mocks.NewMockGetterService(mockCtrl)
+	dedicatedHostsMock.EXPECT().Get(gomock.Any(), gomock.Any()).Return(&dedicatedhosts.Placement{
+		HostID: hostGroupID + "/hosts/my-host",
+		Zone:   "2",
+	}, nil)
+
+	// The other services are not set, so a mismatch must fail before any resource is created.
+	clusterScope := &scope.ClusterScope{
+		AzureCluster: &infrav1.AzureCluster{},
+	}
+
+	s := azureMachineService{
+		clusterScope: clusterScope,
+		machineScope: &scope.MachineScope{
+			Logger: log.Log.Logger,
+			Machine: &clusterv1.Machine{
+				Spec: clusterv1.MachineSpec{FailureDomain: to.StringPtr("1")},
+			},
+			AzureMachine: &infrav1.AzureMachine{
+				Spec: infrav1.AzureMachineSpec{
+					DedicatedHost: &infrav1.DedicatedHostSpec{HostGroupID: hostGroupID},
+				},
+			},
+		},
+		dedicatedHostsSvc: dedicatedHostsMock,
+	}
+
+	_, err := s.Reconcile(context.TODO())
+	g.Expect(err).To(HaveOccurred())
+	_, terminal := azure.IsTerminalError(err)
+	g.Expect(terminal).To(BeTrue())
+}
+
+func TestReconcileBasicLoadBalancerZone(t *testing.T) {
+	g := NewWithT(t)
+
+	clusterScope := &scope.ClusterScope{
+		AzureCluster: &infrav1.AzureCluster{
+			Spec: infrav1.AzureClusterSpec{
+				NetworkSpec: infrav1.NetworkSpec{
+					APIServerLB: &infrav1.APIServerLBSpec{SKU: infrav1.SKUBasic},
+				},
+			},
+		},
+	}
+
+	// The other services are not set, so a zonal machine must fail before any resource is created.
+	s := azureMachineService{
+		clusterScope: clusterScope,
+		machineScope: &scope.MachineScope{
+			Logger: log.Log.Logger,
+			Machine: &clusterv1.Machine{
+				Spec: clusterv1.MachineSpec{FailureDomain: to.StringPtr("1")},
+			},
+			AzureMachine: &infrav1.AzureMachine{},
+		},
+	}
+
+	_, err := s.Reconcile(context.TODO())
+	g.Expect(err).To(HaveOccurred())
+	_, terminal := azure.IsTerminalError(err)
+	g.Expect(terminal).To(BeTrue())
+}
+
+func TestTrackBootstrap(t *testing.T) {
+	testcases := []struct {
+		name                       string
+		bootstrapSentinelExtension bool
+		expectExtension            bool
+	}{
+		{
+			name:                       "bootstrap sentinel extension disabled",
+			bootstrapSentinelExtension: false,
+			expectExtension:            false,
+		},
+		{
+			name:                       "bootstrap sentinel extension enabled",
+			bootstrapSentinelExtension: true,
+			expectExtension:            true,
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			machineScope, _ := newSpotMachineScopes(g, infrav1.AzureMachineStatus{})
+			machineScope.AzureMachine.Spec.OSDisk.OSType = "Linux"
+
+			r := &AzureMachineReconciler{BootstrapSentinelExtension: tc.bootstrapSentinelExtension}
+			r.trackBootstrap(machineScope)
+			if tc.expectExtension {
+				g.Expect(machineScope.BootstrapExtensionSpec()).NotTo(BeNil())
+				g.Expect(conditions.IsFalse(machineScope.AzureMachine, infrav1.BootstrapSucceededCondition)).To(BeTrue())
+			} else {
+				g.Expect(machineScope.BootstrapExtensionSpec()).To(BeNil())
+				g.Expect(conditions.Has(machineScope.AzureMachine, infrav1.BootstrapSucceededCondition)).To(BeFalse())
+			}
+		})
+	}
+}
@@ -0,0 +1,108 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha3"
+	azure "sigs.k8s.io/cluster-api-provider-azure/cloud"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/scope"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/virtualmachines"
+)
+
+// vmResizePollInterval is the interval at which a resize of the VM is checked.
+const vmResizePollInterval = 30 * time.Second
+
+// reconcileVMSize resizes the VM in place when the VMSize of an AzureMachine allowing it no longer matches the size
+// of the VM. The resize doesn't wait for Azure and spans several reconciles: the VM is either resized on its hardware
+// cluster, or deallocated, resized and started again. The outcome of the resize is recorded in the VMResized
+// condition, and a failed resize is not retried until the VMSize of the AzureMachine changes. It returns whether the
+// VM is running with its final size.
+func (r *AzureMachineReconciler) reconcileVMSize(ctx context.Context, machineScope *scope.MachineScope, ams *azureMachineService, vm *infrav1.VM) (bool, error) {
+	if machineScope.IsVMResizing() {
+		return r.reconcileVMResizing(ctx, machineScope, ams, vm)
+	}
+
+	size := machineScope.AzureMachine.Spec.VMSize
+	if !machineScope.AzureMachine.Spec.AllowInPlaceResize || vm.VMSize == "" || strings.EqualFold(vm.VMSize, size) ||
+		strings.EqualFold(machineScope.AzureMachine.Status.LastResizeVMSize, size) {
+		return true, nil
+	}
+
+	machineScope.Info("Resizing VM", "id", *machineScope.GetVMID(), "from", vm.VMSize, "to", size)
+	r.Recorder.Eventf(machineScope.AzureMachine, corev1.EventTypeNormal, "VMResizing", "Resizing VM %s from %s to %s", machineScope.Name(), vm.VMSize, size)
+	if _, err := ams.ResizeVM(ctx, size, vm.AvailabilityZone); err != nil {
+		if _, ok := azure.IsTerminalError(err); !ok {
+			return true, err
+		}
+		machineScope.AzureMachine.Status.LastResizeVMSize = size
+		r.setVMResizeFailed(machineScope, size, err)
+		return true, nil
+	}
+	machineScope.SetVMResizing(size)
+	return false, nil
+}
+
+// reconcileVMResizing follows a resize of the VM started by an earlier reconcile, from the size and power state of
+// the VM.
+func (r *AzureMachineReconciler) reconcileVMResizing(ctx context.Context, machineScope *scope.MachineScope, ams *azureMachineService, vm *infrav1.VM) (bool, error) {
+	size := machineScope.AzureMachine.Status.LastResizeVMSize
+	powerState, err := ams.VMPowerState(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	resized := strings.EqualFold(vm.VMSize, size)
+	switch {
+	case powerState == virtualmachines.PowerStateDeallocating || powerState == virtualmachines.PowerStateStarting:
+		return false, nil
+	case powerState == virtualmachines.PowerStateDeallocated && !resized:
+		if err := ams.UpdateVMSize(ctx, size); err != nil {
+			r.setVMResizeFailed(machineScope, size, err)
+			// Start the VM again, so that it keeps running with its previous size.
+			return false, ams.StartVMAsync(ctx)
+		}
+		return false, nil
+	case powerState == virtualmachines.PowerStateDeallocated:
+		return false, ams.StartVMAsync(ctx)
+	case !resized:
+		r.setVMResizeFailed(machineScope, size, errors.Errorf("VM %s still has size %s after resizing it", machineScope.Name(), vm.VMSize))
+		return true, nil
+	}
+
+	machineScope.SetVMResized()
+	r.Recorder.Eventf(machineScope.AzureMachine, corev1.EventTypeNormal, "VMResized", "VM %s resized to %s", machineScope.Name(), size)
+	return true, nil
+}
+
+// setVMResizeFailed records a failed resize of the VM, which is not retried until the VMSize of the AzureMachine
+// changes.
+func (r *AzureMachineReconciler) setVMResizeFailed(machineScope *scope.MachineScope, size string, err error) {
+	reason := infrav1.VMResizeFailedReason
+	if errors.Is(err, virtualmachines.ErrVMSizeNotAvailable) {
+		reason = infrav1.VMSizeNotAvailableReason
+	}
+	machineScope.Error(err, "Failed to resize VM", "id", *machineScope.GetVMID(), "size", size)
+	machineScope.SetVMResizeFailed(reason, err.Error())
+	r.Recorder.Eventf(machineScope.AzureMachine, corev1.EventTypeWarning, "FailedVMResize", "Failed to resize VM %s to %s: %v", machineScope.Name(), size, err)
+}
@@ -0,0 +1,281 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2019-12-01/compute"
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/cluster-api/util/conditions"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha3"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/resourceskus/mock_resourceskus"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/virtualmachines"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/virtualmachines/mock_virtualmachines"
+)
+
+func TestReconcileVMSize(t *testing.T) {
+	powerState := func(state string) compute.VirtualMachineInstanceView {
+		return compute.VirtualMachineInstanceView{
+			Statuses: &[]compute.InstanceViewStatus{{Code: to.StringPtr("PowerState/" + state)}},
+		}
+	}
+	internalError := autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error")
+
+	testcases := []struct {
+		name              string
+		allowResize       bool
+		resizing          bool
+		lastResizeVMSize  string
+		vmSize            string
+		zone              string
+		expectedResized   bool
+		expectedError     bool
+		expectedCondition *corev1.ConditionStatus
+		expectedReason    string
+		expectedEvents    []string
+		expect            func(m *mock_virtualmachines.MockClientMockRecorder, mSkus *mock_resourceskus.MockClientMockRecorder)
+	}{
+		{
+			name:            "VM size is unchanged",
+			vmSize:          "Standard_D4s_v3",
+			expectedResized: true,
+			expect: func(m *mock_virtualmachines.MockClientMockRecorder, mSkus *mock_resourceskus.MockClientMockRecorder) {
+			},
+		},
+		{
+			name:            "in-place resize is not allowed",
+			vmSize:          "Standard_D2s_v3",
+			expectedResized: true,
+			expect: func(m *mock_virtualmachines.MockClientMockRecorder, mSkus *mock_resourceskus.MockClientMockRecorder) {
+			},
+		},
+		{
+			name:              "resize VM in place",
+			allowResize:       true,
+			vmSize:            "Standard_D2s_v3",
+			expectedCondition: conditionStatus(corev1.ConditionFalse),
+			expectedReason:    infrav1.VMResizingReason,
+			expectedEvents:    []string{"VMResizing"},
+			expect: func(m *mock_virtualmachines.MockClientMockRecorder, mSkus *mock_resourceskus.MockClientMockRecorder) {
+				m.ListAvailableSizes(gomock.Any(), "my-rg", "my-vm").Return([]compute.VirtualMachineSize{{Name: to.StringPtr("Standard_D4s_v3")}}, nil)
+				m.UpdateAsync(gomock.Any(), "my-rg", "my-vm", gomock.Any())
+			},
+		},
+		{
+			name:              "deallocate VM to resize it",
+			allowResize:       true,
+			vmSize:            "Standard_D2s_v3",
+			zone:              "1",
+			expectedCondition: conditionStatus(corev1.ConditionFalse),
+			expectedReason:    infrav1.VMResizingReason,
+			expectedEvents:    []string{"VMResizing"},
+			expect: func(m *mock_virtualmachines.MockClientMockRecorder, mSkus *mock_resourceskus.MockClientMockRecorder) {
+				m.ListAvailableSizes(gomock.Any(), "my-rg", "my-vm").Return(nil, nil)
+				mSkus.IsVMSizeAvailable(gomock.Any(), "test-location", "1", "Standard_D4s_v3").Return(true, nil)
+				m.DeallocateAsync(gomock.Any(), "my-rg", "my-vm")
+			},
+		},
+		{
+			name:              "VM size is not available",
+			allowResize:       true,
+			vmSize:            "Standard_D2s_v3",
+			zone:              "1",
+			expectedResized:   true,
+			expectedCondition: conditionStatus(corev1.ConditionFalse),
+			expectedReason:    infrav1.VMSizeNotAvailableReason,
+			expectedEvents:    []string{"VMResizing", "FailedVMResize"},
+			expect: func(m *mock_virtualmachines.MockClientMockRecorder, mSkus *mock_resourceskus.MockClientMockRecorder) {
+				m.ListAvailableSizes(gomock.Any(), "my-rg", "my-vm").Return(nil, nil)
+				mSkus.IsVMSizeAvailable(gomock.Any(), "test-location", "1", "Standard_D4s_v3").Return(false, nil)
+			},
+		},
+		{
+			name:              "fail to resize VM",
+			allowResize:       true,
+			vmSize:            "Standard_D2s_v3",
+			expectedResized:   true,
+			expectedCondition: conditionStatus(corev1.ConditionFalse),
+			expectedReason:    infrav1.VMResizeFailedReason,
+			expectedEvents:    []string{"VMResizing", "FailedVMResize"},
+			expect: func(m *mock_virtualmachines.MockClientMockRecorder, mSkus *mock_resourceskus.MockClientMockRecorder) {
+				m.ListAvailableSizes(gomock.Any(), "my-rg", "my-vm").Return([]compute.VirtualMachineSize{{Name: to.StringPtr("Standard_D4s_v3")}}, nil)
+				m.UpdateAsync(gomock.Any(), "my-rg", "my-vm", gomock.Any()).Return(internalError)
+			},
+		},
+		{
+			name:            "transient error is retried",
+			allowResize:     true,
+			vmSize:          "Standard_D2s_v3",
+			expectedResized: true,
+			expectedError:   true,
+			expectedEvents:  []string{"VMResizing"},
+			expect: func(m *mock_virtualmachines.MockClientMockRecorder, mSkus *mock_resourceskus.MockClientMockRecorder) {
+				m.ListAvailableSizes(gomock.Any(), "my-rg", "my-vm").Return(nil, internalError)
+			},
+		},
+		{
+			name:             "failed resize is not retried",
+			allowResize:      true,
+			lastResizeVMSize: "Standard_D4s_v3",
+			vmSize:           "Standard_D2s_v3",
+			expectedResized:  true,
+			expect: func(m *mock_virtualmachines.MockClientMockRecorder, mSkus *mock_resourceskus.MockClientMockRecorder) {
+			},
+		},
+		{
+			name:              "wait for VM deallocation",
+			resizing:          true,
+			vmSize:            "Standard_D2s_v3",
+			expectedCondition: conditionStatus(corev1.ConditionFalse),
+			expectedReason:    infrav1.VMResizingReason,
+			expect: func(m *mock_virtualmachines.MockClientMockRecorder, mSkus *mock_resourceskus.MockClientMockRecorder) {
+				m.InstanceView(gomock.Any(), "my-rg", "my-vm").Return(powerState(virtualmachines.PowerStateDeallocating), nil)
+			},
+		},
+		{
+			name:              "resize deallocated VM",
+			resizing:          true,
+			vmSize:            "Standard_D2s_v3",
+			expectedCondition: conditionStatus(corev1.ConditionFalse),
+			expectedReason:    infrav1.VMResizingReason,
+			expect: func(m *mock_virtualmachines.MockClientMockRecorder, mSkus *mock_resourceskus.MockClientMockRecorder) {
+				m.InstanceView(gomock.Any(), "my-rg", "my-vm").Return(powerState(virtualmachines.PowerStateDeallocated), nil)
+				m.UpdateAsync(gomock.Any(), "my-rg", "my-vm", gomock.Any())
+			},
+		},
+		{
+			name:              "start VM again when the resize of the deallocated VM fails",
+			resizing:          true,
+			vmSize:            "Standard_D2s_v3",
+			expectedCondition: conditionStatus(corev1.ConditionFalse),
+			expectedReason:    infrav1.VMResizeFailedReason,
+			expectedEvents:    []string{"FailedVMResize"},
+			expect: func(m *mock_virtualmachines.MockClientMockRecorder, mSkus *mock_resourceskus.MockClientMockRecorder) {
+				m.InstanceView(gomock.Any(), "my-rg", "my-vm").Return(powerState(virtualmachines.PowerStateDeallocated), nil)
+				m.UpdateAsync(gomock.Any(), "my-rg", "my-vm", gomock.Any()).Return(internalError)
+				m.StartAsync(gomock.Any(), "my-rg", "my-vm")
+			},
+		},
+		{
+			name:              "start resized VM",
+			resizing:          true,
+			vmSize:            "Standard_D4s_v3",
+			expectedCondition: conditionStatus(corev1.ConditionFalse),
+			expectedReason:    infrav1.VMResizingReason,
+			expect: func(m *mock_virtualmachines.MockClientMockRecorder, mSkus *mock_resourceskus.MockClientMockRecorder) {
+				m.InstanceView(gomock.Any(), "my-rg", "my-vm").Return(powerState(virtualmachines.PowerStateDeallocated), nil)
+				m.StartAsync(gomock.Any(), "my-rg", "my-vm")
+			},
+		},
+		{
+			name:              "VM is resized",
+			resizing:          true,
+			vmSize:            "Standard_D4s_v3",
+			expectedResized:   true,
+			expectedCondition: conditionStatus(corev1.ConditionTrue),
+			expectedEvents:    []string{"VMResized"},
+			expect: func(m *mock_virtualmachines.MockClientMockRecorder, mSkus *mock_resourceskus.MockClientMockRecorder) {
+				m.InstanceView(gomock.Any(), "my-rg", "my-vm").Return(powerState("running"), nil)
+			},
+		},
+		{
+			name:              "VM is running with its previous size",
+			resizing:          true,
+			vmSize:            "Standard_D2s_v3",
+			expectedResized:   true,
+			expectedCondition: conditionStatus(corev1.ConditionFalse),
+			expectedReason:    infrav1.VMResizeFailedReason,
+			expectedEvents:    []string{"FailedVMResize"},
+			expect: func(m *mock_virtualmachines.MockClientMockRecorder, mSkus *mock_resourceskus.MockClientMockRecorder) {
+				m.InstanceView(gomock.Any(), "my-rg", "my-vm").Return(powerState("running"), nil)
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			vmMock := mock_virtualmachines.NewMockClient(mockCtrl)
+			skusMock := mock_resourceskus.NewMockClient(mockCtrl)
+			tc.expect(vmMock.EXPECT(), skusMock.EXPECT())
+
+			machineScope, clusterScope := newSpotMachineScopes(g, infrav1.AzureMachineStatus{LastResizeVMSize: tc.lastResizeVMSize})
+			machineScope.AzureMachine.Spec.VMSize = "Standard_D4s_v3"
+			machineScope.AzureMachine.Spec.AllowInPlaceResize = tc.allowResize
+			if tc.resizing {
+				machineScope.SetVMResizing("Standard_D4s_v3")
+			}
+			recorder := record.NewFakeRecorder(10)
+			r := &AzureMachineReconciler{Recorder: recorder}
+			ams := &azureMachineService{
+				machineScope: machineScope,
+				clusterScope: clusterScope,
+				virtualMachinesSvc: &virtualmachines.Service{
+					Scope:              clusterScope,
+					MachineScope:       machineScope,
+					Client:             vmMock,
+					ResourceSkusClient: skusMock,
+				},
+			}
+
+			resized, err := r.reconcileVMSize(context.TODO(), machineScope, ams, &infrav1.VM{VMSize: tc.vmSize, AvailabilityZone: tc.zone})
+			if tc.expectedError {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+			g.Expect(resized).To(Equal(tc.expectedResized))
+
+			condition := conditions.Get(machineScope.AzureMachine, infrav1.VMResizedCondition)
+			if tc.expectedCondition == nil {
+				g.Expect(condition).To(BeNil())
+			} else {
+				g.Expect(condition).NotTo(BeNil())
+				g.Expect(condition.Status).To(Equal(*tc.expectedCondition))
+				g.Expect(condition.Reason).To(Equal(tc.expectedReason))
+				g.Expect(machineScope.AzureMachine.Status.LastResizeVMSize).To(Equal("Standard_D4s_v3"))
+			}
+
+			close(recorder.Events)
+			var events []string
+			for event := range recorder.Events {
+				events = append(events, event)
+			}
+			g.Expect(events).To(HaveLen(len(tc.expectedEvents)))
+			for i, reason := range tc.expectedEvents {
+				g.Expect(events[i]).To(ContainSubstring(reason))
+			}
+		})
+	}
+}
+
+func conditionStatus(status corev1.ConditionStatus) *corev1.ConditionStatus {
+	return &status
+}
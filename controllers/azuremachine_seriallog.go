@@ -0,0 +1,107 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/cluster-api/util"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha3"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/scope"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/bootdiagnostics"
+)
+
+const (
+	// serialLogKey is the key of the serial console log in the serial log ConfigMap.
+	serialLogKey = "serial.log"
+	// serialLogLines is the number of lines of the serial console log stored in the serial log ConfigMap.
+	serialLogLines = 200
+	// serialLogEventLines is the number of lines of the serial console log included in the capture event.
+	serialLogEventLines = 10
+)
+
+// reconcileSerialLog stores the end of the serial console log of the VM in a ConfigMap when requested with the
+// fetch serial log annotation, or once when the AzureMachine failed. Capturing the log is best effort: failures only
+// emit an event and remove the annotation, so that they never block the reconciliation of the machine.
+func (r *AzureMachineReconciler) reconcileSerialLog(ctx context.Context, machineScope *scope.MachineScope, ams *azureMachineService) {
+	_, requested := machineScope.AzureMachine.Annotations[infrav1.FetchSerialLogAnnotation]
+	failed := machineScope.AzureMachine.Status.FailureReason != nil || machineScope.AzureMachine.Status.FailureMessage != nil
+	if !requested && (!failed || machineScope.AzureMachine.Status.SerialLogConfigMapName != "") {
+		return
+	}
+	if machineScope.GetVMID() == nil || !machineScope.AzureMachine.Spec.BootDiagnostics.IsEnabled() {
+		if requested {
+			r.Recorder.Eventf(machineScope.AzureMachine, corev1.EventTypeWarning, "FailedSerialLogCapture", "Serial console log is only available for VMs with boot diagnostics enabled")
+			delete(machineScope.AzureMachine.Annotations, infrav1.FetchSerialLogAnnotation)
+		}
+		return
+	}
+
+	if err := r.captureSerialLog(ctx, machineScope, ams); err != nil {
+		machineScope.Error(err, "failed to capture serial console log")
+		r.Recorder.Eventf(machineScope.AzureMachine, corev1.EventTypeWarning, "FailedSerialLogCapture", err.Error())
+		delete(machineScope.AzureMachine.Annotations, infrav1.FetchSerialLogAnnotation)
+	}
+}
+
+func (r *AzureMachineReconciler) captureSerialLog(ctx context.Context, machineScope *scope.MachineScope, ams *azureMachineService) error {
+	logInterface, err := ams.bootDiagnosticsSvc.Get(ctx, &bootdiagnostics.Spec{VMName: machineScope.Name(), Lines: serialLogLines})
+	if err != nil {
+		return errors.Wrap(err, "failed to get serial console log")
+	}
+	log, ok := logInterface.(string)
+	if !ok {
+		return errors.Errorf("expected serial console log but got %T", logInterface)
+	}
+
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%s-serial-log", machineScope.Name()),
+			Namespace: machineScope.Namespace(),
+		},
+	}
+	_, err = controllerutil.CreateOrUpdate(ctx, r.Client, configMap, func() error {
+		configMap.OwnerReferences = util.EnsureOwnerRef(configMap.OwnerReferences, metav1.OwnerReference{
+			APIVersion: infrav1.GroupVersion.String(),
+			Kind:       "AzureMachine",
+			Name:       machineScope.Name(),
+			UID:        machineScope.AzureMachine.UID,
+		})
+		configMap.Data = map[string]string{serialLogKey: log}
+		return nil
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to store serial console log in ConfigMap %s", configMap.Name)
+	}
+
+	machineScope.AzureMachine.Status.SerialLogConfigMapName = configMap.Name
+	delete(machineScope.AzureMachine.Annotations, infrav1.FetchSerialLogAnnotation)
+
+	lines := strings.Split(log, "\n")
+	if len(lines) > serialLogEventLines {
+		lines = lines[len(lines)-serialLogEventLines:]
+	}
+	r.Recorder.Eventf(machineScope.AzureMachine, corev1.EventTypeNormal, "SerialLogCaptured", "Serial console log stored in ConfigMap %s, last lines:\n%s", configMap.Name, strings.Join(lines, "\n"))
+	return nil
+}
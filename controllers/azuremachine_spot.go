@@ -0,0 +1,109 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	capierrors "sigs.k8s.io/cluster-api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/scope"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/virtualmachines"
+)
+
+const (
+	// maxSpotVMRestartAttempts is the number of attempts to restart an evicted Spot VM before its machine fails.
+	maxSpotVMRestartAttempts = 5
+	// spotVMRestartBackoff is the delay before the second attempt to restart an evicted Spot VM, doubled after
+	// each attempt.
+	spotVMRestartBackoff = time.Minute
+)
+
+// reconcileSpotVM restarts a Spot VM deallocated by an eviction, waiting longer between each attempt as Azure may
+// not have capacity for it yet. The machine fails once the VM can't be restarted after maxSpotVMRestartAttempts
+// attempts, so that it is replaced. It returns whether the VM is running.
+func (r *AzureMachineReconciler) reconcileSpotVM(ctx context.Context, machineScope *scope.MachineScope, ams *azureMachineService) (reconcile.Result, bool, error) {
+	// A Spot VM deallocated to resize it is started again by the resize.
+	if machineScope.IsVMResizing() {
+		return reconcile.Result{}, true, nil
+	}
+
+	powerState, err := ams.VMPowerState(ctx)
+	if err != nil {
+		return reconcile.Result{}, false, err
+	}
+
+	status := &machineScope.AzureMachine.Status
+	if powerState != virtualmachines.PowerStateDeallocated && powerState != virtualmachines.PowerStateDeallocating {
+		status.SpotVMEvictionTime = nil
+		status.SpotVMRestartAttempts = 0
+		status.LastSpotVMRestartTime = nil
+		return reconcile.Result{}, true, nil
+	}
+
+	machineScope.SetNotReady()
+	if status.SpotVMEvictionTime == nil {
+		machineScope.Info("Spot VM was evicted", "id", *machineScope.GetVMID())
+		r.Recorder.Eventf(machineScope.AzureMachine, corev1.EventTypeWarning, "SpotVMEvicted", "Spot VM %s was evicted and deallocated", machineScope.Name())
+		now := metav1.Now()
+		status.SpotVMEvictionTime = &now
+	}
+	if powerState == virtualmachines.PowerStateDeallocating {
+		return reconcile.Result{RequeueAfter: spotVMRestartBackoff}, false, nil
+	}
+
+	if status.LastSpotVMRestartTime != nil {
+		next := status.LastSpotVMRestartTime.Add(spotVMRestartDelay(status.SpotVMRestartAttempts))
+		if wait := time.Until(next); wait > 0 {
+			return reconcile.Result{RequeueAfter: wait}, false, nil
+		}
+	}
+
+	status.SpotVMRestartAttempts++
+	now := metav1.Now()
+	status.LastSpotVMRestartTime = &now
+	if err := ams.StartVM(ctx); err != nil {
+		r.Recorder.Eventf(machineScope.AzureMachine, corev1.EventTypeWarning, "SpotVMRestartFailed", "Failed to restart evicted Spot VM %s (attempt %d of %d): %v", machineScope.Name(), status.SpotVMRestartAttempts, maxSpotVMRestartAttempts, err)
+		if status.SpotVMRestartAttempts >= maxSpotVMRestartAttempts {
+			machineScope.Error(err, "Failed to restart evicted Spot VM, giving up", "id", *machineScope.GetVMID())
+			machineScope.SetFailureReason(capierrors.UpdateMachineError)
+			machineScope.SetFailureMessage(errors.Errorf("Spot VM %s was evicted and could not be restarted after %d attempts", machineScope.Name(), status.SpotVMRestartAttempts))
+			return reconcile.Result{}, false, nil
+		}
+		return reconcile.Result{RequeueAfter: spotVMRestartDelay(status.SpotVMRestartAttempts)}, false, nil
+	}
+
+	machineScope.Info("Restarted evicted Spot VM", "id", *machineScope.GetVMID(), "attempts", status.SpotVMRestartAttempts)
+	r.Recorder.Eventf(machineScope.AzureMachine, corev1.EventTypeNormal, "SpotVMRestarted", "Evicted Spot VM %s restarted", machineScope.Name())
+	status.SpotVMEvictionTime = nil
+	status.SpotVMRestartAttempts = 0
+	status.LastSpotVMRestartTime = nil
+	return reconcile.Result{}, true, nil
+}
+
+// spotVMRestartDelay returns the delay between the given attempt to restart an evicted Spot VM and the next one.
+func spotVMRestartDelay(attempts int32) time.Duration {
+	if attempts < 1 {
+		return 0
+	}
+	return spotVMRestartBackoff << uint(attempts-1)
+}
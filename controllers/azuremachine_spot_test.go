@@ -0,0 +1,210 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2019-12-01/compute"
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha3"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha3"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/scope"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/virtualmachines"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/virtualmachines/mock_virtualmachines"
+)
+
+func TestReconcileSpotVM(t *testing.T) {
+	powerState := func(state string) compute.VirtualMachineInstanceView {
+		return compute.VirtualMachineInstanceView{
+			Statuses: &[]compute.InstanceViewStatus{{Code: to.StringPtr("PowerState/" + state)}},
+		}
+	}
+	internalError := autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error")
+
+	testcases := []struct {
+		name             string
+		status           infrav1.AzureMachineStatus
+		expectedRunning  bool
+		expectedRequeue  time.Duration
+		expectedAttempts int32
+		expectedFailure  bool
+		expectedEvents   []string
+		expect           func(m *mock_virtualmachines.MockClientMockRecorder)
+	}{
+		{
+			name:            "running spot VM",
+			status:          infrav1.AzureMachineStatus{SpotVMRestartAttempts: 2, LastSpotVMRestartTime: &metav1.Time{Time: time.Now()}},
+			expectedRunning: true,
+			expect: func(m *mock_virtualmachines.MockClientMockRecorder) {
+				m.InstanceView(gomock.Any(), "my-rg", "my-vm").Return(powerState("running"), nil)
+			},
+		},
+		{
+			name:            "restart evicted spot VM",
+			expectedRunning: true,
+			expectedEvents:  []string{"SpotVMEvicted", "SpotVMRestarted"},
+			expect: func(m *mock_virtualmachines.MockClientMockRecorder) {
+				m.InstanceView(gomock.Any(), "my-rg", "my-vm").Return(powerState(virtualmachines.PowerStateDeallocated), nil)
+				m.Start(gomock.Any(), "my-rg", "my-vm")
+			},
+		},
+		{
+			name:            "report eviction of spot VM being deallocated",
+			expectedRequeue: spotVMRestartBackoff,
+			expectedEvents:  []string{"SpotVMEvicted"},
+			expect: func(m *mock_virtualmachines.MockClientMockRecorder) {
+				m.InstanceView(gomock.Any(), "my-rg", "my-vm").Return(powerState(virtualmachines.PowerStateDeallocating), nil)
+			},
+		},
+		{
+			name:            "wait for evicted spot VM to be deallocated",
+			status:          infrav1.AzureMachineStatus{SpotVMEvictionTime: &metav1.Time{Time: time.Now()}},
+			expectedRequeue: spotVMRestartBackoff,
+			expect: func(m *mock_virtualmachines.MockClientMockRecorder) {
+				m.InstanceView(gomock.Any(), "my-rg", "my-vm").Return(powerState(virtualmachines.PowerStateDeallocating), nil)
+			},
+		},
+		{
+			name:             "retry to restart evicted spot VM later",
+			expectedRequeue:  spotVMRestartBackoff,
+			expectedAttempts: 1,
+			expectedEvents:   []string{"SpotVMEvicted", "SpotVMRestartFailed"},
+			expect: func(m *mock_virtualmachines.MockClientMockRecorder) {
+				m.InstanceView(gomock.Any(), "my-rg", "my-vm").Return(powerState(virtualmachines.PowerStateDeallocated), nil)
+				m.Start(gomock.Any(), "my-rg", "my-vm").Return(internalError)
+			},
+		},
+		{
+			name:             "wait before the next attempt to restart evicted spot VM",
+			status:           infrav1.AzureMachineStatus{SpotVMEvictionTime: &metav1.Time{Time: time.Now()}, SpotVMRestartAttempts: 2, LastSpotVMRestartTime: &metav1.Time{Time: time.Now()}},
+			expectedRequeue:  2 * spotVMRestartBackoff,
+			expectedAttempts: 2,
+			expect: func(m *mock_virtualmachines.MockClientMockRecorder) {
+				m.InstanceView(gomock.Any(), "my-rg", "my-vm").Return(powerState(virtualmachines.PowerStateDeallocated), nil)
+			},
+		},
+		{
+			name:             "fail machine when evicted spot VM can't be restarted",
+			status:           infrav1.AzureMachineStatus{SpotVMEvictionTime: &metav1.Time{Time: time.Now().Add(-time.Hour * 48)}, SpotVMRestartAttempts: maxSpotVMRestartAttempts - 1, LastSpotVMRestartTime: &metav1.Time{Time: time.Now().Add(-time.Hour * 24)}},
+			expectedAttempts: maxSpotVMRestartAttempts,
+			expectedFailure:  true,
+			expectedEvents:   []string{"SpotVMRestartFailed"},
+			expect: func(m *mock_virtualmachines.MockClientMockRecorder) {
+				m.InstanceView(gomock.Any(), "my-rg", "my-vm").Return(powerState(virtualmachines.PowerStateDeallocated), nil)
+				m.Start(gomock.Any(), "my-rg", "my-vm").Return(internalError)
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			vmMock := mock_virtualmachines.NewMockClient(mockCtrl)
+			tc.expect(vmMock.EXPECT())
+
+			machineScope, clusterScope := newSpotMachineScopes(g, tc.status)
+			recorder := record.NewFakeRecorder(10)
+			r := &AzureMachineReconciler{Recorder: recorder}
+			ams := &azureMachineService{
+				machineScope:       machineScope,
+				clusterScope:       clusterScope,
+				virtualMachinesSvc: &virtualmachines.Service{Scope: clusterScope, MachineScope: machineScope, Client: vmMock},
+			}
+
+			result, running, err := r.reconcileSpotVM(context.TODO(), machineScope, ams)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(running).To(Equal(tc.expectedRunning))
+			if tc.expectedRequeue > 0 {
+				g.Expect(result.RequeueAfter).To(BeNumerically("~", tc.expectedRequeue, time.Second))
+			} else {
+				g.Expect(result.RequeueAfter).To(BeZero())
+			}
+			g.Expect(machineScope.AzureMachine.Status.SpotVMRestartAttempts).To(Equal(tc.expectedAttempts))
+			g.Expect(machineScope.AzureMachine.Status.FailureReason != nil).To(Equal(tc.expectedFailure))
+
+			close(recorder.Events)
+			var events []string
+			for event := range recorder.Events {
+				events = append(events, event)
+			}
+			g.Expect(events).To(HaveLen(len(tc.expectedEvents)))
+			for i, reason := range tc.expectedEvents {
+				g.Expect(events[i]).To(ContainSubstring(reason))
+			}
+		})
+	}
+}
+
+func newSpotMachineScopes(g *WithT, status infrav1.AzureMachineStatus) (*scope.MachineScope, *scope.ClusterScope) {
+	s := runtime.NewScheme()
+	g.Expect(clusterv1.AddToScheme(s)).To(Succeed())
+	g.Expect(infrav1.AddToScheme(s)).To(Succeed())
+
+	cluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-cluster"},
+	}
+	azureMachine := &infrav1.AzureMachine{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-vm"},
+		Spec: infrav1.AzureMachineSpec{
+			ProviderID:    to.StringPtr("azure:///subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/virtualMachines/my-vm"),
+			SpotVMOptions: &infrav1.SpotVMOptions{},
+		},
+		Status: status,
+	}
+	client := fake.NewFakeClientWithScheme(s, cluster, azureMachine)
+
+	clusterScope, err := scope.NewClusterScope(scope.ClusterScopeParams{
+		AzureClients: scope.AzureClients{
+			Authorizer: autorest.NullAuthorizer{},
+		},
+		Client:  client,
+		Cluster: cluster,
+		AzureCluster: &infrav1.AzureCluster{
+			Spec: infrav1.AzureClusterSpec{
+				Location:       "test-location",
+				ResourceGroup:  "my-rg",
+				SubscriptionID: "123",
+			},
+		},
+	})
+	g.Expect(err).NotTo(HaveOccurred())
+
+	machineScope, err := scope.NewMachineScope(scope.MachineScopeParams{
+		Client:       client,
+		ClusterScope: clusterScope,
+		Machine:      &clusterv1.Machine{},
+		AzureMachine: azureMachine,
+	})
+	g.Expect(err).NotTo(HaveOccurred())
+	return machineScope, clusterScope
+}
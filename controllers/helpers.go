@@ -19,12 +19,16 @@ package controllers
 import (
 	"context"
 
+	"github.com/Azure/azure-sdk-for-go/services/storage/mgmt/2019-06-01/storage"
 	"github.com/go-logr/logr"
 	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha3"
+	capierrors "sigs.k8s.io/cluster-api/errors"
 	"sigs.k8s.io/cluster-api/util"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -32,6 +36,11 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha3"
+	azure "sigs.k8s.io/cluster-api-provider-azure/cloud"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/scope"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/images"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/marketplaceagreements"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/storageaccounts"
 	"sigs.k8s.io/cluster-api-provider-azure/util/reconciler"
 )
 
@@ -113,3 +122,98 @@ func GetObjectsToRequestsByNamespaceAndClusterName(ctx context.Context, c client
 	}
 	return results
 }
+
+// ReconcileBootDiagnosticsStorageAccount creates the boot diagnostics storage account of the cluster if it doesn't
+// exist, and returns its blob endpoint. The account is only created once a VM with boot diagnostics needs it.
+func ReconcileBootDiagnosticsStorageAccount(ctx context.Context, storageAccountsSvc *storageaccounts.Service, clusterScope *scope.ClusterScope) (string, error) {
+	name := clusterScope.BootDiagnosticsStorageAccountName()
+	if err := storageAccountsSvc.Reconcile(ctx, &storageaccounts.Spec{Name: name}); err != nil {
+		return "", err
+	}
+	accountInterface, err := storageAccountsSvc.Get(ctx, &storageaccounts.Spec{Name: name})
+	if err != nil {
+		return "", err
+	}
+	account, ok := accountInterface.(*storage.Account)
+	if !ok {
+		return "", errors.Errorf("expected storage account but got %T", accountInterface)
+	}
+	if account.AccountProperties == nil || account.PrimaryEndpoints == nil || account.PrimaryEndpoints.Blob == nil {
+		return "", errors.Errorf("storage account %s has no blob endpoint", name)
+	}
+	return *account.PrimaryEndpoints.Blob, nil
+}
+
+// FailureReporter is the scope of an object whose failure can be recorded in its status, such as a MachineScope or
+// a MachinePoolScope.
+type FailureReporter interface {
+	logr.Logger
+	SetFailureReason(v capierrors.MachineStatusError)
+	SetFailureMessage(v error)
+}
+
+// SetTerminalFailure records a terminal error of an object, which is no longer reconciled until manual intervention.
+func SetTerminalFailure(recorder record.EventRecorder, obj runtime.Object, failureReporter FailureReporter, reason capierrors.MachineStatusError, err error) {
+	failureReporter.Error(err, "Failed to reconcile, manual intervention is required")
+	recorder.Eventf(obj, corev1.EventTypeWarning, "TerminalError", err.Error())
+	failureReporter.SetFailureReason(reason)
+	failureReporter.SetFailureMessage(err)
+}
+
+// ReconcileMarketplaceTerms makes sure the marketplace terms of the purchase plan of an image, if it has one, are
+// accepted, accepting them on behalf of the subscription when accept is set.
+func ReconcileMarketplaceTerms(ctx context.Context, marketplaceAgreementsSvc azure.OldService, image *infrav1.Image, accept bool) error {
+	if image.Marketplace == nil || image.Marketplace.Plan == nil {
+		return nil
+	}
+	plan := image.Marketplace.Plan
+	return marketplaceAgreementsSvc.Reconcile(ctx, &marketplaceagreements.Spec{
+		Publisher: plan.Publisher,
+		Product:   plan.Product,
+		Plan:      plan.Name,
+		Accept:    accept,
+	})
+}
+
+// ResolveImage returns the image to create VMs from, with a latest version resolved to the concrete version it refers
+// to, and the newer version of the image available, if any. The image pinned by a previous call is kept as long as it
+// is a resolved version of the image, so that all VMs use the same image build. The purchase plan of a marketplace
+// image is read from the image when it isn't set.
+func ResolveImage(ctx context.Context, imagesSvc *images.Service, image, pinned *infrav1.Image) (*infrav1.Image, string, error) {
+	if image.ID != nil {
+		return image, "", nil
+	}
+
+	latestInterface, err := imagesSvc.Get(ctx, &images.Spec{Image: image})
+	if err != nil {
+		return nil, "", err
+	}
+	latest, ok := latestInterface.(*infrav1.Image)
+	if !ok {
+		return nil, "", errors.Errorf("expected image but got %T", latestInterface)
+	}
+
+	resolved := image
+	if images.IsPinnedVersionOf(pinned, image) {
+		resolved = pinned
+	} else if images.Version(image) == azure.LatestVersion {
+		resolved = latest
+	}
+
+	if resolved.Marketplace != nil && resolved.Marketplace.Plan == nil {
+		plan, err := imagesSvc.GetPlan(ctx, resolved.Marketplace)
+		if err != nil {
+			return nil, "", err
+		}
+		if plan != nil {
+			resolved = resolved.DeepCopy()
+			resolved.Marketplace.Plan = plan
+		}
+	}
+
+	var newerVersion string
+	if images.CompareVersions(images.Version(latest), images.Version(resolved)) > 0 {
+		newerVersion = images.Version(latest)
+	}
+	return resolved, newerVersion, nil
+}
@@ -21,11 +21,16 @@ import (
 
 	. "github.com/onsi/gomega"
 
+	"github.com/go-logr/logr"
 	"github.com/golang/mock/gomock"
+	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/klog/klogr"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha3"
+	capierrors "sigs.k8s.io/cluster-api/errors"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 
@@ -110,3 +115,24 @@ func newCluster(name string) *clusterv1.Cluster {
 		},
 	}
 }
+
+type fakeFailureReporter struct {
+	logr.Logger
+	reason  capierrors.MachineStatusError
+	message error
+}
+
+func (f *fakeFailureReporter) SetFailureReason(v capierrors.MachineStatusError) { f.reason = v }
+func (f *fakeFailureReporter) SetFailureMessage(v error)                        { f.message = v }
+
+func TestSetTerminalFailure(t *testing.T) {
+	g := NewWithT(t)
+	recorder := record.NewFakeRecorder(1)
+	failureReporter := &fakeFailureReporter{Logger: klogr.New()}
+	err := errors.New("image not found")
+
+	SetTerminalFailure(recorder, &infrav1.AzureMachine{}, failureReporter, capierrors.InvalidConfigurationMachineError, err)
+	g.Expect(failureReporter.reason).To(Equal(capierrors.InvalidConfigurationMachineError))
+	g.Expect(failureReporter.message).To(Equal(err))
+	g.Expect(recorder.Events).To(Receive(Equal("Warning TerminalError image not found")))
+}
@@ -19,6 +19,7 @@ package v1alpha3_test
 import (
 	"testing"
 
+	"github.com/Azure/go-autorest/autorest/to"
 	"github.com/onsi/gomega"
 
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha3"
@@ -92,3 +93,69 @@ func TestAzureMachinePool_Validate(t *testing.T) {
 		})
 	}
 }
+
+func TestAzureMachinePool_ValidateUpdate(t *testing.T) {
+	deletePolicy := infrav1.SpotEvictionPolicyDelete
+	deallocatePolicy := infrav1.SpotEvictionPolicyDeallocate
+	pool := func(spotVMOptions *infrav1.SpotVMOptions) *exp.AzureMachinePool {
+		return &exp.AzureMachinePool{
+			Spec: exp.AzureMachinePoolSpec{
+				Template: exp.AzureMachineTemplate{
+					SpotVMOptions: spotVMOptions,
+				},
+			},
+		}
+	}
+
+	cases := []struct {
+		Name   string
+		Old    *exp.AzureMachinePool
+		New    *exp.AzureMachinePool
+		Expect func(g *gomega.GomegaWithT, actual error)
+	}{
+		{
+			Name: "ChangesMaxPrice",
+			Old:  pool(&infrav1.SpotVMOptions{}),
+			New:  pool(&infrav1.SpotVMOptions{MaxPrice: to.StringPtr("0.04")}),
+			Expect: func(g *gomega.GomegaWithT, actual error) {
+				g.Expect(actual).ToNot(gomega.HaveOccurred())
+			},
+		},
+		{
+			Name: "SetsDefaultEvictionPolicy",
+			Old:  pool(&infrav1.SpotVMOptions{}),
+			New:  pool(&infrav1.SpotVMOptions{EvictionPolicy: &deallocatePolicy}),
+			Expect: func(g *gomega.GomegaWithT, actual error) {
+				g.Expect(actual).ToNot(gomega.HaveOccurred())
+			},
+		},
+		{
+			Name: "AddsSpotVMOptions",
+			Old:  pool(nil),
+			New:  pool(&infrav1.SpotVMOptions{}),
+			Expect: func(g *gomega.GomegaWithT, actual error) {
+				g.Expect(actual).To(gomega.HaveOccurred())
+				g.Expect(actual.Error()).To(gomega.ContainSubstring("Spot VM options can't be added to or removed from an existing machine pool"))
+			},
+		},
+		{
+			Name: "ChangesEvictionPolicy",
+			Old:  pool(&infrav1.SpotVMOptions{}),
+			New:  pool(&infrav1.SpotVMOptions{EvictionPolicy: &deletePolicy}),
+			Expect: func(g *gomega.GomegaWithT, actual error) {
+				g.Expect(actual).To(gomega.HaveOccurred())
+				g.Expect(actual.Error()).To(gomega.ContainSubstring("the eviction policy of an existing machine pool can't be changed"))
+			},
+		},
+	}
+
+	for _, c := range cases {
+		c := c
+		t.Run(c.Name, func(t *testing.T) {
+			t.Parallel()
+			g := gomega.NewGomegaWithT(t)
+			actualErr := c.New.ValidateUpdate(c.Old)
+			c.Expect(g, actualErr)
+		})
+	}
+}
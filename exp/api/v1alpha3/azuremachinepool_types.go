@@ -18,6 +18,7 @@ package v1alpha3
 
 import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha3"
 	"sigs.k8s.io/cluster-api/errors"
 
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha3"
@@ -47,6 +48,25 @@ type (
 		// If AcceleratedNetworking is set to true with a VMSize that does not support it, Azure will return an error.
 		// +optional
 		AcceleratedNetworking *bool `json:"acceleratedNetworking,omitempty"`
+
+		// ProximityPlacementGroupName is the name of a proximity placement group in the cluster resource group to
+		// place the scale set in. The provider creates it if it doesn't exist.
+		// +optional
+		ProximityPlacementGroupName string `json:"proximityPlacementGroupName,omitempty"`
+
+		// Extensions are the VM extensions installed on the scale set instances. Existing instances get changes
+		// of the extensions when they are upgraded to the latest scale set model.
+		// +optional
+		Extensions []infrav1.VMExtension `json:"extensions,omitempty"`
+
+		// BootDiagnostics configures the boot diagnostics of the scale set instances, enabled by default.
+		// +optional
+		BootDiagnostics *infrav1.BootDiagnostics `json:"bootDiagnostics,omitempty"`
+
+		// SpotVMOptions runs the scale set instances on Spot VMs. Evicted instances are reported in the
+		// evictedInstances field of the AzureMachinePool status.
+		// +optional
+		SpotVMOptions *infrav1.SpotVMOptions `json:"spotVMOptions,omitempty"`
 	}
 
 	// AzureMachinePoolSpec defines the desired state of AzureMachinePool
@@ -86,6 +106,16 @@ type (
 		// +optional
 		ProvisioningState *infrav1.VMState `json:"provisioningState,omitempty"`
 
+		// EvictedInstances are the provider IDs of the Spot instances of the scale set deallocated by an eviction.
+		// +optional
+		EvictedInstances []string `json:"evictedInstances,omitempty"`
+
+		// Image is the image the instances of the scale set are created from, with a latest version resolved to the
+		// concrete version it referred to when the image was first reconciled, so that all instances use the same
+		// image build.
+		// +optional
+		Image *infrav1.Image `json:"image,omitempty"`
+
 		// ErrorReason will be set in the event that there is a terminal problem
 		// reconciling the MachinePool and will contain a succinct value suitable
 		// for machine interpretation.
@@ -123,6 +153,10 @@ type (
 		// controller's output.
 		// +optional
 		FailureMessage *string `json:"failureMessage,omitempty"`
+
+		// Conditions defines current service state of the AzureMachinePool.
+		// +optional
+		Conditions clusterv1.Conditions `json:"conditions,omitempty"`
 	}
 
 	// +kubebuilder:object:root=true
@@ -155,6 +189,16 @@ type (
 	}
 )
 
+// GetConditions returns the list of conditions for an AzureMachinePool API object.
+func (amp *AzureMachinePool) GetConditions() clusterv1.Conditions {
+	return amp.Status.Conditions
+}
+
+// SetConditions will set the given conditions on an AzureMachinePool object.
+func (amp *AzureMachinePool) SetConditions(conditions clusterv1.Conditions) {
+	amp.Status.Conditions = conditions
+}
+
 func init() {
 	SchemeBuilder.Register(&AzureMachinePool{}, &AzureMachinePoolList{})
 }
@@ -58,7 +58,16 @@ func (amp *AzureMachinePool) ValidateCreate() error {
 // ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
 func (amp *AzureMachinePool) ValidateUpdate(old runtime.Object) error {
 	azuremachinepoollog.Info("validate update", "name", amp.Name)
-	return amp.Validate()
+	var errs []error
+	if oldPool, ok := old.(*AzureMachinePool); ok {
+		if err := amp.ValidateSpotVMOptionsUpdate(oldPool); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if err := amp.Validate(); err != nil {
+		errs = append(errs, err)
+	}
+	return kerrors.NewAggregate(errs)
 }
 
 // ValidateDelete implements webhook.Validator so a webhook will be registered for the type
@@ -71,6 +80,7 @@ func (amp *AzureMachinePool) ValidateDelete() error {
 func (amp *AzureMachinePool) Validate() error {
 	validators := []func() error{
 		amp.ValidateImage,
+		amp.ValidateExtensions,
 	}
 
 	var errs []error
@@ -99,3 +109,32 @@ func (amp *AzureMachinePool) ValidateImage() error {
 	}
 	return nil
 }
+
+// ValidateExtensions of an AzureMachinePool
+func (amp *AzureMachinePool) ValidateExtensions() error {
+	if errs := infrav1.ValidateVMExtensions(amp.Spec.Template.Extensions, field.NewPath("extensions")); len(errs) > 0 {
+		return kerrors.NewAggregate(errs.ToAggregate().Errors())
+	}
+	return nil
+}
+
+// ValidateSpotVMOptionsUpdate of an AzureMachinePool, as the priority and eviction policy of a scale set can't be
+// changed
+func (amp *AzureMachinePool) ValidateSpotVMOptionsUpdate(old *AzureMachinePool) error {
+	oldOptions, newOptions := old.Spec.Template.SpotVMOptions, amp.Spec.Template.SpotVMOptions
+	fldPath := field.NewPath("spec", "template", "spotVMOptions")
+	if (oldOptions == nil) != (newOptions == nil) {
+		return field.Forbidden(fldPath, "Spot VM options can't be added to or removed from an existing machine pool")
+	}
+	if oldOptions != nil && evictionPolicy(oldOptions) != evictionPolicy(newOptions) {
+		return field.Forbidden(fldPath.Child("evictionPolicy"), "the eviction policy of an existing machine pool can't be changed")
+	}
+	return nil
+}
+
+func evictionPolicy(spotVMOptions *infrav1.SpotVMOptions) infrav1.SpotEvictionPolicy {
+	if spotVMOptions.EvictionPolicy == nil {
+		return infrav1.SpotEvictionPolicyDeallocate
+	}
+	return *spotVMOptions.EvictionPolicy
+}
@@ -22,11 +22,13 @@ import (
 
 type (
 	VMSSVM struct {
-		ID               string          `json:"id,omitempty"`
-		InstanceID       string          `json:"instanceID,omitempty"`
-		Name             string          `json:"name,omitempty"`
-		AvailabilityZone string          `json:"availabilityZone,omitempty"`
-		State            infrav1.VMState `json:"vmState,omitempty"`
+		ID               string                     `json:"id,omitempty"`
+		InstanceID       string                     `json:"instanceID,omitempty"`
+		Name             string                     `json:"name,omitempty"`
+		AvailabilityZone string                     `json:"availabilityZone,omitempty"`
+		State            infrav1.VMState            `json:"vmState,omitempty"`
+		PowerState       string                     `json:"powerState,omitempty"`
+		ExtensionStates  map[string]infrav1.VMState `json:"extensionStates,omitempty"`
 	}
 
 	VMSS struct {
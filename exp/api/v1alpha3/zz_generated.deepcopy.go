@@ -23,6 +23,7 @@ package v1alpha3
 import (
 	"k8s.io/apimachinery/pkg/runtime"
 	apiv1alpha3 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha3"
+	cluster_apiapiv1alpha3 "sigs.k8s.io/cluster-api/api/v1alpha3"
 	"sigs.k8s.io/cluster-api/errors"
 )
 
@@ -121,6 +122,16 @@ func (in *AzureMachinePoolStatus) DeepCopyInto(out *AzureMachinePoolStatus) {
 		*out = new(apiv1alpha3.VMState)
 		**out = **in
 	}
+	if in.EvictedInstances != nil {
+		in, out := &in.EvictedInstances, &out.EvictedInstances
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Image != nil {
+		in, out := &in.Image, &out.Image
+		*out = new(apiv1alpha3.Image)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.FailureReason != nil {
 		in, out := &in.FailureReason, &out.FailureReason
 		*out = new(errors.MachineStatusError)
@@ -131,6 +142,13 @@ func (in *AzureMachinePoolStatus) DeepCopyInto(out *AzureMachinePoolStatus) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make(cluster_apiapiv1alpha3.Conditions, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureMachinePoolStatus.
@@ -157,6 +175,23 @@ func (in *AzureMachineTemplate) DeepCopyInto(out *AzureMachineTemplate) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.Extensions != nil {
+		in, out := &in.Extensions, &out.Extensions
+		*out = make([]apiv1alpha3.VMExtension, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.BootDiagnostics != nil {
+		in, out := &in.BootDiagnostics, &out.BootDiagnostics
+		*out = new(apiv1alpha3.BootDiagnostics)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SpotVMOptions != nil {
+		in, out := &in.SpotVMOptions, &out.SpotVMOptions
+		*out = new(apiv1alpha3.SpotVMOptions)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureMachineTemplate.
@@ -495,7 +530,9 @@ func (in *VMSS) DeepCopyInto(out *VMSS) {
 	if in.Instances != nil {
 		in, out := &in.Instances, &out.Instances
 		*out = make([]VMSSVM, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 }
 
@@ -512,6 +549,13 @@ func (in *VMSS) DeepCopy() *VMSS {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VMSSVM) DeepCopyInto(out *VMSSVM) {
 	*out = *in
+	if in.ExtensionStates != nil {
+		in, out := &in.ExtensionStates, &out.ExtensionStates
+		*out = make(map[string]apiv1alpha3.VMState, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VMSSVM.
@@ -21,11 +21,14 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/Azure/go-autorest/autorest/to"
 	"github.com/go-logr/logr"
 	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -48,7 +51,12 @@ import (
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha3"
 	azure "sigs.k8s.io/cluster-api-provider-azure/cloud"
 	"sigs.k8s.io/cluster-api-provider-azure/cloud/scope"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/images"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/marketplaceagreements"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/proximityplacementgroups"
 	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/scalesets"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/storageaccounts"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/virtualmachines"
 	"sigs.k8s.io/cluster-api-provider-azure/controllers"
 	infrav1exp "sigs.k8s.io/cluster-api-provider-azure/exp/api/v1alpha3"
 	"sigs.k8s.io/cluster-api-provider-azure/util/reconciler"
@@ -62,6 +70,8 @@ type (
 		Scheme           *runtime.Scheme
 		Recorder         record.EventRecorder
 		ReconcileTimeout time.Duration
+		// AcceptMarketplaceTerms accepts the marketplace terms of the purchase plans of machine images.
+		AcceptMarketplaceTerms bool
 	}
 
 	// azureMachinePoolService provides structure and behavior around the operations needed to reconcile Azure Machine Pools
@@ -69,6 +79,10 @@ type (
 		machinePoolScope           *scope.MachinePoolScope
 		clusterScope               *scope.ClusterScope
 		virtualMachinesScaleSetSvc *scalesets.Service
+		ppgSvc                     azure.OldService
+		storageAccountsSvc         *storageaccounts.Service
+		imagesSvc                  *images.Service
+		marketplaceAgreementsSvc   azure.OldService
 	}
 
 	// annotationReaderWriter provides an interface to read and write annotations
@@ -252,6 +266,14 @@ func (r *AzureMachinePoolReconciler) reconcileNormal(ctx context.Context, machin
 
 	ams := newAzureMachinePoolService(machinePoolScope, clusterScope)
 
+	if err := r.reconcileImage(ctx, machinePoolScope, ams); err != nil {
+		if terr, ok := azure.IsTerminalError(err); ok {
+			controllers.SetTerminalFailure(r.Recorder, machinePoolScope.AzureMachinePool, machinePoolScope, terr.Reason, err)
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, errors.Wrap(err, "failed to resolve VMSS image")
+	}
+
 	// Get or create the virtual machine.
 	vmss, err := ams.CreateOrUpdate(ctx)
 	if err != nil {
@@ -262,12 +284,21 @@ func (r *AzureMachinePoolReconciler) reconcileNormal(ctx context.Context, machin
 	machinePoolScope.AzureMachinePool.Spec.ProviderID = fmt.Sprintf("azure:///%s", vmss.ID)
 	providerIDList := make([]string, len(vmss.Instances))
 	var readyCount int32
+	var evictedInstances []string
 	for i, vm := range vmss.Instances {
 		providerIDList[i] = fmt.Sprintf("azure:///%s", vm.ID)
+		// evicted Spot instances keep their succeeded provisioning state but don't run
+		if machinePoolScope.AzureMachinePool.Spec.Template.SpotVMOptions != nil &&
+			(vm.PowerState == virtualmachines.PowerStateDeallocated || vm.PowerState == virtualmachines.PowerStateDeallocating) {
+			evictedInstances = append(evictedInstances, providerIDList[i])
+			continue
+		}
 		if vm.State == infrav1.VMStateSucceeded {
 			readyCount++
 		}
 	}
+	r.setEvictedInstances(machinePoolScope, evictedInstances)
+	setVMExtensionsCondition(machinePoolScope, vmss)
 	machinePoolScope.AzureMachinePool.Spec.ProviderIDList = providerIDList
 	machinePoolScope.AzureMachinePool.Status.ProvisioningState = &vmss.State
 	machinePoolScope.AzureMachinePool.Status.Replicas = int32(len(providerIDList))
@@ -275,7 +306,7 @@ func (r *AzureMachinePoolReconciler) reconcileNormal(ctx context.Context, machin
 
 	switch vmss.State {
 	case infrav1.VMStateSucceeded:
-		machinePoolScope.Info("Machine Pool is running", "id", *machinePoolScope.GetID())
+		machinePoolScope.Info("Machine Pool is running", "id", *machinePoolScope.GetID(), "readyReplicas", readyCount)
 		machinePoolScope.SetReady()
 	case infrav1.VMStateUpdating:
 		machinePoolScope.Info("Machine Pool is updating", "id", *machinePoolScope.GetID())
@@ -293,6 +324,113 @@ func (r *AzureMachinePoolReconciler) reconcileNormal(ctx context.Context, machin
 	return reconcile.Result{}, nil
 }
 
+// setEvictedInstances records the Spot instances of the scale set deallocated by an eviction, with an event for
+// newly evicted instances.
+func (r *AzureMachinePoolReconciler) setEvictedInstances(machinePoolScope *scope.MachinePoolScope, evictedInstances []string) {
+	known := make(map[string]bool, len(machinePoolScope.AzureMachinePool.Status.EvictedInstances))
+	for _, providerID := range machinePoolScope.AzureMachinePool.Status.EvictedInstances {
+		known[providerID] = true
+	}
+	var newlyEvicted []string
+	for _, providerID := range evictedInstances {
+		if !known[providerID] {
+			newlyEvicted = append(newlyEvicted, providerID)
+		}
+	}
+	if len(newlyEvicted) > 0 {
+		machinePoolScope.Info("Spot instances were evicted", "instances", newlyEvicted)
+		r.Recorder.Eventf(machinePoolScope.AzureMachinePool, corev1.EventTypeWarning, "SpotInstancesEvicted", "Spot instances were evicted and deallocated: %s", strings.Join(newlyEvicted, ", "))
+	}
+	machinePoolScope.AzureMachinePool.Status.EvictedInstances = evictedInstances
+}
+
+// setVMExtensionsCondition reports the provisioning state of the VM extensions of the scale set instances: not ready
+// with an error when an extension failed on an instance, not ready while extensions are being provisioned, and ready
+// otherwise.
+func setVMExtensionsCondition(machinePoolScope *scope.MachinePoolScope, vmss *infrav1exp.VMSS) {
+	if len(machinePoolScope.AzureMachinePool.Spec.Template.Extensions) == 0 {
+		machinePoolScope.SetVMExtensionsReady()
+		return
+	}
+	var provisioning []string
+	for _, vm := range vmss.Instances {
+		names := make([]string, 0, len(vm.ExtensionStates))
+		for name := range vm.ExtensionStates {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			switch vm.ExtensionStates[name] {
+			case infrav1.VMStateSucceeded:
+			case infrav1.VMStateFailed:
+				machinePoolScope.SetVMExtensionsNotReady(infrav1.VMExtensionsFailedReason, clusterv1.ConditionSeverityError,
+					fmt.Sprintf("VM extension %s failed to provision on instance %s", name, vm.Name))
+				return
+			default:
+				provisioning = append(provisioning, fmt.Sprintf("%s on instance %s", name, vm.Name))
+			}
+		}
+	}
+	if len(provisioning) > 0 {
+		machinePoolScope.SetVMExtensionsNotReady(infrav1.VMExtensionsProvisioningReason, clusterv1.ConditionSeverityInfo,
+			fmt.Sprintf("VM extensions are being provisioned: %s", strings.Join(provisioning, ", ")))
+		return
+	}
+	machinePoolScope.SetVMExtensionsReady()
+}
+
+// reconcileImage pins the image of the machine pool in its status, resolving a latest version to the concrete version
+// available when the scale set is created, and emits an event when a newer version of the image is available. The
+// image of a scale set created before image versions were pinned is pinned to the version the scale set uses. It
+// returns a terminal error when a new image of the machine pool doesn't exist, or when its marketplace terms aren't
+// accepted and accepting them before creating or updating the scale set isn't enabled.
+func (r *AzureMachinePoolReconciler) reconcileImage(ctx context.Context, machinePoolScope *scope.MachinePoolScope, ams *azureMachinePoolService) error {
+	image, err := getVMImage(machinePoolScope)
+	if err != nil {
+		return err
+	}
+
+	pinned := machinePoolScope.AzureMachinePool.Status.Image
+	if pinned == nil && machinePoolScope.GetID() != nil {
+		vmss, err := ams.Get(ctx)
+		if err != nil {
+			return err
+		}
+		if vmss != nil && images.IsPinnedVersionOf(&vmss.Image, image) {
+			pinned = vmss.Image.DeepCopy()
+			machinePoolScope.AzureMachinePool.Status.Image = pinned
+		}
+	}
+	// An existing scale set keeps using its pinned image until the image of the machine pool changes.
+	unchanged := machinePoolScope.GetID() != nil && images.IsPinnedVersionOf(pinned, image)
+
+	resolved, newerVersion, err := controllers.ResolveImage(ctx, ams.imagesSvc, image, pinned)
+	if err != nil {
+		// Looking up the pinned image only checks for newer versions, which must not block existing instances.
+		if unchanged {
+			machinePoolScope.V(2).Info("Unable to look up the image of the scale set", "reason", err.Error())
+			return nil
+		}
+		return err
+	}
+
+	// Make sure the image exists before creating or updating the scale set with it.
+	if !unchanged {
+		if err := ams.imagesSvc.Validate(ctx, resolved); err != nil {
+			return err
+		}
+		if err := controllers.ReconcileMarketplaceTerms(ctx, ams.marketplaceAgreementsSvc, resolved, r.AcceptMarketplaceTerms); err != nil {
+			return err
+		}
+	}
+	machinePoolScope.AzureMachinePool.Status.Image = resolved
+
+	if newerVersion != "" {
+		r.Recorder.Eventf(machinePoolScope.AzureMachinePool, corev1.EventTypeNormal, "NewerImageVersionAvailable", "Version %s of the image is available, the machine pool uses version %s", newerVersion, images.Version(resolved))
+	}
+	return nil
+}
+
 func (r *AzureMachinePoolReconciler) reconcileDelete(ctx context.Context, machinePoolScope *scope.MachinePoolScope, clusterScope *scope.ClusterScope) (_ reconcile.Result, reterr error) {
 	machinePoolScope.Info("Handling deleted AzureMachinePool")
 
@@ -485,6 +623,10 @@ func newAzureMachinePoolService(machinePoolScope *scope.MachinePoolScope, cluste
 		machinePoolScope:           machinePoolScope,
 		clusterScope:               clusterScope,
 		virtualMachinesScaleSetSvc: scalesets.NewService(machinePoolScope),
+		ppgSvc:                     proximityplacementgroups.NewService(clusterScope),
+		storageAccountsSvc:         storageaccounts.NewService(clusterScope),
+		imagesSvc:                  images.NewService(clusterScope),
+		marketplaceAgreementsSvc:   marketplaceagreements.NewService(clusterScope),
 	}
 }
 
@@ -500,9 +642,12 @@ func (s *azureMachinePoolService) CreateOrUpdate(ctx context.Context) (*infrav1e
 		return nil, errors.Wrapf(err, "failed to base64 decode ssh public key")
 	}
 
-	image, err := getVMImage(s.machinePoolScope)
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to get VMSS image")
+	// Use the image version pinned in the status, if any.
+	image := s.machinePoolScope.AzureMachinePool.Status.Image
+	if image == nil {
+		if image, err = getVMImage(s.machinePoolScope); err != nil {
+			return nil, errors.Wrap(err, "failed to get VMSS image")
+		}
 	}
 
 	bootstrapData, err := s.machinePoolScope.GetBootstrapData(ctx)
@@ -510,22 +655,60 @@ func (s *azureMachinePoolService) CreateOrUpdate(ctx context.Context) (*infrav1e
 		return nil, errors.Wrap(err, "failed to retrieve bootstrap data")
 	}
 
+	extensions, err := s.machinePoolScope.VMExtensionSpecs(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get VM extensions")
+	}
+
+	// The node subnet may live in a different subscription than the scale set, so build its ID
+	// against the subscription of the virtual network when it hasn't been discovered yet.
+	nodeSubnet := s.clusterScope.NodeSubnet()
+	subnetID := nodeSubnet.ID
+	if subnetID == "" {
+		vnet := s.clusterScope.Vnet()
+		subnetID = azure.SubnetID(azure.VnetAuthorizer(s.clusterScope).SubscriptionID(), vnet.ResourceGroup, vnet.Name, nodeSubnet.Name)
+	}
+
 	vmssSpec := &scalesets.Spec{
-		Name:                   s.machinePoolScope.Name(),
-		ResourceGroup:          s.clusterScope.ResourceGroup(),
-		Location:               s.clusterScope.Location(),
-		ClusterName:            s.clusterScope.ClusterName(),
-		MachinePoolName:        s.machinePoolScope.Name(),
-		Sku:                    ampSpec.Template.VMSize,
-		Capacity:               replicas,
-		SSHKeyData:             string(decoded),
-		Image:                  image,
-		OSDisk:                 ampSpec.Template.OSDisk,
-		CustomData:             bootstrapData,
-		AdditionalTags:         s.machinePoolScope.AdditionalTags(),
-		SubnetID:               s.clusterScope.AzureCluster.Spec.NetworkSpec.GetNodeSubnet().ID,
-		PublicLoadBalancerName: s.clusterScope.ClusterName(),
-		AcceleratedNetworking:  ampSpec.Template.AcceleratedNetworking,
+		Name:                  s.machinePoolScope.Name(),
+		ResourceGroup:         s.clusterScope.ResourceGroup(),
+		Location:              s.clusterScope.Location(),
+		ClusterName:           s.clusterScope.ClusterName(),
+		MachinePoolName:       s.machinePoolScope.Name(),
+		Sku:                   ampSpec.Template.VMSize,
+		Capacity:              replicas,
+		SSHKeyData:            string(decoded),
+		Image:                 image,
+		OSDisk:                ampSpec.Template.OSDisk,
+		CustomData:            bootstrapData,
+		AdditionalTags:        s.machinePoolScope.AdditionalTags(),
+		SubnetID:              subnetID,
+		AcceleratedNetworking: ampSpec.Template.AcceleratedNetworking,
+		Extensions:            extensions,
+		SpotVMOptions:         ampSpec.Template.SpotVMOptions,
+		ApplicationSecurityGroupID: azure.ApplicationSecurityGroupID(
+			s.clusterScope.SubscriptionID(),
+			s.clusterScope.ResourceGroup(),
+			azure.GenerateApplicationSecurityGroupName(s.clusterScope.ClusterName(), infrav1.Node),
+		),
+	}
+
+	if ampSpec.Template.BootDiagnostics.IsEnabled() {
+		vmssSpec.BootDiagnosticsStorageURI, err = controllers.ReconcileBootDiagnosticsStorageAccount(ctx, s.storageAccountsSvc, s.clusterScope)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to reconcile boot diagnostics storage account")
+		}
+	}
+
+	if s.clusterScope.NodeOutboundLBEnabled() {
+		vmssSpec.PublicLoadBalancerName = s.clusterScope.ClusterName()
+	}
+
+	if ppgName := ampSpec.Template.ProximityPlacementGroupName; ppgName != "" {
+		if err := s.ppgSvc.Reconcile(ctx, &proximityplacementgroups.Spec{Name: ppgName}); err != nil {
+			return nil, errors.Wrapf(err, "failed to reconcile proximity placement group %s", ppgName)
+		}
+		vmssSpec.ProximityPlacementGroupID = azure.ProximityPlacementGroupID(s.clusterScope.SubscriptionID(), s.clusterScope.ResourceGroup(), ppgName)
 	}
 
 	err = s.virtualMachinesScaleSetSvc.Reconcile(ctx, vmssSpec)
@@ -568,6 +751,15 @@ func (s *azureMachinePoolService) Delete(ctx context.Context) error {
 		return errors.Wrapf(err, "failed to delete machine pool")
 	}
 
+	// the proximity placement group of the cluster is only deleted with the cluster
+	ppgName := s.machinePoolScope.AzureMachinePool.Spec.Template.ProximityPlacementGroupName
+	if ppgName != "" && ppgName != s.clusterScope.ProximityPlacementGroupName() {
+		err = s.ppgSvc.Delete(ctx, &proximityplacementgroups.Spec{Name: ppgName})
+		if err != nil {
+			return errors.Wrapf(err, "failed to delete proximity placement group of machine pool %s", s.machinePoolScope.Name())
+		}
+	}
+
 	return nil
 }
 
@@ -28,6 +28,7 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha3"
 	clusterv1exp "sigs.k8s.io/cluster-api/exp/api/v1alpha3"
+	"sigs.k8s.io/cluster-api/util/conditions"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
@@ -347,3 +348,74 @@ func newCluster(name string) *clusterv1.Cluster {
 		},
 	}
 }
+
+func Test_setVMExtensionsCondition(t *testing.T) {
+	extensions := []infrav1.VMExtension{{Name: "my-script"}, {Name: "monitoring-agent"}}
+	cases := []struct {
+		Name       string
+		Extensions []infrav1.VMExtension
+		Instances  []infrav1exp.VMSSVM
+		Expect     func(*gomega.GomegaWithT, *clusterv1.Condition)
+	}{
+		{
+			Name:      "no extensions",
+			Instances: []infrav1exp.VMSSVM{{Name: "vm0"}},
+			Expect: func(g *gomega.GomegaWithT, condition *clusterv1.Condition) {
+				g.Expect(condition).To(gomega.BeNil())
+			},
+		},
+		{
+			Name:       "extensions provisioned",
+			Extensions: extensions,
+			Instances: []infrav1exp.VMSSVM{
+				{Name: "vm0", ExtensionStates: map[string]infrav1.VMState{"my-script": infrav1.VMStateSucceeded, "monitoring-agent": infrav1.VMStateSucceeded}},
+			},
+			Expect: func(g *gomega.GomegaWithT, condition *clusterv1.Condition) {
+				g.Expect(condition.Status).To(gomega.Equal(v1.ConditionTrue))
+			},
+		},
+		{
+			Name:       "extension provisioning",
+			Extensions: extensions,
+			Instances: []infrav1exp.VMSSVM{
+				{Name: "vm0", ExtensionStates: map[string]infrav1.VMState{"my-script": infrav1.VMStateSucceeded}},
+				{Name: "vm1", ExtensionStates: map[string]infrav1.VMState{"my-script": infrav1.VMStateCreating}},
+			},
+			Expect: func(g *gomega.GomegaWithT, condition *clusterv1.Condition) {
+				g.Expect(condition.Status).To(gomega.Equal(v1.ConditionFalse))
+				g.Expect(condition.Reason).To(gomega.Equal(infrav1.VMExtensionsProvisioningReason))
+				g.Expect(condition.Severity).To(gomega.Equal(clusterv1.ConditionSeverityInfo))
+				g.Expect(condition.Message).To(gomega.ContainSubstring("my-script on instance vm1"))
+			},
+		},
+		{
+			Name:       "extension failed",
+			Extensions: extensions,
+			Instances: []infrav1exp.VMSSVM{
+				{Name: "vm0", ExtensionStates: map[string]infrav1.VMState{"my-script": infrav1.VMStateCreating}},
+				{Name: "vm1", ExtensionStates: map[string]infrav1.VMState{"my-script": infrav1.VMStateSucceeded, "monitoring-agent": infrav1.VMStateFailed}},
+			},
+			Expect: func(g *gomega.GomegaWithT, condition *clusterv1.Condition) {
+				g.Expect(condition.Status).To(gomega.Equal(v1.ConditionFalse))
+				g.Expect(condition.Reason).To(gomega.Equal(infrav1.VMExtensionsFailedReason))
+				g.Expect(condition.Severity).To(gomega.Equal(clusterv1.ConditionSeverityError))
+				g.Expect(condition.Message).To(gomega.Equal("VM extension monitoring-agent failed to provision on instance vm1"))
+			},
+		},
+	}
+
+	for _, c := range cases {
+		c := c
+		t.Run(c.Name, func(t *testing.T) {
+			g := gomega.NewGomegaWithT(t)
+			amp := &infrav1exp.AzureMachinePool{
+				Spec: infrav1exp.AzureMachinePoolSpec{
+					Template: infrav1exp.AzureMachineTemplate{Extensions: c.Extensions},
+				},
+			}
+			s := &scope.MachinePoolScope{AzureMachinePool: amp}
+			setVMExtensionsCondition(s, &infrav1exp.VMSS{Instances: c.Instances})
+			c.Expect(g, conditions.Get(amp, infrav1.VMExtensionsReadyCondition))
+		})
+	}
+}
@@ -0,0 +1,175 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package ipam carves subnet address ranges out of a virtual network address space.
+package ipam
+
+import (
+	"encoding/binary"
+	"net"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// azureReservedAddresses is the number of addresses Azure reserves at the start of every subnet.
+	// https://docs.microsoft.com/en-us/azure/virtual-network/virtual-networks-faq#are-there-any-restrictions-on-using-ip-addresses-within-these-subnets
+	azureReservedAddresses = 4
+	// internalLBAddressOffset is the offset of the preferred internal LB address from the start of a subnet.
+	internalLBAddressOffset = 100
+	// defaultSubnetPrefixLength is the size of subnets allocated without an explicit size.
+	defaultSubnetPrefixLength = 16
+	// MinSubnetPrefixLength is the largest subnet size that can be requested.
+	MinSubnetPrefixLength = 8
+	// MaxSubnetPrefixLength is the smallest subnet size Azure supports.
+	MaxSubnetPrefixLength = 29
+)
+
+// Allocator hands out non-overlapping IPv4 CIDR blocks from an address space.
+type Allocator struct {
+	space     *net.IPNet
+	allocated []*net.IPNet
+}
+
+// NewAllocator returns an Allocator for the address space described by cidr.
+func NewAllocator(cidr string) (*Allocator, error) {
+	space, err := parseCIDR(cidr)
+	if err != nil {
+		return nil, err
+	}
+	return &Allocator{space: space}, nil
+}
+
+// Reserve marks a user specified CIDR block as used, so it is never handed out by Allocate.
+// It returns an error if the block overlaps a block that was reserved or allocated before.
+func (a *Allocator) Reserve(cidr string) error {
+	block, err := parseCIDR(cidr)
+	if err != nil {
+		return err
+	}
+	for _, used := range a.allocated {
+		if Overlaps(block, used) {
+			return errors.Errorf("CIDR block %s overlaps %s", cidr, used.String())
+		}
+	}
+	a.allocated = append(a.allocated, block)
+	return nil
+}
+
+// Allocate returns the first free CIDR block with the given prefix length inside the address space.
+func (a *Allocator) Allocate(prefixLength int) (string, error) {
+	spaceOnes, _ := a.space.Mask.Size()
+	if prefixLength < spaceOnes || prefixLength > 32 {
+		return "", errors.Errorf("cannot allocate a /%d block from %s", prefixLength, a.space.String())
+	}
+	size := uint64(1) << uint(32-prefixLength)
+	first, last := bounds(a.space)
+	for candidate := uint64(first); candidate+size-1 <= uint64(last); {
+		block := &net.IPNet{IP: toIP(uint32(candidate)), Mask: net.CIDRMask(prefixLength, 32)}
+		var conflict *net.IPNet
+		for _, used := range a.allocated {
+			if Overlaps(block, used) {
+				conflict = used
+				break
+			}
+		}
+		if conflict == nil {
+			a.allocated = append(a.allocated, block)
+			return block.String(), nil
+		}
+		// skip to the first aligned block past the conflicting one
+		_, conflictLast := bounds(conflict)
+		candidate = (uint64(conflictLast)/size + 1) * size
+	}
+	return "", errors.Errorf("no free /%d block left in %s", prefixLength, a.space.String())
+}
+
+// DefaultPrefixLength returns the prefix length of subnets allocated from the address space described
+// by cidr when no size is requested: a /16, or a quarter of the address space for smaller spaces.
+func DefaultPrefixLength(cidr string) (int, error) {
+	space, err := parseCIDR(cidr)
+	if err != nil {
+		return 0, err
+	}
+	ones, _ := space.Mask.Size()
+	if ones+2 > defaultSubnetPrefixLength {
+		return min(ones+2, MaxSubnetPrefixLength), nil
+	}
+	return defaultSubnetPrefixLength, nil
+}
+
+// InternalLBIPAddress returns the preferred internal load balancer address inside a subnet.
+// This is the 100th address of the subnet, or the last usable address for subnets that are too small.
+func InternalLBIPAddress(subnetCIDR string) (string, error) {
+	subnet, err := parseCIDR(subnetCIDR)
+	if err != nil {
+		return "", err
+	}
+	first, last := bounds(subnet)
+	if uint64(last)-uint64(first) < azureReservedAddresses+1 {
+		return "", errors.Errorf("subnet %s has no usable addresses", subnetCIDR)
+	}
+	// the last address of the subnet is reserved for broadcast
+	address := last - 1
+	if uint64(first)+internalLBAddressOffset < uint64(last) {
+		address = first + internalLBAddressOffset
+	}
+	return toIP(address).String(), nil
+}
+
+// Contains returns true if the CIDR block inner lies entirely inside the CIDR block outer.
+func Contains(outer, inner *net.IPNet) bool {
+	outerOnes, _ := outer.Mask.Size()
+	innerOnes, _ := inner.Mask.Size()
+	return outerOnes <= innerOnes && outer.Contains(inner.IP)
+}
+
+// Overlaps returns true if the CIDR blocks a and b share at least one address.
+func Overlaps(a, b *net.IPNet) bool {
+	return a.Contains(b.IP) || b.Contains(a.IP)
+}
+
+// parseCIDR parses an IPv4 CIDR block and returns the network it describes.
+func parseCIDR(cidr string) (*net.IPNet, error) {
+	_, block, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid CIDR block %q", cidr)
+	}
+	if block.IP.To4() == nil {
+		return nil, errors.Errorf("CIDR block %s is not an IPv4 range", cidr)
+	}
+	return block, nil
+}
+
+// bounds returns the first and last address of an IPv4 CIDR block.
+func bounds(block *net.IPNet) (uint32, uint32) {
+	first := binary.BigEndian.Uint32(block.IP.To4())
+	ones, _ := block.Mask.Size()
+	return first, first | uint32((uint64(1)<<uint(32-ones))-1)
+}
+
+func toIP(address uint32) net.IP {
+	ip := make(net.IP, net.IPv4len)
+	binary.BigEndian.PutUint32(ip, address)
+	return ip
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,182 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ipam
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestAllocate(t *testing.T) {
+	testcases := []struct {
+		name          string
+		space         string
+		reserved      []string
+		prefixLengths []int
+		expected      []string
+		expectedError string
+	}{
+		{
+			name:          "allocates consecutive blocks",
+			space:         "10.0.0.0/8",
+			prefixLengths: []int{16, 16},
+			expected:      []string{"10.0.0.0/16", "10.1.0.0/16"},
+		},
+		{
+			name:          "skips reserved blocks",
+			space:         "10.0.0.0/16",
+			reserved:      []string{"10.0.0.0/24", "10.0.1.16/28"},
+			prefixLengths: []int{24, 28, 24},
+			expected:      []string{"10.0.2.0/24", "10.0.1.0/28", "10.0.3.0/24"},
+		},
+		{
+			name:          "ignores reserved blocks outside of the address space",
+			space:         "172.16.0.0/16",
+			reserved:      []string{"10.0.0.0/8"},
+			prefixLengths: []int{18},
+			expected:      []string{"172.16.0.0/18"},
+		},
+		{
+			name:          "address space exhausted",
+			space:         "10.0.0.0/24",
+			reserved:      []string{"10.0.0.0/25"},
+			prefixLengths: []int{25, 25},
+			expected:      []string{"10.0.0.128/25"},
+			expectedError: "no free /25 block left in 10.0.0.0/24",
+		},
+		{
+			name:          "block larger than address space",
+			space:         "10.0.0.0/16",
+			prefixLengths: []int{8},
+			expectedError: "cannot allocate a /8 block from 10.0.0.0/16",
+		},
+		{
+			name:          "overlapping reserved blocks",
+			space:         "10.0.0.0/8",
+			reserved:      []string{"10.0.0.0/16", "10.0.128.0/24"},
+			expectedError: "CIDR block 10.0.128.0/24 overlaps 10.0.0.0/16",
+		},
+	}
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+
+			a, err := NewAllocator(tc.space)
+			g.Expect(err).NotTo(HaveOccurred())
+
+			var allocated []string
+			err = func() error {
+				for _, r := range tc.reserved {
+					if err := a.Reserve(r); err != nil {
+						return err
+					}
+				}
+				for _, p := range tc.prefixLengths {
+					cidr, err := a.Allocate(p)
+					if err != nil {
+						return err
+					}
+					allocated = append(allocated, cidr)
+				}
+				return nil
+			}()
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+			g.Expect(allocated).To(Equal(tc.expected))
+		})
+	}
+}
+
+func TestDefaultPrefixLength(t *testing.T) {
+	g := NewWithT(t)
+
+	for cidr, expected := range map[string]int{
+		"10.0.0.0/8":     16,
+		"10.0.0.0/14":    16,
+		"10.0.0.0/16":    18,
+		"192.168.0.0/28": 29,
+	} {
+		prefixLength, err := DefaultPrefixLength(cidr)
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(prefixLength).To(Equal(expected), cidr)
+	}
+
+	_, err := DefaultPrefixLength("fd00::/8")
+	g.Expect(err).To(MatchError("CIDR block fd00::/8 is not an IPv4 range"))
+}
+
+func TestInternalLBIPAddress(t *testing.T) {
+	testcases := []struct {
+		name          string
+		subnet        string
+		expected      string
+		expectedError string
+	}{
+		{
+			name:     "default control plane subnet",
+			subnet:   "10.0.0.0/16",
+			expected: "10.0.0.100",
+		},
+		{
+			name:     "unaligned subnet",
+			subnet:   "192.168.10.16/24",
+			expected: "192.168.10.100",
+		},
+		{
+			name:     "small subnet",
+			subnet:   "10.0.0.64/26",
+			expected: "10.0.0.126",
+		},
+		{
+			name:     "smallest subnet",
+			subnet:   "10.0.0.8/29",
+			expected: "10.0.0.14",
+		},
+		{
+			name:          "subnet without usable addresses",
+			subnet:        "10.0.0.8/30",
+			expectedError: "subnet 10.0.0.8/30 has no usable addresses",
+		},
+		{
+			name:          "invalid subnet",
+			subnet:        "10.0.0.0",
+			expectedError: "invalid CIDR block \"10.0.0.0\": invalid CIDR address: 10.0.0.0",
+		},
+	}
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+
+			ip, err := InternalLBIPAddress(tc.subnet)
+			if tc.expectedError != "" {
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(ip).To(Equal(tc.expected))
+			}
+		})
+	}
+}
@@ -82,6 +82,8 @@ var (
 	healthAddr                  string
 	webhookPort                 int
 	reconcileTimeout            time.Duration
+	acceptMarketplaceTerms      bool
+	bootstrapSentinelExtension  bool
 )
 
 func InitFlags(fs *pflag.FlagSet) {
@@ -154,6 +156,18 @@ func InitFlags(fs *pflag.FlagSet) {
 		"The maximum duration a reconcile loop can run (e.g. 90m)",
 	)
 
+	fs.BoolVar(&acceptMarketplaceTerms,
+		"accept-marketplace-terms",
+		false,
+		"Accept the marketplace terms of the purchase plans of machine images on behalf of the subscription of the cluster, instead of failing machines whose image terms are not accepted",
+	)
+
+	fs.BoolVar(&bootstrapSentinelExtension,
+		"bootstrap-sentinel-extension",
+		false,
+		"Wait for the bootstrap sentinel file /run/cluster-api/bootstrap-success.complete on new Linux VMs before marking their machines ready. Requires a bootstrap provider writing the file",
+	)
+
 	feature.MutableGates.AddFlag(fs)
 }
 
@@ -202,9 +216,11 @@ func main() {
 
 	if webhookPort == 0 {
 		if err = (&controllers.AzureMachineReconciler{
-			Client:   mgr.GetClient(),
-			Log:      ctrl.Log.WithName("controllers").WithName("AzureMachine"),
-			Recorder: mgr.GetEventRecorderFor("azuremachine-reconciler"),
+			Client:                     mgr.GetClient(),
+			Log:                        ctrl.Log.WithName("controllers").WithName("AzureMachine"),
+			Recorder:                   mgr.GetEventRecorderFor("azuremachine-reconciler"),
+			AcceptMarketplaceTerms:     acceptMarketplaceTerms,
+			BootstrapSentinelExtension: bootstrapSentinelExtension,
 		}).SetupWithManager(mgr, controller.Options{MaxConcurrentReconciles: azureMachineConcurrency}); err != nil {
 			setupLog.Error(err, "unable to create controller", "controller", "AzureMachine")
 			os.Exit(1)
@@ -221,9 +237,10 @@ func main() {
 		setupLog.V(1).Info(fmt.Sprintf("%+v\n", feature.Gates))
 		if feature.Gates.Enabled(capifeature.MachinePool) {
 			if err = (&infrav1controllersexp.AzureMachinePoolReconciler{
-				Client:   mgr.GetClient(),
-				Log:      ctrl.Log.WithName("controllers").WithName("AzureMachinePool"),
-				Recorder: mgr.GetEventRecorderFor("azurecluster-reconciler"),
+				Client:                 mgr.GetClient(),
+				Log:                    ctrl.Log.WithName("controllers").WithName("AzureMachinePool"),
+				Recorder:               mgr.GetEventRecorderFor("azurecluster-reconciler"),
+				AcceptMarketplaceTerms: acceptMarketplaceTerms,
 			}).SetupWithManager(mgr, controller.Options{MaxConcurrentReconciles: azureMachinePoolConcurrency}); err != nil {
 				setupLog.Error(err, "unable to create controller", "controller", "AzureMachinePool")
 				os.Exit(1)